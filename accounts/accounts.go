@@ -39,6 +39,7 @@ const (
 	MimetypeDataWithValidator = "data/validator"
 	MimetypeTypedData         = "data/typed"
 	MimetypeClique            = "application/x-clique-header"
+	MimetypeDccs              = "application/x-dccs-header"
 	MimetypeTextPlain         = "text/plain"
 )
 
@@ -17,15 +17,24 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"math/big"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/contracts/checkpointoracle"
+	"github.com/ethereum/go-ethereum/contracts/nexty/governance"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 	"gopkg.in/urfave/cli.v1"
@@ -118,3 +127,69 @@ func newClefSigner(ctx *cli.Context) *bind.TransactOpts {
 	}
 	return bind.NewClefTransactor(clef, accounts.Account{Address: common.HexToAddress(ctx.String(signerFlag.Name))})
 }
+
+// newSealerKey decrypts the sealer key file specified on the command line.
+func newSealerKey(ctx *cli.Context) *keystore.Key {
+	keyjson, err := ioutil.ReadFile(ctx.String(keyFileFlag.Name))
+	if err != nil {
+		utils.Fatalf("Failed to read the key file: %v", err)
+	}
+	password := ""
+	if ctx.IsSet(passwordFileFlag.Name) {
+		content, err := ioutil.ReadFile(ctx.String(passwordFileFlag.Name))
+		if err != nil {
+			utils.Fatalf("Failed to read the password file: %v", err)
+		}
+		password = strings.TrimRight(string(content), "\r\n")
+	}
+	key, err := keystore.DecryptKey(keyjson, password)
+	if err != nil {
+		utils.Fatalf("Failed to decrypt the key file: %v", err)
+	}
+	return key
+}
+
+// newTransactor returns a transaction signer backed by the sealer key file if
+// specified, or by clef otherwise.
+func newTransactor(ctx *cli.Context) *bind.TransactOpts {
+	if ctx.IsSet(keyFileFlag.Name) {
+		return bind.NewKeyedTransactor(newSealerKey(ctx).PrivateKey)
+	}
+	return newClefSigner(ctx)
+}
+
+// getDccsSealers retrieves the active sealers from the DCCS governance
+// contract, sorted by address. Entries that fail to load are reported and
+// skipped.
+func getDccsSealers(client *rpc.Client) []common.Address {
+	backend := ethclient.NewClient(client)
+	gov, err := governance.NewNextyGovernanceCaller(params.GovernanceAddress, backend)
+	if err != nil {
+		utils.Fatalf("Failed to setup governance contract: %v", err)
+	}
+	// The signers array has no length getter, read it from its storage slot
+	length, err := backend.StorageAt(context.Background(), params.GovernanceAddress, common.Hash{}, nil)
+	if err != nil {
+		utils.Fatalf("Failed to retrieve the sealer count: %v", err)
+	}
+	count := new(big.Int).SetBytes(length)
+	if !count.IsInt64() {
+		utils.Fatalf("Invalid sealer count %v", count)
+	}
+	var sealers []common.Address
+	for i := int64(0); i < count.Int64(); i++ {
+		sealer, err := gov.Signers(nil, big.NewInt(i))
+		if err != nil {
+			log.Warn("Failed to retrieve sealer, skipping", "index", i, "err", err)
+			continue
+		}
+		sealers = append(sealers, sealer)
+	}
+	if len(sealers) == 0 {
+		utils.Fatalf("No active sealer found in the governance contract %s", params.GovernanceAddress.Hex())
+	}
+	sort.Slice(sealers, func(i, j int) bool {
+		return bytes.Compare(sealers[i][:], sealers[j][:]) < 0
+	})
+	return sealers
+}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"strings"
@@ -47,7 +48,10 @@ var commandDeploy = cli.Command{
 		clefURLFlag,
 		signerFlag,
 		signersFlag,
+		dccsFlag,
 		thresholdFlag,
+		keyFileFlag,
+		passwordFileFlag,
 	},
 	Action: utils.MigrateFlags(deploy),
 }
@@ -62,6 +66,8 @@ var commandSign = cli.Command{
 		indexFlag,
 		hashFlag,
 		oracleFlag,
+		keyFileFlag,
+		passwordFileFlag,
 	},
 	Action: utils.MigrateFlags(sign),
 }
@@ -75,6 +81,8 @@ var commandPublish = cli.Command{
 		signerFlag,
 		indexFlag,
 		signaturesFlag,
+		keyFileFlag,
+		passwordFileFlag,
 	},
 	Action: utils.MigrateFlags(publish),
 }
@@ -86,14 +94,22 @@ var commandPublish = cli.Command{
 func deploy(ctx *cli.Context) error {
 	// Gather all the addresses that should be permitted to sign
 	var addrs []common.Address
-	for _, account := range strings.Split(ctx.String(signersFlag.Name), ",") {
-		if trimmed := strings.TrimSpace(account); !common.IsHexAddress(trimmed) {
-			utils.Fatalf("Invalid account in --signers: '%s'", trimmed)
+	if ctx.Bool(dccsFlag.Name) {
+		addrs = getDccsSealers(newRPCClient(ctx.GlobalString(nodeURLFlag.Name)))
+	} else {
+		for _, account := range strings.Split(ctx.String(signersFlag.Name), ",") {
+			if trimmed := strings.TrimSpace(account); !common.IsHexAddress(trimmed) {
+				utils.Fatalf("Invalid account in --signers: '%s'", trimmed)
+			}
+			addrs = append(addrs, common.HexToAddress(account))
 		}
-		addrs = append(addrs, common.HexToAddress(account))
 	}
 	// Retrieve and validate the signing threshold
 	needed := ctx.Int(thresholdFlag.Name)
+	if needed == 0 && ctx.Bool(dccsFlag.Name) {
+		// Default to the super majority of the sealers
+		needed = len(addrs)*2/3 + 1
+	}
 	if needed == 0 || needed > len(addrs) {
 		utils.Fatalf("Invalid signature threshold %d", needed)
 	}
@@ -104,11 +120,11 @@ func deploy(ctx *cli.Context) error {
 	}
 	fmt.Printf("\nSignatures needed to publish: %d\n", needed)
 
-	// setup clef signer, create an abigen transactor and an RPC client
-	transactor, client := newClefSigner(ctx), newClient(ctx)
+	// setup the signer, create an abigen transactor and an RPC client
+	transactor, client := newTransactor(ctx), newClient(ctx)
 
 	// Deploy the checkpoint oracle
-	fmt.Println("Sending deploy request to Clef...")
+	fmt.Println("Sending deploy request...")
 	oracle, tx, _, err := contract.DeployCheckpointOracle(transactor, client, addrs, big.NewInt(int64(params.CheckpointFrequency)),
 		big.NewInt(int64(params.CheckpointProcessConfirmations)), big.NewInt(int64(needed)))
 	if err != nil {
@@ -116,6 +132,14 @@ func deploy(ctx *cli.Context) error {
 	}
 	log.Info("Deployed checkpoint oracle", "address", oracle, "tx", tx.Hash().Hex())
 
+	// Print the oracle config to be published for the light clients
+	config, _ := json.MarshalIndent(&params.CheckpointOracleConfig{
+		Address:   oracle,
+		Signers:   addrs,
+		Threshold: uint64(needed),
+	}, "", "  ")
+	fmt.Printf("\nCheckpoint oracle config:\n%s\n", config)
+
 	return nil
 }
 
@@ -201,6 +225,23 @@ func sign(ctx *cli.Context) error {
 	fmt.Printf("Oracle     => %s\n", address.Hex())
 	fmt.Printf("Index %4d => %s\n", cindex, chash.Hex())
 
+	// Sign checkpoint with the sealer key if specified.
+	if ctx.IsSet(keyFileFlag.Name) {
+		key := newSealerKey(ctx)
+		if !offline {
+			if err := isAdmin(key.Address); err != nil {
+				return err
+			}
+		}
+		sig, err := crypto.Sign(sighash(cindex, address, chash), key.PrivateKey)
+		if err != nil {
+			utils.Fatalf("Failed to sign checkpoint, err %v", err)
+		}
+		sig[64] += 27 // Transform V from 0/1 to 27/28 according to the yellow paper
+		fmt.Printf("Signer     => %s\n", key.Address.Hex())
+		fmt.Printf("Signature  => %s\n", hexutil.Encode(sig))
+		return nil
+	}
 	// Sign checkpoint in clef mode.
 	signer = ctx.String(signerFlag.Name)
 
@@ -301,8 +342,8 @@ func publish(ctx *cli.Context) error {
 	fmt.Printf("Sentry number => %d\nSentry hash   => %s\n", recent.Number, recent.Hash().Hex())
 
 	// Publish the checkpoint into the oracle
-	fmt.Println("Sending publish request to Clef...")
-	tx, err := oracle.RegisterCheckpoint(newClefSigner(ctx), checkpoint.SectionIndex, checkpoint.Hash().Bytes(), recent.Number, recent.Hash(), sigs)
+	fmt.Println("Sending publish request...")
+	tx, err := oracle.RegisterCheckpoint(newTransactor(ctx), checkpoint.SectionIndex, checkpoint.Hash().Bytes(), recent.Number, recent.Hash(), sigs)
 	if err != nil {
 		utils.Fatalf("Register contract failed %v", err)
 	}
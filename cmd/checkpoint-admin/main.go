@@ -104,6 +104,18 @@ var (
 		Name:  "signatures",
 		Usage: "Comma separated checkpoint signatures to submit",
 	}
+	dccsFlag = cli.BoolFlag{
+		Name:  "dccs",
+		Usage: "Use the active DCCS sealers of the governance contract as trusted checkpoint signers",
+	}
+	keyFileFlag = cli.StringFlag{
+		Name:  "keyfile",
+		Usage: "Sealer key file to sign with instead of clef",
+	}
+	passwordFileFlag = cli.StringFlag{
+		Name:  "password",
+		Usage: "Password file to decrypt the sealer key file",
+	}
 )
 
 func main() {
@@ -17,10 +17,13 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
 	"gopkg.in/urfave/cli.v1"
 )
 
@@ -36,7 +39,8 @@ var commandStatus = cli.Command{
 // status fetches the admin list of specified registrar contract.
 func status(ctx *cli.Context) error {
 	// Create a wrapper around the checkpoint oracle contract
-	addr, oracle := newContract(newRPCClient(ctx.GlobalString(nodeURLFlag.Name)))
+	client := newRPCClient(ctx.GlobalString(nodeURLFlag.Name))
+	addr, oracle := newContract(client)
 	fmt.Printf("Oracle => %s\n", addr.Hex())
 	fmt.Println()
 
@@ -45,8 +49,19 @@ func status(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	// On DCCS networks, flag the admins which are not active sealers anymore
+	active := make(map[common.Address]bool)
+	if code, err := ethclient.NewClient(client).CodeAt(context.Background(), params.GovernanceAddress, nil); err == nil && len(code) > 0 {
+		for _, sealer := range getDccsSealers(client) {
+			active[sealer] = true
+		}
+	}
 	for i, admin := range admins {
-		fmt.Printf("Admin %d => %s\n", i+1, admin.Hex())
+		if len(active) > 0 && !active[admin] {
+			fmt.Printf("Admin %d => %s (inactive sealer)\n", i+1, admin.Hex())
+		} else {
+			fmt.Printf("Admin %d => %s\n", i+1, admin.Hex())
+		}
 	}
 	fmt.Println()
 
@@ -453,13 +453,14 @@ func signer(c *cli.Context) error {
 	log.Info("Loaded 4byte database", "embeds", embeds, "locals", locals, "local", fourByteLocal)
 
 	var (
-		api       core.ExternalAPI
-		pwStorage storage.Storage = &storage.NoStorage{}
+		api         core.ExternalAPI
+		pwStorage   storage.Storage = &storage.NoStorage{}
+		sealStorage storage.Storage = storage.NewEphemeralStorage()
 	)
 
 	configDir := c.GlobalString(configdirFlag.Name)
 	if stretchedKey, err := readMasterKey(c, ui); err != nil {
-		log.Warn("Failed to open master, rules disabled", "err", err)
+		log.Warn("Failed to open master, rules disabled and sealings not persisted", "err", err)
 	} else {
 		vaultLocation := filepath.Join(configDir, common.Bytes2Hex(crypto.Keccak256([]byte("vault"), stretchedKey)[:10]))
 
@@ -467,11 +468,13 @@ func signer(c *cli.Context) error {
 		pwkey := crypto.Keccak256([]byte("credentials"), stretchedKey)
 		jskey := crypto.Keccak256([]byte("jsstorage"), stretchedKey)
 		confkey := crypto.Keccak256([]byte("config"), stretchedKey)
+		sealkey := crypto.Keccak256([]byte("sealings"), stretchedKey)
 
 		// Initialize the encrypted storages
 		pwStorage = storage.NewAESEncryptedStorage(filepath.Join(vaultLocation, "credentials.json"), pwkey)
 		jsStorage := storage.NewAESEncryptedStorage(filepath.Join(vaultLocation, "jsstorage.json"), jskey)
 		configStorage := storage.NewAESEncryptedStorage(filepath.Join(vaultLocation, "config.json"), confkey)
+		sealStorage = storage.NewAESEncryptedStorage(filepath.Join(vaultLocation, "sealings.json"), sealkey)
 
 		// Do we have a rule-file?
 		if ruleFile := c.GlobalString(ruleFlag.Name); ruleFile != "" {
@@ -509,6 +512,7 @@ func signer(c *cli.Context) error {
 		"light-kdf", lightKdf, "advanced", advanced)
 	am := core.StartClefAccountManager(ksLoc, nousb, lightKdf, scpath)
 	apiImpl := core.NewSignerAPI(am, chainId, nousb, ui, db, advanced, pwStorage)
+	apiImpl.SetSealingStorage(sealStorage)
 
 	// Establish the bidirectional communication, by creating a new UI backend and registering
 	// it with the UI.
@@ -146,6 +146,7 @@ func init() {
 		disasmCommand,
 		runCommand,
 		stateTestCommand,
+		precompiledCommand,
 	}
 }
 
@@ -0,0 +1,153 @@
+// Copyright 2019 The gonex Authors
+// This file is part of gonex.
+//
+// gonex is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// gonex is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with gonex. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/precompiled"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+var (
+	SeedFlag = cli.StringFlag{
+		Name:  "seed",
+		Usage: "VDF seed (32 bytes hex)",
+	}
+	IterationFlag = cli.Uint64Flag{
+		Name:  "iteration",
+		Usage: "VDF number of iterations",
+	}
+	BitSizeFlag = cli.Uint64Flag{
+		Name:  "bitsize",
+		Usage: "VDF discriminant bit size",
+		Value: 2048,
+	}
+	OutputFlag = cli.StringFlag{
+		Name:  "output",
+		Usage: "VDF output (y, proof) hex",
+	}
+	HeaderFlag = cli.StringFlag{
+		Name:  "header",
+		Usage: "JSON file of the Ethash sealed header to verify",
+	}
+)
+
+var precompiledCommand = cli.Command{
+	Name:  "precompiled",
+	Usage: "runs the CoLoa pre-compiled contracts directly",
+	Subcommands: []cli.Command{
+		{
+			Action:    precompiledRunCmd,
+			Name:      "run",
+			Usage:     "runs a pre-compiled contract with the raw --input",
+			ArgsUsage: "<address>",
+		},
+		{
+			Action: precompiledVDFCmd,
+			Name:   "vdfverify",
+			Usage:  "encodes and runs a vdfVerify call",
+			Flags: []cli.Flag{
+				SeedFlag,
+				IterationFlag,
+				BitSizeFlag,
+				OutputFlag,
+			},
+		},
+		{
+			Action: precompiledEthashCmd,
+			Name:   "ethashverify",
+			Usage:  "encodes and runs an ethashVerify call for a sealed header",
+			Flags: []cli.Flag{
+				HeaderFlag,
+			},
+		},
+	},
+}
+
+func precompiledRunCmd(ctx *cli.Context) error {
+	if len(ctx.Args().First()) == 0 {
+		return errors.New("pre-compiled contract address required")
+	}
+	return runPrecompiled(common.HexToAddress(ctx.Args().First()), common.FromHex(ctx.GlobalString(InputFlag.Name)))
+}
+
+func precompiledVDFCmd(ctx *cli.Context) error {
+	if !ctx.IsSet(SeedFlag.Name) || !ctx.IsSet(IterationFlag.Name) || !ctx.IsSet(OutputFlag.Name) {
+		return errors.New("--seed, --iteration and --output are required")
+	}
+	input, err := precompiled.PackVDFVerify(
+		common.HexToHash(ctx.String(SeedFlag.Name)),
+		ctx.Uint64(IterationFlag.Name),
+		ctx.Uint64(BitSizeFlag.Name),
+		common.FromHex(ctx.String(OutputFlag.Name)))
+	if err != nil {
+		return err
+	}
+	return runPrecompiled(precompiled.VDFVerifyAddress, input)
+}
+
+func precompiledEthashCmd(ctx *cli.Context) error {
+	if !ctx.IsSet(HeaderFlag.Name) {
+		return errors.New("--header is required")
+	}
+	blob, err := ioutil.ReadFile(ctx.String(HeaderFlag.Name))
+	if err != nil {
+		return err
+	}
+	header := new(types.Header)
+	if err := json.Unmarshal(blob, header); err != nil {
+		return err
+	}
+	return runPrecompiled(precompiled.EthashVerifyAddress, precompiled.PackEthashVerifyHeader(header))
+}
+
+// runPrecompiled executes a CoLoa pre-compiled contract and prints the gas it
+// requires along with its result and execution time.
+func runPrecompiled(address common.Address, input []byte) error {
+	p, ok := vm.PrecompiledContractsCoLoa[address]
+	if !ok {
+		return fmt.Errorf("no pre-compiled contract at %x", address)
+	}
+	gas := p.RequiredGas(input)
+
+	start := time.Now()
+	ret, err := p.Run(input)
+	elapsed := time.Since(start)
+
+	fmt.Printf("input:   0x%x\n", input)
+	fmt.Printf("gas:     %d\n", gas)
+	fmt.Printf("time:    %v\n", elapsed)
+	if elapsed > 0 {
+		mgasps := new(big.Float).Quo(new(big.Float).SetUint64(gas*1000), new(big.Float).SetInt64(int64(elapsed)))
+		fmt.Printf("mgas/s:  %s\n", mgasps.Text('f', 2))
+	}
+	if err != nil {
+		fmt.Printf(" error: %v\n", err)
+		return nil
+	}
+	fmt.Printf("0x%x\n", ret)
+	return nil
+}
@@ -0,0 +1,273 @@
+// Copyright 2019 The gonex Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// addressRegexp matches the Ethereum address embedded into a funding request.
+var addressRegexp = regexp.MustCompile("0x[0-9a-fA-F]{40}")
+
+const (
+	signatureExpiry = 10 * time.Minute // Maximum age of a signed funding request
+	signatureSkew   = time.Minute      // Maximum clock drift of a signed funding request
+)
+
+// authRequest is the proof of a funding request as submitted by the user.
+type authRequest struct {
+	URL       string // Social network post URL, or the plain address to fund
+	Signature string // Signature of the request message by the address to fund
+	Timestamp int64  // Unix time included into the signed request message
+}
+
+// authBackend authenticates funding requests of a single kind.
+type authBackend interface {
+	// name returns the name of the backend as enabled on the command line.
+	name() string
+
+	// match reports whether the request is meant for this backend.
+	match(req *authRequest) bool
+
+	// captcha reports whether robots must be kept out by the captcha, if enabled.
+	captcha() bool
+
+	// auth authenticates the request, returning the username, avatar URL and
+	// Ethereum address to fund on success.
+	auth(req *authRequest) (string, string, common.Address, error)
+}
+
+// socialAuth authenticates requests by scraping a public social network post.
+type socialAuth struct {
+	network string // Name of the social network
+	prefix  string // URL prefix of the social network posts
+	scrape  func(url string) (string, string, common.Address, error)
+}
+
+func (a *socialAuth) name() string { return a.network }
+
+func (a *socialAuth) match(req *authRequest) bool {
+	return req.Signature == "" && strings.HasPrefix(req.URL, a.prefix)
+}
+
+func (a *socialAuth) captcha() bool { return true }
+
+func (a *socialAuth) auth(req *authRequest) (string, string, common.Address, error) {
+	return a.scrape(req.URL)
+}
+
+// allowlistAuth authenticates signed requests funding one of a fixed set of
+// addresses, sparing them the captcha.
+type allowlistAuth struct {
+	signed  *signedAuth
+	allowed map[common.Address]struct{}
+}
+
+// newAllowlistAuth loads the allowed addresses from a file, one per line. Empty
+// lines and the ones starting with # are ignored.
+func newAllowlistAuth(path string, signed *signedAuth) (*allowlistAuth, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	a := &allowlistAuth{signed: signed, allowed: make(map[common.Address]struct{})}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		if !common.IsHexAddress(entry) {
+			return nil, fmt.Errorf("invalid address on line %d: %s", line, entry)
+		}
+		a.allowed[common.HexToAddress(entry)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *allowlistAuth) name() string { return "allowlist" }
+
+func (a *allowlistAuth) match(req *authRequest) bool {
+	if req.Signature == "" {
+		return false
+	}
+	_, ok := a.allowed[common.HexToAddress(addressRegexp.FindString(req.URL))]
+	return ok
+}
+
+// The allowlisted users proved their address, there's no need to bother them.
+func (a *allowlistAuth) captcha() bool { return false }
+
+func (a *allowlistAuth) auth(req *authRequest) (string, string, common.Address, error) {
+	_, _, address, err := a.signed.auth(req)
+	if err != nil {
+		return "", "", common.Address{}, err
+	}
+	return address.Hex() + "@allowlist", "", address, nil
+}
+
+// signedAuth authenticates requests signed by the key of the address to fund,
+// without reaching out to any external service. It only proves the ownership of
+// the address, anyone can create any number of them.
+//
+// The signed message includes the time of the request, which expires after a
+// while, and each signed request is only accepted once.
+type signedAuth struct {
+	network string // Network name included into the signed message
+
+	used map[string]time.Time // Accepted requests, until they expire
+	lock sync.Mutex           // Protects the accepted requests
+}
+
+// newSignedAuth creates a signed request authenticator for a network.
+func newSignedAuth(network string) *signedAuth {
+	return &signedAuth{network: network, used: make(map[string]time.Time)}
+}
+
+// message returns the text to be signed for funding the address at a time.
+func (a *signedAuth) message(address common.Address, timestamp int64) string {
+	return fmt.Sprintf("Requesting faucet funds into %s on the %s network at %d.", address.Hex(), a.network, timestamp)
+}
+
+func (a *signedAuth) name() string { return "signed" }
+
+func (a *signedAuth) match(req *authRequest) bool {
+	return req.Signature != ""
+}
+
+func (a *signedAuth) captcha() bool { return true }
+
+func (a *signedAuth) auth(req *authRequest) (string, string, common.Address, error) {
+	address := common.HexToAddress(addressRegexp.FindString(req.URL))
+	if address == (common.Address{}) {
+		return "", "", common.Address{}, errors.New("No Ethereum address found to fund")
+	}
+	now, signed := time.Now(), time.Unix(req.Timestamp, 0)
+	if now.Sub(signed) > signatureExpiry || signed.Sub(now) > signatureSkew {
+		return "", "", common.Address{}, errors.New("Request signature expired, sign a new one")
+	}
+	sig, err := hexutil.Decode(strings.TrimSpace(req.Signature))
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return "", "", common.Address{}, errors.New("Invalid request signature")
+	}
+	// Accept both the [R || S || V] format of personal_sign and the raw one
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pubkey, err := crypto.SigToPub(accounts.TextHash([]byte(a.message(address, req.Timestamp))), sig)
+	if err != nil {
+		return "", "", common.Address{}, errors.New("Invalid request signature")
+	}
+	if crypto.PubkeyToAddress(*pubkey) != address {
+		return "", "", common.Address{}, errors.New("Request not signed by the address to fund")
+	}
+	// Signatures are malleable, reject the reuse of the signed request itself
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	for key, expiry := range a.used {
+		if now.After(expiry) {
+			delete(a.used, key)
+		}
+	}
+	key := fmt.Sprintf("%s@%d", address.Hex(), req.Timestamp)
+	if _, ok := a.used[key]; ok {
+		return "", "", common.Address{}, errors.New("Request signature already used, sign a new one")
+	}
+	a.used[key] = signed.Add(signatureExpiry)
+
+	return address.Hex() + "@signed", "", address, nil
+}
+
+// noAuth accepts any request containing an Ethereum address.
+type noAuth struct{}
+
+func (noAuth) name() string { return "noauth" }
+
+func (noAuth) match(req *authRequest) bool { return true }
+
+func (noAuth) captcha() bool { return true }
+
+func (noAuth) auth(req *authRequest) (string, string, common.Address, error) {
+	return authNoAuth(req.URL)
+}
+
+// newAuthBackends creates the requested authentication backends, in the order
+// they are matched against funding requests.
+func newAuthBackends(names []string, network string, allowlist string, noauth bool) ([]authBackend, error) {
+	enabled := make(map[string]bool)
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			enabled[name] = true
+		}
+	}
+	var (
+		backends []authBackend
+		signed   = newSignedAuth(network)
+	)
+	// Allowlisted addresses are matched first to spare them the captcha
+	if enabled["allowlist"] {
+		if allowlist == "" {
+			return nil, errors.New("allowlist authentication requires an address file")
+		}
+		auth, err := newAllowlistAuth(allowlist, signed)
+		if err != nil {
+			return nil, err
+		}
+		backends = append(backends, auth)
+		delete(enabled, "allowlist")
+	}
+	if enabled["signed"] {
+		backends = append(backends, signed)
+		delete(enabled, "signed")
+	}
+	if enabled["twitter"] {
+		backends = append(backends, &socialAuth{network: "twitter", prefix: "https://twitter.com/", scrape: authTwitter})
+		delete(enabled, "twitter")
+	}
+	if enabled["facebook"] {
+		backends = append(backends, &socialAuth{network: "facebook", prefix: "https://www.facebook.com/", scrape: authFacebook})
+		delete(enabled, "facebook")
+	}
+	for name := range enabled {
+		return nil, fmt.Errorf("unknown authentication backend: %s", name)
+	}
+	if noauth {
+		backends = append(backends, noAuth{})
+	}
+	if len(backends) == 0 {
+		return nil, errors.New("no authentication backend enabled")
+	}
+	return backends, nil
+}
@@ -0,0 +1,177 @@
+// Copyright 2019 The gonex Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/nexty/endurio/stable"
+	"github.com/ethereum/go-ethereum/contracts/nexty/endurio/volatile"
+	"github.com/ethereum/go-ethereum/contracts/nexty/ntf"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// tokenGas is the gas allowance of a token transfer, generous enough to cover
+// the ERC223 receiver callbacks too.
+const tokenGas = 100000
+
+// currency is a currency dispensed by the faucet, either the native coin or one of
+// the system tokens.
+type currency struct {
+	ID      string   // Identifier of the currency in funding requests (empty for the native coin)
+	Symbol  string   // Display symbol of the currency
+	Amounts []string // Formatted payout amounts of each tier
+
+	payout   int            // Number of whole units to pay out in the first tier
+	decimals int64          // Number of decimals of the currency unit, negative until read from the token
+	token    common.Address // Token contract address (zero for the native coin)
+	abi      abi.ABI        // Token contract ABI
+}
+
+// newCurrency creates a dispensable currency, paying out the given number of whole
+// units in the first tier, multiplied by 2.5 in each subsequent one. The decimals
+// of a token are read from its contract once the chain is available.
+func newCurrency(id string, symbol string, payout int, token common.Address, abiJSON string) (*currency, error) {
+	c := &currency{
+		ID:       id,
+		Symbol:   symbol,
+		Amounts:  make([]string, *tiersFlag),
+		payout:   payout,
+		decimals: 18,
+		token:    token,
+	}
+	if token != (common.Address{}) {
+		parsed, err := abi.JSON(strings.NewReader(abiJSON))
+		if err != nil {
+			return nil, err
+		}
+		c.abi, c.decimals = parsed, -1
+	}
+	for i := range c.Amounts {
+		amount := float64(payout) * math.Pow(2.5, float64(i))
+		c.Amounts[i] = fmt.Sprintf("%s %s", strconv.FormatFloat(amount, 'f', -1, 64), symbol)
+		if amount == 1 {
+			c.Amounts[i] = strings.TrimSuffix(c.Amounts[i], "s")
+		}
+	}
+	return c, nil
+}
+
+// newCurrencies creates the currencies enabled on the command line. The native coin is
+// always dispensed, the tokens only if a payout is configured for them.
+func newCurrencies() ([]*currency, error) {
+	native, err := newCurrency("", "Ethers", *payoutFlag, common.Address{}, "")
+	if err != nil {
+		return nil, err
+	}
+	currencies := []*currency{native}
+
+	tokens := []struct {
+		id      string
+		symbol  string
+		payout  int
+		address common.Address
+		abi     string
+	}{
+		{"ntf", "NTF", *ntfFlag, params.TokenAddress, ntf.NtfTokenABI},
+		{"stable", "NUSD", *stableFlag, params.StableTokenAddress, stable.StableTokenABI},
+		{"volatile", "MNTY", *volatileFlag, params.VolatileTokenAddress, volatile.VolatileTokenABI},
+	}
+	for _, token := range tokens {
+		if token.payout <= 0 {
+			continue
+		}
+		currency, err := newCurrency(token.id, token.symbol, token.payout, token.address, token.abi)
+		if err != nil {
+			return nil, err
+		}
+		currencies = append(currencies, currency)
+	}
+	return currencies, nil
+}
+
+// unit returns the number of base units in a whole unit of the currency.
+func (c *currency) unit() *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(c.decimals), nil)
+}
+
+// amount returns the number of base units to pay out in the given tier.
+func (c *currency) amount(tier uint) *big.Int {
+	amount := new(big.Int).Mul(big.NewInt(int64(c.payout)), c.unit())
+	amount = new(big.Int).Mul(amount, new(big.Int).Exp(big.NewInt(5), big.NewInt(int64(tier)), nil))
+	return new(big.Int).Div(amount, new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(tier)), nil))
+}
+
+// transaction creates the unsigned transaction paying out the given tier of the
+// currency into an address.
+func (c *currency) transaction(nonce uint64, to common.Address, tier uint, price *big.Int) (*types.Transaction, error) {
+	if c.token == (common.Address{}) {
+		return types.NewTransaction(nonce, to, c.amount(tier), 21000, price, nil), nil
+	}
+	if c.decimals < 0 {
+		return nil, fmt.Errorf("%s not available yet, try again later", c.Symbol)
+	}
+	data, err := c.abi.Pack("transfer", to, c.amount(tier))
+	if err != nil {
+		return nil, err
+	}
+	return types.NewTransaction(nonce, c.token, new(big.Int), tokenGas, price, data), nil
+}
+
+// balanceAt retrieves the currency balance of an account at the given block.
+func (c *currency) balanceAt(ctx context.Context, client *ethclient.Client, account common.Address, number *big.Int) (*big.Int, error) {
+	if c.token == (common.Address{}) {
+		return client.BalanceAt(ctx, account, number)
+	}
+	var (
+		contract = bind.NewBoundContract(c.token, c.abi, client, client, client)
+		balance  = new(*big.Int)
+	)
+	if err := contract.Call(&bind.CallOpts{Context: ctx, BlockNumber: number}, balance, "balanceOf", account); err != nil {
+		return nil, err
+	}
+	return *balance, nil
+}
+
+// decimalsAt retrieves the number of decimals of the currency unit at the given
+// block.
+func (c *currency) decimalsAt(ctx context.Context, client *ethclient.Client, number *big.Int) (int64, error) {
+	if c.token == (common.Address{}) {
+		return 18, nil
+	}
+	var (
+		contract = bind.NewBoundContract(c.token, c.abi, client, client, client)
+		decimals = new(*big.Int)
+	)
+	if err := contract.Call(&bind.CallOpts{Context: ctx, BlockNumber: number}, decimals, "decimals"); err != nil {
+		return 0, err
+	}
+	if !(*decimals).IsInt64() || (*decimals).Int64() > 77 {
+		return 0, fmt.Errorf("invalid %s decimals %v", c.Symbol, *decimals)
+	}
+	return (*decimals).Int64(), nil
+}
@@ -36,7 +36,6 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -74,20 +73,23 @@ var (
 	minutesFlag = flag.Int("faucet.minutes", 1440, "Number of minutes to wait between funding rounds")
 	tiersFlag   = flag.Int("faucet.tiers", 3, "Number of funding tiers to enable (x3 time, x2.5 funds)")
 
+	ntfFlag      = flag.Int("faucet.ntf", 0, "Number of NTF tokens to pay out per user request (0 = disabled)")
+	stableFlag   = flag.Int("faucet.stable", 0, "Number of stable tokens (NUSD) to pay out per user request (0 = disabled)")
+	volatileFlag = flag.Int("faucet.volatile", 0, "Number of volatile tokens (MNTY) to pay out per user request (0 = disabled)")
+
 	accJSONFlag = flag.String("account.json", "", "Key json file to fund user requests with")
 	accPassFlag = flag.String("account.pass", "", "Decryption password to access faucet funds")
 
 	captchaToken  = flag.String("captcha.token", "", "Recaptcha site key to authenticate client side")
 	captchaSecret = flag.String("captcha.secret", "", "Recaptcha secret key to authenticate server side")
 
+	authFlag      = flag.String("auth", "twitter,facebook", "Comma separated authentication backends to enable (twitter, facebook, allowlist, signed)")
+	allowlistFlag = flag.String("auth.allowlist", "", "File of addresses whose signed requests skip the captcha (one per line)")
+
 	noauthFlag = flag.Bool("noauth", false, "Enables funding requests without authentication")
 	logFlag    = flag.Int("loglevel", 3, "Log level to use for Ethereum and the faucet")
 )
 
-var (
-	ether = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
-)
-
 var (
 	gitCommit = "" // Git SHA1 commit hash of the release (set via linker flags)
 	gitDate   = "" // Git commit date YYYYMMDD of the release (set via linker flags)
@@ -98,16 +100,13 @@ func main() {
 	flag.Parse()
 	log.Root().SetHandler(log.LvlFilterHandler(log.Lvl(*logFlag), log.StreamHandler(os.Stderr, log.TerminalFormat(true))))
 
-	// Construct the payout tiers
-	amounts := make([]string, *tiersFlag)
+	// Construct the dispensed currencies with their payout tiers
+	currencies, err := newCurrencies()
+	if err != nil {
+		log.Crit("Failed to create the faucet currencies", "err", err)
+	}
 	periods := make([]string, *tiersFlag)
 	for i := 0; i < *tiersFlag; i++ {
-		// Calculate the amount for the next tier and format it
-		amount := float64(*payoutFlag) * math.Pow(2.5, float64(i))
-		amounts[i] = fmt.Sprintf("%s Ethers", strconv.FormatFloat(amount, 'f', -1, 64))
-		if amount == 1 {
-			amounts[i] = strings.TrimSuffix(amounts[i], "s")
-		}
 		// Calculate the period for the next tier and format it
 		period := *minutesFlag * int(math.Pow(3, float64(i)))
 		periods[i] = fmt.Sprintf("%d mins", period)
@@ -124,6 +123,15 @@ func main() {
 			periods[i] = strings.TrimSuffix(periods[i], "s")
 		}
 	}
+	// Assemble the authentication backends to verify funding requests with
+	auths, err := newAuthBackends(strings.Split(*authFlag, ","), *netnameFlag, *allowlistFlag, *noauthFlag)
+	if err != nil {
+		log.Crit("Failed to create the authentication backends", "err", err)
+	}
+	enabled := make(map[string]bool)
+	for _, auth := range auths {
+		enabled[auth.name()] = true
+	}
 	// Load up and render the faucet website
 	tmpl, err := Asset("faucet.html")
 	if err != nil {
@@ -131,11 +139,12 @@ func main() {
 	}
 	website := new(bytes.Buffer)
 	err = template.Must(template.New("").Parse(string(tmpl))).Execute(website, map[string]interface{}{
-		"Network":   *netnameFlag,
-		"Amounts":   amounts,
-		"Periods":   periods,
-		"Recaptcha": *captchaToken,
-		"NoAuth":    *noauthFlag,
+		"Network":    *netnameFlag,
+		"Currencies": currencies,
+		"Periods":    periods,
+		"Recaptcha":  *captchaToken,
+		"NoAuth":     *noauthFlag,
+		"Auths":      enabled,
 	})
 	if err != nil {
 		log.Crit("Failed to render the faucet template", "err", err)
@@ -176,7 +185,7 @@ func main() {
 	ks.Unlock(acc, pass)
 
 	// Assemble and start the faucet light service
-	faucet, err := newFaucet(genesis, *ethPortFlag, enodes, *netFlag, *statsFlag, ks, website.Bytes())
+	faucet, err := newFaucet(genesis, *ethPortFlag, enodes, *netFlag, *statsFlag, ks, website.Bytes(), currencies, auths)
 	if err != nil {
 		log.Crit("Failed to start faucet", "err", err)
 	}
@@ -189,23 +198,26 @@ func main() {
 
 // request represents an accepted funding request.
 type request struct {
-	Avatar  string             `json:"avatar"`  // Avatar URL to make the UI nicer
-	Account common.Address     `json:"account"` // Ethereum address being funded
-	Time    time.Time          `json:"time"`    // Timestamp when the request was accepted
-	Tx      *types.Transaction `json:"tx"`      // Transaction funding the account
+	Avatar   string             `json:"avatar"`   // Avatar URL to make the UI nicer
+	Account  common.Address     `json:"account"`  // Ethereum address being funded
+	Currency string             `json:"currency"` // Symbol of the currency being funded
+	Time     time.Time          `json:"time"`     // Timestamp when the request was accepted
+	Tx       *types.Transaction `json:"tx"`       // Transaction funding the account
 }
 
 // faucet represents a crypto faucet backed by an Ethereum light client.
 type faucet struct {
-	config *params.ChainConfig // Chain configurations for signing
-	stack  *node.Node          // Ethereum protocol stack
-	client *ethclient.Client   // Client connection to the Ethereum chain
-	index  []byte              // Index page to serve up on the web
+	config     *params.ChainConfig // Chain configurations for signing
+	stack      *node.Node          // Ethereum protocol stack
+	client     *ethclient.Client   // Client connection to the Ethereum chain
+	index      []byte              // Index page to serve up on the web
+	currencies []*currency         // Currencies dispensed by the faucet, native coin first
+	auths      []authBackend       // Authentication backends to verify requests with
 
 	keystore *keystore.KeyStore // Keystore containing the single signer
 	account  accounts.Account   // Account funding user faucet requests
 	head     *types.Header      // Current head header of the faucet
-	balance  *big.Int           // Current balance of the faucet
+	balances []*big.Int         // Current balances of the faucet, one per currency
 	nonce    uint64             // Current pending nonce of the faucet
 	price    *big.Int           // Current gas price to issue funds with
 
@@ -217,7 +229,7 @@ type faucet struct {
 	lock sync.RWMutex // Lock protecting the faucet's internals
 }
 
-func newFaucet(genesis *core.Genesis, port int, enodes []*discv5.Node, network uint64, stats string, ks *keystore.KeyStore, index []byte) (*faucet, error) {
+func newFaucet(genesis *core.Genesis, port int, enodes []*discv5.Node, network uint64, stats string, ks *keystore.KeyStore, index []byte, currencies []*currency, auths []authBackend) (*faucet, error) {
 	// Assemble the raw devp2p protocol stack
 	stack, err := node.New(&node.Config{
 		Name:    "geth",
@@ -274,14 +286,16 @@ func newFaucet(genesis *core.Genesis, port int, enodes []*discv5.Node, network u
 	client := ethclient.NewClient(api)
 
 	return &faucet{
-		config:   genesis.Config,
-		stack:    stack,
-		client:   client,
-		index:    index,
-		keystore: ks,
-		account:  ks.Accounts()[0],
-		timeouts: make(map[string]time.Time),
-		update:   make(chan struct{}, 1),
+		config:     genesis.Config,
+		stack:      stack,
+		client:     client,
+		index:      index,
+		currencies: currencies,
+		auths:      auths,
+		keystore:   ks,
+		account:    ks.Accounts()[0],
+		timeouts:   make(map[string]time.Time),
+		update:     make(chan struct{}, 1),
 	}, nil
 }
 
@@ -328,24 +342,22 @@ func (f *faucet) apiHandler(conn *websocket.Conn) {
 	}()
 	// Gather the initial stats from the network to report
 	var (
-		head    *types.Header
-		balance *big.Int
-		nonce   uint64
-		err     error
+		head  *types.Header
+		stats map[string]interface{}
+		err   error
 	)
-	for head == nil || balance == nil {
+	for head == nil || stats == nil {
 		// Retrieve the current stats cached by the faucet
 		f.lock.RLock()
 		if f.head != nil {
 			head = types.CopyHeader(f.head)
 		}
-		if f.balance != nil {
-			balance = new(big.Int).Set(f.balance)
+		if f.balances != nil {
+			stats = f.stats()
 		}
-		nonce = f.nonce
 		f.lock.RUnlock()
 
-		if head == nil || balance == nil {
+		if head == nil || stats == nil {
 			// Report the faucet offline until initial stats are ready
 			if err = sendError(conn, errors.New("Faucet offline")); err != nil {
 				log.Warn("Failed to send faucet error to client", "err", err)
@@ -355,12 +367,7 @@ func (f *faucet) apiHandler(conn *websocket.Conn) {
 		}
 	}
 	// Send over the initial stats and the latest header
-	if err = send(conn, map[string]interface{}{
-		"funds":    new(big.Int).Div(balance, ether),
-		"funded":   nonce,
-		"peers":    f.stack.Server().PeerCount(),
-		"requests": f.reqs,
-	}, 3*time.Second); err != nil {
+	if err = send(conn, stats, 3*time.Second); err != nil {
 		log.Warn("Failed to send initial stats to client", "err", err)
 		return
 	}
@@ -372,16 +379,35 @@ func (f *faucet) apiHandler(conn *websocket.Conn) {
 	for {
 		// Fetch the next funding request and validate against github
 		var msg struct {
-			URL     string `json:"url"`
-			Tier    uint   `json:"tier"`
-			Captcha string `json:"captcha"`
+			URL       string `json:"url"`
+			Tier      uint   `json:"tier"`
+			Token     string `json:"token"`
+			Signature string `json:"signature"`
+			Timestamp int64  `json:"timestamp"`
+			Captcha   string `json:"captcha"`
 		}
 		if err = websocket.JSON.Receive(conn, &msg); err != nil {
 			return
 		}
-		if !*noauthFlag && !strings.HasPrefix(msg.URL, "https://gist.github.com/") && !strings.HasPrefix(msg.URL, "https://twitter.com/") &&
-			!strings.HasPrefix(msg.URL, "https://plus.google.com/") && !strings.HasPrefix(msg.URL, "https://www.facebook.com/") {
-			if err = sendError(conn, errors.New("URL doesn't link to supported services")); err != nil {
+		req := &authRequest{URL: msg.URL, Signature: msg.Signature, Timestamp: msg.Timestamp}
+
+		var auth authBackend
+		for _, backend := range f.auths {
+			if backend.match(req) {
+				auth = backend
+				break
+			}
+		}
+		if auth == nil {
+			switch {
+			case strings.HasPrefix(msg.URL, "https://gist.github.com/"):
+				err = errors.New("GitHub authentication discontinued at the official request of GitHub")
+			case strings.HasPrefix(msg.URL, "https://plus.google.com/"):
+				err = errors.New("Google+ authentication discontinued as the service was sunset")
+			default:
+				err = errors.New("URL doesn't link to supported services")
+			}
+			if err = sendError(conn, err); err != nil {
 				log.Warn("Failed to send URL error to client", "err", err)
 				return
 			}
@@ -394,10 +420,24 @@ func (f *faucet) apiHandler(conn *websocket.Conn) {
 			}
 			continue
 		}
-		log.Info("Faucet funds requested", "url", msg.URL, "tier", msg.Tier)
+		var cur *currency
+		for _, c := range f.currencies {
+			if c.ID == msg.Token {
+				cur = c
+				break
+			}
+		}
+		if cur == nil {
+			if err = sendError(conn, errors.New("Invalid funding currency requested")); err != nil {
+				log.Warn("Failed to send currency error to client", "err", err)
+				return
+			}
+			continue
+		}
+		log.Info("Faucet funds requested", "url", msg.URL, "tier", msg.Tier, "currency", cur.Symbol)
 
 		// If captcha verifications are enabled, make sure we're not dealing with a robot
-		if *captchaToken != "" {
+		if *captchaToken != "" && auth.captcha() {
 			form := url.Values{}
 			form.Add("secret", *captchaSecret)
 			form.Add("response", msg.Captcha)
@@ -433,33 +473,7 @@ func (f *faucet) apiHandler(conn *websocket.Conn) {
 			}
 		}
 		// Retrieve the Ethereum address to fund, the requesting user and a profile picture
-		var (
-			username string
-			avatar   string
-			address  common.Address
-		)
-		switch {
-		case strings.HasPrefix(msg.URL, "https://gist.github.com/"):
-			if err = sendError(conn, errors.New("GitHub authentication discontinued at the official request of GitHub")); err != nil {
-				log.Warn("Failed to send GitHub deprecation to client", "err", err)
-				return
-			}
-			continue
-		case strings.HasPrefix(msg.URL, "https://plus.google.com/"):
-			if err = sendError(conn, errors.New("Google+ authentication discontinued as the service was sunset")); err != nil {
-				log.Warn("Failed to send Google+ deprecation to client", "err", err)
-				return
-			}
-			continue
-		case strings.HasPrefix(msg.URL, "https://twitter.com/"):
-			username, avatar, address, err = authTwitter(msg.URL)
-		case strings.HasPrefix(msg.URL, "https://www.facebook.com/"):
-			username, avatar, address, err = authFacebook(msg.URL)
-		case *noauthFlag:
-			username, avatar, address, err = authNoAuth(msg.URL)
-		default:
-			err = errors.New("Something funky happened, please open an issue at https://github.com/ethereum/go-ethereum/issues")
-		}
+		username, avatar, address, err := auth.auth(req)
 		if err != nil {
 			if err = sendError(conn, err); err != nil {
 				log.Warn("Failed to send prefix error to client", "err", err)
@@ -467,21 +481,29 @@ func (f *faucet) apiHandler(conn *websocket.Conn) {
 			}
 			continue
 		}
-		log.Info("Faucet request valid", "url", msg.URL, "tier", msg.Tier, "user", username, "address", address)
+		log.Info("Faucet request valid", "url", msg.URL, "tier", msg.Tier, "currency", cur.Symbol, "user", username, "address", address)
 
-		// Ensure the user didn't request funds too recently
+		// Ensure the user didn't request funds of this currency too recently
+		key := username
+		if cur.ID != "" {
+			key += "/" + cur.ID
+		}
 		f.lock.Lock()
 		var (
 			fund    bool
 			timeout time.Time
 		)
-		if timeout = f.timeouts[username]; time.Now().After(timeout) {
+		if timeout = f.timeouts[key]; time.Now().After(timeout) {
 			// User wasn't funded recently, create the funding transaction
-			amount := new(big.Int).Mul(big.NewInt(int64(*payoutFlag)), ether)
-			amount = new(big.Int).Mul(amount, new(big.Int).Exp(big.NewInt(5), big.NewInt(int64(msg.Tier)), nil))
-			amount = new(big.Int).Div(amount, new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(msg.Tier)), nil))
-
-			tx := types.NewTransaction(f.nonce+uint64(len(f.reqs)), address, amount, 21000, f.price, nil)
+			tx, err := cur.transaction(f.nonce+uint64(len(f.reqs)), address, msg.Tier, f.price)
+			if err != nil {
+				f.lock.Unlock()
+				if err = sendError(conn, err); err != nil {
+					log.Warn("Failed to send transaction creation error to client", "err", err)
+					return
+				}
+				continue
+			}
 			signed, err := f.keystore.SignTx(f.account, tx, f.config.ChainID)
 			if err != nil {
 				f.lock.Unlock()
@@ -501,15 +523,16 @@ func (f *faucet) apiHandler(conn *websocket.Conn) {
 				continue
 			}
 			f.reqs = append(f.reqs, &request{
-				Avatar:  avatar,
-				Account: address,
-				Time:    time.Now(),
-				Tx:      signed,
+				Avatar:   avatar,
+				Account:  address,
+				Currency: cur.Symbol,
+				Time:     time.Now(),
+				Tx:       signed,
 			})
 			timeout := time.Duration(*minutesFlag*int(math.Pow(3, float64(msg.Tier)))) * time.Minute
 			grace := timeout / 288 // 24h timeout => 5m grace
 
-			f.timeouts[username] = time.Now().Add(timeout - grace)
+			f.timeouts[key] = time.Now().Add(timeout - grace)
 			fund = true
 		}
 		f.lock.Unlock()
@@ -522,7 +545,7 @@ func (f *faucet) apiHandler(conn *websocket.Conn) {
 			}
 			continue
 		}
-		if err = sendSuccess(conn, fmt.Sprintf("Funding request accepted for %s into %s", username, address.Hex())); err != nil {
+		if err = sendSuccess(conn, fmt.Sprintf("Funding request accepted for %s into %s (%s)", username, address.Hex(), cur.Amounts[msg.Tier])); err != nil {
 			log.Warn("Failed to send funding success to client", "err", err)
 			return
 		}
@@ -547,14 +570,20 @@ func (f *faucet) refresh(head *types.Header) error {
 			return err
 		}
 	}
-	// Retrieve the balance, nonce and gas price from the current head
+	// Retrieve the balances, nonce and gas price from the current head
 	var (
-		balance *big.Int
-		nonce   uint64
-		price   *big.Int
+		balances = make([]*big.Int, len(f.currencies))
+		decimals = make([]int64, len(f.currencies))
+		nonce    uint64
+		price    *big.Int
 	)
-	if balance, err = f.client.BalanceAt(ctx, f.account.Address, head.Number); err != nil {
-		return err
+	for i, currency := range f.currencies {
+		if balances[i], err = currency.balanceAt(ctx, f.client, f.account.Address, head.Number); err != nil {
+			return err
+		}
+		if decimals[i], err = currency.decimalsAt(ctx, f.client, head.Number); err != nil {
+			return err
+		}
 	}
 	if nonce, err = f.client.NonceAt(ctx, f.account.Address, head.Number); err != nil {
 		return err
@@ -564,8 +593,11 @@ func (f *faucet) refresh(head *types.Header) error {
 	}
 	// Everything succeeded, update the cached stats and eject old requests
 	f.lock.Lock()
-	f.head, f.balance = head, balance
+	f.head, f.balances = head, balances
 	f.price, f.nonce = price, nonce
+	for i, currency := range f.currencies {
+		currency.decimals = decimals[i]
+	}
 	for len(f.reqs) > 0 && f.reqs[0].Tx.Nonce() < f.nonce {
 		f.reqs = f.reqs[1:]
 	}
@@ -602,18 +634,11 @@ func (f *faucet) loop() {
 			}
 			// Faucet state retrieved, update locally and send to clients
 			f.lock.RLock()
-			log.Info("Updated faucet state", "number", head.Number, "hash", head.Hash(), "age", common.PrettyAge(timestamp), "balance", f.balance, "nonce", f.nonce, "price", f.price)
-
-			balance := new(big.Int).Div(f.balance, ether)
-			peers := f.stack.Server().PeerCount()
+			log.Info("Updated faucet state", "number", head.Number, "hash", head.Hash(), "age", common.PrettyAge(timestamp), "balance", f.balances[0], "nonce", f.nonce, "price", f.price)
 
+			stats := f.stats()
 			for _, conn := range f.conns {
-				if err := send(conn, map[string]interface{}{
-					"funds":    balance,
-					"funded":   f.nonce,
-					"peers":    peers,
-					"requests": f.reqs,
-				}, time.Second); err != nil {
+				if err := send(conn, stats, time.Second); err != nil {
 					log.Warn("Failed to send stats to client", "err", err)
 					conn.Close()
 					continue
@@ -650,6 +675,23 @@ func (f *faucet) loop() {
 	}
 }
 
+// stats assembles the faucet statistics to report to the clients. The native
+// funds are reported as "funds", the token ones by currency identifier as "tokens".
+// The caller must hold the faucet lock.
+func (f *faucet) stats() map[string]interface{} {
+	tokens := make(map[string]*big.Int)
+	for i, currency := range f.currencies[1:] {
+		tokens[currency.ID] = new(big.Int).Div(f.balances[i+1], currency.unit())
+	}
+	return map[string]interface{}{
+		"funds":    new(big.Int).Div(f.balances[0], f.currencies[0].unit()),
+		"tokens":   tokens,
+		"funded":   f.nonce,
+		"peers":    f.stack.Server().PeerCount(),
+		"requests": f.reqs,
+	}
+}
+
 // sends transmits a data packet to the remote end of the websocket, but also
 // setting a write deadline to prevent waiting forever on the node.
 func send(conn *websocket.Conn, value interface{}, timeout time.Duration) error {
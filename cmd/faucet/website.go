@@ -1,6 +1,6 @@
 // Code generated by go-bindata. DO NOT EDIT.
 // sources:
-// faucet.html (11.27kB)
+// faucet.html (14.01kB)
 
 package main
 
@@ -69,7 +69,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _faucetHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcc\x5a\x6d\x93\xdb\x36\x92\xfe\x3c\xfe\x15\x1d\x9e\xbd\x92\xce\x43\x52\x33\x63\x7b\x7d\x12\xa9\x94\xd7\x9b\xdd\xf3\xd5\x5d\x92\x4a\x9c\xba\xdb\xca\xa6\xae\x40\xb2\x25\xc2\x03\x02\x0c\x00\x4a\xa3\x4c\xe9\xbf\x5f\x35\x40\x52\xd4\xcb\x4c\xec\xb5\xaf\x6a\xfd\x61\x4c\x02\x8d\x46\xa3\xfb\x69\xf4\x0b\x95\x7c\xf5\xe7\xef\xde\xbe\xff\xdb\xf7\xdf\x40\x69\x2b\xb1\x78\x92\xd0\x7f\x20\x98\x5c\xa5\x01\xca\x60\xf1\xe4\x22\x29\x91\x15\x8b\x27\x17\x17\x49\x85\x96\x41\x5e\x32\x6d\xd0\xa6\x41\x63\x97\xe1\xeb\x60\x3f\x51\x5a\x5b\x87\xf8\x6b\xc3\xd7\x69\xf0\x3f\xe1\x4f\x6f\xc2\xb7\xaa\xaa\x99\xe5\x99\xc0\x00\x72\x25\x2d\x4a\x9b\x06\xef\xbe\x49\xb1\x58\xe1\x60\x9d\x64\x15\xa6\xc1\x9a\xe3\xa6\x56\xda\x0e\x48\x37\xbc\xb0\x65\x5a\xe0\x9a\xe7\x18\xba\x97\x4b\xe0\x92\x5b\xce\x44\x68\x72\x26\x30\xbd\x0a\x16\x4f\x88\x8f\xe5\x56\xe0\xe2\xfe\x3e\xfa\x16\xed\x46\xe9\xdb\xdd\x6e\x06\x6f\x1a\x5b\xa2\xb4\x3c\x67\x16\x0b\xf8\x0b\x6b\x72\xb4\x49\xec\x29\xdd\x22\xc1\xe5\x2d\x94\x1a\x97\x69\x40\xa2\x9b\x59\x1c\xe7\x85\xfc\x60\xa2\x5c\xa8\xa6\x58\x0a\xa6\x31\xca\x55\x15\xb3\x0f\xec\x2e\x16\x3c\x33\xb1\xdd\x70\x6b\x51\x87\x99\x52\xd6\x58\xcd\xea\xf8\x26\xba\x89\xfe\x18\xe7\xc6\xc4\xfd\x58\x54\x71\x19\xe5\xc6\x04\xa0\x51\xa4\x81\xb1\x5b\x81\xa6\x44\xb4\x01\xc4\x8b\x7f\x6c\xdf\xa5\x92\x36\x64\x1b\x34\xaa\xc2\xf8\x45\xf4\xc7\x68\xea\xb6\x1c\x0e\x3f\xbe\x2b\x6d\x6b\x72\xcd\x6b\x0b\x46\xe7\x1f\xbd\xef\x87\x5f\x1b\xd4\xdb\xf8\x26\xba\x8a\xae\xda\x17\xb7\xcf\x07\x13\x2c\x92\xd8\x33\x5c\x7c\x16\xef\x50\x2a\xbb\x8d\xaf\xa3\x17\xd1\x55\x5c\xb3\xfc\x96\xad\xb0\xe8\x76\xa2\xa9\xa8\x1b\xfc\x62\xfb\x3e\x64\xc3\x0f\xc7\x26\xfc\x12\x9b\x55\xaa\x42\x69\xa3\x0f\x26\xbe\x8e\xae\x5e\x47\xd3\x6e\xe0\x94\xbf\xdb\x80\x8c\x46\x5b\x5d\x44\x6b\xd4\x84\x5c\x11\xe6\x28\x2d\x6a\xb8\xa7\xd1\x8b\x8a\xcb\xb0\x44\xbe\x2a\xed\x0c\xae\xa6\xd3\x67\xf3\x73\xa3\xeb\xd2\x0f\x17\xdc\xd4\x82\x6d\x67\xb0\x14\x78\xe7\x87\x98\xe0\x2b\x19\x72\x8b\x95\x99\x81\xe7\xec\x26\x76\x6e\xcf\x5a\xab\x95\x46\x63\xda\xcd\x6a\x65\xb8\xe5\x4a\xce\x08\x51\xcc\xf2\x35\x9e\xa3\x35\x35\x93\x27\x0b\x58\x66\x94\x68\x2c\x1e\x09\x92\x09\x95\xdf\xfa\x31\xe7\xcd\xc3\x43\xe4\x4a\x28\x3d\x83\x4d\xc9\xdb\x65\xe0\x36\x82\x5a\x63\xcb\x1e\x6a\x56\x14\x5c\xae\x66\xf0\xaa\x6e\xcf\x03\x15\xd3\x2b\x2e\x67\x30\xdd\x2f\x49\xe2\x4e\x8d\x49\xec\x2f\xae\x27\x17\x49\xa6\x8a\xad\xb3\x61\xc1\xd7\x90\x0b\x66\x4c\x1a\x1c\xa9\xd8\x5d\x48\x07\x04\x74\x0f\x31\x2e\xbb\xa9\x83\x39\xad\x36\x01\xb8\x8d\xd2\xc0\x0b\x11\x66\xca\x5a\x55\xcd\xe0\x8a\xc4\x6b\x97\x1c\xf1\x13\xa1\x58\x85\x57\xd7\xdd\xe4\x45\x52\x5e\x75\x4c\x2c\xde\xd9\xd0\xd9\xa7\xb7\x4c\xb0\x48\x78\xb7\x76\xc9\x60\xc9\xc2\x8c\xd9\x32\x00\xa6\x39\x0b\x4b\x5e\x14\x28\xd3\xc0\xea\x06\x09\x47\x7c\x01\xc3\xeb\xef\x81\xdb\xaf\xbc\xea\xe4\x8a\x0b\xbe\x6e\x8f\x35\x78\x3c\x3a\xe1\xc3\x87\x78\x0d\xed\x83\x5a\x2e\x0d\xda\x70\x70\xa6\x01\x31\x97\x75\x63\xc3\x95\x56\x4d\xdd\xcf\x5f\x24\x6e\x14\x78\x91\x06\x8d\x16\x41\x7b\xfd\xbb\x47\xbb\xad\x5b\x55\x04\xfd\xc1\x95\xae\x42\xb2\x84\x56\x22\x80\x5a\xb0\x1c\x4b\x25\x0a\xd4\x69\xf0\xa3\xca\x39\x13\x20\xfd\x99\xe1\xa7\x1f\xfe\x13\x5a\x93\x71\xb9\x82\xad\x6a\x34\x7c\x63\x4b\xd4\xd8\x54\xc0\x8a\x82\xe0\x1a\x45\xd1\x40\x10\x87\xdd\x53\x51\xc3\xcc\xca\x3d\xd5\x45\x92\x35\xd6\xaa\x9e\x30\xb3\x12\x32\x2b\xc3\x02\x97\xac\x11\x16\x0a\xad\xea\x42\x6d\x64\x68\xd5\x6a\x45\x91\xce\x1f\xc2\x2f\x0a\xa0\x60\x96\xb5\x53\x69\xd0\xd1\x76\x36\x64\xa6\x56\x75\x53\xb7\x56\xf4\x83\x78\x57\x33\x59\x60\x41\x36\x17\x06\x83\xc5\x5f\xf9\x1a\xa1\x42\x7f\x96\x8b\x63\x48\xe4\x4c\xa3\x0d\x87\x4c\x4f\x80\x91\xc4\x5e\x18\x7f\x24\x68\xff\x25\x8d\xe8\x38\xf5\x47\xa8\x50\x36\x70\xf0\x16\x6a\xba\x57\x82\xc5\xfd\xbd\x66\x72\x85\xf0\x94\x17\x77\x97\xf0\x94\x55\xaa\x91\x16\x66\x29\x44\x6f\xdc\xa3\xd9\xed\x0e\xb8\x03\x24\x82\x2f\x12\xf6\x18\xbc\x41\xc9\x5c\xf0\xfc\x36\x0d\x2c\x47\x9d\xde\xdf\x13\xf3\xdd\x6e\x0e\xf7\xf7\x7c\x09\x4f\xa3\x1f\x30\x67\xb5\xcd\x4b\xb6\xdb\xad\x74\xf7\x1c\xe1\x1d\xe6\x8d\xc5\xf1\xe4\xfe\x1e\x85\xc1\xdd\xce\x34\x59\xc5\xed\xb8\x5b\x4e\xe3\xb2\xd8\xed\x48\xe6\x56\xce\xdd\x0e\x62\x62\x2a\x0b\xbc\x83\xa7\xd1\xf7\xa8\xb9\x2a\x0c\x78\xfa\x24\x66\x8b\x24\x16\x7c\xd1\xae\x3b\x54\x52\xdc\x88\x3d\x5e\x62\x02\x4c\x8f\x73\xe7\x36\x4e\xd4\xa1\xa4\x67\xbc\x60\x15\xf6\xd2\xb7\x78\x30\xdc\xe2\x2d\x6e\xd3\xe0\xfe\x7e\xb8\xb6\x9d\xcd\x99\x10\x19\x23\xbd\xf8\xa3\xf5\x8b\x7e\x43\xc2\xe9\x9a\x1b\x97\x52\x2d\x3a\x09\xf6\x62\x7f\xa4\x5b\x1f\x5d\x5c\x56\xd5\x33\xb8\xb9\x1e\xdc\x5a\xe7\x3c\xfe\xd5\x91\xc7\xdf\x9c\x25\xae\x99\x44\x01\xee\x6f\x68\x2a\x26\xba\xe7\xd6\x5b\x06\xce\x77\xbc\x28\xa4\x3b\xba\x17\xad\xbf\xeb\xa7\x73\x50\x6b\xd4\x4b\xa1\x36\x33\x60\x8d\x55\x73\xa8\xd8\x5d\x1f\xef\x6e\xa6\xd3\xa1\xdc\x94\x0a\xb2\x4c\xa0\xbb\x5d\x34\xfe\xda\xa0\xb1\xa6\xbf\x4b\xfc\x94\xfb\x4b\x57\x4a\x81\xd2\x60\x71\xa4\x0d\xda\x91\x54\xeb\xa8\x06\xa6\xef\x95\x79\x56\xf6\xa5\x52\x7d\x08\x19\x8a\xd1\xb2\x1e\x44\xbb\x60\x91\x58\xbd\xa7\xbb\x48\x6c\xf1\x49\x21\x40\x53\x8a\xf7\x50\x04\xf0\x37\x1a\x9d\xbd\x46\xd4\x3e\xbf\x20\xc8\x82\x7b\x4d\x62\x5b\x7c\xc6\xce\x04\xc2\x8c\x19\xfc\x98\xed\x5d\xa4\xdf\x6f\xef\x5e\x3f\x77\xff\x12\x99\xb6\x19\x32\xfb\x31\x02\x2c\x1b\x59\x0c\xce\xef\xee\xce\xcf\x15\xa0\x91\x7c\x8d\xda\x70\xbb\xfd\x58\x09\xb0\xd8\x8b\xe0\xdf\x0f\x45\x48\x62\xab\x1f\xc7\xda\xf0\xe5\x0b\x39\xf7\xef\xa5\x24\x37\x8b\x7f\x57\x1b\x28\x14\x1a\xb0\x25\x37\x40\xc1\xf5\xeb\x24\x2e\x6f\x7a\x92\x7a\xf1\x9e\x26\x9c\x52\x61\xe9\x52\x0b\xe0\x06\x74\x23\x5d\xe4\x55\x12\x6c\x89\x87\xe9\x48\x1b\xa4\x23\x78\xaf\x28\xa5\x5b\xa3\xb4\x50\x31\xc1\x73\xae\x1a\x03\x2c\xb7\x4a\x1b\x58\x6a\x55\x01\xde\x95\xac\x31\x96\x18\xd1\xf5\xc1\xd6\x8c\x0b\xe7\x4b\xce\xa4\xa0\x34\xb0\x3c\x6f\xaa\x86\x52\x52\xb9\x02\x94\xaa\x59\x95\xad\x2c\x56\x81\x0f\x4c\x42\xc9\x55\x2f\x8f\xa9\x59\x05\xcc\x5a\x96\xdf\x9a\x4b\xe8\x6e\x05\x60\x1a\xc1\x72\x2c\x68\x55\xae\xaa\x4a\x49\xb8\xd1\x05\xd4\x4c\xdb\x2d\x98\xc3\xdc\x82\xe5\xb9\x8b\x72\x11\xbc\x91\x5b\x25\x11\x4a\xb6\x76\x12\xc2\x7b\x5f\x4e\x90\x5c\x7f\x61\x39\x66\x4a\xf5\xd4\x50\xb1\x6d\xb7\x5d\x2b\xfd\x86\xdb\x92\x7b\xf5\xd4\xa8\x2b\x5a\x5a\x80\xe0\x15\xb7\x26\x4a\xe2\x7a\x7f\xa3\xee\x63\xb3\x08\x4b\xa5\xf9\x6f\x94\xd8\x88\xe1\xf5\x69\x8f\x2e\x97\xee\x6e\x74\x56\x17\xb8\xb4\x33\x78\xe1\xef\xc6\x63\x1c\xb7\x15\xd0\x39\x10\x77\x3c\x5d\x65\x49\x01\x67\x06\x37\x3e\x9d\xf5\x89\x44\x61\x07\x12\x14\x47\x50\xf3\x9b\xbe\x7e\x5d\xdf\xf5\x72\xf4\x39\xf1\xb4\x67\x42\x08\x38\x54\xca\x9a\xf7\x6a\xbc\x84\x8a\xdd\x22\x30\x48\xd8\x51\x85\xdc\x0a\xed\xea\x2b\xee\xfa\x03\xb1\xdd\x20\xda\xaf\xc9\x75\xd3\x1f\x3c\x43\x2e\x57\xcf\xae\xa7\x1e\x91\xf4\x40\xec\x9f\x5d\x4f\xb9\xb4\xea\xd9\xf5\x74\x7a\x37\xfd\xc8\x7f\xcf\xae\xa7\x4a\x3e\xbb\x9e\xda\x12\x9f\x5d\x4f\x9f\x5d\xdf\x0c\xb1\xec\x47\xba\xcc\x92\xa8\xd0\xd0\x6e\x1d\xc4\x03\xb0\x4c\xaf\xd0\xa6\xc1\xff\xb2\x4c\x35\x76\x96\x09\x26\x6f\x83\x85\x13\x97\xb2\x0d\x87\x82\xf3\xf9\x29\xd4\xcc\x10\x24\x48\x62\x87\x92\xb6\x17\x62\x60\x6c\x1a\xad\x55\x23\x29\x2a\x02\x9d\xd9\x79\xa8\x1c\x11\xca\x48\x31\x93\x28\xc9\x74\xbc\x78\xab\xea\x6d\xe8\x98\xb8\xe5\x27\x6a\x34\x4d\x5d\x2b\x6d\xa3\xa1\x3a\x19\xd5\x41\x02\x4d\xfc\x7a\xfa\xf2\xf5\xab\x47\xc5\x37\x94\x65\xbb\x33\xf4\x12\xb2\x4c\xad\x11\x7c\x4e\x9f\xa9\x3b\x60\xb2\x80\x25\xd7\x08\x6c\xc3\xb6\x5f\x25\x71\xe1\x2a\xb0\xcf\x47\xed\xb2\xf5\xae\x7f\x2a\xd8\x76\x2e\x7f\x09\x75\x93\x09\x6e\x4a\x60\x20\x71\x03\x89\xb1\x5a\xc9\xd5\xc2\x8d\xe6\x54\x92\xba\x57\xa8\x95\xb1\x8f\x99\x1f\xab\x0c\x8b\xe2\x0c\x00\xbe\x94\xfd\x37\x9b\x4d\xd4\x69\xd2\x19\xbf\x44\x51\xc7\x74\xfd\x35\x92\xdb\x6d\xec\xdd\x48\xc9\xf8\x6b\x5e\xa4\xd7\xaf\xaf\x5f\xbd\xba\x7e\xf1\x6f\xaf\x5f\xbe\xbc\x7e\xfd\xe2\xe5\x43\xc8\xa0\x43\x7d\x26\x30\x7c\x1a\xfd\xad\xa2\xaa\xb5\xcf\xa1\x3d\x5e\xba\xdc\x8d\x22\x74\x41\x35\x88\x0e\xfe\x61\x0c\x35\x92\x12\x91\x90\x89\xb3\x39\xc4\x27\xa0\xc8\xc1\xe8\x11\xc9\x3e\x13\x5a\x1d\x7c\x08\x29\xaa\xb1\x74\xc2\xae\x98\xe7\x4a\xf6\x70\xba\x04\xc3\xab\x5a\x6c\x21\xdf\x5b\xfd\x3c\xae\x1e\x34\xca\xef\xc2\xea\xd0\x6c\x1e\x64\x2e\xfa\x57\xaa\x40\x8a\xfa\xa6\x31\x39\xd6\xae\xcb\x4b\x91\xf4\x4f\xdb\xdf\x98\xb4\x5c\x62\x17\x71\x23\xf8\x4e\x8a\x2d\x34\x06\x61\xa9\x34\x14\x98\x35\xab\x95\x4b\x13\x34\xd4\x9a\xaf\x99\xc5\x2e\xcc\x9a\x16\x15\x3d\x28\x06\x95\x0d\xa5\x3c\x62\x90\x81\xfc\x4d\x35\x90\x33\x09\x56\xb3\xfc\xd6\x7b\x4a\xa3\x35\x79\x4a\x8d\xfe\x34\x7d\xa0\xcf\x50\xa8\x8d\x23\xf1\xe7\x5e\x72\x14\x2e\xea\x1b\x44\x28\xd5\x06\xaa\x26\x77\x0e\x49\x51\xdd\x1d\x62\xc3\xb8\x85\x46\x5a\x2e\xbc\x3e\x6d\xa3\x25\xe5\x08\x78\x10\xa5\x4f\x6a\xbf\x04\xab\xc5\xfb\x12\xcf\xa4\x44\x7d\xd5\x06\x1a\xdf\x7a\x72\xa8\xb5\xb2\x98\x93\x41\x81\xad\x18\x97\x86\x2c\xe2\xf2\x00\xac\x3e\xa2\xaa\xeb\x9f\xda\x87\x7d\x87\xd2\x4d\xc7\x31\xfc\x55\xa8\x8c\x09\x58\x13\xd2\x33\x41\xe9\x9c\x82\x52\xd1\xd1\x07\xda\x32\x96\xd9\xc6\x80\x5a\xba\x51\x2f\x39\xad\x5f\x33\x4d\x16\xc4\xaa\xb6\x90\xb6\xfd\x35\x1a\x33\xa8\xd7\x6d\xd7\x90\x5e\xa9\x72\x3f\x98\xef\xb5\x9e\xc2\xcf\xbf\xcc\x9f\xb4\xa2\xfc\x19\x97\x0e\x12\x84\x6f\x7f\x64\x5b\x32\x0b\xb9\x46\x66\xd1\x40\x2e\x94\x69\xb4\x97\xb0\xd0\xaa\x06\x92\xb2\xe3\xd4\x71\xa6\x89\xda\xed\xd6\x31\x19\x97\xcc\x94\x93\xb6\x3d\xa8\xd1\x59\xa9\x9f\xeb\xc6\x2f\x08\x75\x63\x62\xc0\xd3\xe9\x1c\x78\xd2\xf1\x8d\x04\xca\x95\x2d\xe7\xc0\x9f\x3f\xef\x89\x2f\xf8\x12\xc6\x1d\xc5\xcf\xfc\x97\xc8\xde\x45\xb4\x0b\xa4\x29\x0c\x77\x73\x1b\xb6\x7c\x4c\x2d\x78\x8e\x63\x7e\x09\x57\x93\x79\x37\x9b\x69\x64\xb7\xdd\x5b\x6b\x47\xff\x9f\xfb\xbb\x9b\x1f\x6a\xc6\x29\xff\x40\x37\xbe\xf6\x37\xc0\x60\xc5\x8d\x85\x46\x0b\x68\x7d\xd8\x9b\xa0\x37\x88\xa3\x1b\x6a\xe5\x04\x97\xed\x43\x8b\xa9\xee\x08\x9e\x4d\x64\x50\x16\xe3\xff\xf8\xf1\xbb\x6f\x23\x63\x35\x97\x2b\xbe\xdc\x8e\xef\x1b\x2d\x66\xf0\x74\x1c\xfc\x4b\xa3\x45\x30\xf9\x79\xfa\x4b\xb4\x66\xa2\xc1\x4b\x67\xef\x99\xfb\x7b\xb2\xcb\x25\xb4\x8f\x33\x38\xdc\x70\x37\x99\xcc\xcf\xf7\x49\x06\x6d\x1d\x8d\x06\xed\x98\x08\x7b\xe0\x1f\xeb\x88\x41\x85\xb6\x54\xce\x75\x35\xe6\x4a\x4a\xcc\x2d\x34\xb5\x92\xad\x4a\x40\x28\x63\xf6\x40\xec\x28\xd2\x53\x50\xb4\xf4\xa9\x0b\xd6\xff\x8d\xd9\x8f\x2a\xbf\x45\x3b\x1e\x8f\x37\x5c\x16\x6a\x13\x09\xe5\xaf\xda\x88\x9c\x54\xe5\x4a\x40\x9a\xa6\xd0\x46\xd1\x60\x02\x5f\x43\xb0\x31\x14\x4f\x03\x98\xd1\x23\x3d\x4d\xe0\x39\x1c\x2f\x2f\x29\xde\x3f\x87\x20\x66\x35\x0f\x26\xde\x1d\x3a\xc5\x2b\x59\xa1\x31\x6c\x85\x43\x01\x5d\x65\xd4\x83\x8c\xce\x51\x99\x15\xa4\xe0\x0c\x54\x33\x6d\xd0\x93\x44\x54\x8d\x77\x68\x23\xcc\x3a\xb2\x34\x05\xd9\x08\xb1\x07\xa9\x77\x8a\x79\x07\xbf\x03\xf2\xc8\xc7\x9a\xaf\xd2\x14\xa8\x34\x25\x15\x17\xfb\x95\x64\x7c\x5f\x44\x4f\x22\x8a\x0b\xfb\x15\x93\xf9\x10\xcd\x07\xdc\xb0\xf8\x3d\x76\x58\x1c\xf3\xc3\xe2\x01\x86\xae\x67\xf1\x18\x3f\xdf\xe3\x18\xb0\x73\x03\x0f\x70\x93\x4d\x95\xa1\x7e\x8c\x9d\xef\x59\xb4\xec\x9c\xaa\xdf\x49\x3b\x58\x7b\x09\x57\xaf\x26\x0f\x70\x47\xad\xd5\x83\xcc\xa5\xb2\xdb\xf1\xbd\x60\x5b\xca\x99\x60\x64\x55\xfd\xd6\xb5\x18\x46\x97\x2e\xe2\xce\xa0\xe7\x70\xe9\x9a\xc7\x33\x18\xb9\x37\x9a\xe7\x15\xba\x55\x2f\xa7\xd3\xe9\x25\x74\x5f\x5d\xfe\xc4\xc8\x09\x75\x83\xbb\x07\xe4\x31\x4d\x9e\x53\xdc\xff\x1c\x89\x5a\x1e\xbd\x4c\xed\xfb\x67\x48\xd5\xc7\x86\x03\xb1\xe0\x0f\x7f\x80\x93\xd9\x43\x18\xc7\x31\xfc\x17\xa3\x32\x5c\x08\xd7\x3d\x70\x4d\x83\x9e\xbe\xe2\xc6\xb8\x62\xdc\x40\xa1\x24\xb6\x6b\x3e\xed\xda\x3f\x91\xb1\x25\x83\x05\x4c\x8f\x05\xa4\xeb\x70\x10\x16\xce\x44\x8b\x01\xdf\xc3\x40\x70\xb1\x1b\xee\x77\xb0\x92\x57\x08\x5f\xa5\x10\x04\xc3\xc5\x27\x14\x44\xd0\x33\xbb\x30\x68\xdf\x7b\x5b\x8c\xdb\xe8\x78\x2e\x76\x4d\x2e\xe1\x66\x3a\x9d\x4e\x4e\x84\xd8\xed\xd5\xfb\xa6\xa6\xb4\x09\x98\xdc\xba\x2b\xb1\xd7\xad\x4b\x1c\x29\x05\xa2\x2b\x4d\x40\xae\x84\xf0\x39\x4b\xbb\x94\x14\xdc\x36\x4f\x52\x08\xaf\xe6\x67\xa2\xe8\x40\x93\x83\xa3\x1d\x9b\xe7\x8c\xee\x8f\x4d\x74\xa8\xb3\x23\xe2\xf0\xea\xc0\x28\x07\xf6\x3a\x6f\x98\x8b\x5e\x6e\xbe\xd7\xe8\x91\xb9\xf6\xf6\x3a\xd6\xd9\x40\x7e\xcf\xe7\xf9\xd5\x47\x1e\xa3\x9f\xae\x1b\x53\x8e\x8f\x04\x9d\xcc\x4f\x6d\xf3\xce\xa2\xa6\x2c\x59\x51\xc8\x22\x5b\x50\x29\xa0\xf1\xc4\x24\x2e\x55\xd7\x18\x6a\x94\x05\xea\x2e\xa5\xf0\x99\x3d\x25\x80\x07\x26\xf3\x55\xe5\x10\x4e\x9f\xe8\x30\x2e\x25\x53\x12\x01\x00\x8e\x9c\xc0\x01\xf5\x00\xa9\x44\x8c\x82\xd5\x06\x0b\x48\xc1\x7f\x04\x1f\x4f\xa2\x46\xf2\xbb\xf1\x24\x6c\xdf\x8f\x79\x74\xf3\xf3\xbe\x4c\xec\xc4\x7e\x9e\x42\x90\x58\x0d\xbc\x48\x47\x01\x3c\x3f\xe7\x82\x14\x75\x47\x8b\xbd\x04\xc3\xa5\x00\x89\x2d\x16\xae\x0f\xea\xeb\xb5\xbf\x07\x19\xcb\x6f\x57\xae\x10\x9a\x51\xaa\x35\x3e\x61\xcb\xd6\xcc\x32\xed\xb8\x4e\xe6\xb0\x27\x6f\x0b\xc5\x9c\x8c\x33\x07\x5f\x91\xba\x76\x2b\xf4\x9f\x28\xdc\x5b\xa6\x74\x81\x3a\xd4\xac\xe0\x8d\x99\xc1\x8b\xfa\x6e\xfe\xf7\xee\x13\x8e\x6b\x0a\x3f\x2a\x6a\xad\x71\x71\x22\x51\xdb\x65\x7c\x0e\x41\x12\x13\xc1\xef\xb1\xe9\x0f\x3b\xfc\xf8\x0e\x67\x5a\xdf\xd0\x7f\x1a\x6f\xc7\x2b\x5e\x14\x02\x49\xe0\x3d\x7b\x72\x46\xb2\xff\xd0\xa5\x0e\xb7\x84\xb6\xe7\xbd\x5f\xb3\x03\x14\x06\x1f\x59\xd0\xb7\xcf\x47\x04\x80\x90\x8e\xcc\x9d\xce\xdb\x62\xdb\x0d\xeb\x91\xd3\x45\xfb\x53\x8a\xa2\xd1\x2e\xd7\x1a\x87\x2d\xc0\x2e\x61\x64\x28\xf7\x2b\xcc\x68\x12\x95\x4d\xc5\x24\xff\x0d\xc7\x14\x97\x26\x5e\x57\xae\x1f\x1f\x9c\x5e\xc9\x27\xc2\xec\x1b\xe5\xa3\x2e\xc6\x8d\x5a\x25\x8e\x3a\xeb\xbe\xd8\xd7\xf6\x33\x98\xce\x47\x9f\xa8\xa1\xf3\xbb\x84\x19\xd3\x30\x7c\x09\xbb\xe0\x0b\x5a\xd1\xee\xdd\x5c\xc6\xf4\xc8\x77\x32\x5c\x7e\x2e\xd5\x26\x1d\xdd\x4c\x7b\x21\xbd\xa1\x9d\x9d\x47\x2d\xd6\x4e\x8c\x41\x52\x76\xae\xb9\x80\x9b\xe9\x97\x90\xd6\x77\x43\x8e\x4e\x60\x35\xaf\xb1\x00\x96\x5b\xbe\xc6\xff\x87\x83\x7c\x01\x25\x7f\xb2\x88\x84\xc3\x4e\x79\x0e\xa6\x07\xf2\xd2\x6c\xaf\xdb\x7f\x25\x7f\x83\xd8\x69\xf8\x39\x04\x67\x0f\xf2\x20\x12\x8f\x08\x8f\x5c\xfb\x61\xbf\x77\x1f\x98\x82\xe3\x98\x42\xd9\x6e\xff\x71\x74\x12\x95\xb6\x12\xe3\x20\xb1\xee\x47\x32\x24\x73\xcf\xc1\x31\xf0\xc3\x87\x29\xdd\xee\xb0\x90\xa1\xfa\x1d\x8f\xea\x2c\x18\x24\x27\x7d\x2d\xd6\x65\x22\xb0\xdb\xff\x96\x28\x8e\xe1\x47\xcb\xb4\x05\x06\x3f\xbd\x83\xa6\x2e\x98\xf5\x9f\x72\x28\x3e\xfa\x4f\x25\xdd\x8f\x8d\x32\xa6\x0d\x2c\x95\xde\x30\x5d\xb4\xfd\x19\x5b\xe2\xd6\x7d\xca\xe9\x52\x3f\x83\xf6\x1d\xdd\x62\x6b\x26\xc6\x27\x75\xdf\xd3\xf1\x28\x1a\x9a\x7c\x34\x89\x90\xe5\xe5\x29\xa1\x8b\x58\xfd\xbe\x29\x7c\xeb\x4a\x80\xf1\xd3\xb1\x2d\xb9\x99\x44\xcc\x5a\x3d\x1e\x1d\x80\x61\x34\x21\xbb\x5e\x0d\x4a\xb2\x7e\x79\x72\xe0\x56\x8f\xf1\xd8\x27\xd3\x7d\x22\xd0\x91\xe7\xc6\x8c\x3d\xae\x46\x97\x03\xde\x87\xb0\x1a\x3d\x1b\xf5\x86\xda\xbb\xf7\xfe\x1c\xe9\x59\x49\x0e\x58\x8f\xc8\xcb\x46\x27\xdb\xb3\xa2\x78\x4b\xfe\x33\x0e\xce\x78\xfa\x31\x3a\x26\xbd\xb2\xfd\x7d\xfd\xa8\x96\xfd\xcf\x32\x1e\x50\x31\x2f\x46\x93\xc8\x34\x99\xef\x4d\x8c\x5f\xf6\x05\x58\x47\xe6\xc0\x7b\x1c\x0a\x4e\x12\x0a\xda\xe2\x30\xa9\x08\x8f\x92\x90\x47\xa2\x46\xbb\xa5\x3f\xd5\xee\x92\x14\x3e\x9d\xf4\xad\xad\x6f\x0c\x25\x57\xbe\xf5\xbf\xc1\xcc\xb8\x4e\x02\xb4\x78\x77\xdd\x1c\xdf\xb5\x79\xf3\xfd\xbb\x41\xe7\xa6\xf7\x88\xb1\xe3\xde\xff\x0e\xf0\x5c\x9f\xe4\xec\x0f\x0f\x37\x9b\x4d\xb4\x52\x6a\x25\xfc\x4f\x0e\xfb\x46\x4a\xcc\x6a\x1e\x7d\x30\x01\x30\xb3\x95\x39\x14\xb8\x44\xbd\x18\xb0\x6f\xbb\x2b\x49\xec\x7f\x12\x97\xc4\xfe\x57\xbf\xff\x17\x00\x00\xff\xff\x31\x9f\x54\x5e\x06\x2c\x00\x00")
+var _faucetHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd5\x5b\x7d\x73\xdb\x36\x9a\xff\xdb\xfb\x29\x50\x5d\xba\x92\x2e\x22\x25\xc7\x49\xd6\x27\x4b\xea\x64\xd3\xec\x5e\x76\x76\xdb\x4e\x93\xce\x5d\xa7\xdb\xb9\x81\x48\x48\x62\x4c\x11\x5c\x02\xb4\xac\x7a\xf4\xdd\xef\xf7\x00\x20\xf8\x22\xd9\x71\x9b\xec\xdc\x5e\x66\x62\x93\x78\x79\xf0\xbc\xbf\x81\x9e\x7d\xf1\xf5\xb7\xaf\xdf\xff\xf8\xdd\x1b\xb6\xd1\xdb\x74\xf1\xbb\x19\xfd\x62\x29\xcf\xd6\xf3\x9e\xc8\x7a\x8b\xdf\x9d\xcd\x36\x82\xc7\xf8\x7d\x36\xdb\x0a\xcd\x59\xb4\xe1\x85\x12\x7a\xde\x2b\xf5\x2a\xb8\xec\xd5\x13\x1b\xad\xf3\x40\xfc\xa3\x4c\x6e\xe6\xbd\xff\x0e\x7e\x78\x15\xbc\x96\xdb\x9c\xeb\x64\x99\x8a\x1e\x8b\x64\xa6\x45\x86\x5d\x6f\xdf\xcc\x45\xbc\x16\x8d\x7d\x19\xdf\x8a\x79\xef\x26\x11\xbb\x5c\x16\xba\xb1\x74\x97\xc4\x7a\x33\x8f\xc5\x4d\x12\x89\xc0\xbc\x8c\x58\x92\x25\x3a\xe1\x69\xa0\x22\x9e\x8a\xf9\x39\xc0\x10\x1c\x9d\xe8\x54\x2c\xee\xee\xc2\x6f\x84\xde\xc9\xe2\xfa\x70\x98\xb2\x57\xa5\xde\x00\x4c\x12\x71\x2d\x62\xf6\x27\x5e\x46\x42\xcf\xc6\x76\xa5\xd9\x94\x26\xd9\x35\xdb\x14\x62\x35\xef\x11\xea\x6a\x3a\x1e\x47\x71\xf6\x41\x85\x51\x2a\xcb\x78\x95\xf2\x42\x84\x91\xdc\x8e\xf9\x07\x7e\x3b\x4e\x93\xa5\x1a\xeb\x5d\xa2\xb5\x28\x82\xa5\x94\x5a\xe9\x82\xe7\xe3\x8b\xf0\x22\xfc\xc3\x38\x52\x6a\xec\xc7\xc2\x6d\x92\x85\x18\xe9\xb1\x42\xa4\xf3\x9e\xd2\xfb\x54\xa8\x8d\x10\xa0\x6c\xbc\xf8\x6d\xe7\xae\xc0\x91\x80\xef\x84\x92\x5b\x31\x7e\x1e\xfe\x21\x9c\x98\x23\x9b\xc3\x0f\x9f\x4a\xc7\xaa\xa8\x48\x72\xcd\x54\x11\x3d\xfa\xdc\x0f\xff\x28\x45\xb1\x07\x91\xe7\xe1\xb9\x7b\x31\xe7\x7c\x50\xbd\xc5\x6c\x6c\x01\x2e\x3e\x09\x76\x90\x49\xbd\x1f\x3f\x0b\x9f\xe3\x80\x9c\x47\xd7\x7c\x2d\xe2\xea\x24\x9a\x0a\xab\xc1\xcf\x76\xee\x7d\x32\xfc\xd0\x15\xe1\xe7\x38\x6c\x0b\xc9\x64\x1a\xa0\x40\xe2\xf9\x25\xc4\xe6\x06\x8e\xe1\x9b\x03\x48\x68\x74\xd4\x59\x78\x23\x0a\xd2\xdc\x34\x88\xb0\x5c\x14\xec\x8e\x46\xcf\xb0\x2d\xd8\x88\x64\xbd\xd1\x53\x76\x3e\x99\x7c\x79\x75\x6a\xf4\x66\x63\x87\xe3\x44\xe5\x29\xdf\x4f\xd9\x2a\x15\xb7\x76\x88\xa7\xc9\x3a\x0b\x12\x2d\xb6\x6a\xca\x2c\x64\x33\x71\x30\x67\xe6\x85\x5c\x17\x42\x29\x77\x58\x2e\x15\x4c\x4d\x66\x53\xd2\x28\x98\xf1\x8d\x38\xb5\x56\xe5\x3c\x3b\xda\xc0\x97\x4a\xa6\xa5\x16\x1d\x44\x96\xa9\x8c\xae\xed\x98\xb1\xe6\x26\x11\x91\x4c\x65\x31\x65\xbb\x4d\xe2\xb6\x31\x73\x10\xcb\x0b\xe1\xc0\xb3\x9c\xc7\x71\x92\xad\xa7\xec\x65\xee\xe8\x61\x5b\x5e\xac\x13\x1c\x38\xa9\xb7\x80\xa5\x8e\x8d\xb3\xb1\x75\x5c\x78\x5a\xca\x78\x6f\x64\x18\x27\x37\x2c\x4a\xb9\x52\x70\x38\x6d\x16\x1b\x87\xd4\x5a\x40\x7e\x88\x27\x59\x35\xd5\x9a\x2b\xe4\xae\xc7\xcc\x41\xf3\x9e\x45\x02\x0a\xa5\xb5\xdc\x82\x26\x42\xcf\x6d\xe9\xc0\x4b\x83\x74\x1d\x9c\x3f\xab\x26\xe1\x59\xcf\x2b\x20\x5a\xdc\xc2\x96\x49\x3e\x5e\x32\x50\x8f\xa4\xda\xbb\xe2\x6c\xc5\x83\x25\xd7\x9b\x1e\xe3\x45\xc2\x83\x4d\x12\xc7\x22\xc3\xbe\xa2\x14\xa4\x47\xc9\x82\x35\xdd\xdf\x3d\xde\x6f\x73\x5e\xe1\x35\x06\x62\x8e\xac\xc6\x63\x87\xc2\xfb\x89\xb8\x64\xee\x41\xae\x56\x08\x06\x41\x83\xa6\xc6\xe2\x24\xcb\x4b\x1d\xac\x0b\x59\xe6\x7e\xfe\x6c\x66\x46\x59\x12\x23\x82\x14\x69\xcf\xb9\x7f\xf3\xa8\xf7\xb9\x63\x45\xcf\x13\x2e\x8b\x6d\x40\x92\x28\x24\x16\x40\x8f\x22\xb1\x91\x69\x2c\x8a\x79\xef\xee\x2e\x59\x31\x59\xb0\x90\x68\x55\xa1\x33\xec\xea\x75\x85\x95\x30\x68\xf0\xe2\x9d\x8c\x10\x31\x58\x66\x79\xc3\x7e\xf8\xfe\xaf\xcc\x89\x16\xda\xc4\xf6\xb2\x2c\xd8\x1b\x30\xab\x10\xe5\x96\x41\xc5\x48\xad\xc3\x30\xbc\xbb\x13\xa9\x12\x87\xc3\x8f\xf7\xcf\x67\xf1\xe1\xd0\x5b\x74\xd0\x50\x10\x21\x38\xee\xde\x78\x9a\xca\x5d\x9a\x28\x7d\x38\x9c\xa0\x9f\xd6\x72\x5d\x16\xa2\xe2\x42\x63\xe0\xd7\xf1\xe2\x5d\xb5\x91\xc9\x15\x03\xb2\xb0\x59\x38\x51\xa5\xd9\x16\xd8\xc2\x7f\xb2\x81\xcc\xc9\x36\x79\x3a\x04\xee\x84\xb4\xc1\xde\xe3\x64\xcc\xf8\x58\x6a\xc1\x52\x67\xb5\xe4\x60\x47\x25\x74\xdc\x2f\xc4\x24\xc3\xff\x20\x16\x2b\x5e\xa6\x9a\xc5\x85\xcc\x63\xb9\xcb\x02\x2d\xd7\xeb\xd4\xd3\x60\x37\xf5\x58\xcc\x35\x77\x53\xf3\x5e\xb5\xb6\x52\x67\xae\x72\x99\x97\xb9\x53\x68\x3b\x28\x6e\x81\x55\x2c\x62\x52\x7f\xc8\xa2\xb7\xf8\x33\x9c\x10\x28\x62\x86\xe5\x6b\xcd\x06\xa9\xc8\x58\xf8\xba\x2c\x0a\x91\x45\x89\x50\x43\x76\x7e\x38\xac\xca\x2c\x56\x95\xf4\x8c\xe0\x1c\xb1\x67\x5d\x73\x8a\xe0\xb0\x75\xd0\xc4\xe2\xc8\xa8\x66\x63\x8b\xbd\xe5\x01\x73\xff\x66\x65\x5a\x41\xf2\x34\xc3\xaf\x97\xac\xf5\x16\x14\xe4\x93\x89\xd7\x05\xb2\x29\xc1\x9e\x44\xd0\xa4\xe9\xbc\x89\xf0\xe1\xd0\x22\xe5\x49\x97\x96\xd6\xa9\x38\x37\x4d\x8e\xce\x25\x1f\x47\x2e\xea\xee\x8e\xe0\x87\xef\xf6\xdb\xa5\x4c\x0f\x87\x19\x02\x50\x25\x65\x8f\x40\x12\xdf\x8e\xd8\x13\xbe\x95\x65\xa6\x09\x13\xb3\xe3\x95\x79\x55\xa7\x0e\x5b\xcc\xf8\x43\xfe\x89\xc9\x2c\x4a\x93\xe8\x1a\xb3\x09\x94\x10\x18\xe0\x80\xc3\xe1\x8a\x69\x79\x0d\x36\x3a\x8c\xde\x7e\x4d\x43\x86\xce\x27\xe1\xf7\x22\xe2\xb9\x46\x0a\x79\x38\x20\x7e\xb8\xe7\x50\xdc\x8a\x08\xe1\x62\x30\xac\xc4\xa6\xca\xe5\x36\xd1\x83\x0a\xe2\xb0\x61\x6c\x0e\x7d\xb8\xb8\x31\x01\x85\x7e\xdc\x02\xee\x77\xa2\x48\x64\xac\x98\x5d\x3f\x1b\xf3\x45\x9b\x01\xb5\xb6\x7b\xf2\xc6\x65\x5a\xfb\xa4\x31\x19\x80\x77\x61\xc6\x23\x1a\x8c\x9b\x08\x9f\x70\x70\xeb\xc0\x13\xe1\xf4\x1b\x21\x50\x5c\x8b\x3d\x79\xa7\xe6\x5e\x37\x8b\x70\x93\x2e\x39\x71\xcc\x52\xe8\x37\xfd\x22\xc8\xee\x6e\x12\x65\xb2\xe5\x45\x85\x41\xc3\x48\x1f\xe7\xb1\x3b\x31\x49\xcb\x7c\xca\x2e\x9e\x35\x02\xd2\x29\x67\xfe\xb2\xe3\xcc\x2f\x4e\x2e\x06\x83\x44\xca\xcc\xcf\x40\x6d\x41\x88\x7b\x76\xd6\xdf\x70\xf0\xdd\x4d\x01\x85\x5f\x8f\x9a\x0f\xe3\x93\x2b\x26\x11\x84\x57\xf0\x90\x48\x19\x4a\x2d\xaf\x10\xcf\x6f\x7d\x2a\x73\x31\x99\x34\xf1\xa6\x2c\x9f\x83\x39\xc6\x71\x3a\xef\xa6\xbc\x6b\xb4\x53\xe6\x27\x79\x48\x58\xb1\x12\x71\x87\x1b\x74\x22\xb1\xd6\xac\x6a\x88\xde\x33\xf3\x24\xee\x2b\x24\x85\x3e\x05\x68\xa0\xe1\x40\x37\x12\x19\xc0\xd6\x45\xbd\x0e\x0b\xe3\x5f\x15\xdd\x0b\xca\xde\xef\x0b\xee\xd6\x43\x13\xed\xb9\x10\x85\x4d\x1d\x49\x65\x99\x79\x05\x51\xf1\x27\x9c\x4c\x4a\xb8\xe4\x4a\x3c\xe6\x78\x93\xc4\xd5\xc7\x9b\xd7\x4f\x3d\x1f\x3e\xac\xd0\x4b\xc1\xf5\x63\x10\x30\xfe\xbd\x46\xc0\x78\x78\x8b\x40\xe5\xe7\x8e\x1d\xac\x71\x42\xbf\x1d\xbf\xad\xcc\xc4\xfe\xd1\xb8\x05\x30\x7d\x3a\xaf\xc6\x11\x03\xb5\x5f\x36\x88\x1e\xb9\xa5\xdf\x84\x57\x99\x21\x20\x16\x70\x39\x8f\x46\x0e\x46\xe1\xb1\xb2\xef\x6d\xd1\xe1\xad\x78\xd8\x44\x9a\x2f\x9f\xc9\x27\x7d\x2c\x49\xbe\x58\xfc\xa7\xdc\xb1\x58\x0a\x85\xd4\x26\x51\x8c\xd2\xb8\xaf\x90\xcc\x5e\xf8\x25\xf9\xe2\x3d\x4d\x18\x5d\x00\x63\x28\xd9\x65\x78\x2f\xca\xcc\xe4\x78\x48\x59\x28\x27\x6a\x25\xc8\x2e\x1d\x0c\xd9\x7b\x49\x45\xc6\x0d\x78\x0c\xf7\x83\x70\x96\xc8\x52\x31\x1e\x69\x59\x28\xb6\x2a\xe4\x96\x89\xdb\x0d\x2f\x95\x26\x40\xe4\xf5\xf8\x0d\x4f\x52\xe3\x02\x8c\xb4\x29\xf5\xe3\x51\x54\x6e\x4b\x2a\x92\xb0\x46\x64\xb2\x5c\x6f\x1c\x2e\x5a\x32\x1b\x6d\x53\x89\xa9\x0a\x1f\xf0\x1f\xa9\xa4\xd6\x88\x04\x6a\x54\xa5\x6a\x38\x14\xe9\x1b\x22\x69\x4c\xbb\x1e\x9b\xdc\xa2\xde\x84\x76\xb2\x8b\x22\x86\x3b\x2e\xf4\x9e\xa9\x76\xb6\x0b\xd4\x4c\x78\xaf\x22\x2b\xf4\x25\x59\xd1\x19\x75\x4a\x5b\xaf\x20\x6d\x0c\xd9\x1b\xac\xd9\x43\xe3\x01\x50\x29\xcb\x3e\x9f\x56\x46\x1b\x01\x9c\xd9\x52\xc0\x69\x83\x5d\x7b\x9f\x67\x5a\x5e\x00\xcb\x4d\x62\x99\x9d\x8b\x62\x4b\x38\xc7\x2c\x4d\xf0\xa0\xc2\xd9\x38\xaf\xc3\x4a\x9d\x3f\xa5\xc1\x46\x16\xc9\x2f\x94\x90\xa7\xb5\x97\xb5\x56\xdb\xa2\xbd\x91\xad\xc6\xba\xe3\x7e\xab\xe8\x61\x14\x2c\x15\x2b\x84\x8f\xe7\x36\x7a\x74\x4d\xc6\x01\x3b\x65\x2f\x15\x4c\xd3\x56\xa1\x90\x0c\x35\xb5\xb5\x9c\xcd\x04\x63\xdd\x08\x13\x71\x47\xab\xed\xa1\x97\x97\x58\xcf\xba\x05\xe1\xc4\x03\x21\x65\x6b\x73\xec\x26\xe1\xec\xbd\xc5\x69\x84\x8d\xd7\x82\x71\x86\xac\xab\xdd\x1e\x72\x48\x9b\xe6\x42\x62\x9a\x63\x18\x12\x42\x7f\x45\x5e\x62\xfe\xbd\x05\x08\x49\x7d\xf9\x6c\x62\x95\x9f\x1e\x08\x3c\x7e\x63\xbd\xc4\xaf\xc9\xed\xe4\x91\xff\xb0\x58\x66\xf8\x01\x21\xe2\xe7\x97\xcf\x2e\x9a\x66\x63\x47\x2a\xdd\xa1\x55\x38\x19\xbf\x2a\x6b\x42\xce\x0f\xe2\xa9\x3b\xf8\x3f\x7c\x29\x4b\x3d\x5d\xa6\x3c\x43\xb8\x30\xe8\x52\x5a\x66\x54\xe4\x74\xd1\x45\xfa\x46\xfa\x42\x18\x5b\x65\xb3\x8d\x40\xc5\x06\x0a\x0e\x1d\x4a\x4a\x79\x03\x23\x9a\x8d\x33\xc8\xfa\x64\xb1\xc4\x98\x61\x38\x5b\x16\xe3\xc5\x6b\x99\xef\x03\x03\xc4\x6c\x3f\x62\xa3\x2a\x73\xea\x30\x86\x4d\x76\x72\x6a\x02\xa4\x42\x8d\x2f\x27\x2f\x2e\x5f\x3e\x88\xbe\xa2\xd2\xd1\xd0\xe0\x31\xc4\x22\x54\x24\xb6\xa0\x5b\xca\x5b\x86\x72\x85\xad\x12\x98\x31\xdf\xf1\xfd\x17\x50\x99\xb8\xa1\xd1\x2d\x67\xdf\x54\xf0\xda\x9a\x3f\x87\x86\x57\xd0\xfe\xa5\x54\xfc\x4f\x0e\xa9\x11\xcb\xcb\x25\xea\xe1\x0d\xd4\x3c\x13\x3b\x44\x27\x14\xb3\xd9\x7a\x61\x46\x23\xea\xdd\x98\x57\x96\x4b\x6c\x7f\x40\x55\xc4\x76\x29\x40\xd7\xb1\xb2\x7c\x2e\x5d\xd9\xed\x76\x5e\x2e\x46\x51\x36\x22\xcd\xc7\xe4\x6d\x11\x74\xf5\x7e\x6c\x4d\x4e\x66\xe3\xaf\x10\x5a\x9f\x5d\x3e\x7b\xf9\xf2\xd9\xf3\xff\xb8\x7c\xf1\xe2\xd9\xe5\xf3\x17\xf7\x69\x11\x11\xf5\x4f\x53\x22\xdb\x77\xf8\x3c\x2a\x94\x53\xf2\x94\xfe\xcb\x28\xd0\x72\x8f\x08\x2d\x6f\x5c\xb7\x86\xa1\xea\xb5\x7c\xb3\xba\x30\x62\x44\xba\x19\xa9\x9a\x1d\xb3\x48\xc6\x62\x51\xfb\xc5\x2a\x25\xb0\xe0\x0c\xe3\x1f\xef\x0f\x1f\xca\x1e\x10\xc4\x59\xab\x77\xa2\x13\xe0\xa0\xf9\x36\xf7\x69\x16\xe2\x9e\xc1\xa6\xa1\xcd\x44\x40\xa5\xc8\x88\x96\x89\x1e\x31\x11\xae\x43\x63\x28\x16\x75\x04\x4f\x45\xcd\x1a\x23\xd5\x0a\x80\x0b\xac\x50\x74\x25\x91\x7d\x20\x39\x30\xd0\x76\x48\x4a\x84\x3e\xd2\xea\xd3\x76\x43\xba\x45\x40\x7c\xa3\xe9\x5e\x35\x14\xaa\xa3\x88\xec\x7d\x6b\x9f\xb8\xcd\x31\x85\x45\x2b\xca\x4b\xce\x27\x6c\x9b\x64\xa8\xe1\xed\xae\x08\x1c\x91\x59\xba\x47\xa2\xc0\x4a\x54\x62\xd4\x29\x10\xe1\x63\x55\xf9\x54\xd3\xec\x13\xb4\x99\x20\xfd\x5f\xe9\xf2\xdb\xd5\x3d\x82\xd8\x71\xc5\x3c\x9d\x60\x10\x74\x9c\x84\xe0\x14\x55\x42\x01\x38\x32\xd0\xff\x57\xba\x4d\x7a\x6c\x84\xff\x59\x95\x10\x13\xea\x3a\xc9\x6d\x53\x53\x82\xc7\x36\x07\x7d\x84\x32\x7d\x23\x49\x9d\x1a\xf5\x15\x29\x51\x45\x0b\x95\x57\x31\x55\x8b\x45\xef\x37\x2b\x56\x99\x51\xf1\x8b\x2a\xed\x53\xd5\xcb\xe8\xd7\x03\x98\x7d\xa2\xff\xac\x82\x2c\x49\x09\xe1\x88\x28\xac\xee\x06\x10\xc1\x7c\xd0\x25\x65\xdb\xe6\x30\xda\x8f\x0a\xf0\xde\xd0\xf5\xd1\xe0\xdb\xf6\x29\xd6\x69\x99\xd2\x6d\x0b\x3d\xa2\x92\x4d\x95\x2a\x12\xb9\xb9\x34\x26\xd1\xff\x71\xff\x0b\x07\xa6\x28\x40\x5c\xb9\x14\xb2\x6f\xc9\xaf\xc0\xa9\xb0\x15\x3c\x60\x2c\x96\xe5\x7a\x6d\x8a\x94\x02\x41\x22\xb9\xe1\x40\xda\x29\xb0\x7a\x28\x76\x62\x2a\x6d\x94\x8f\x3f\x22\xae\x90\xd3\xd2\x05\x0e\xb1\x6e\xd6\xf4\x10\x34\xea\x18\x4b\x8d\xaf\xd2\x6c\xed\x43\x4b\x2c\xdd\xa8\xa6\x52\x53\xb2\x29\x21\xd8\x86\xca\xa2\x32\x32\x8e\x9e\x6d\xf8\x8d\x21\x62\xc7\x13\xcd\x50\x62\x25\xa9\xe5\x27\x54\x1e\x46\x25\x61\x4d\xcd\xa2\xe8\xa8\xdf\x38\x13\xdb\xc5\xfb\xda\x27\x34\xea\x59\xdf\x29\x04\x56\xaf\xed\x72\x8a\x90\x5a\x44\x24\x50\xc6\xd7\x3c\xc9\x14\x49\xc4\x94\x5d\x00\xf3\xf1\x4e\xa2\x7f\x72\x0f\xf5\x85\xa7\x99\x1e\x8f\xd9\x9f\x53\xb9\x44\x61\x79\x43\x9a\x8e\xa3\x8d\x65\xd2\xf5\x43\x8b\x5b\xf0\x0f\x1a\xe5\xb3\xab\x16\x2d\xe6\xb4\x1f\xbb\x48\x82\x62\x9b\x6b\x36\x77\xd7\x75\x34\xa6\x44\x71\xe3\x2e\x21\xe9\x95\xfa\xc8\xad\x79\xd3\x49\xc6\x48\xaf\xe7\x87\xbc\x20\xe6\xec\xa7\x9f\x1b\x3b\x9d\x73\xb2\xdb\x1d\xce\x5f\x8b\x95\xd1\x1d\x32\x04\xcb\x1b\xbd\x81\x5b\x03\xaa\x31\x22\x2c\x8d\x43\x9f\x37\x8d\xcd\xa4\x53\x95\x6b\x82\x57\xae\xce\xf2\xe8\xba\x13\x2a\x70\x83\xa1\xbb\x9c\x6c\x1e\xff\x37\xae\x37\xe1\x2a\x95\xb2\x18\x7c\x0d\x75\x0c\x33\xb9\xc3\xba\x31\xf5\x08\x27\x43\x7b\x71\xf9\x64\xd0\x0b\x6b\x77\x3a\x0c\xc9\x54\x06\x7e\xc0\x2e\x3a\x7c\x8c\x8a\xa8\x10\x9c\x02\x6e\x94\x4a\x98\x9d\x15\x08\x5d\x0b\x30\x12\x4a\x17\x73\x9a\xc8\x0d\x73\x3d\xee\x1b\xae\x36\x15\xfe\x85\x30\x4a\x79\x44\xd7\x19\x31\x64\x40\x00\x92\xf9\xe4\x8a\x25\xb3\x0a\x6e\x98\x8a\x6c\xad\x37\x18\x7a\xfa\xd4\x2f\x3e\x83\x06\x0f\xaa\x15\x3f\x25\x3f\x87\xfa\x36\xa4\x53\xd8\x7c\xce\x9a\xa7\x99\x03\x1d\x1c\x95\x23\xf1\x17\x83\x64\xc4\xce\x1d\x73\xf0\x6f\x09\xd2\xae\xab\x37\xa7\xb6\xf6\xd7\xc1\xb1\xa6\xc5\x19\xa3\x6b\x2d\xde\xd8\xf6\x3a\x49\x78\x8d\xf8\xca\xca\x22\x65\xce\x65\x59\x8d\xf3\x02\x35\xeb\x9a\x5c\x39\x32\x43\xf7\xe0\x4c\xa8\x22\xc1\x82\x09\x15\x06\x07\x7f\x79\xf7\xed\x37\x21\x9c\x28\x4c\x33\x59\xed\x07\x77\x38\x6d\xca\x20\xe2\x7f\xa3\x4b\xcd\xe1\x4f\x93\x9f\xc3\x1b\x9e\x96\x62\x64\xd4\x7b\x6a\x7e\x8e\xac\x66\x4f\xed\xaf\xc7\x5e\x21\x8e\xea\x90\x69\x0f\xa8\xef\x0b\xdb\xc7\x38\x45\x9a\xd6\x8f\xbe\xe5\xd8\xa1\x6e\xc4\xdc\xe3\x94\xb5\x09\x3d\x0c\x87\x57\xa7\xaf\x40\x1a\x17\x37\xd0\x3a\xa1\x07\xb4\xd0\xfb\x97\xae\x6c\x38\x32\x17\x84\x1d\xe3\x21\xb1\x51\x66\x19\xbc\x14\x2b\x73\x48\xca\xf2\x90\x41\x7d\x55\x6d\xdc\xd5\x8a\x13\x46\xe6\xd6\xcf\x4d\xe5\xf8\x5f\x62\xf9\x0e\xd1\x17\xc7\x0f\x06\xbb\x24\x8b\xe5\x2e\x44\x34\x36\x11\x8d\xbe\x4e\xd0\x32\x92\x29\xf4\x0e\xae\xc3\x96\x74\xbd\x21\xfb\x8a\xf5\x76\x8a\x8a\xbb\x1e\x9b\xd2\x23\x3d\x0d\xd9\x53\xd6\xdd\xbe\xa1\xe2\xf3\x29\xeb\x8d\x79\x9e\xf4\x86\xd6\x0c\x2b\x81\xcb\xac\x4a\xc4\x1a\x08\x9a\xee\xa1\x57\x6e\xa2\x63\xab\xd6\x58\x60\x14\x23\xa7\xcf\xa5\xec\x92\x90\x1a\xed\x95\x96\x93\xad\x98\x65\xc0\x31\x2b\xd3\xb4\x36\x0e\x6b\x8c\x57\x95\xda\xb7\x96\x87\x36\xa4\x7f\x81\x4d\xd4\xbe\x25\x16\xc7\xf5\x4e\xd2\x09\xdb\x1f\x77\x3e\xc5\xef\x18\x5e\x35\xad\xc8\x43\x33\xea\x77\x2f\xb8\xda\xf8\xa9\xd0\x66\xf5\x86\x86\x21\xfb\x23\x83\x1e\x78\x96\xc4\x8d\x83\xed\xda\x9f\x92\xf8\xe7\xe1\x49\x5b\x6e\xd1\x04\x85\xff\x08\x51\x22\xee\x52\x85\x55\xa7\xc9\x32\x97\x22\x0f\xc1\xb3\x97\x28\x0d\x70\x66\xe0\x1e\x68\x59\xb9\x5d\x42\xef\x1e\x00\x67\x2f\x45\x1c\x38\x23\xf0\xb7\x99\x6e\xec\x85\x73\x7b\x39\xbc\x07\xba\x40\xc6\x74\x2f\x70\xfa\x06\x6a\x70\x97\xf2\x3d\xb5\x11\x58\x5f\xcb\xfc\xb5\xb9\x0c\xe8\x8f\x4c\x7a\x35\x65\x1e\xc2\xc8\xdc\xb6\x63\x8d\x79\xeb\x5b\x2f\x60\x76\xbd\x40\xdc\x19\xb1\xea\x8b\x9d\x3f\x72\x72\x41\x48\x50\x0f\xf7\xe0\xa3\xca\x28\xa2\x24\xef\x53\x30\x72\x30\x3c\x4e\xee\xfd\x13\xb0\xf2\x51\xbf\x85\x16\xfb\xfd\xef\xd9\xd1\x6c\xdb\x98\xe0\x89\xfe\xc6\xa9\x9a\xa1\x0b\xcb\x42\xdc\x98\xf6\xbe\x5f\xbf\x4d\x6c\x8b\x1b\xd5\x58\x2c\x33\x71\xa4\xf7\x8f\x09\x7a\x47\x38\xba\x65\x6c\xc1\x26\x5d\x04\xc9\x4b\x37\x82\xe2\x89\x58\xd9\x80\xdb\x0e\x83\x67\x87\xe6\x79\xad\x9d\xe0\x29\x28\x47\x92\xd4\xdc\x7c\xb4\xc2\x67\x51\x67\xd6\x9f\xe9\xf7\x56\x16\x03\x97\x1b\x9c\x8a\xdc\xc3\x11\xdd\xc3\x4e\x86\x47\x48\x1c\x6a\xf6\xbe\xca\x29\x47\x46\x66\xbf\x37\x8e\xd9\xf3\xd6\x54\x09\x94\xef\x92\x63\x4d\xe9\x8e\x39\xb5\x09\xaa\xdb\x4a\x0c\x76\xd7\x17\x73\x16\x9c\x5f\x9d\xc8\x21\x1a\x9c\x6c\x90\xd6\x15\xcf\x09\xde\x77\x45\xd4\xe6\x59\x67\x71\x70\xde\x12\x4a\x4b\x5e\xa7\x05\x73\xe6\xf1\x4e\x6a\x8e\x76\xc4\x55\xcb\xab\xcb\xb3\x06\xfe\x16\xce\xd3\xf3\x47\x92\xe1\xa7\xf3\x52\x6d\x06\x1d\x44\xbb\x4e\x96\x64\xf3\x56\x53\x23\x41\x98\x8b\x76\x23\x0b\xaa\xfb\x50\x7d\x75\x45\x62\xea\xb2\x42\x04\x36\x33\xae\x12\x2a\x5b\xc6\x51\xb6\xdf\x12\x99\x6d\xb4\x36\xd5\xe9\x57\x1a\x8c\x49\x48\xe9\x56\x09\xff\x3a\x46\x60\x14\xb5\xa5\xa9\xb4\x58\xa4\x3c\xa7\x36\x12\x84\x63\x3e\xa0\x1c\x0c\xc3\x32\x4b\x6e\x07\xc3\xc0\xbd\x77\x61\x54\xf3\x2e\x78\x1b\x89\x59\xb4\x9f\x02\xf8\x4c\x53\x48\x9b\xf7\x29\x62\x9d\x4a\x57\x11\xfb\xfb\x8b\x1a\x83\xe6\x56\xc6\x66\x3a\x5e\x98\x1b\x4b\x5b\x9c\xff\xbd\x47\x1f\x74\xac\x4d\xd5\x3b\xa5\x44\x73\x70\x04\x96\xa3\x2a\x05\x11\x04\x75\x78\xc5\xea\xe5\xae\x2b\x10\x91\x70\xae\x98\x6d\x3f\x98\x8b\x51\xe6\xbf\x81\x30\x6f\x4b\x59\x40\x28\x41\xc1\xe3\xa4\x54\x53\xf6\x1c\x63\x7f\xaf\xbe\x11\x31\xd7\xb7\x0f\xa2\x0a\xa7\xb7\x38\xc2\xc8\xde\xf9\x11\x4a\xac\x3b\x67\x4b\xb9\x68\x4f\x93\x28\x4f\xb1\xfb\x63\x67\x78\x4e\x34\xbf\xea\x64\x27\x6e\xb0\x99\xff\xe6\xd2\x8d\x6f\x93\x38\x4e\x05\x51\x53\x83\x27\x4b\x25\xe5\x68\xda\x5b\xfb\x48\xe6\xae\xae\xeb\x3d\x07\x46\xb7\x9b\x0f\x6c\xf0\xb7\xe0\x7d\xd2\x0e\x9b\xaa\x18\x81\xb8\xb6\x8b\x19\x2e\xfa\x86\x51\xee\x1b\xdd\xb8\x2c\x4c\x3a\x38\x08\x9c\xf6\x8d\x10\xc7\x28\x3d\x8d\x55\x7f\x18\x6e\xca\x2d\xcf\x20\xbe\x01\x05\xad\xa1\xe5\x95\xe9\x89\xf5\x8e\xfd\xf5\x11\x32\xf5\x7d\x77\xbf\x0a\x80\x7d\xc7\xc4\x7e\x25\xfa\xe7\x75\x97\x87\x3e\x5c\xe9\xff\x4a\x0e\x9d\x3e\x25\x58\xf2\x82\x35\x5f\x82\x2a\x32\xb3\x42\xd2\xe9\xd5\x1c\xa6\xfa\xb6\xa7\x65\x6a\x0a\x94\xb0\xf3\xfe\xc5\xc4\x23\x69\x05\x6d\xe4\xdc\x77\x8a\x78\x24\x0c\xc2\xb2\xb2\xdb\x05\xa2\xc8\xe7\xc0\xd6\xf6\xc5\x3a\x14\xa0\xf0\xca\x71\x06\x8f\xe8\x1b\xe5\x7f\x02\x21\x9f\x81\xc9\xbf\x1a\x45\xd2\xc3\x8a\x79\x46\x4d\x5b\xf8\xd2\xac\xe7\xed\xbf\x93\xbd\xb1\xb1\xe1\x30\x96\x9e\x24\xe4\x5e\x4d\xec\x2c\xec\x98\xf6\xfd\x76\x6f\xbe\x13\xe9\x75\x03\x0e\xa5\xc2\xfe\xd3\x2c\xd8\x88\xde\xa6\x03\x38\x5b\xf3\xf5\x35\xe1\xec\x21\x18\x00\x76\xb8\x9d\xef\x1d\xda\xb5\x16\xb5\x36\x44\xa7\x14\x64\x8d\xcc\xc5\x97\x8b\x55\x9a\xc2\x0e\xf5\x47\xea\x08\x7f\xef\xe0\x77\x35\x4a\xd0\x1f\xde\xa2\xe2\x44\xf1\x65\xbf\xc8\xa0\xe0\x69\xbf\x51\xa8\xbe\x62\x87\x08\x14\xf5\x7e\x76\xbc\x88\x5d\xa7\x0e\xf3\x7b\xf3\x45\x46\x95\x17\xe2\xd8\xb7\xe4\xc5\x20\xa4\xc1\x51\x69\xfa\x64\xd0\x0f\x9b\x22\x87\x87\x10\x3c\xda\x1c\x2f\x34\xe1\xcc\x9f\x3b\x67\xdf\x98\xfa\x60\xf0\x64\x40\x5f\xb6\x0c\x43\xae\x75\x31\xe8\xb7\x94\xa1\x3f\x24\xb9\x9e\x37\xaa\x46\xbf\x7d\xd6\x32\xab\x87\x60\xd4\x99\xb6\xcf\x12\xaa\xe5\x91\x52\x03\xab\x57\x58\x55\xc3\x6e\xab\x55\xff\xcb\xbe\x17\x54\x6d\xde\x35\x1d\xf3\x93\x98\xb4\x40\xf7\xc9\xca\xfa\x47\xc7\xf3\x38\x7e\x4d\xf6\x33\xe8\x9d\xb0\xf4\xae\x76\x0c\x3d\xb3\xad\xbf\x7e\x90\xcb\xf6\xdb\xd0\x7b\x58\x9c\xc4\xd8\xac\xca\xa5\x6d\xdb\x0c\x5e\xf8\xea\xac\x5a\x66\x94\xb7\x1b\x0a\x8e\xb2\x0d\x3a\xa2\x9d\x71\x04\x9d\x0c\xe5\x81\xa8\xe1\x8e\xb4\x54\x1d\x46\x55\x83\xd0\xe9\xee\xf7\x75\x2a\x56\x77\x17\xa9\xcd\x4c\x37\x42\x3e\xc9\xa6\x9e\xfe\x08\x1a\x9e\x53\x4b\xa2\x10\xa9\xe4\x94\xc7\x21\x0f\x47\xfe\x6d\x3a\xaf\x66\xdf\xa0\x06\xfb\x46\x51\x42\x67\x6f\xe0\x77\x62\xa9\x4c\x0f\x85\x39\x33\x32\xfd\x33\xdb\x27\x7b\xf5\xdd\xdb\x46\xaf\xcc\x1b\xda\xc0\x20\xed\xff\x6e\xe5\x54\x87\xe8\xe4\x1f\xca\xd0\x7d\xfa\x5a\xca\x75\x6a\xff\x44\xc6\xb7\x90\xa8\xc7\x42\x7f\x0a\x83\x0a\x6c\x9f\x45\x0c\x75\x9d\x28\x16\x0d\xf0\xae\xaf\x34\x1b\xdb\x3f\xe1\x98\x8d\xed\x5f\xa9\xfd\x2f\xee\x67\x6c\x5c\xb6\x36\x00\x00")
 
 func faucetHtmlBytes() ([]byte, error) {
 	return bindataRead(
@@ -85,7 +85,7 @@ func faucetHtml() (*asset, error) {
 	}
 
 	info := bindataFileInfo{name: "faucet.html", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
-	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xdb, 0xa2, 0x98, 0x44, 0x4b, 0x50, 0xf8, 0xa1, 0xac, 0x4a, 0x76, 0x2e, 0xcc, 0x3d, 0xcb, 0x81, 0x9e, 0x2a, 0xaa, 0x87, 0xf5, 0x9d, 0x53, 0x4, 0x8a, 0xdd, 0x5a, 0xfe, 0xd3, 0xc3, 0xf, 0x11}}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x7e, 0x78, 0x88, 0x53, 0x8f, 0x97, 0x53, 0x29, 0xb7, 0x3b, 0xe5, 0x18, 0x72, 0xdb, 0x1a, 0x3d, 0x38, 0xc, 0x8c, 0xe3, 0xd2, 0x6c, 0xb8, 0x20, 0x75, 0x59, 0x9, 0x5f, 0x64, 0x93, 0x4c, 0x30}}
 	return a, nil
 }
 
@@ -19,6 +19,7 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -28,6 +29,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/dccs"
 	"github.com/ethereum/go-ethereum/console"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -57,6 +59,44 @@ participating.
 
 It expects the genesis file as argument.`,
 	}
+	genesisCommand = cli.Command{
+		Name:     "genesis",
+		Usage:    "Manage Nexty genesis files",
+		Category: "BLOCKCHAIN COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Action:    utils.MigrateFlags(buildGenesis),
+				Name:      "build",
+				Usage:     "Build a genesis with the system contracts predeployed",
+				ArgsUsage: "<specPath> [<genesisPath>]",
+				Description: `
+The genesis build command assembles a reproducible genesis file from a
+declarative spec, ready to be used with the init command. The NTF token and
+the governance contract, with the initial sealers staked, are placed into the
+allocations, as are the Endurio contracts if CoLoa is scheduled.
+
+The spec is a JSON file such as:
+
+    {
+      "chainId": 66666,
+      "dccs": {"period": 2, "epoch": 30000, "stakeRequire": 500, ...},
+      "owner": "0x...",
+      "sealers": [{"address": "0x...", "stake": 500}],
+      "balances": {"0x...": "1000000000000000000000"},
+      "ntf": {"0x...": 1000},
+      "stable": {"0x...": 1000},
+      "volatile": {"0x...": 1000}
+    }
+
+The dccs section takes the chain configuration fields, with thangLongBlock set
+to 0 and coLoaBlock, if any, after the genesis. The owner receives the NTF
+supply left after the sealer stakes and token allocations, which are counted
+in whole tokens. Native balances are counted in wei.
+
+The genesis is written to the given path, or to the standard output.`,
+			},
+		},
+	}
 	importCommand = cli.Command{
 		Action:    utils.MigrateFlags(importChain),
 		Name:      "import",
@@ -229,6 +269,43 @@ func initGenesis(ctx *cli.Context) error {
 	return nil
 }
 
+// buildGenesis builds a genesis from a declarative spec and writes it out.
+func buildGenesis(ctx *cli.Context) error {
+	specPath := ctx.Args().First()
+	if len(specPath) == 0 {
+		utils.Fatalf("Must supply path to genesis spec JSON file")
+	}
+	file, err := os.Open(specPath)
+	if err != nil {
+		utils.Fatalf("Failed to read genesis spec file: %v", err)
+	}
+	defer file.Close()
+
+	spec := new(dccs.GenesisSpec)
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(spec); err != nil {
+		utils.Fatalf("invalid genesis spec file: %v", err)
+	}
+	genesis, err := spec.Build()
+	if err != nil {
+		utils.Fatalf("Failed to build genesis: %v", err)
+	}
+	out, err := json.MarshalIndent(genesis, "", "  ")
+	if err != nil {
+		utils.Fatalf("Failed to encode genesis: %v", err)
+	}
+	if ctx.NArg() < 2 {
+		fmt.Println(string(out))
+		return nil
+	}
+	if err := ioutil.WriteFile(ctx.Args().Get(1), append(out, '\n'), 0644); err != nil {
+		utils.Fatalf("Failed to write genesis file: %v", err)
+	}
+	log.Info("Successfully wrote genesis file", "path", ctx.Args().Get(1), "hash", genesis.ToBlock(nil).Hash())
+	return nil
+}
+
 func importChain(ctx *cli.Context) error {
 	if len(ctx.Args()) < 1 {
 		utils.Fatalf("This command requires an argument.")
@@ -411,7 +488,7 @@ func copyDb(ctx *cli.Context) error {
 	dl := downloader.New(0, chainDb, syncBloom, new(event.TypeMux), chain, nil, nil)
 
 	// Create a source peer to satisfy downloader requests from
-	db, err := rawdb.NewLevelDBDatabaseWithFreezer(ctx.Args().First(), ctx.GlobalInt(utils.CacheFlag.Name)/2, 256, ctx.Args().Get(1), "")
+	db, err := rawdb.NewPersistentDatabaseWithFreezer("", ctx.Args().First(), ctx.GlobalInt(utils.CacheFlag.Name)/2, 256, ctx.Args().Get(1), "")
 	if err != nil {
 		return err
 	}
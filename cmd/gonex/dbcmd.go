@@ -0,0 +1,414 @@
+// Copyright 2019 The gonex Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/log"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	dbIterateLimitFlag = cli.Uint64Flag{
+		Name:  "limit",
+		Usage: "Maximum number of entries to print (0 = no limit)",
+		Value: 100,
+	}
+	dbCheckFixFlag = cli.BoolFlag{
+		Name:  "fix",
+		Usage: "Delete the dangling block data and reset the invalid chain heads",
+	}
+)
+
+var (
+	dbCommand = cli.Command{
+		Name:     "db",
+		Usage:    "Low level database operations",
+		Category: "DATABASE COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Action:    utils.MigrateFlags(compactFreezer),
+				Name:      "freezer-compact",
+				Usage:     "Drop the ancient chain data beyond a retention",
+				ArgsUsage: "",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.AncientFlag,
+					utils.AncientRetentionFlag,
+					utils.TestnetFlag,
+					utils.RinkebyFlag,
+					utils.GoerliFlag,
+					utils.DccsFlag,
+				},
+				Description: `
+The freezer-compact command rewrites the ancient tables listed by the
+--ancient.retention flag, keeping only the items of the given number of most
+recent blocks. Only the bodies and receipts tables can be compacted. The node
+must be stopped while compacting.
+
+A node started with the same retention drops the old items by itself, but only
+once a whole data file of them has accumulated.`,
+			},
+			{
+				Action:    utils.MigrateFlags(verifyFreezer),
+				Name:      "verify",
+				Usage:     "Verify the ancient chain data against the header chain",
+				ArgsUsage: "",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.AncientFlag,
+					utils.TestnetFlag,
+					utils.RinkebyFlag,
+					utils.GoerliFlag,
+					utils.DccsFlag,
+				},
+				Description: `
+The verify command rechecks the hashes, parent links and total difficulties
+of all the ancient headers, as well as the transaction and receipt roots of
+the ancient bodies and receipts retained, and that the recent chain continues
+the ancient one.`,
+			},
+			{
+				Action:    utils.MigrateFlags(convertDB),
+				Name:      "convert",
+				Usage:     "Convert the chain database to another engine",
+				ArgsUsage: "",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.DBEngineFlag,
+					utils.CacheFlag,
+					utils.TestnetFlag,
+					utils.RinkebyFlag,
+					utils.GoerliFlag,
+					utils.DccsFlag,
+				},
+				Description: `
+The convert command copies the chain database into a new one backed by the
+engine given by the --db.engine flag, which then replaces the old one. The
+ancient chain data is not touched. The node must be stopped while converting.
+
+Should the conversion be interrupted, the old database is kept in use and the
+command can be run again.`,
+			},
+			{
+				Action:    utils.MigrateFlags(dbGet),
+				Name:      "get",
+				Usage:     "Show the value stored under a raw database key",
+				ArgsUsage: "<key>",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.TestnetFlag,
+					utils.RinkebyFlag,
+					utils.GoerliFlag,
+					utils.DccsFlag,
+				},
+				Description: `
+The get command prints the raw value stored under a key of the chain database,
+followed by its decoding according to the database schema. Keys are given in
+hex if prefixed by 0x, and as plain strings otherwise (e.g. LastBlock). The
+ancient chain data is not reachable by raw keys.`,
+			},
+			{
+				Action:    utils.MigrateFlags(dbPut),
+				Name:      "put",
+				Usage:     "Store a value under a raw database key",
+				ArgsUsage: "<key> <value>",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.TestnetFlag,
+					utils.RinkebyFlag,
+					utils.GoerliFlag,
+					utils.DccsFlag,
+				},
+				Description: `
+The put command overwrites the value stored under a key of the chain database.
+Keys and values are given in hex if prefixed by 0x, and as plain strings
+otherwise. The node must be stopped, and nothing checks the value written.`,
+			},
+			{
+				Action:    utils.MigrateFlags(dbDelete),
+				Name:      "delete",
+				Usage:     "Delete a raw database key",
+				ArgsUsage: "<key>",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.TestnetFlag,
+					utils.RinkebyFlag,
+					utils.GoerliFlag,
+					utils.DccsFlag,
+				},
+				Description: `
+The delete command removes a key from the chain database. Keys are given in
+hex if prefixed by 0x, and as plain strings otherwise. The node must be
+stopped.`,
+			},
+			{
+				Action:    utils.MigrateFlags(dbIterate),
+				Name:      "iterate",
+				Usage:     "List the database keys starting with a prefix",
+				ArgsUsage: "[prefix]",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.TestnetFlag,
+					utils.RinkebyFlag,
+					utils.GoerliFlag,
+					utils.DccsFlag,
+					dbIterateLimitFlag,
+				},
+				Description: `
+The iterate command lists the keys of the chain database starting with the
+given prefix, in hex if prefixed by 0x and as a plain string otherwise, along
+with the kind of entry each holds and the size of its value.`,
+			},
+			{
+				Action:    utils.MigrateFlags(dbCheck),
+				Name:      "check",
+				Usage:     "Check the consistency of the chain database",
+				ArgsUsage: "",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.AncientFlag,
+					utils.TestnetFlag,
+					utils.RinkebyFlag,
+					utils.GoerliFlag,
+					utils.DccsFlag,
+					dbCheckFixFlag,
+				},
+				Description: `
+The check command verifies that the canonical hashes, header numbers and
+headers agree with each other, looks for block bodies and receipts without a
+header, and checks that the head header, block and fast block pointers
+reference canonical blocks holding the required data.
+
+With --fix, the dangling bodies and receipts are deleted and the invalid heads
+are reset to the closest valid blocks below them. Inconsistencies within the
+canonical chain itself are only reported. The node must be stopped.`,
+			},
+		},
+	}
+)
+
+func compactFreezer(ctx *cli.Context) error {
+	retention := utils.MakeAncientRetention(ctx)
+	if len(retention) == 0 {
+		utils.Fatalf("No ancient retention given, use --%s", utils.AncientRetentionFlag.Name)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack)
+	defer chaindb.Close()
+
+	start := time.Now()
+	if err := rawdb.CompactFreezer(chaindb, retention); err != nil {
+		utils.Fatalf("Failed to compact ancient tables: %v", err)
+	}
+	log.Info("Compacted ancient tables", "tables", retention, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+func verifyFreezer(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack)
+	defer chaindb.Close()
+
+	start := time.Now()
+	blocks, err := rawdb.VerifyFreezer(chaindb)
+	if err != nil {
+		utils.Fatalf("Ancient chain verification failed: %v", err)
+	}
+	log.Info("Verified ancient chain", "blocks", blocks, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+func convertDB(ctx *cli.Context) error {
+	if !ctx.GlobalIsSet(utils.DBEngineFlag.Name) {
+		utils.Fatalf("No database engine given, use --%s", utils.DBEngineFlag.Name)
+	}
+	engine := utils.MakeDBEngine(ctx)
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	start := time.Now()
+	if err := rawdb.ConvertDatabase(stack.ResolvePath("chaindata"), engine, ctx.GlobalInt(utils.CacheFlag.Name), 256); err != nil {
+		utils.Fatalf("Failed to convert database: %v", err)
+	}
+	log.Info("Converted database", "engine", engine, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// parseDBBytes parses a raw database key or value given on the command line,
+// in hex if prefixed by 0x, or as a plain string otherwise.
+func parseDBBytes(arg string) []byte {
+	if !strings.HasPrefix(arg, "0x") && !strings.HasPrefix(arg, "0X") {
+		return []byte(arg)
+	}
+	blob, err := hexutil.Decode(arg)
+	if err != nil {
+		utils.Fatalf("Invalid hex %q: %v", arg, err)
+	}
+	return blob
+}
+
+func dbGet(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	key := parseDBBytes(ctx.Args().First())
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack)
+	defer chaindb.Close()
+
+	value, err := chaindb.Get(key)
+	if err != nil {
+		utils.Fatalf("Failed to read key %#x: %v", key, err)
+	}
+	fmt.Printf("key:   %#x (%v)\n", key, rawdb.DescribeKey(key))
+	fmt.Printf("value: %#x\n", value)
+
+	decoded, err := rawdb.DescribeValue(key, value)
+	if err != nil {
+		utils.Fatalf("Failed to decode value: %v", err)
+	}
+	fmt.Println(decoded)
+	return nil
+}
+
+func dbPut(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		utils.Fatalf("This command requires two arguments.")
+	}
+	key, value := parseDBBytes(ctx.Args().Get(0)), parseDBBytes(ctx.Args().Get(1))
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack)
+	defer chaindb.Close()
+
+	if prev, err := chaindb.Get(key); err == nil {
+		log.Info("Overwriting database entry", "key", hexutil.Bytes(key), "previous", hexutil.Bytes(prev))
+	}
+	if err := chaindb.Put(key, value); err != nil {
+		utils.Fatalf("Failed to write key %#x: %v", key, err)
+	}
+	log.Info("Stored database entry", "key", hexutil.Bytes(key), "kind", rawdb.DescribeKey(key).Kind, "size", len(value))
+	return nil
+}
+
+func dbDelete(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	key := parseDBBytes(ctx.Args().First())
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack)
+	defer chaindb.Close()
+
+	prev, err := chaindb.Get(key)
+	if err != nil {
+		utils.Fatalf("Failed to read key %#x: %v", key, err)
+	}
+	if err := chaindb.Delete(key); err != nil {
+		utils.Fatalf("Failed to delete key %#x: %v", key, err)
+	}
+	log.Info("Deleted database entry", "key", hexutil.Bytes(key), "kind", rawdb.DescribeKey(key).Kind, "previous", hexutil.Bytes(prev))
+	return nil
+}
+
+func dbIterate(ctx *cli.Context) error {
+	if len(ctx.Args()) > 1 {
+		utils.Fatalf("This command accepts at most one argument.")
+	}
+	var prefix []byte
+	if len(ctx.Args()) == 1 {
+		prefix = parseDBBytes(ctx.Args().First())
+	}
+	limit := ctx.Uint64(dbIterateLimitFlag.Name)
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack)
+	defer chaindb.Close()
+
+	it := chaindb.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+
+	var count uint64
+	for it.Next() {
+		if limit != 0 && count == limit {
+			log.Info("Iteration limit reached", "limit", limit)
+			break
+		}
+		fmt.Printf("%#x %v (%d bytes)\n", it.Key(), rawdb.DescribeKey(it.Key()), len(it.Value()))
+		count++
+	}
+	if err := it.Error(); err != nil {
+		utils.Fatalf("Failed to iterate database: %v", err)
+	}
+	log.Info("Iterated database", "prefix", hexutil.Bytes(prefix), "entries", count)
+	return nil
+}
+
+func dbCheck(ctx *cli.Context) error {
+	fix := ctx.Bool(dbCheckFixFlag.Name)
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack)
+	defer chaindb.Close()
+
+	start := time.Now()
+	chain := rawdb.CheckCanonicalChain(chaindb)
+	bodies, receipts, err := rawdb.CheckDanglingBlockData(chaindb, fix)
+	if err != nil {
+		utils.Fatalf("Failed to check block data: %v", err)
+	}
+	heads := rawdb.CheckChainHeads(chaindb, fix)
+
+	context := []interface{}{
+		"chain", chain, "bodies", bodies, "receipts", receipts, "heads", heads,
+		"elapsed", common.PrettyDuration(time.Since(start)),
+	}
+	switch {
+	case chain+bodies+receipts+heads == 0:
+		log.Info("Database is consistent", context...)
+	case fix && chain == 0:
+		log.Warn("Repaired database", context...)
+	default:
+		log.Warn("Database inconsistencies found", context...)
+	}
+	return nil
+}
@@ -65,6 +65,8 @@ var (
 		utils.BootnodesV5Flag,
 		utils.DataDirFlag,
 		utils.AncientFlag,
+		utils.AncientRetentionFlag,
+		utils.DBEngineFlag,
 		utils.KeyStoreDirFlag,
 		utils.ExternalSignerFlag,
 		utils.NoUSBFlag,
@@ -110,7 +112,10 @@ var (
 		utils.CacheDatabaseFlag,
 		utils.CacheTrieFlag,
 		utils.CacheGCFlag,
+		utils.CacheSnapshotFlag,
 		utils.CacheNoPrefetchFlag,
+		utils.SnapshotFlag,
+		utils.StateHistoryFlag,
 		utils.ListenPortFlag,
 		utils.MaxPeersFlag,
 		utils.MaxPendingPeersFlag,
@@ -129,6 +134,8 @@ var (
 		utils.MinerLegacyExtraDataFlag,
 		utils.MinerRecommitIntervalFlag,
 		utils.MinerNoVerfiyFlag,
+		utils.MinerLeaseFileFlag,
+		utils.MinerLeaseIntervalFlag,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
 		utils.DiscoveryV5Flag,
@@ -206,6 +213,7 @@ func init() {
 	app.Commands = []cli.Command{
 		// See chaincmd.go:
 		initCommand,
+		genesisCommand,
 		importCommand,
 		exportCommand,
 		importPreimagesCommand,
@@ -217,6 +225,12 @@ func init() {
 		// See accountcmd.go:
 		accountCommand,
 		walletCommand,
+		// See snapshot.go:
+		snapshotCommand,
+		// See dbcmd.go:
+		dbCommand,
+		// See sealercmd.go:
+		protectionCommand,
 		// See consolecmd.go:
 		consoleCommand,
 		attachCommand,
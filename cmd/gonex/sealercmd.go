@@ -0,0 +1,142 @@
+// Copyright 2019 The gonex Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"os"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/consensus/dccs"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/log"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	protectionCommand = cli.Command{
+		Name:     "protection",
+		Usage:    "Manage the sealer slashing protection database",
+		Category: "ACCOUNT COMMANDS",
+		Description: `
+Before signing any header, the DCCS engine consults a slashing protection
+database in the data directory, recording the highest header signed by each
+local sealer. A header below that height, or a different header at the same
+height, is refused so that a restarted or duplicated sealer never signs
+conflicting blocks.
+
+The database is kept in an interchange format, allowing the signing history
+to follow a sealer key moved to another node:
+
+    {
+      "metadata": {
+        "interchange_format_version": "1",
+        "genesis_hash": "0x..."
+      },
+      "data": [
+        {
+          "sealer": "0x...",
+          "signed_blocks": [{"number": "1234", "seal_hash": "0x..."}]
+        }
+      ]
+    }
+
+Block numbers are decimal strings. A block without a seal hash locks its whole
+height. The node must be stopped while the database is being modified.
+
+The nodes of a hot-standby pair (see --miner.lease.file) do not share their
+databases. The history of the primary has to be exported from it and imported
+on the standby before the standby takes over the sealing, otherwise it may sign
+headers conflicting with the ones of the primary.`,
+		Subcommands: []cli.Command{
+			{
+				Action:    utils.MigrateFlags(exportProtection),
+				Name:      "export",
+				Usage:     "Export the slashing protection history",
+				ArgsUsage: "[<filename>]",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+				},
+				Description: `
+Writes the signing history of the local sealers in the interchange format, to
+the given file or to the standard output.`,
+			},
+			{
+				Action:    utils.MigrateFlags(importProtection),
+				Name:      "import",
+				Usage:     "Import a slashing protection history",
+				ArgsUsage: "<filename>",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+				},
+				Description: `
+Merges a signing history in the interchange format into the local database,
+keeping the highest signed header of every sealer. A history exported from a
+different network is rejected.`,
+			},
+		},
+	}
+)
+
+func exportProtection(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	protection, err := dccs.NewSlashingProtection(stack.ResolvePath(dccs.ProtectionFile))
+	if err != nil {
+		utils.Fatalf("Failed to open slashing protection database: %v", err)
+	}
+	out := os.Stdout
+	if ctx.NArg() > 0 {
+		if out, err = os.OpenFile(ctx.Args().First(), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600); err != nil {
+			utils.Fatalf("Failed to create export file: %v", err)
+		}
+		defer out.Close()
+	}
+	if err := protection.Export(out); err != nil {
+		utils.Fatalf("Failed to export slashing protection history: %v", err)
+	}
+	return nil
+}
+
+func importProtection(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	// Check the history against the local network, if the chain was initialised
+	db := utils.MakeChainDatabase(ctx, stack)
+	genesis := rawdb.ReadCanonicalHash(db, 0)
+	db.Close()
+
+	protection, err := dccs.NewSlashingProtection(stack.ResolvePath(dccs.ProtectionFile))
+	if err != nil {
+		utils.Fatalf("Failed to open slashing protection database: %v", err)
+	}
+	file, err := os.Open(ctx.Args().First())
+	if err != nil {
+		utils.Fatalf("Failed to read import file: %v", err)
+	}
+	defer file.Close()
+
+	if err := protection.Import(file, genesis); err != nil {
+		utils.Fatalf("Failed to import slashing protection history: %v", err)
+	}
+	log.Info("Imported slashing protection history", "file", ctx.Args().First())
+	return nil
+}
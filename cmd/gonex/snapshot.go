@@ -0,0 +1,180 @@
+// Copyright 2019 The gonex Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state/pruner"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	bloomFilterSizeFlag = cli.Uint64Flag{
+		Name:  "bloomfilter.size",
+		Usage: "Megabytes of memory allocated to the bloom filter marking the live state",
+		Value: 2048,
+	}
+)
+
+var (
+	snapshotCommand = cli.Command{
+		Name:     "snapshot",
+		Usage:    "Manage the state of the blockchain",
+		Category: "BLOCKCHAIN COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Action:    utils.MigrateFlags(pruneState),
+				Name:      "prune-state",
+				Usage:     "Delete the stale state from the database",
+				ArgsUsage: "",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+					utils.TestnetFlag,
+					utils.RinkebyFlag,
+					utils.GoerliFlag,
+					utils.DccsFlag,
+					bloomFilterSizeFlag,
+				},
+				Description: `
+The prune-state command deletes every state trie node and contract code that
+is not reachable from the state of the recent blocks or the genesis. The node
+must be stopped while pruning.
+
+The live state is first marked in a bloom filter, which is then committed to
+the data directory before any stale state is swept away. Should the pruning be
+interrupted from there on, it is resumed by the next run of this command or
+when the node is started. A larger filter leaves less stale state behind.`,
+			},
+			{
+				Action:    utils.MigrateFlags(exportState),
+				Name:      "export-state",
+				Usage:     "Export the state of a block into a portable state file",
+				ArgsUsage: "<filename> [<blockNum>]",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+					utils.TestnetFlag,
+					utils.RinkebyFlag,
+					utils.GoerliFlag,
+					utils.DccsFlag,
+				},
+				Description: `
+The export-state command writes the accounts, storage, contract codes and MRU
+numbers of the state of the given block, or of the head block by default, into
+a checksummed binary file. If the file ends with .gz, the output is gzipped.`,
+			},
+			{
+				Action:    utils.MigrateFlags(importState),
+				Name:      "import-state",
+				Usage:     "Import a state from a portable state file",
+				ArgsUsage: "<filename>",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+					utils.TestnetFlag,
+					utils.RinkebyFlag,
+					utils.GoerliFlag,
+					utils.DccsFlag,
+				},
+				Description: `
+The import-state command rebuilds the state tries held by a file written by
+export-state, and commits them once the file checksum and the resulting state
+root are verified. If the block the state belongs to is known locally, its
+state root must match too.`,
+			},
+		},
+	}
+)
+
+func pruneState(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack)
+	defer chaindb.Close()
+
+	pruner, err := pruner.NewPruner(chaindb, stack.ResolvePath(""), ctx.Uint64(bloomFilterSizeFlag.Name))
+	if err != nil {
+		utils.Fatalf("Failed to create state pruner: %v", err)
+	}
+	if err := pruner.Prune(); err != nil {
+		utils.Fatalf("Failed to prune state: %v", err)
+	}
+	return nil
+}
+
+func exportState(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 || len(ctx.Args()) > 2 {
+		utils.Fatalf("This command requires one or two arguments.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack)
+	defer chaindb.Close()
+
+	var header *types.Header
+	if len(ctx.Args()) == 2 {
+		number, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+		if err != nil {
+			utils.Fatalf("Invalid block number: %v", err)
+		}
+		header = rawdb.ReadHeader(chaindb, rawdb.ReadCanonicalHash(chaindb, number), number)
+	} else if hash := rawdb.ReadHeadBlockHash(chaindb); hash != (common.Hash{}) {
+		if number := rawdb.ReadHeaderNumber(chaindb, hash); number != nil {
+			header = rawdb.ReadHeader(chaindb, hash, *number)
+		}
+	}
+	if header == nil {
+		utils.Fatalf("Block not found")
+	}
+	if err := utils.ExportState(chaindb, header, ctx.Args().First()); err != nil {
+		utils.Fatalf("Export error: %v", err)
+	}
+	return nil
+}
+
+func importState(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack)
+	defer chaindb.Close()
+
+	manifest, err := utils.ImportState(chaindb, ctx.Args().First())
+	if err != nil {
+		utils.Fatalf("Import error: %v", err)
+	}
+	header := rawdb.ReadHeader(chaindb, manifest.Hash, manifest.Number)
+	switch {
+	case header == nil:
+		log.Warn("Imported state of unknown block", "number", manifest.Number, "hash", manifest.Hash)
+	case header.Root != manifest.Root:
+		utils.Fatalf("Imported state root %x mismatches block %d root %x", manifest.Root, manifest.Number, header.Root)
+	}
+	return nil
+}
@@ -50,7 +50,8 @@ services:
     environment:
       - WS_SECRET={{.Secret}}{{if .VHost}}
       - VIRTUAL_HOST={{.VHost}}{{end}}{{if .Banned}}
-      - BANNED={{.Banned}}{{end}}
+      - BANNED={{.Banned}}{{end}}{{if .DccsStats}}
+      - DCCS_STATS={{.DccsStats}}{{end}}
     logging:
       driver: "json-file"
       options:
@@ -61,8 +62,9 @@ services:
 
 // deployEthstats deploys a new nextats container to a remote machine via SSH,
 // docker and docker-compose. If an instance with the specified network name
-// already exists there, it will be overwritten!
-func deployEthstats(client *sshClient, network string, port int, secret string, vhost string, trusted []string, banned []string, nocache bool) ([]byte, error) {
+// already exists there, it will be overwritten! A non-zero dccsStats is the
+// version of the DCCS stats reported by the nodes, enabling their display.
+func deployEthstats(client *sshClient, network string, port int, secret string, vhost string, trusted []string, banned []string, dccsStats int, nocache bool) ([]byte, error) {
 	// Generate the content to upload to the server
 	workdir := fmt.Sprintf("%d", rand.Int63())
 	files := make(map[string][]byte)
@@ -85,11 +87,12 @@ func deployEthstats(client *sshClient, network string, port int, secret string,
 
 	composefile := new(bytes.Buffer)
 	template.Must(template.New("").Parse(ethstatsComposefile)).Execute(composefile, map[string]interface{}{
-		"Network": network,
-		"Port":    port,
-		"Secret":  secret,
-		"VHost":   vhost,
-		"Banned":  strings.Join(banned, ","),
+		"Network":   network,
+		"Port":      port,
+		"Secret":    secret,
+		"VHost":     vhost,
+		"Banned":    strings.Join(banned, ","),
+		"DccsStats": dccsStats,
 	})
 	files[filepath.Join(workdir, "docker-compose.yaml")] = composefile.Bytes()
 
@@ -114,17 +117,22 @@ type ethstatsInfos struct {
 	secret string
 	config string
 	banned []string
+	dccs   int
 }
 
 // Report converts the typed struct into a plain string->string map, containing
 // most - but not all - fields for reporting to the user.
 func (info *ethstatsInfos) Report() map[string]string {
-	return map[string]string{
+	report := map[string]string{
 		"Website address":       info.host,
 		"Website listener port": strconv.Itoa(info.port),
 		"Login secret":          info.secret,
 		"Banned addresses":      strings.Join(info.banned, "\n"),
 	}
+	if info.dccs > 0 {
+		report["DCCS stats version"] = strconv.Itoa(info.dccs)
+	}
+	return report
 }
 
 // checkEthstats does a health-check against an nextats server to verify whether
@@ -161,6 +169,9 @@ func checkEthstats(client *sshClient, network string) (*ethstatsInfos, error) {
 	// Retrieve the IP blacklist
 	banned := strings.Split(infos.envvars["BANNED"], ",")
 
+	// Retrieve the DCCS stats version, if enabled
+	dccs, _ := strconv.Atoi(infos.envvars["DCCS_STATS"])
+
 	// Run a sanity check to see if the port is reachable
 	if err = checkPort(host, port); err != nil {
 		log.Warn("Nexstats service seems unreachable", "server", host, "port", port, "err", err)
@@ -172,5 +183,6 @@ func checkEthstats(client *sshClient, network string) (*ethstatsInfos, error) {
 		secret: secret,
 		config: config,
 		banned: banned,
+		dccs:   dccs,
 	}, nil
 }
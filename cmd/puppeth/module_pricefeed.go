@@ -0,0 +1,153 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"strconv"
+	"text/template"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// pricefeedDockerfile is the Dockerfile required to run a stand-in price
+// service, feeding a fixed price to the DCCS sealers.
+var pricefeedDockerfile = `
+FROM busybox:latest
+
+ADD pricefeed.sh /pricefeed.sh
+
+ENTRYPOINT ["/bin/sh", "/pricefeed.sh"]
+`
+
+// pricefeedScript refreshes the price data every second, so the sealers never
+// consider it stale, and serves it over HTTP.
+var pricefeedScript = `
+mkdir -p /www
+while true; do
+	echo "{\"price\":\"$PRICE\",\"timestamp\":$(date +%s),\"exchange\":\"puppeth\"}" > /www/price.tmp
+	mv /www/price.tmp /www/price.json
+	sleep 1
+done &
+exec httpd -f -p 80 -h /www
+`
+
+// pricefeedComposefile is the docker-compose.yml file required to deploy and
+// maintain a stand-in price service.
+var pricefeedComposefile = `
+version: '2'
+services:
+  pricefeed:
+    build: .
+    image: {{.Network}}/pricefeed
+    container_name: {{.Network}}_pricefeed_1
+    ports:
+      - "{{.Port}}:80"
+    environment:
+      - PRICE={{.Price}}
+    logging:
+      driver: "json-file"
+      options:
+        max-size: "1m"
+        max-file: "10"
+    restart: always
+`
+
+// deployPricefeed deploys a new stand-in price service container to a remote
+// machine via SSH, docker and docker-compose. If an instance with the specified
+// network name already exists there, it will be overwritten!
+func deployPricefeed(client *sshClient, network string, config *pricefeedInfos, nocache bool) ([]byte, error) {
+	// Generate the content to upload to the server
+	workdir := fmt.Sprintf("%d", rand.Int63())
+	files := make(map[string][]byte)
+
+	files[filepath.Join(workdir, "Dockerfile")] = []byte(pricefeedDockerfile)
+	files[filepath.Join(workdir, "pricefeed.sh")] = []byte(pricefeedScript)
+
+	composefile := new(bytes.Buffer)
+	template.Must(template.New("").Parse(pricefeedComposefile)).Execute(composefile, map[string]interface{}{
+		"Network": network,
+		"Port":    config.port,
+		"Price":   config.price,
+	})
+	files[filepath.Join(workdir, "docker-compose.yaml")] = composefile.Bytes()
+
+	// Upload the deployment files to the remote server (and clean up afterwards)
+	if out, err := client.Upload(files); err != nil {
+		return out, err
+	}
+	defer client.Run("rm -rf " + workdir)
+
+	// Build and deploy the price service
+	if nocache {
+		return nil, client.Stream(fmt.Sprintf("cd %s && docker-compose -p %s build --pull --no-cache && docker-compose -p %s up -d --force-recreate --timeout 60", workdir, network, network))
+	}
+	return nil, client.Stream(fmt.Sprintf("cd %s && docker-compose -p %s up -d --build --force-recreate --timeout 60", workdir, network))
+}
+
+// pricefeedInfos is returned from a price service status check to allow
+// reporting various configuration parameters.
+type pricefeedInfos struct {
+	host  string
+	port  int
+	price string
+}
+
+// url returns the price service endpoint to configure the sealers with.
+func (info *pricefeedInfos) url() string {
+	return fmt.Sprintf("http://%s:%d/price.json", info.host, info.port)
+}
+
+// Report converts the typed struct into a plain string->string map, containing
+// most - but not all - fields for reporting to the user.
+func (info *pricefeedInfos) Report() map[string]string {
+	return map[string]string{
+		"Price service URL": info.url(),
+		"Listener port":     strconv.Itoa(info.port),
+		"Stand-in price":    info.price,
+	}
+}
+
+// checkPricefeed does a health-check against a stand-in price service to verify
+// whether it's running, and if yes, gathering a collection of useful infos about it.
+func checkPricefeed(client *sshClient, network string) (*pricefeedInfos, error) {
+	// Inspect a possible price service container on the host
+	infos, err := inspectContainer(client, fmt.Sprintf("%s_pricefeed_1", network))
+	if err != nil {
+		return nil, err
+	}
+	if !infos.running {
+		return nil, ErrServiceOffline
+	}
+	port := infos.portmap["80/tcp"]
+	if port == 0 {
+		return nil, ErrNotExposed
+	}
+	// Run a sanity check to see if the port is reachable
+	if err = checkPort(client.server, port); err != nil {
+		log.Warn("Price service seems unreachable", "server", client.server, "port", port, "err", err)
+	}
+	// Container available, assemble and return the useful infos
+	return &pricefeedInfos{
+		host:  client.address,
+		port:  port,
+		price: infos.envvars["PRICE"],
+	}, nil
+}
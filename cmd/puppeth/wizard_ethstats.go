@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/ethereum/go-ethereum/consensus/dccs"
 	"github.com/ethereum/go-ethereum/log"
 )
 
@@ -114,7 +115,12 @@ func (w *wizard) deployEthstats() {
 			trusted = append(trusted, client.address)
 		}
 	}
-	if out, err := deployEthstats(client, w.network, infos.port, infos.secret, infos.host, trusted, infos.banned, nocache); err != nil {
+	// Let nextats display the DCCS stats reported by the nodes
+	dccsStats := 0
+	if w.conf.Genesis != nil && w.conf.Genesis.Config.Dccs != nil {
+		dccsStats = dccs.StatsVersion
+	}
+	if out, err := deployEthstats(client, w.network, infos.port, infos.secret, infos.host, trusted, infos.banned, dccsStats, nocache); err != nil {
 		log.Error("Failed to deploy nextats container", "err", err)
 		if len(out) > 0 {
 			fmt.Printf("%s\n", out)
@@ -33,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/deployer"
+	"github.com/ethereum/go-ethereum/consensus/dccs"
 	"github.com/ethereum/go-ethereum/contracts/nexty/ntf"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/log"
@@ -124,7 +125,7 @@ func (w *wizard) makeGenesis() {
 			ThangLongBlock: common.Big0,
 			ThangLongEpoch: 3000,
 			// CoLoa hardfork
-			CoLoaBlock:              common.Big0,
+			CoLoaBlock:              common.Big2,
 			LeakDuration:            1024,
 			ApplicationConfirmation: 128,
 			RandomSeedIteration:     20000000, // around 128 seconds
@@ -189,25 +190,42 @@ func (w *wizard) makeGenesis() {
 
 		// Generate nexty token foundation contract
 		fmt.Println()
-		fmt.Println("Which account is allowed to be the onwer of NTF token contract? (optional)")
-		var onwer *common.Address
-		if address := w.readAddress(); address != nil {
-			onwer = address
-		}
-		if onwer != nil {
-			code, storage, err := deployer.DeployContract(func(sim *backends.SimulatedBackend, auth *bind.TransactOpts) (common.Address, error) {
-				address, _, _, err := ntf.DeployNtfToken(auth, sim, *onwer)
-				return address, err
-			})
+		if genesis.Config.Dccs.ThangLongBlock.Sign() == 0 {
+			// No preparation block to deploy the governance in, stake the sealers from the genesis
+			fmt.Println("Which account should own the NTF token supply to stake the sealers with? (mandatory)")
+			var owner *common.Address
+			for owner == nil {
+				owner = w.readAddress()
+			}
+			alloc, err := dccs.GenesisAlloc(genesis.Config, *owner, signers)
 			if err != nil {
-				fmt.Println("Can't deploy nexty foundation token smart contract")
+				log.Error("Failed to stake the sealers", "err", err)
 				return
 			}
+			for address, account := range alloc {
+				genesis.Alloc[address] = account
+			}
+		} else {
+			fmt.Println("Which account is allowed to be the onwer of NTF token contract? (optional)")
+			var onwer *common.Address
+			if address := w.readAddress(); address != nil {
+				onwer = address
+			}
+			if onwer != nil {
+				code, storage, err := deployer.DeployContract(func(sim *backends.SimulatedBackend, auth *bind.TransactOpts) (common.Address, error) {
+					address, _, _, err := ntf.DeployNtfToken(auth, sim, *onwer)
+					return address, err
+				})
+				if err != nil {
+					fmt.Println("Can't deploy nexty foundation token smart contract")
+					return
+				}
 
-			genesis.Alloc[params.TokenAddress] = core.GenesisAccount{
-				Balance: big.NewInt(0),
-				Code:    code,
-				Storage: storage,
+				genesis.Alloc[params.TokenAddress] = core.GenesisAccount{
+					Balance: big.NewInt(0),
+					Code:    code,
+					Storage: storage,
+				}
 			}
 		}
 
@@ -215,6 +233,11 @@ func (w *wizard) makeGenesis() {
 		fmt.Println()
 		fmt.Printf("Which block should CoLoa come into effect? (default = %v)\n", genesis.Config.Dccs.CoLoaBlock)
 		genesis.Config.Dccs.CoLoaBlock = w.readDefaultBigInt(genesis.Config.Dccs.CoLoaBlock)
+		if genesis.Config.Dccs.CoLoaBlock.Cmp(big.NewInt(2)) < 0 {
+			// the sealing queue is built from the parent seal, unavailable before block 2
+			log.Warn("CoLoa requires a sealed parent block, postponing to block 2")
+			genesis.Config.Dccs.CoLoaBlock = big.NewInt(2)
+		}
 		genesis.Config.IstanbulBlock = genesis.Config.Dccs.CoLoaBlock
 
 		fmt.Println()
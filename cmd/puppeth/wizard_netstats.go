@@ -37,6 +37,7 @@ func (w *wizard) networkStats() {
 	}
 	// Clear out some previous configs to refill from current scan
 	w.conf.ethstats = ""
+	w.conf.pricefeed = ""
 	w.conf.bootnodes = w.conf.bootnodes[:0]
 
 	// Iterate over all the specified hosts and check their status
@@ -75,6 +76,7 @@ func (w *wizard) gatherStats(server string, pubkey []byte, client *sshClient) *s
 	var (
 		genesis   string
 		ethstats  string
+		pricefeed string
 		bootnodes []string
 	)
 	// Ensure a valid SSH connection to the remote server
@@ -113,6 +115,15 @@ func (w *wizard) gatherStats(server string, pubkey []byte, client *sshClient) *s
 		stat.services["nextats"] = infos.Report()
 		ethstats = infos.config
 	}
+	logger.Debug("Checking for pricefeed availability")
+	if infos, err := checkPricefeed(client, w.network); err != nil {
+		if err != ErrServiceUnknown {
+			stat.services["pricefeed"] = map[string]string{"offline": err.Error()}
+		}
+	} else {
+		stat.services["pricefeed"] = infos.Report()
+		pricefeed = infos.url()
+	}
 	logger.Debug("Checking for bootnode availability")
 	if infos, err := checkNode(client, w.network, true); err != nil {
 		if err != ErrServiceUnknown {
@@ -180,6 +191,9 @@ func (w *wizard) gatherStats(server string, pubkey []byte, client *sshClient) *s
 	if ethstats != "" {
 		w.conf.ethstats = ethstats
 	}
+	if pricefeed != "" {
+		w.conf.pricefeed = pricefeed
+	}
 	w.conf.bootnodes = append(w.conf.bootnodes, bootnodes...)
 
 	return stat
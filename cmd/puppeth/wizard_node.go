@@ -17,12 +17,16 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/dccs"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
 )
 
@@ -174,6 +178,32 @@ func (w *wizard) deployNode(boot bool) {
 					return
 				}
 			}
+			// Sealers outside of the genesis set have to join through the governance contract
+			if key, err := keystore.DecryptKey([]byte(infos.keyJSON), infos.keyPass); err == nil && !w.isGenesisSealer(key.Address) {
+				fmt.Println()
+				fmt.Printf("Sealer %s is not in the genesis set, stake it through NextyGovernance (y/n)? (default = yes)\n", key.Address.Hex())
+				if !w.readDefaultYesNo(true) || !w.stakeSealer(key.Address) {
+					log.Warn("Sealer not staked, deposit and join through NextyGovernance before sealing", "address", key.Address)
+				}
+			}
+			// Dccs sealers also need a price service and a VDF generator
+			if infos.priceURL == "" {
+				infos.priceURL = w.conf.pricefeed
+			}
+			fmt.Println()
+			if infos.priceURL == "" {
+				fmt.Printf("Which price service should the sealer fetch from? (optional)\n")
+				infos.priceURL = w.readDefaultString("")
+			} else {
+				fmt.Printf("Which price service should the sealer fetch from? (default = %s)\n", infos.priceURL)
+				infos.priceURL = w.readDefaultString(infos.priceURL)
+			}
+			if infos.vdfGen == "" {
+				infos.vdfGen = "internal"
+			}
+			fmt.Println()
+			fmt.Printf("Which VDF generator should the sealer use (internal|<external VDF command>)? (default = %s)\n", infos.vdfGen)
+			infos.vdfGen = w.readDefaultString(infos.vdfGen)
 		}
 		// Establish the gas dynamics to be enforced by the signer
 		fmt.Println()
@@ -208,3 +238,56 @@ func (w *wizard) deployNode(boot bool) {
 
 	w.networkStats()
 }
+
+// isGenesisSealer reports whether an address is one of the DCCS sealers set
+// in the genesis extra-data, active in the governance contract from the start.
+func (w *wizard) isGenesisSealer(address common.Address) bool {
+	extra := w.conf.Genesis.ExtraData
+	if len(extra) < 32+65 {
+		return false
+	}
+	signers := extra[32 : len(extra)-65]
+	for i := 0; i+common.AddressLength <= len(signers); i += common.AddressLength {
+		if common.BytesToAddress(signers[i:i+common.AddressLength]) == address {
+			return true
+		}
+	}
+	return false
+}
+
+// stakeSealer deposits the required NTF stake from a coinbase account into the
+// governance contract and joins the sealer with it, through the RPC endpoint
+// of a synced node. It reports whether the sealer is staked.
+func (w *wizard) stakeSealer(sealer common.Address) bool {
+	fmt.Println()
+	fmt.Println("Which RPC endpoint of a synced node should the staking transactions be sent to?")
+	endpoint := w.readString()
+
+	fmt.Println()
+	fmt.Println("Please paste the key JSON of the coinbase account holding the NTF stake:")
+	keyJSON := w.readJSON()
+
+	fmt.Println()
+	fmt.Println("What's the unlock password for the account? (won't be echoed)")
+	key, err := keystore.DecryptKey([]byte(keyJSON), w.readPassword())
+	if err != nil {
+		log.Error("Failed to decrypt key with given passphrase")
+		return false
+	}
+	client, err := ethclient.Dial(endpoint)
+	if err != nil {
+		log.Error("Failed to connect to RPC endpoint", "endpoint", endpoint, "err", err)
+		return false
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	if err := dccs.StakeSealer(ctx, client, bind.NewKeyedTransactor(key.PrivateKey), sealer); err != nil {
+		log.Error("Failed to stake sealer", "sealer", sealer, "coinbase", key.Address, "err", err)
+		return false
+	}
+	log.Info("Sealer staked through NextyGovernance", "sealer", sealer, "coinbase", key.Address)
+	return true
+}
@@ -0,0 +1,85 @@
+// Copyright 2017 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/consensus/dccs"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// deployPricefeed queries the user for various input on deploying a stand-in
+// price service, after which it executes it.
+func (w *wizard) deployPricefeed() {
+	// Do some sanity check before the user wastes time on input
+	if w.conf.Genesis == nil || w.conf.Genesis.Config.Dccs == nil {
+		log.Error("No DCCS genesis block configured")
+		return
+	}
+	// Select the server to interact with
+	server := w.selectServer()
+	if server == "" {
+		return
+	}
+	client := w.servers[server]
+
+	// Retrieve any active price service configurations from the server
+	infos, err := checkPricefeed(client, w.network)
+	if err != nil {
+		infos = &pricefeedInfos{
+			host:  client.address,
+			port:  8080,
+			price: "1",
+		}
+	}
+	existed := err == nil
+
+	// Figure out which port to listen on
+	fmt.Println()
+	fmt.Printf("Which port should the price service listen on? (default = %d)\n", infos.port)
+	infos.port = w.readDefaultInt(infos.port)
+
+	// Figure out the price to feed the sealers with
+	for {
+		fmt.Println()
+		fmt.Printf("What price of the volatile token should be fed to the sealers? (default = %s)\n", infos.price)
+		price := w.readDefaultString(infos.price)
+		if p := dccs.PriceFromString(price); p == nil || p.Rat().Sign() <= 0 {
+			log.Error("Invalid price", "price", price)
+			continue
+		}
+		infos.price = price
+		break
+	}
+	// Try to deploy the price service on the host
+	nocache := false
+	if existed {
+		fmt.Println()
+		fmt.Printf("Should the price service be built from scratch (y/n)? (default = no)\n")
+		nocache = w.readDefaultYesNo(false)
+	}
+	if out, err := deployPricefeed(client, w.network, infos, nocache); err != nil {
+		log.Error("Failed to deploy price service container", "err", err)
+		if len(out) > 0 {
+			fmt.Printf("%s\n", out)
+		}
+		return
+	}
+	// All ok, run a network scan to pick any changes up
+	w.networkStats()
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state/statefile"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -312,3 +313,47 @@ func ExportPreimages(db ethdb.Database, fn string) error {
 	log.Info("Exported preimages", "file", fn)
 	return nil
 }
+
+// ExportState exports the state of the given block into the specified file in
+// the portable state file format, truncating any data already present in it.
+func ExportState(db ethdb.Database, header *types.Header, fn string) error {
+	log.Info("Exporting state", "file", fn, "number", header.Number, "root", header.Root)
+
+	// Open the file handle and potentially wrap with a gzip stream
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+	_, err = statefile.Export(writer, db, header)
+	return err
+}
+
+// ImportState imports a state exported in the portable state file format into
+// the database, returning the manifest of the file once its content and state
+// root are verified.
+func ImportState(db ethdb.Database, fn string) (*statefile.Manifest, error) {
+	log.Info("Importing state", "file", fn)
+
+	// Open the file handle and potentially unwrap the gzip stream
+	fh, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = fh
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return nil, err
+		}
+	}
+	manifest, _, err := statefile.Import(reader, db)
+	return manifest, err
+}
@@ -41,6 +41,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/dccs"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/dashboard"
@@ -138,6 +139,14 @@ var (
 		Name:  "datadir.ancient",
 		Usage: "Data directory for ancient chain segments (default = inside chaindata)",
 	}
+	AncientRetentionFlag = cli.StringFlag{
+		Name:  "ancient.retention",
+		Usage: "Number of recent blocks kept in the ancient tables, older ones are dropped (e.g. receipts=90000,bodies=90000)",
+	}
+	DBEngineFlag = cli.StringFlag{
+		Name:  "db.engine",
+		Usage: "Backing database implementation of new databases ('leveldb' or 'boltdb')",
+	}
 	KeyStoreDirFlag = DirectoryFlag{
 		Name:  "keystore",
 		Usage: "Directory for the keystore (default = inside the datadir)",
@@ -178,7 +187,7 @@ var (
 	}
 	DeveloperFlag = cli.BoolFlag{
 		Name:  "dev",
-		Usage: "Ephemeral proof-of-authority network with a pre-funded developer account, mining enabled",
+		Usage: "Ephemeral DCCS network with a pre-funded and staked developer account, mining enabled",
 	}
 	DeveloperPeriodFlag = cli.IntFlag{
 		Name:  "dev.period",
@@ -428,10 +437,23 @@ var (
 		Usage: "Percentage of cache memory allowance to use for trie pruning (default = 25% full mode, 0% archive mode)",
 		Value: 25,
 	}
+	CacheSnapshotFlag = cli.IntFlag{
+		Name:  "cache.snapshot",
+		Usage: "Percentage of cache memory allowance to use for state snapshot caching (only with --snapshot)",
+		Value: 10,
+	}
 	CacheNoPrefetchFlag = cli.BoolFlag{
 		Name:  "cache.noprefetch",
 		Usage: "Disable heuristic state prefetch during block import (less CPU and disk IO, more time waiting for data)",
 	}
+	SnapshotFlag = cli.BoolFlag{
+		Name:  "snapshot",
+		Usage: "Maintain a flat snapshot of the state to speed up state reads (generated in the background)",
+	}
+	StateHistoryFlag = cli.Uint64Flag{
+		Name:  "state.history",
+		Usage: "Number of recent blocks whose state can be rebuilt from reverse diffs (0 = disabled)",
+	}
 	// Miner settings
 	MiningEnabledFlag = cli.BoolFlag{
 		Name:  "mine",
@@ -503,6 +525,15 @@ var (
 		Name:  "miner.noverify",
 		Usage: "Disable remote sealing verification",
 	}
+	MinerLeaseFileFlag = cli.StringFlag{
+		Name:  "miner.lease.file",
+		Usage: "Lock file shared with a hot-standby sealer, only its holder may seal (import the primary's slashing protection history on the standby first)",
+	}
+	MinerLeaseIntervalFlag = cli.DurationFlag{
+		Name:  "miner.lease.interval",
+		Usage: "Interval at which a hot-standby sealer retries to acquire the lease",
+		Value: eth.DefaultConfig.Miner.LeaseInterval,
+	}
 	// Account settings
 	UnlockedAccountFlag = cli.StringFlag{
 		Name:  "unlock",
@@ -1063,6 +1094,42 @@ func makeDatabaseHandles() int {
 	return int(raised / 2) // Leave half for networking and other stuff
 }
 
+// MakeDBEngine returns the database engine requested by the user.
+func MakeDBEngine(ctx *cli.Context) string {
+	switch engine := ctx.GlobalString(DBEngineFlag.Name); engine {
+	case rawdb.EngineLevelDB, rawdb.EngineBoltDB:
+		return engine
+	default:
+		Fatalf("Invalid --%s, must be '%s' or '%s'", DBEngineFlag.Name, rawdb.EngineLevelDB, rawdb.EngineBoltDB)
+	}
+	return ""
+}
+
+// MakeAncientRetention parses the per table retention of the ancient chain data,
+// given as a comma separated list of table=blocks pairs.
+func MakeAncientRetention(ctx *cli.Context) map[string]uint64 {
+	retention := make(map[string]uint64)
+	for _, spec := range strings.Split(ctx.GlobalString(AncientRetentionFlag.Name), ",") {
+		if spec = strings.TrimSpace(spec); spec == "" {
+			continue
+		}
+		parts := strings.SplitN(spec, "=", 2)
+		if len(parts) != 2 {
+			Fatalf("Invalid ancient retention %q, expected table=blocks", spec)
+		}
+		name := strings.TrimSpace(parts[0])
+		if !rawdb.FreezerPrunableTables[name] {
+			Fatalf("Ancient table %q cannot be pruned", name)
+		}
+		blocks, err := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 64)
+		if err != nil {
+			Fatalf("Invalid ancient retention %q: %v", spec, err)
+		}
+		retention[name] = blocks
+	}
+	return retention
+}
+
 // MakeAddress converts an account specified directly as a hex encoded string or
 // a key index in the key store to an internal account representation.
 func MakeAddress(ks *keystore.KeyStore, account string) (accounts.Account, error) {
@@ -1221,6 +1288,9 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 		cfg.ExternalSigner = ctx.GlobalString(ExternalSignerFlag.Name)
 	}
 
+	if ctx.GlobalIsSet(DBEngineFlag.Name) {
+		cfg.DBEngine = MakeDBEngine(ctx)
+	}
 	if ctx.GlobalIsSet(KeyStoreDirFlag.Name) {
 		cfg.KeyStoreDir = ctx.GlobalString(KeyStoreDirFlag.Name)
 	}
@@ -1379,6 +1449,12 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.GlobalIsSet(MinerNoVerfiyFlag.Name) {
 		cfg.Noverify = ctx.Bool(MinerNoVerfiyFlag.Name)
 	}
+	if ctx.GlobalIsSet(MinerLeaseFileFlag.Name) {
+		cfg.LeaseFile = ctx.GlobalString(MinerLeaseFileFlag.Name)
+	}
+	if ctx.GlobalIsSet(MinerLeaseIntervalFlag.Name) {
+		cfg.LeaseInterval = ctx.GlobalDuration(MinerLeaseIntervalFlag.Name)
+	}
 }
 
 func setWhitelist(ctx *cli.Context, cfg *eth.Config) {
@@ -1458,6 +1534,15 @@ func SetShhConfig(ctx *cli.Context, stack *node.Node, cfg *whisper.Config) {
 	}
 }
 
+// snapshotCache returns the megabytes of the cache allowance given to the state
+// snapshot, never less than one as zero disables the snapshot altogether.
+func snapshotCache(ctx *cli.Context) int {
+	if size := ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheSnapshotFlag.Name) / 100; size > 0 {
+		return size
+	}
+	return 1
+}
+
 // SetEthConfig applies eth-related command line flags to the config.
 func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	// Avoid conflicting network flags
@@ -1490,6 +1575,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if ctx.GlobalIsSet(AncientFlag.Name) {
 		cfg.DatabaseFreezer = ctx.GlobalString(AncientFlag.Name)
 	}
+	if ctx.GlobalIsSet(AncientRetentionFlag.Name) {
+		cfg.DatabaseRetention = MakeAncientRetention(ctx)
+	}
 
 	if gcmode := ctx.GlobalString(GCModeFlag.Name); gcmode != "full" && gcmode != "archive" {
 		Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
@@ -1506,6 +1594,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheGCFlag.Name) {
 		cfg.TrieDirtyCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheGCFlag.Name) / 100
 	}
+	if ctx.GlobalBool(SnapshotFlag.Name) {
+		cfg.SnapshotCache = snapshotCache(ctx)
+	}
+	if ctx.GlobalIsSet(StateHistoryFlag.Name) {
+		cfg.StateHistory = ctx.GlobalUint64(StateHistoryFlag.Name)
+	}
 	if ctx.GlobalIsSet(DocRootFlag.Name) {
 		cfg.DocRoot = ctx.GlobalString(DocRootFlag.Name)
 	}
@@ -1569,7 +1663,20 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 		}
 		log.Info("Using developer account", "address", developer.Address)
 
-		cfg.Genesis = core.DeveloperGenesisBlock(uint64(ctx.GlobalInt(DeveloperPeriodFlag.Name)), developer.Address)
+		cfg.Genesis, err = dccs.DeveloperGenesisBlock(uint64(ctx.GlobalInt(DeveloperPeriodFlag.Name)), developer.Address)
+		if err != nil {
+			Fatalf("Failed to create developer genesis: %v", err)
+		}
+		// Use the fast local stand-ins for the VDF generator and the price feed
+		if !ctx.GlobalIsSet(VDFGen.Name) {
+			cfg.VDFGen = "internal"
+		}
+		if !ctx.GlobalIsSet(PriceServiceURLFlag.Name) {
+			if cfg.PriceServiceURL, err = dccs.StartDeveloperPriceService("1"); err != nil {
+				Fatalf("Failed to start developer price service: %v", err)
+			}
+			log.Info("Using developer price service", "url", cfg.PriceServiceURL)
+		}
 		if !ctx.GlobalIsSet(MinerGasPriceFlag.Name) && !ctx.GlobalIsSet(MinerLegacyGasPriceFlag.Name) {
 			cfg.Miner.GasPrice = big.NewInt(1)
 		}
@@ -1773,6 +1880,10 @@ func MakeChain(ctx *cli.Context, stack *node.Node) (chain *core.BlockChain, chai
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheGCFlag.Name) {
 		cache.TrieDirtyLimit = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheGCFlag.Name) / 100
 	}
+	if ctx.GlobalBool(SnapshotFlag.Name) {
+		cache.SnapshotLimit = snapshotCache(ctx)
+	}
+	cache.StateHistory = ctx.GlobalUint64(StateHistoryFlag.Name)
 	vmcfg := vm.Config{EnablePreimageRecording: ctx.GlobalBool(VMEnableDebugFlag.Name)}
 	chain, err = core.NewBlockChain(chainDb, cache, config, engine, vmcfg, nil, nil)
 	if err != nil {
@@ -136,3 +136,30 @@ type PoW interface {
 	// Hashrate returns the current mining hashrate of a PoW consensus engine.
 	Hashrate() float64
 }
+
+// HeaderRetriever retrieves block headers missing from the local database, e.g.
+// from the servers of a light client. Retrieved headers are stored locally.
+type HeaderRetriever interface {
+	// HeaderByNumber retrieves the canonical header with the given number.
+	HeaderByNumber(number uint64) (*types.Header, error)
+
+	// Ancestors retrieves a chain of headers, newest first, starting with the
+	// header of the given hash and walking backwards along the parent links.
+	// The number is the block number of the requested header, or an upper bound
+	// of it when unknown.
+	Ancestors(hash common.Hash, number uint64, amount uint64) ([]*types.Header, error)
+}
+
+// CheckpointSyncer is a consensus engine able to bootstrap a light client from
+// a trusted checkpoint.
+type CheckpointSyncer interface {
+	Engine
+
+	// CheckpointHead returns the number of the block to start syncing from, given
+	// the latest block covered by the trusted checkpoint.
+	CheckpointHead(latest uint64) uint64
+
+	// FetchCheckpointHeaders retrieves all the headers older than the head which
+	// are needed to verify the chain following it.
+	FetchCheckpointHeaders(chain ChainReader, head *types.Header, retriever HeaderRetriever) error
+}
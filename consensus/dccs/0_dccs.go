@@ -23,7 +23,6 @@ import (
 	"math/rand"
 	"time"
 
-	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
@@ -382,6 +381,12 @@ func (d *Dccs) seal(chain consensus.ChainReader, block *types.Block, results cha
 	signer, signFn := d.signer, d.signFn
 	d.lock.RUnlock()
 
+	// Stand by if the sealing lease is held by a paired node
+	if !d.leaseHeld() {
+		log.Debug("Sealing lease held by paired node, standing by")
+		return nil
+	}
+
 	// Bail out if we're unauthorized to sign a block
 	snap, err := d.snapshot(chain, number-1, header.ParentHash, nil)
 	if err != nil {
@@ -409,13 +414,8 @@ func (d *Dccs) seal(chain consensus.ChainReader, block *types.Block, results cha
 
 		log.Trace("Out-of-turn signing requested", "wiggle", common.PrettyDuration(wiggle))
 	}
-	// Sign all the things!
-	sighash, err := signFn(accounts.Account{Address: signer}, accounts.MimetypeClique, DccsRLP(header))
-	if err != nil {
-		return err
-	}
-	copy(header.Extra[len(header.Extra)-extraSeal:], sighash)
-	// Wait until sealing is terminated or delay timeout.
+	// Wait until sealing is terminated or delay timeout. Signing is deferred until
+	// the slot is ours, so recommitted work never signs the same height twice.
 	log.Trace("Waiting for slot to sign and propagate", "delay", common.PrettyDuration(delay))
 	go func() {
 		select {
@@ -423,6 +423,11 @@ func (d *Dccs) seal(chain consensus.ChainReader, block *types.Block, results cha
 			return
 		case <-time.After(delay):
 		}
+		// Sign all the things!
+		if err := d.signHeader(chain, header, signer, signFn); err != nil {
+			log.Warn("Refused to sign sealed block", "number", number, "err", err)
+			return
+		}
 
 		select {
 		case results <- block.WithSeal(header):
@@ -27,7 +27,6 @@ import (
 
 	"github.com/ethereum/go-ethereum/contracts/nexty/governance"
 
-	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
 	"github.com/ethereum/go-ethereum/common"
@@ -63,6 +62,8 @@ var (
 	} // rewards per year in percent of current total supply
 	initialSupply = big.NewInt(18e+10)   // initial total supply in NTY
 	blockPerYear  = big.NewInt(15778476) // Number of blocks per year with blocktime = 2s
+
+	ntfTokenOwner = common.HexToAddress("0x000000270840d8ebdffc7d162193cc5ba1ad8707") // NTF token wallet of the public networks
 )
 
 // Init the first hardfork of DCCS consensus
@@ -507,6 +508,12 @@ func (d *Dccs) seal1(chain consensus.ChainReader, block *types.Block, results ch
 	signer, signFn := d.signer, d.signFn
 	d.lock.RUnlock()
 
+	// Stand by if the sealing lease is held by a paired node
+	if !d.leaseHeld() {
+		log.Debug("Sealing lease held by paired node, standing by")
+		return nil
+	}
+
 	// Bail out if we're unauthorized to sign a block
 	snap, err := d.snapshot1(chain, header, nil)
 	if err != nil {
@@ -547,13 +554,8 @@ func (d *Dccs) seal1(chain consensus.ChainReader, block *types.Block, results ch
 		delay += wiggle
 		log.Trace("Out-of-turn signing requested", "wiggle", common.PrettyDuration(wiggle))
 	}
-	// Sign all the things!
-	sighash, err := signFn(accounts.Account{Address: signer}, accounts.MimetypeClique, DccsRLP(header))
-	if err != nil {
-		return err
-	}
-	copy(header.Extra[len(header.Extra)-extraSeal:], sighash)
-	// Wait until sealing is terminated or delay timeout.
+	// Wait until sealing is terminated or delay timeout. Signing is deferred until
+	// the slot is ours, so recommitted work never signs the same height twice.
 	log.Trace("Waiting for slot to sign and propagate", "delay", common.PrettyDuration(delay))
 	go func() {
 		select {
@@ -561,6 +563,11 @@ func (d *Dccs) seal1(chain consensus.ChainReader, block *types.Block, results ch
 			return
 		case <-time.After(delay):
 		}
+		// Sign all the things!
+		if err := d.signHeader(chain, header, signer, signFn); err != nil {
+			log.Warn("Refused to sign sealed block", "number", number, "err", err)
+			return
+		}
 
 		select {
 		case results <- block.WithSeal(header):
@@ -709,11 +716,11 @@ func deployContract(state *state.StateDB, address common.Address, code []byte, s
 	state.Commit(true)
 }
 
-// deployConsensusContracts deploys the consensus contract without any owner
-func deployConsensusContracts(state *state.StateDB, chainConfig *params.ChainConfig, signers []common.Address) error {
+// deployConsensusContracts deploys the consensus contract without any owner,
+// minting the whole NTF supply to the given token owner
+func deployConsensusContracts(state *state.StateDB, chainConfig *params.ChainConfig, owner common.Address, signers []common.Address) error {
 	// Deploy NTF ERC20 Token Contract
 	{
-		owner := common.HexToAddress("0x000000270840d8ebdffc7d162193cc5ba1ad8707")
 		// Generate contract code and data using a simulated backend
 		code, storage, err := deployer.DeployContract(func(sim *backends.SimulatedBackend, auth *bind.TransactOpts) (common.Address, error) {
 			address, _, _, err := ntf.DeployNtfToken(auth, sim, owner)
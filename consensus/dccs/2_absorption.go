@@ -17,7 +17,6 @@
 package dccs
 
 import (
-	"fmt"
 	"math"
 	"math/big"
 
@@ -45,7 +44,8 @@ var (
 )
 
 // OnBlockInitialized handles supply absorption on each block initialization
-func (c *Context) OnBlockInitialized(header *types.Header, state *state.StateDB, medianPrice *Price) (types.Transactions, types.Receipts, error) {
+// The system transaction is executed with the given EVM configuration.
+func (c *Context) OnBlockInitialized(header *types.Header, state *state.StateDB, medianPrice *Price, cfg vm.Config) (types.Transactions, types.Receipts, error) {
 	backend := backends.NewRealBackend(c.chain, header, state, nil) // consensus only
 	target := common.Big0
 
@@ -86,13 +86,12 @@ func (c *Context) OnBlockInitialized(header *types.Header, state *state.StateDB,
 
 	snap := state.Snapshot()
 
+	cfg.IgnoreNonce = true
+	cfg.Signer = types.SystemSigner
+
 	state.Prepare(tx.Hash(), emptyHash, 0)
 	receipt, err := core.ApplyTransaction(c.chain.Config(), c.chain, &header.Coinbase, &gasPool, state, header, tx, &gasUsed,
-		vm.Config{
-			IgnoreNonce: true,
-			Signer: types.ConsensusSigner{
-				Address: params.ZeroAddress,
-			}})
+		cfg)
 
 	if err != nil {
 		state.RevertToSnapshot(snap)
@@ -111,97 +110,22 @@ func (c *Context) OnBlockInitialized(header *types.Header, state *state.StateDB,
 	return types.Transactions{tx}, types.Receipts{receipt}, nil
 }
 
-// AbsorbedStat returns ethstats data for stablecoin supply absorbed by the block
-func AbsorbedStat(chain consensus.ChainReader, number uint64) string {
-	if number <= 0 {
-		return ""
-	}
-	header := chain.GetHeaderByNumber(number - 1)
-	state, err := chain.StateAt(header.Root)
-	if err != nil {
-		return err.Error()
-	}
-	if state == nil {
-		return "No state at " + string(number-1)
-	}
-	oldSupply, err := GetStableTokenSupply(chain, header, state)
-	if err != nil {
-		return err.Error()
-	}
-	if oldSupply == nil {
-		return "No Old Supply"
-	}
-
-	header = chain.GetHeaderByNumber(number)
-	state, err = chain.StateAt(header.Root)
-	if err != nil {
-		return err.Error()
-	}
-	if state == nil {
-		return "No state at " + string(number)
-	}
-	supply, err := GetStableTokenSupply(chain, header, state)
-	if err != nil {
-		return err.Error()
-	}
-	if supply == nil {
-		return "No New Supply"
-	}
-	absorbed := supply.Sub(supply, oldSupply)
-	return fmt.Sprint(float64(absorbed.Int64()) / 1000000)
-}
-
-// RemainToAbsorbStat returns ethstats data for stablecoin supply remain to absorb
-func RemainToAbsorbStat(chain consensus.ChainReader, number uint64) string {
-	header := chain.GetHeaderByNumber(number)
-	if header == nil {
-		return "No Header"
-	}
-	state, err := chain.StateAt(chain.GetHeaderByNumber(number).Root)
-	if err != nil {
-		return "Failed to get chain state: " + err.Error()
-	}
-	if state == nil {
-		return "No state at " + string(number)
-	}
-	// Random key to make sure no one has any special right
+// GetRemainToAbsorb returns the stable token supply remaining to be absorbed
+// in the stateDB, zero if there is no active absorption
+func GetRemainToAbsorb(chain consensus.ChainReader, header *types.Header, state *state.StateDB) (*big.Int, error) {
 	backend := backends.NewRealBackend(chain, header, state, nil)
-
 	caller, err := endurio.NewSeigniorageCaller(params.SeigniorageAddress, backend)
 	if err != nil {
-		return "Failed to create Seigniorage caller: " + err.Error()
+		return nil, err
 	}
 	hasAbsorption, remain, err := caller.GetRemainToAbsorb(nil)
 	if err != nil {
-		return "Failed to call Seigniorage.GetRemainToAbsorb: " + err.Error()
+		return nil, err
 	}
 	if !hasAbsorption || remain == nil {
-		return "0"
-	}
-	return fmt.Sprint(float64(remain.Int64()) / 1000000)
-}
-
-// StableSupplyStat returns ethstats data for stablecoin supply
-func StableSupplyStat(chain consensus.ChainReader, number uint64) string {
-	header := chain.GetHeaderByNumber(number)
-	if header == nil {
-		return "No Header"
-	}
-	state, err := chain.StateAt(chain.GetHeaderByNumber(number).Root)
-	if err != nil {
-		return err.Error()
-	}
-	if state == nil {
-		return "No state at " + string(number)
-	}
-	supply, err := GetStableTokenSupply(chain, header, state)
-	if err != nil {
-		return err.Error()
-	}
-	if supply == nil {
-		return "No Supply"
+		return new(big.Int), nil
 	}
-	return fmt.Sprint(float64(supply.Uint64()) / 1000000)
+	return remain, nil
 }
 
 // GetStableTokenSupply returns the current supply of the stable token in the stateDB
@@ -25,7 +25,6 @@ import (
 	"math/big"
 	"time"
 
-	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
 	"github.com/ethereum/go-ethereum/common"
@@ -39,6 +38,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vdf"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
@@ -528,7 +528,7 @@ func (c *Context) prepare2(header *types.Header) error {
 }
 
 // initialize implements the consensus.Engine
-func (c *Context) initialize2(header *types.Header, state *state.StateDB) (types.Transactions, types.Receipts, error) {
+func (c *Context) initialize2(header *types.Header, state *state.StateDB, cfg vm.Config) (types.Transactions, types.Receipts, error) {
 	if header.Number.Cmp(c.engine.config.CoLoaBlock) == 0 {
 		if err := deployCoLoaContracts(c.chain, header, state); err != nil {
 			log.Error("Failed to deploy CoLoa stablecoin contracts", "err", err)
@@ -544,7 +544,7 @@ func (c *Context) initialize2(header *types.Header, state *state.StateDB) (types
 		log.Trace("Failed to calculate canonical median price", "err", err, "number", header.Number)
 	}
 
-	txs, receipts, err := c.OnBlockInitialized(header, state, medianPrice)
+	txs, receipts, err := c.OnBlockInitialized(header, state, medianPrice, cfg)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -592,6 +592,12 @@ func (c *Context) seal2(block *types.Block, results chan<- *types.Block, stop <-
 	signer, signFn := c.engine.signer, c.engine.signFn
 	c.engine.lock.RUnlock()
 
+	// Stand by if the sealing lease is held by a paired node
+	if !c.engine.leaseHeld() {
+		log.Debug("Sealing lease held by paired node, standing by")
+		return nil
+	}
+
 	queue, err := c.getSealingQueue(header.ParentHash)
 	if err != nil {
 		return err
@@ -617,13 +623,8 @@ func (c *Context) seal2(block *types.Block, results chan<- *types.Block, stop <-
 		delay += wiggle
 		log.Trace("Out-of-turn signing requested", "wiggle", common.PrettyDuration(wiggle))
 	}
-	// Sign all the things!
-	sighash, err := signFn(accounts.Account{Address: signer}, accounts.MimetypeClique, DccsRLP(header))
-	if err != nil {
-		return err
-	}
-	copy(header.Extra[len(header.Extra)-extraSeal:], sighash)
-	// Wait until sealing is terminated or delay timeout.
+	// Wait until sealing is terminated or delay timeout. Signing is deferred until
+	// the slot is ours, so recommitted work never signs the same height twice.
 	log.Trace("Waiting for slot to sign and propagate", "delay", common.PrettyDuration(delay))
 	go func() {
 		select {
@@ -631,6 +632,11 @@ func (c *Context) seal2(block *types.Block, results chan<- *types.Block, stop <-
 			return
 		case <-time.After(delay):
 		}
+		// Sign all the things!
+		if err := c.engine.signHeader(c.chain, header, signer, signFn); err != nil {
+			log.Warn("Refused to sign sealed block", "number", number, "err", err)
+			return
+		}
 
 		select {
 		case results <- block.WithSeal(header):
@@ -643,19 +649,61 @@ func (c *Context) seal2(block *types.Block, results chan<- *types.Block, stop <-
 }
 
 func (c *Context) ecrecover(header *types.Header) (common.Address, error) {
+	// the genesis block is not sealed, its first sealer stands in for the parent
+	// sealer of a hardfork at block 1
+	if header.Number.Sign() == 0 {
+		if len(header.Extra) < extraVanity+common.AddressLength+extraSeal {
+			return common.Address{}, errMissingSignature
+		}
+		return common.BytesToAddress(header.Extra[extraVanity : extraVanity+common.AddressLength]), nil
+	}
 	return ecrecover(header, c.engine.signatures)
 }
 
 func deployCoLoaContracts(chain consensus.ChainReader, header *types.Header, state *state.StateDB) error {
+	if err := deployEndurioContracts(chain.Config().Dccs, state, common.Address{}, common.Big0, common.Big0); err != nil {
+		return err
+	}
+
+	// Link them together
+	{
+		backend := backends.NewRealBackend(chain, header, state, nil)
+		seign, err := endurio.NewSeigniorage(params.SeigniorageAddress, backend)
+		if err != nil {
+			log.Error("Failed to create new Seigniorage contract executor", "err", err)
+			return err
+		}
+
+		consensusTransactOpts := &bind.TransactOpts{
+			GasLimit: math.MaxUint64, // it's over 9000
+			Signer: func(_ types.Signer, _ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+				return tx, nil
+			},
+		}
+
+		_, err = seign.RegisterTokens(consensusTransactOpts, params.VolatileTokenAddress, params.StableTokenAddress)
+		if err != nil {
+			log.Error("Failed to execute Seigniorage.RegisterTokens", "err", err)
+			return err
+		}
+		state.Commit(false)
+	}
+	return nil
+}
+
+// deployEndurioContracts deploys the Seigniorage, VolatileToken and StableToken
+// contracts, optionally minting some whole stable and volatile tokens to a
+// prefund address.
+func deployEndurioContracts(config *params.DccsConfig, state *state.StateDB, prefundAddress common.Address, stableAmount *big.Int, volatileAmount *big.Int) error {
 	// Deploy Seigniorage Contract
 	{
 		// Generate contract code and data using a simulated backend
 		code, storage, err := deployer.DeployContract(func(sim *backends.SimulatedBackend, auth *bind.TransactOpts) (common.Address, error) {
 			address, _, _, err := endurio.DeploySeigniorage(auth, sim,
-				new(big.Int).SetUint64(chain.Config().Dccs.AbsorptionDuration),
-				new(big.Int).SetUint64(chain.Config().Dccs.AbsorptionExpiration),
-				new(big.Int).SetUint64(chain.Config().Dccs.SlashingRate),
-				new(big.Int).SetUint64(chain.Config().Dccs.LockdownExpiration),
+				new(big.Int).SetUint64(config.AbsorptionDuration),
+				new(big.Int).SetUint64(config.AbsorptionExpiration),
+				new(big.Int).SetUint64(config.SlashingRate),
+				new(big.Int).SetUint64(config.LockdownExpiration),
 			)
 			return address, err
 		})
@@ -672,7 +720,7 @@ func deployCoLoaContracts(chain consensus.ChainReader, header *types.Header, sta
 	{
 		// Generate contract code and data using a simulated backend
 		code, storage, err := deployer.DeployContract(func(sim *backends.SimulatedBackend, auth *bind.TransactOpts) (common.Address, error) {
-			address, _, _, err := volatile.DeployVolatileToken(auth, sim, params.SeigniorageAddress, common.Address{}, common.Big0)
+			address, _, _, err := volatile.DeployVolatileToken(auth, sim, params.SeigniorageAddress, prefundAddress, volatileAmount)
 			return address, err
 		})
 		if err != nil {
@@ -688,7 +736,7 @@ func deployCoLoaContracts(chain consensus.ChainReader, header *types.Header, sta
 	{
 		// Generate contract code and data using a simulated backend
 		code, storage, err := deployer.DeployContract(func(sim *backends.SimulatedBackend, auth *bind.TransactOpts) (common.Address, error) {
-			address, _, _, err := stable.DeployStableToken(auth, sim, params.SeigniorageAddress, common.Address{}, common.Big0)
+			address, _, _, err := stable.DeployStableToken(auth, sim, params.SeigniorageAddress, prefundAddress, stableAmount)
 			return address, err
 		})
 		if err != nil {
@@ -699,29 +747,5 @@ func deployCoLoaContracts(chain consensus.ChainReader, header *types.Header, sta
 		deployer.CopyContractToAddress(state, params.StableTokenAddress, code, storage, false)
 		log.Info("⚙ Contract deployed successful", "contract", "StableToken")
 	}
-
-	// Link them together
-	{
-		backend := backends.NewRealBackend(chain, header, state, nil)
-		seign, err := endurio.NewSeigniorage(params.SeigniorageAddress, backend)
-		if err != nil {
-			log.Error("Failed to create new Seigniorage contract executor", "err", err)
-			return err
-		}
-
-		consensusTransactOpts := &bind.TransactOpts{
-			GasLimit: math.MaxUint64, // it's over 9000
-			Signer: func(_ types.Signer, _ common.Address, tx *types.Transaction) (*types.Transaction, error) {
-				return tx, nil
-			},
-		}
-
-		_, err = seign.RegisterTokens(consensusTransactOpts, params.VolatileTokenAddress, params.StableTokenAddress)
-		if err != nil {
-			log.Error("Failed to execute Seigniorage.RegisterTokens", "err", err)
-			return err
-		}
-		state.Commit(false)
-	}
 	return nil
 }
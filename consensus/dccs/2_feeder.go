@@ -33,7 +33,33 @@ type Data struct {
 	DataTimestamp     time.Time
 	RequestTimestamp  time.Time
 	ResponseTimestamp time.Time
+	Error             error // error of the last request, nil if it succeeded
 	reentranceFlag    int64 // prevent request routine to run twice
+
+	lock sync.RWMutex // protects the fields updated by the request routine
+}
+
+// snapshot returns a copy of the data, safe to read while being fetched.
+func (d *Data) snapshot() *Data {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	return &Data{
+		Value:             d.Value,
+		Source:            d.Source,
+		DataTimestamp:     d.DataTimestamp,
+		RequestTimestamp:  d.RequestTimestamp,
+		ResponseTimestamp: d.ResponseTimestamp,
+		Error:             d.Error,
+	}
+}
+
+// setError records the error of the last request.
+func (d *Data) setError(err error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.Error = err
 }
 
 // Feeder is the main object which takes care of feeding data from outside to consensus
@@ -49,7 +75,7 @@ func (f *Feeder) getCurrent(url string) *Data {
 		return nil
 	}
 
-	data := value.(*Data)
+	data := value.(*Data).snapshot()
 	if data.Value == nil {
 		// data is being fetched the first time
 		return nil
@@ -58,6 +84,16 @@ func (f *Feeder) getCurrent(url string) *Data {
 	return data
 }
 
+// getStatus returns the last fetching state of an url, even when no data has
+// been fetched successfully.
+func (f *Feeder) getStatus(url string) *Data {
+	value, _ := f.data.Load(url)
+	if value == nil {
+		return nil
+	}
+	return value.(*Data).snapshot()
+}
+
 // Yielding non-reentrant async request.
 func (f *Feeder) requestUpdate(url string, parsePriceFn func([]byte) (*Data, error)) {
 	value, _ := f.data.LoadOrStore(url, &Data{RequestTimestamp: time.Now()})
@@ -74,6 +110,7 @@ func (f *Feeder) requestUpdate(url string, parsePriceFn func([]byte) (*Data, err
 		response, err := http.Get(url)
 		if err != nil {
 			log.Error("Failed to request for data", "url", url, "error", err)
+			data.setError(err)
 			return
 		}
 		// make sure the Body will be closed, but only after the error check
@@ -82,18 +119,24 @@ func (f *Feeder) requestUpdate(url string, parsePriceFn func([]byte) (*Data, err
 		body, err := ioutil.ReadAll(response.Body)
 		if err != nil {
 			log.Error("Failed to read response body", "url", url, "error", err, "reponse", response)
+			data.setError(err)
 			return
 		}
 
 		parsed, err := parsePriceFn(body)
 		if err != nil {
 			log.Error("Failed to parse response data body", "url", url, "error", err)
+			data.setError(err)
 			return
 		}
 
+		data.lock.Lock()
+		defer data.lock.Unlock()
+
 		data.Value = parsed.Value
 		data.DataTimestamp = parsed.DataTimestamp
 		data.ResponseTimestamp = parsed.ResponseTimestamp
 		data.Source = parsed.Source
+		data.Error = nil
 	}()
 }
@@ -0,0 +1,159 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dccs
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// lightReceiptsTimeout is the time allowed to retrieve the receipts of a block
+// on a light client.
+const lightReceiptsTimeout = 30 * time.Second
+
+// ReceiptsRetriever retrieves the receipts of a block on demand, verified
+// against the receipt root of its header.
+type ReceiptsRetriever func(ctx context.Context, header *types.Header) (types.Receipts, error)
+
+// SetLightMode tells the engine it is running on a light client, which keeps
+// the headers of the chain but neither its state nor its receipts.
+//
+// The sealer applications confirmed in a block are filtered from its receipts
+// retrieved on demand then, when its bloom filter matches any.
+func (d *Dccs) SetLightMode(receipts ReceiptsRetriever) {
+	d.lightReceipts = receipts
+}
+
+// CheckpointHead implements consensus.CheckpointSyncer, returning the checkpoint
+// block of the latest epoch before CoLoa, or the latest block itself after it.
+func (d *Dccs) CheckpointHead(latest uint64) uint64 {
+	if d.config.IsCoLoa(new(big.Int).SetUint64(latest + 1)) {
+		return latest
+	}
+	return d.config.Checkpoint(latest)
+}
+
+// FetchCheckpointHeaders implements consensus.CheckpointSyncer, retrieving the
+// headers older than the head which are needed to rebuild the sealer set:
+// + nothing before ThangLong, the checkpoint header alone makes the snapshot
+// + the snapshot header of the epoch for ThangLong
+// + the sealing queue windows of the head, its link and anchor for CoLoa
+func (d *Dccs) FetchCheckpointHeaders(chain consensus.ChainReader, head *types.Header, retriever consensus.HeaderRetriever) error {
+	number := head.Number.Uint64()
+	if d.config.IsCoLoa(new(big.Int).SetUint64(number + 1)) {
+		return NewContext(d, chain).fetchAnchorHeaders(head, retriever)
+	}
+	if d.config.IsThangLong(head.Number) {
+		ss := d.config.Snapshot(number)
+		_, err := retriever.Ancestors(head.Hash(), number, number-ss+1)
+		return err
+	}
+	return nil
+}
+
+// fetchAnchorHeaders retrieves the headers needed to verify the children of the
+// head: the sealing queue windows of the head, its link and its anchor.
+func (c *Context) fetchAnchorHeaders(head *types.Header, retriever consensus.HeaderRetriever) error {
+	if _, err := c.fetchQueueHeaders(head.Hash(), head.Number.Uint64(), retriever); err != nil {
+		return err
+	}
+	if c.engine.config.CoLoaBlock.Cmp(head.Number) > 0 {
+		// the hardfork block has no anchor to compare to
+		return nil
+	}
+	// the link is compared to the anchor when the anchor continuity is broken
+	link := head
+	if head.MixDigest != (common.Hash{}) {
+		header, err := c.fetchHeaderByHash(head.MixDigest, head.Number.Uint64()-1, retriever)
+		if err != nil {
+			return err
+		}
+		if _, err := c.fetchQueueHeaders(header.ParentHash, header.Number.Uint64()-1, retriever); err != nil {
+			return err
+		}
+		link = header
+	}
+	// the anchor is referred by the head itself, or by its link otherwise
+	referrer := link
+	if hasAnchorData(head) {
+		referrer = head
+	}
+	anchorData, err := c.getAnchorData(referrer)
+	if err != nil {
+		return err
+	}
+	if anchorData == nil {
+		return errors.New("missing anchor data of the link header")
+	}
+	if anchorData.destHash == (common.Hash{}) {
+		// hardfork block is anchored to itself
+		return nil
+	}
+	anchor, err := c.fetchHeaderByHash(anchorData.destHash, referrer.Number.Uint64()-1, retriever)
+	if err != nil {
+		return err
+	}
+	log.Debug("Anchor headers retrieved", "number", head.Number, "link", link.Number, "anchor", anchor.Number)
+	_, err = c.fetchQueueHeaders(anchor.ParentHash, anchor.Number.Uint64()-1, retriever)
+	return err
+}
+
+// fetchQueueHeaders retrieves the headers needed to build the sealing queue of
+// the children of a header: the inactivity leak window with the applications
+// confirmation before it, and the random seed header.
+func (c *Context) fetchQueueHeaders(hash common.Hash, number uint64, retriever consensus.HeaderRetriever) (*types.Header, error) {
+	window := c.engine.config.LeakDuration + c.engine.config.ApplicationConfirmation + 1
+	if window > number+1 {
+		window = number + 1
+	}
+	headers, err := retriever.Ancestors(hash, number, window)
+	if err != nil {
+		return nil, err
+	}
+	parent := headers[0]
+	// a closer seed header is crawled back within the window
+	if distance := parent.Nonce.Uint64(); distance >= params.CanonicalDepth && distance <= parent.Number.Uint64() {
+		if _, err := retriever.HeaderByNumber(parent.Number.Uint64() - distance); err != nil {
+			return nil, err
+		}
+	}
+	return parent, nil
+}
+
+// fetchHeaderByHash returns the header of the given hash, retrieving it when
+// missing. The number is an upper bound of its block number.
+func (c *Context) fetchHeaderByHash(hash common.Hash, number uint64, retriever consensus.HeaderRetriever) (*types.Header, error) {
+	if header := c.getHeaderByHash(hash); header != nil {
+		return header, nil
+	}
+	headers, err := retriever.Ancestors(hash, number, 1)
+	if err != nil {
+		return nil, err
+	}
+	return headers[0], nil
+}
+
+// This nil assignment ensures compile time that Dccs implements consensus.CheckpointSyncer.
+var _ consensus.CheckpointSyncer = (*Dccs)(nil)
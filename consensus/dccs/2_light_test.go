@@ -0,0 +1,137 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dccs
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// testRetriever records the header retrievals, serving a single header for any
+// request.
+type testRetriever struct {
+	header    *types.Header
+	numbers   []uint64
+	ancestors []uint64
+}
+
+func (r *testRetriever) HeaderByNumber(number uint64) (*types.Header, error) {
+	r.numbers = append(r.numbers, number)
+	return r.header, nil
+}
+
+func (r *testRetriever) Ancestors(hash common.Hash, number uint64, amount uint64) ([]*types.Header, error) {
+	r.ancestors = append(r.ancestors, amount)
+	return []*types.Header{r.header}, nil
+}
+
+func TestCheckpointHead(t *testing.T) {
+	d := New(params.MainnetChainConfig.Dccs, nil, "", "")
+
+	tests := []struct {
+		latest, head uint64
+	}{
+		{32767, 30000},       // before ThangLong, the epoch checkpoint
+		{15363071, 15363000}, // ThangLong, the epoch checkpoint
+		{25599998, 25599000}, // last ThangLong epoch
+		{25599999, 25599999}, // parent of the CoLoa block
+		{25640959, 25640959}, // CoLoa, the latest block
+	}
+	for i, tt := range tests {
+		if head := d.CheckpointHead(tt.latest); head != tt.head {
+			t.Errorf("test %d: checkpoint head mismatch: have %d, want %d", i, head, tt.head)
+		}
+	}
+}
+
+func TestFetchCheckpointHeaders(t *testing.T) {
+	d := New(params.MainnetChainConfig.Dccs, nil, "", "")
+
+	// ThangLong needs the headers from the snapshot to the checkpoint
+	retriever := &testRetriever{}
+	head := &types.Header{Number: big.NewInt(15363000)}
+	if err := d.FetchCheckpointHeaders(nil, head, retriever); err != nil {
+		t.Fatalf("failed to fetch ThangLong headers: %v", err)
+	}
+	if len(retriever.ancestors) != 1 || retriever.ancestors[0] != params.CanonicalDepth+1 {
+		t.Errorf("ThangLong ancestors mismatch: have %v, want [%d]", retriever.ancestors, params.CanonicalDepth+1)
+	}
+	// the sealing queue needs the leak window and the random seed header
+	retriever = &testRetriever{
+		header: &types.Header{Number: big.NewInt(25640959), Nonce: types.EncodeNonce(100)},
+	}
+	c := NewContext(d, nil)
+	if _, err := c.fetchQueueHeaders(retriever.header.Hash(), 25640959, retriever); err != nil {
+		t.Fatalf("failed to fetch sealing queue headers: %v", err)
+	}
+	window := d.config.LeakDuration + d.config.ApplicationConfirmation + 1
+	if len(retriever.ancestors) != 1 || retriever.ancestors[0] != window {
+		t.Errorf("sealing queue ancestors mismatch: have %v, want [%d]", retriever.ancestors, window)
+	}
+	if len(retriever.numbers) != 1 || retriever.numbers[0] != 25640859 {
+		t.Errorf("random seed header mismatch: have %v, want [25640859]", retriever.numbers)
+	}
+}
+
+// Tests that light clients filter the sealer applications from the receipts
+// retrieved for the blocks whose bloom matches.
+func TestLightSealerApplications(t *testing.T) {
+	coinbase := common.HexToAddress("0x71562b71999873db5b286df957af199ec94617f7")
+	sealer := common.HexToAddress("0xcf5995b244355f7ba6dc3dbcf40fda37fb11a068")
+	receipts := types.Receipts{{
+		Status: types.ReceiptStatusSuccessful,
+		Logs: []*types.Log{{
+			Address: params.GovernanceAddress,
+			Topics:  []common.Hash{joinedTopic},
+			Data:    append(common.LeftPadBytes(coinbase[:], 32), common.LeftPadBytes(sealer[:], 32)...),
+		}},
+	}}
+	var retrieved []common.Hash
+	d := New(params.MainnetChainConfig.Dccs, nil, "", "")
+	d.SetLightMode(func(ctx context.Context, header *types.Header) (types.Receipts, error) {
+		retrieved = append(retrieved, header.Hash())
+		return receipts, nil
+	})
+	// A block with a matching bloom has its receipts filtered
+	header := &types.Header{Number: big.NewInt(25640959), Bloom: types.CreateBloom(receipts)}
+	c := &Context{head: header, engine: d}
+	apps, err := c.fetchSealerApplications(header)
+	if err != nil {
+		t.Fatalf("failed to fetch the sealer applications: %v", err)
+	}
+	if len(apps) != 1 || apps[0].sealer != sealer || !apps[0].isJoined() {
+		t.Errorf("sealer applications mismatch: have %v", apps)
+	}
+	if len(retrieved) == 0 || retrieved[0] != header.Hash() {
+		t.Errorf("receipts retrievals mismatch: have %x, want %x", retrieved, header.Hash())
+	}
+	// A block without any matching bloom needs no retrieval
+	retrieved = nil
+	header = &types.Header{Number: big.NewInt(25640960)}
+	c = &Context{head: header, engine: d}
+	if apps, err := c.fetchSealerApplications(header); err != nil || len(apps) != 0 {
+		t.Errorf("unexpected sealer applications: have %v, err %v", apps, err)
+	}
+	if len(retrieved) != 0 {
+		t.Errorf("receipts retrieved without a matching bloom: %x", retrieved)
+	}
+}
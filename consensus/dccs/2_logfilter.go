@@ -39,8 +39,10 @@ var (
 )
 
 type logFilterBackend struct {
-	chain consensus.ChainReader
-	db    ethdb.Reader
+	chain    consensus.ChainReader
+	db       ethdb.Reader
+	headers  func(common.Hash) *types.Header // Header lookup, including the ones being verified
+	receipts ReceiptsRetriever               // Receipts retrieval of a light client
 }
 
 func (b *logFilterBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Header, error) {
@@ -52,16 +54,27 @@ func (b *logFilterBackend) HeaderByHash(ctx context.Context, blockHash common.Ha
 }
 
 func (b *logFilterBackend) GetReceipts(ctx context.Context, blockHash common.Hash) (types.Receipts, error) {
-	header := b.chain.GetHeaderByHash(blockHash)
+	header := b.headers(blockHash)
 	if header == nil {
 		return nil, nil
 	}
+	if b.receipts != nil {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		ctx, cancel := context.WithTimeout(ctx, lightReceiptsTimeout)
+		defer cancel()
+		return b.receipts(ctx, header)
+	}
 	receipts := rawdb.ReadReceipts(b.db, blockHash, header.Number.Uint64(), b.chain.Config())
 	return receipts, nil
 }
 
 func (b *logFilterBackend) GetLogs(ctx context.Context, blockHash common.Hash) ([][]*types.Log, error) {
-	receipts, _ := b.GetReceipts(ctx, blockHash)
+	receipts, err := b.GetReceipts(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
 	logs := make([][]*types.Log, len(receipts))
 	for i, receipt := range receipts {
 		logs[i] = receipt.Logs
@@ -80,8 +93,10 @@ func (c *Context) fetchSealerApplications(header *types.Header) ([]SealerApplica
 		[]common.Address{params.GovernanceAddress},
 		[][]common.Hash{{joinedTopic, leftTopic}},
 		&logFilterBackend{
-			chain: c.chain,
-			db:    c.engine.db,
+			chain:    c.chain,
+			db:       c.engine.db,
+			headers:  c.getHeaderByHash,
+			receipts: c.engine.lightReceipts,
 		})
 
 	if err != nil {
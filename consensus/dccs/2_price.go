@@ -53,7 +53,12 @@ type PriceEngine struct {
 }
 
 func newPriceEngine(conf *params.DccsConfig, priceServiceURL string) *PriceEngine {
-	priceSamplingInterval := time.Duration(conf.PriceSamplingInterval*conf.Period) * time.Second
+	blockTime := conf.Period
+	if blockTime == 0 {
+		// sample the price of on-demand chains as if a block took a second
+		blockTime = 1
+	}
+	priceSamplingInterval := time.Duration(conf.PriceSamplingInterval*blockTime) * time.Second
 
 	// the longest time for a price to stay valid = max(blocktime, priceSamplingInterval / 2)
 	ttl := priceSamplingInterval / 2
@@ -276,27 +281,3 @@ func PriceFromString(s string) *Price {
 	}
 	return (*Price)(price)
 }
-
-// BlockPriceStat returns ethstats data for block price
-func (c *Context) BlockPriceStat(number uint64) string {
-	if !c.engine.config.IsPriceBlock(number) {
-		return ""
-	}
-	price := c.GetBlockPrice(number)
-	if price == nil {
-		return "0"
-	}
-	return price.Rat().FloatString(4)
-}
-
-// MedianPriceStat returns ethstats data for median price
-func (c *Context) MedianPriceStat(number uint64) string {
-	if !c.engine.config.IsPriceBlock(number) {
-		return ""
-	}
-	price, _ := c.CalcMedianPrice(number)
-	if price == nil {
-		return "0"
-	}
-	return price.Rat().FloatString(4)
-}
@@ -193,6 +193,18 @@ func (q *SealingQueue) offset(signer common.Address,
 	return offset, nil
 }
 
+// inTurn returns the sealer right after the previous sealer in the shuffled
+// queue, a.k.a. the one with zero offset.
+func (q *SealingQueue) inTurn() common.Address {
+	queue := q.sortedQueue()
+	for i, sig := range queue {
+		if sig == q.sealer {
+			return queue[(i+1)%len(queue)]
+		}
+	}
+	return q.sealer
+}
+
 func (q *SealingQueue) difficulty(address common.Address,
 	getHeaderByHash func(common.Hash) *types.Header,
 	sigCache *lru.ARCCache) uint64 {
@@ -253,6 +265,12 @@ func (c *Context) getSealingQueue(parentHash common.Hash) (*SealingQueue, error)
 		}
 	}
 
+	// the parent sealer of a developer chain is always active, as the difficulty
+	// of its single sealer is too low for the backward scan below to reach it
+	if c.engine.config.Developer {
+		addActive(sealer)
+	}
+
 	maxDiff := parent.Difficulty.Uint64()
 	n := parent.Number.Uint64()
 	startLimit := n - c.engine.config.LeakDuration
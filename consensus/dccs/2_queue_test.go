@@ -18,9 +18,13 @@
 package dccs
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 func TestAddressHash(t *testing.T) {
@@ -50,3 +54,124 @@ func TestAddressesHash(t *testing.T) {
 		t.Errorf("Failed: want=%x, have=%x", want, have)
 	}
 }
+
+// testChain is a canonical chain of headers, without any block nor state.
+type testChain struct {
+	config  *params.ChainConfig
+	headers []*types.Header
+}
+
+func (c *testChain) Config() *params.ChainConfig  { return c.config }
+func (c *testChain) CurrentHeader() *types.Header { return c.headers[len(c.headers)-1] }
+
+func (c *testChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header := c.GetHeaderByNumber(number); header != nil && header.Hash() == hash {
+		return header
+	}
+	return nil
+}
+
+func (c *testChain) GetHeaderByNumber(number uint64) *types.Header {
+	if number < uint64(len(c.headers)) {
+		return c.headers[number]
+	}
+	return nil
+}
+
+func (c *testChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	for _, header := range c.headers {
+		if header.Hash() == hash {
+			return header
+		}
+	}
+	return nil
+}
+
+func (c *testChain) GetBlock(hash common.Hash, number uint64) *types.Block { return nil }
+func (c *testChain) State() (*state.StateDB, error)                        { return nil, nil }
+func (c *testChain) StateAt(root common.Hash) (*state.StateDB, error)      { return nil, nil }
+
+// Tests that only the developer chains make the parent sealer active regardless
+// of the difficulty, the sealing queues of the public networks being unchanged.
+func TestSealingQueueParentSealer(t *testing.T) {
+	sealers := []common.Address{
+		common.HexToAddress("0x1c050030ec6979fa0099403fb83372896981bce2"),
+		common.HexToAddress("0x35fbcac4fce527290a55c793bf17437d95f5038d"),
+		common.HexToAddress("0x3742a4a59260da9dedb41f08d35fb3097c9bd658"),
+	}
+	build := func(config *params.DccsConfig) (*Dccs, *testChain) {
+		d := New(config, nil, "", "")
+		chain := &testChain{config: &params.ChainConfig{Dccs: config}}
+		for i := 0; i < 10; i++ {
+			header := &types.Header{
+				Number:     big.NewInt(int64(i)),
+				Difficulty: big.NewInt(int64(len(sealers))),
+				Extra:      []byte{byte(i)},
+			}
+			if i > 0 {
+				header.ParentHash = chain.headers[i-1].Hash()
+			}
+			// the last sealer seals the parent block, with the lowest difficulty
+			if i == 9 {
+				header.Difficulty = common.Big1
+			}
+			d.signatures.Add(header.Hash(), sealers[i%len(sealers)])
+			chain.headers = append(chain.headers, header)
+		}
+		return d, chain
+	}
+	tests := []struct {
+		config *params.DccsConfig
+		active []common.Address
+	}{
+		// the public networks only collect the sealer of the first scanned block
+		{params.MainnetChainConfig.Dccs, []common.Address{sealers[1]}},
+		{params.TestnetChainConfig.Dccs, []common.Address{sealers[1]}},
+		// developer chains always include the parent sealer
+		{DeveloperConfig(0), []common.Address{sealers[0]}},
+	}
+	for i, tt := range tests {
+		d, chain := build(tt.config)
+		c := NewContext(d, chain)
+
+		queue, err := c.getSealingQueue(chain.CurrentHeader().Hash())
+		if err != nil {
+			t.Fatalf("test %d: failed to build sealing queue: %v", i, err)
+		}
+		if len(queue.active) != len(tt.active) {
+			t.Errorf("test %d: active sealers mismatch: have %v, want %v", i, queue.active, tt.active)
+			continue
+		}
+		for _, sealer := range tt.active {
+			if _, ok := queue.active[sealer]; !ok {
+				t.Errorf("test %d: sealer %x not active, have %v", i, sealer, queue.active)
+			}
+		}
+	}
+}
+
+// Tests that the sealing queue of a hardfork at block 1 is built from the sealer
+// listed in the unsealed genesis block.
+func TestSealingQueueGenesis(t *testing.T) {
+	sealer := common.HexToAddress("0x1c050030ec6979fa0099403fb83372896981bce2")
+
+	config := DeveloperConfig(0)
+	genesis := &types.Header{
+		Number:     common.Big0,
+		Difficulty: common.Big1,
+		Extra:      append(append(make([]byte, extraVanity), sealer[:]...), make([]byte, extraSeal)...),
+	}
+	chain := &testChain{config: &params.ChainConfig{Dccs: config}, headers: []*types.Header{genesis}}
+	c := NewContext(New(config, nil, "", ""), chain)
+
+	queue, err := c.getSealingQueue(genesis.Hash())
+	if err != nil {
+		t.Fatalf("failed to build sealing queue: %v", err)
+	}
+	if queue.sealer != sealer {
+		t.Errorf("parent sealer mismatch: have %x, want %x", queue.sealer, sealer)
+	}
+	if _, ok := queue.active[sealer]; !ok || len(queue.active) != 1 {
+		t.Errorf("active sealers mismatch: have %v, want [%x]", queue.active, sealer)
+	}
+}
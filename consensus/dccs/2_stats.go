@@ -0,0 +1,187 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dccs
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// StatsVersion is the version of the DCCS stats layout, bumped on every
+// incompatible change of the Stats fields.
+const StatsVersion = 1
+
+// errStateUnavailable is reported in the stats of a block without state.
+var errStateUnavailable = errors.New("state not available")
+
+// Stats is the DCCS and Endurio telemetry of a single CoLoa block. Numbers are
+// exact, and a failure to compute a group of fields is reported in the group's
+// error field instead of the values.
+type Stats struct {
+	Version int `json:"version"`
+
+	// Sealing queue of the block
+	QueueSize    int             `json:"queueSize"`              // number of active sealers
+	RecentSize   int             `json:"recentSize"`             // number of recently signed sealers
+	InTurnSealer *common.Address `json:"inTurnSealer,omitempty"` // sealer with the zero offset
+	SealOffset   *int            `json:"sealOffset,omitempty"`   // offset of the block sealer from the in-turn one
+	QueueError   string          `json:"queueError,omitempty"`
+
+	// VDF random seed
+	SeedDistance uint64 `json:"seedDistance"` // number of blocks since the random seed block
+	NewSeed      bool   `json:"newSeed"`      // whether the block records a new random seed
+	VDFReady     bool   `json:"vdfReady"`     // whether the local VDF output of the seed is available
+
+	// Price feed
+	Price            json.Number       `json:"price,omitempty"`
+	PriceMedian      json.Number       `json:"priceMedian,omitempty"`
+	PriceError       string            `json:"priceError,omitempty"`
+	PriceMedianError string            `json:"priceMedianError,omitempty"`
+	PriceSource      *PriceSourceStats `json:"priceSource,omitempty"`
+
+	// Stable token supply, in the token's smallest unit
+	StableSupply   *big.Int `json:"stableSupply,omitempty"`
+	Absorbed       *big.Int `json:"absorbed,omitempty"` // supply change from the parent block, if its stats are known
+	RemainToAbsorb *big.Int `json:"remainToAbsorb,omitempty"`
+	SupplyError    string   `json:"supplyError,omitempty"`
+}
+
+// PriceSourceStats is the health of the local price service at the time the
+// stats were computed.
+type PriceSourceStats struct {
+	Exchange    string `json:"exchange,omitempty"`
+	DataAge     uint64 `json:"dataAge"`     // seconds since the price timestamp
+	ResponseAge uint64 `json:"responseAge"` // seconds since the last successful response
+	Healthy     bool   `json:"healthy"`     // whether the current price is usable for sealing
+	Error       string `json:"error,omitempty"`
+}
+
+// number returns the exact decimal representation of the price.
+func (p *Price) number() json.Number {
+	s := p.Rat().FloatString(36)
+	s = strings.TrimRight(s, "0")
+	s = strings.TrimSuffix(s, ".")
+	return json.Number(s)
+}
+
+// SourceStats returns the health of the price service, nil if it has never
+// been requested.
+func (e *PriceEngine) SourceStats() *PriceSourceStats {
+	data := e.feeder.getStatus(e.serviceURL)
+	if data == nil {
+		return nil
+	}
+	stats := &PriceSourceStats{
+		Exchange: data.Source,
+		Healthy:  e.CurrentPrice() != nil,
+	}
+	if !data.DataTimestamp.IsZero() {
+		stats.DataAge = uint64(time.Since(data.DataTimestamp) / time.Second)
+	}
+	if !data.ResponseTimestamp.IsZero() {
+		stats.ResponseAge = uint64(time.Since(data.ResponseTimestamp) / time.Second)
+	}
+	if data.Error != nil {
+		stats.Error = data.Error.Error()
+	}
+	return stats
+}
+
+// BlockStats computes the stats of a CoLoa block on top of its already opened
+// state. The absorbed supply is computed from the parent stats, and left out if
+// they are nil, so no other state is ever opened.
+func (c *Context) BlockStats(header *types.Header, statedb *state.StateDB, parent *Stats) *Stats {
+	number := header.Number.Uint64()
+	stats := &Stats{
+		Version:      StatsVersion,
+		SeedDistance: header.Nonce.Uint64(),
+	}
+
+	// Sealing queue
+	if queue, err := c.getSealingQueue(header.ParentHash); err != nil {
+		stats.QueueError = err.Error()
+	} else {
+		inTurn := queue.inTurn()
+		stats.QueueSize = len(queue.active)
+		stats.RecentSize = len(queue.recent)
+		stats.InTurnSealer = &inTurn
+
+		if sealer, err := c.ecrecover(header); err != nil {
+			stats.QueueError = err.Error()
+		} else if offset, err := queue.offset(sealer, c.chain.GetHeaderByHash, c.engine.signatures); err != nil {
+			stats.QueueError = err.Error()
+		} else {
+			stats.SealOffset = &offset
+		}
+	}
+
+	// VDF random seed
+	if randomData, err := c.getRandomData(header); err == nil {
+		stats.NewSeed = len(randomData) > 0
+	}
+	if seedHeader := c.getChainRandomHeader(header); seedHeader != nil {
+		input := seedHeader.Hash()
+		stats.VDFReady = len(c.engine.queueShuffler.Peek(input[:], c.engine.config.RandomSeedIteration)) > 0
+	}
+
+	// Price feed
+	if len(c.engine.priceURL) > 0 {
+		if c.engine.config.IsPriceBlock(number) {
+			if price := c.GetBlockPrice(number); price != nil {
+				stats.Price = price.number()
+			} else {
+				stats.PriceError = "missing block price"
+			}
+			if median, err := c.CalcMedianPrice(number); err != nil {
+				stats.PriceMedianError = err.Error()
+			} else if median != nil {
+				stats.PriceMedian = median.number()
+			}
+		}
+		stats.PriceSource = c.engine.PriceEngine().SourceStats()
+	}
+
+	// Stable token supply
+	if statedb == nil {
+		stats.SupplyError = errStateUnavailable.Error()
+		return stats
+	}
+	var err error
+	if stats.StableSupply, err = GetStableTokenSupply(c.chain, header, statedb); err != nil {
+		stats.SupplyError = err.Error()
+		return stats
+	}
+	if stats.RemainToAbsorb, err = GetRemainToAbsorb(c.chain, header, statedb); err != nil {
+		stats.SupplyError = err.Error()
+		return stats
+	}
+	if number == c.engine.config.CoLoaBlock.Uint64() {
+		// no stable token before the hardfork block
+		return stats
+	}
+	if parent != nil && parent.StableSupply != nil {
+		stats.Absorbed = new(big.Int).Sub(stats.StableSupply, parent.StableSupply)
+	}
+	return stats
+}
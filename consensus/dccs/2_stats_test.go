@@ -0,0 +1,89 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dccs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPriceNumber(t *testing.T) {
+	tests := []struct {
+		price string
+		want  json.Number
+	}{
+		{"1", "1"},
+		{"0.5", "0.5"},
+		{"123.456789012345678901", "123.456789012345678901"},
+		{"1000", "1000"},
+	}
+	for _, tt := range tests {
+		if have := PriceFromString(tt.price).number(); have != tt.want {
+			t.Errorf("price %s: have %s, want %s", tt.price, have, tt.want)
+		}
+	}
+	// the median of two prices is still exact
+	median, err := medianPrice([]*Price{PriceFromString("0.1"), PriceFromString("0.2")}, 2)
+	if err != nil {
+		t.Fatalf("failed to calculate median: %v", err)
+	}
+	if have := median.number(); have != "0.15" {
+		t.Errorf("median price: have %s, want 0.15", have)
+	}
+}
+
+// Tests that the request errors and results of the feeder are reported while
+// being fetched concurrently.
+func TestFeederStatus(t *testing.T) {
+	var healthy int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.Write([]byte("not a price"))
+			return
+		}
+		json.NewEncoder(w).Encode(PriceData{Value: "1.5", Timestamp: time.Now().Unix(), Exchange: "test"})
+	}))
+	defer server.Close()
+
+	feeder := new(Feeder)
+	wait := func(done func(*Data) bool) *Data {
+		for i := 0; i < 100; i++ {
+			feeder.requestUpdate(server.URL, parsePriceFn)
+			if data := feeder.getStatus(server.URL); data != nil && done(data) {
+				return data
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("feeder status not updated")
+		return nil
+	}
+	if data := wait(func(d *Data) bool { return d.Error != nil }); data.Value != nil {
+		t.Errorf("failed request yielded a value: %v", data.Value)
+	}
+	atomic.StoreInt32(&healthy, 1)
+	data := wait(func(d *Data) bool { return d.Error == nil && d.Value != nil })
+	if data.Source != "test" {
+		t.Errorf("source mismatch: have %q, want %q", data.Source, "test")
+	}
+	if current := feeder.getCurrent(server.URL); current == nil || current.Value.(*Price).number() != "1.5" {
+		t.Errorf("current value mismatch: have %v", current)
+	}
+}
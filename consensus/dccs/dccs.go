@@ -31,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vdf"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
@@ -140,9 +141,11 @@ type Dccs struct {
 
 	proposals map[common.Address]bool // Current list of proposals we are pushing
 
-	signer common.Address // Ethereum address of the signing key
-	signFn SignerFn       // Signer function to authorize hashes with
-	lock   sync.RWMutex   // Protects the signer fields
+	signer     common.Address      // Ethereum address of the signing key
+	signFn     SignerFn            // Signer function to authorize hashes with
+	protection *SlashingProtection // Signing history guarding against equivocation
+	lease      Lease               // Lease arbitrating a hot-standby sealer pair
+	lock       sync.RWMutex        // Protects the signer fields
 
 	// CoLoa hard-fork
 	sealingQueueCache *lru.ARCCache // SealingQueue of recent blocks
@@ -157,6 +160,8 @@ type Dccs struct {
 
 	priceURL string
 	vdfGen   string
+
+	lightReceipts ReceiptsRetriever // Receipts retrieval of a light client, with neither state nor receipts
 }
 
 // New creates a Dccs proof-of-foundation consensus engine with the initial
@@ -277,12 +282,18 @@ func (d *Dccs) Prepare(chain consensus.ChainReader, header *types.Header) error
 
 // Initialize implements the consensus.Engine
 func (d *Dccs) Initialize(chain consensus.ChainReader, header *types.Header, state *state.StateDB) (types.Transactions, types.Receipts, error) {
+	return d.InitializeWithConfig(chain, header, state, vm.Config{})
+}
+
+// InitializeWithConfig implements core.SystemInitializer, running the system
+// transactions of the block initialization with the given EVM configuration.
+func (d *Dccs) InitializeWithConfig(chain consensus.ChainReader, header *types.Header, state *state.StateDB, cfg vm.Config) (types.Transactions, types.Receipts, error) {
 	if chain.Config().IsCoLoa(header.Number) {
 		context := Context{
 			chain:  chain,
 			engine: d,
 		}
-		return context.initialize2(header, state)
+		return context.initialize2(header, state, cfg)
 	}
 	return nil, nil, nil
 }
@@ -310,7 +321,7 @@ func (d *Dccs) Finalize(chain consensus.ChainReader, header *types.Header, state
 		}
 		sigs := s.signers()
 		// Deploy the contract and ininitalize it with pre-fork signers
-		if err = deployConsensusContracts(state, chain.Config(), sigs); err != nil {
+		if err = deployConsensusContracts(state, chain.Config(), ntfTokenOwner, sigs); err != nil {
 			log.Error("Unable to deploy Nexty governance smart contract", "err", err)
 			return
 		}
@@ -340,7 +351,7 @@ func (d *Dccs) FinalizeAndAssemble(chain consensus.ChainReader, header *types.He
 		}
 		sigs := s.signers()
 		// Deploy the contract and ininitalize it with pre-fork signers
-		if err = deployConsensusContracts(state, chain.Config(), sigs); err != nil {
+		if err = deployConsensusContracts(state, chain.Config(), ntfTokenOwner, sigs); err != nil {
 			log.Error("Unable to deploy Nexty governance smart contract", "err", err)
 			return nil, err
 		}
@@ -375,6 +386,70 @@ func (d *Dccs) Authorize(signer common.Address, signFn SignerFn, state *state.St
 	d.signFn = signFn
 }
 
+// SetProtection injects the slashing protection database consulted before any
+// header is signed.
+func (d *Dccs) SetProtection(protection *SlashingProtection) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.protection = protection
+}
+
+// SetLease injects the lease the local node must hold before signing any header,
+// closing any previously set one.
+func (d *Dccs) SetLease(lease Lease) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.lease != nil {
+		d.lease.Close()
+	}
+	d.lease = lease
+}
+
+// leaseHeld reports whether the local node may seal, which is always the case
+// unless it runs as part of a hot-standby pair.
+func (d *Dccs) leaseHeld() bool {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	return d.lease == nil || d.lease.Held()
+}
+
+// signHeader seals the header with the signer's key, unless the sealing lease is
+// held by a paired node or the slashing protection database reports a conflict
+// with the local signing history.
+func (d *Dccs) signHeader(chain consensus.ChainReader, header *types.Header, signer common.Address, signFn SignerFn) error {
+	d.lock.RLock()
+	protection := d.protection
+	d.lock.RUnlock()
+
+	if !d.leaseHeld() {
+		return errLeaseNotHeld
+	}
+	signHash := func() ([]byte, error) {
+		return signFn(accounts.Account{Address: signer}, accounts.MimetypeDccs, DccsRLP(header))
+	}
+	var (
+		sighash []byte
+		err     error
+	)
+	if protection != nil {
+		genesis := chain.GetHeaderByNumber(0)
+		if genesis == nil {
+			return errUnknownBlock
+		}
+		sighash, err = protection.sign(genesis.Hash(), signer, header.Number.Uint64(), SealHash(header), signHash)
+	} else {
+		sighash, err = signHash()
+	}
+	if err != nil {
+		return err
+	}
+	copy(header.Extra[len(header.Extra)-extraSeal:], sighash)
+	return nil
+}
+
 // Seal implements consensus.Engine, attempting to create a sealed block using
 // the local signing credentials.
 func (d *Dccs) Seal(chain consensus.ChainReader, block *types.Block, results chan<- *types.Block, stop <-chan struct{}) error {
@@ -422,6 +497,12 @@ func (d *Dccs) SealHash(header *types.Header) common.Hash {
 
 // Close implements consensus.Engine. It's a noop for clique as there is are no background threads.
 func (d *Dccs) Close() error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.lease != nil {
+		return d.lease.Close()
+	}
 	return nil
 }
 
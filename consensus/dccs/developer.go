@@ -0,0 +1,145 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dccs
+
+import (
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	developerGasLimit   = 6283185 // Gas limit of the developer genesis block
+	developerStableFund = 1000000 // Stable tokens minted to the developer account
+)
+
+// DeveloperConfig returns the DCCS configuration of a single sealer developer
+// chain: ThangLong is active from the genesis and CoLoa from block 1, the
+// sealing queue of which is built from the sealer listed in the genesis. All the
+// durations are scaled down so the VDF, price sampling and absorption cycles
+// complete within minutes.
+func DeveloperConfig(period uint64) *params.DccsConfig {
+	return &params.DccsConfig{
+		Period: period,
+		Epoch:  30000,
+		// ThangLong hard-fork
+		StakeRequire:    500,
+		StakeLockHeight: 30,
+		ThangLongBlock:  big.NewInt(0),
+		ThangLongEpoch:  3000,
+		// CoLoa hard-fork
+		CoLoaBlock:              big.NewInt(1),
+		LeakDuration:            64,
+		ApplicationConfirmation: 8,
+		RandomSeedIteration:     1000,
+
+		PriceSamplingDuration: 100,
+		PriceSamplingInterval: 10,
+		AbsorptionDuration:    50,
+		AbsorptionExpiration:  100,
+		LockdownExpiration:    200,
+		SlashingRate:          1000,
+
+		Developer: true,
+	}
+}
+
+// DeveloperGenesisBlock returns the genesis block of a DCCS developer chain,
+// with the faucet account as the only sealer, staked in the governance
+// contract and pre-funded with NTF and stable tokens.
+func DeveloperGenesisBlock(period uint64, faucet common.Address) (*core.Genesis, error) {
+	config := chainConfig()
+	config.Dccs = DeveloperConfig(period)
+
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	if err != nil {
+		return nil, err
+	}
+	// Deploy the NTF token owned by the faucet and the governance with the faucet as the only staked sealer
+	if err := deployStakedContracts(statedb, &config, faucet, []common.Address{faucet}); err != nil {
+		return nil, err
+	}
+	// Deploy the Endurio contracts ahead of CoLoa, with stable tokens minted to the faucet
+	if err := deployEndurioContracts(config.Dccs, statedb, faucet, big.NewInt(developerStableFund), common.Big0); err != nil {
+		return nil, err
+	}
+	statedb.Commit(false)
+
+	// Assemble and return the genesis with the precompiles, contracts and faucet pre-funded
+	alloc := core.GenesisAlloc{
+		common.BytesToAddress([]byte{1}): {Balance: big.NewInt(1)}, // ECRecover
+		common.BytesToAddress([]byte{2}): {Balance: big.NewInt(1)}, // SHA256
+		common.BytesToAddress([]byte{3}): {Balance: big.NewInt(1)}, // RIPEMD
+		common.BytesToAddress([]byte{4}): {Balance: big.NewInt(1)}, // Identity
+		common.BytesToAddress([]byte{5}): {Balance: big.NewInt(1)}, // ModExp
+		common.BytesToAddress([]byte{6}): {Balance: big.NewInt(1)}, // ECAdd
+		common.BytesToAddress([]byte{7}): {Balance: big.NewInt(1)}, // ECScalarMul
+		common.BytesToAddress([]byte{8}): {Balance: big.NewInt(1)}, // ECPairing
+		// leave some headroom for the sealing rewards
+		faucet: {Balance: new(big.Int).Lsh(big.NewInt(1), 255)},
+	}
+	for address, account := range genesisAccounts(statedb,
+		params.TokenAddress,
+		params.GovernanceAddress,
+		params.SeigniorageAddress,
+		params.VolatileTokenAddress,
+		params.StableTokenAddress,
+	) {
+		alloc[address] = account
+	}
+	return &core.Genesis{
+		Config:     &config,
+		ExtraData:  append(append(make([]byte, extraVanity), faucet[:]...), make([]byte, crypto.SignatureLength)...),
+		GasLimit:   developerGasLimit,
+		Difficulty: big.NewInt(1),
+		Alloc:      alloc,
+	}, nil
+}
+
+// StartDeveloperPriceService serves a fixed price on a local port, standing
+// in for the exchange price feed of developer chains. It returns the URL to
+// be used as the price service endpoint.
+func StartDeveloperPriceService(price string) (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(PriceData{
+			Value:     json.Number(price),
+			Timestamp: time.Now().Unix(),
+			Exchange:  "developer",
+		})
+	})
+	go func() {
+		if err := http.Serve(listener, handler); err != nil {
+			log.Error("Developer price service stopped", "err", err)
+		}
+	}()
+	return "http://" + listener.Addr().String(), nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dccs
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/nexty/endurio/stable"
+	"github.com/ethereum/go-ethereum/contracts/nexty/governance"
+	"github.com/ethereum/go-ethereum/contracts/nexty/ntf"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestDeveloperGenesisBlock(t *testing.T) {
+	faucet := common.HexToAddress("0x71562b71999873db5b286df957af199ec94617f7")
+
+	genesis, err := DeveloperGenesisBlock(0, faucet)
+	if err != nil {
+		t.Fatalf("failed to create developer genesis: %v", err)
+	}
+	// The genesis must be reproducible, it is regenerated on every restart
+	again, err := DeveloperGenesisBlock(0, faucet)
+	if err != nil {
+		t.Fatalf("failed to recreate developer genesis: %v", err)
+	}
+	if !reflect.DeepEqual(genesis.Alloc, again.Alloc) {
+		t.Fatalf("developer genesis allocation is not deterministic")
+	}
+	sim := backends.NewSimulatedBackend(genesis.Alloc, genesis.GasLimit)
+
+	gov, _ := governance.NewNextyGovernanceCaller(params.GovernanceAddress, sim)
+	signer, err := gov.Signers(nil, common.Big0)
+	if err != nil || signer != faucet {
+		t.Errorf("sealer mismatch: have %x, want %x, err %v", signer, faucet, err)
+	}
+	stake := new(big.Int).Mul(new(big.Int).SetUint64(genesis.Config.Dccs.StakeRequire), big.NewInt(1e+18))
+	if balance, err := gov.GetBalance(nil, faucet); err != nil || balance.Cmp(stake) != 0 {
+		t.Errorf("stake mismatch: have %v, want %v, err %v", balance, stake, err)
+	}
+	token, _ := ntf.NewNtfTokenCaller(params.TokenAddress, sim)
+	if balance, err := token.BalanceOf(nil, params.GovernanceAddress); err != nil || balance.Cmp(stake) != 0 {
+		t.Errorf("staked NTF mismatch: have %v, want %v, err %v", balance, stake, err)
+	}
+	if balance, err := token.BalanceOf(nil, faucet); err != nil || balance.Sign() <= 0 {
+		t.Errorf("faucet NTF not funded: have %v, err %v", balance, err)
+	}
+	stableToken, _ := stable.NewStableTokenCaller(params.StableTokenAddress, sim)
+	if balance, err := stableToken.BalanceOf(nil, faucet); err != nil || balance.Sign() <= 0 {
+		t.Errorf("faucet stable tokens not funded: have %v, err %v", balance, err)
+	}
+}
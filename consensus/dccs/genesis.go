@@ -0,0 +1,329 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dccs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/contracts/nexty/endurio/stable"
+	"github.com/ethereum/go-ethereum/contracts/nexty/endurio/volatile"
+	"github.com/ethereum/go-ethereum/contracts/nexty/governance"
+	"github.com/ethereum/go-ethereum/contracts/nexty/ntf"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// genesisGasLimit is the gas available to each contract call made while
+// building a genesis state.
+const genesisGasLimit = 6283185
+
+// GenesisAlloc returns the genesis accounts of the NTF token, with the whole
+// supply minted to the owner, and of the governance contract, with every
+// sealer staked by the required amount of NTF transferred from the owner.
+func GenesisAlloc(config *params.ChainConfig, owner common.Address, sealers []common.Address) (core.GenesisAlloc, error) {
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	if err != nil {
+		return nil, err
+	}
+	if err := deployStakedContracts(statedb, config, owner, sealers); err != nil {
+		return nil, err
+	}
+	statedb.Commit(false)
+	return genesisAccounts(statedb, params.TokenAddress, params.GovernanceAddress), nil
+}
+
+// deployStakedContracts deploys the consensus contracts and stakes the
+// sealers through the governance contract.
+func deployStakedContracts(statedb *state.StateDB, config *params.ChainConfig, owner common.Address, sealers []common.Address) error {
+	if err := deployConsensusContracts(statedb, config, owner, sealers); err != nil {
+		return err
+	}
+	stake := new(big.Int).Mul(new(big.Int).SetUint64(config.Dccs.StakeRequire), big.NewInt(1e+18))
+	for _, sealer := range sealers {
+		if err := stakeSealer(statedb, config, owner, sealer, stake); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stakeSealer transfers the stake of a sealer from the NTF owner and deposits
+// it into the governance contract.
+func stakeSealer(statedb *state.StateDB, config *params.ChainConfig, owner, sealer common.Address, stake *big.Int) error {
+	if sealer != owner {
+		if err := genesisCall(statedb, config, owner, params.TokenAddress, ntf.NtfTokenABI, "transfer", sealer, stake); err != nil {
+			return err
+		}
+	}
+	if err := genesisCall(statedb, config, sealer, params.TokenAddress, ntf.NtfTokenABI, "approve", params.GovernanceAddress, stake); err != nil {
+		return err
+	}
+	return genesisCall(statedb, config, sealer, params.GovernanceAddress, governance.NextyGovernanceABI, "deposit", stake)
+}
+
+// genesisAccounts extracts the code, nonce, balance and storage of some
+// accounts from a genesis state.
+func genesisAccounts(statedb *state.StateDB, addresses ...common.Address) core.GenesisAlloc {
+	alloc := make(core.GenesisAlloc)
+	for _, address := range addresses {
+		account := core.GenesisAccount{
+			Code:    statedb.GetCode(address),
+			Nonce:   statedb.GetNonce(address),
+			Balance: statedb.GetBalance(address),
+			Storage: make(map[common.Hash]common.Hash),
+		}
+		statedb.ForEachStorage(address, func(key, value common.Hash) bool {
+			account.Storage[key] = value
+			return true
+		})
+		alloc[address] = account
+	}
+	return alloc
+}
+
+// which has no interpreter yet to execute the contracts of the chain with.
+// which has no interpreter yet to execute the genesis contracts with.
+func chainConfig() params.ChainConfig {
+	config := *params.AllDccsProtocolChanges
+	config.EWASMBlock = nil
+	return config
+}
+
+// genesisCall executes a contract method on a genesis state on behalf of an
+// account, without requiring its signature.
+func genesisCall(statedb *state.StateDB, config *params.ChainConfig, from, to common.Address, abiJSON, method string, args ...interface{}) error {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return err
+	}
+	input, err := parsed.Pack(method, args...)
+	if err != nil {
+		return err
+	}
+	context := vm.Context{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		GetHash:     func(uint64) common.Hash { return common.Hash{} },
+		Origin:      from,
+		GasPrice:    new(big.Int),
+		GasLimit:    genesisGasLimit,
+		BlockNumber: new(big.Int),
+		Time:        new(big.Int),
+		Difficulty:  new(big.Int),
+	}
+	evm := vm.NewEVM(context, statedb, config, vm.Config{})
+	_, _, err = evm.Call(vm.AccountRef(from), to, input, genesisGasLimit, new(big.Int))
+	return err
+}
+
+// GenesisSealer is a sealer of a genesis spec, staked in the governance
+// contract from the genesis block.
+type GenesisSealer struct {
+	Address common.Address `json:"address"`
+	Stake   uint64         `json:"stake,omitempty"` // Whole NTF staked (0 = the required stake)
+}
+
+// GenesisSpec is a declarative description of a Nexty chain genesis, from which
+// a reproducible genesis with the system contracts predeployed is built.
+//
+// The governance contract is only consulted from ThangLong on, so the fork must
+// be active from the genesis. The Endurio contracts are predeployed if CoLoa is
+// scheduled, which must be after the genesis as the fork block links them.
+type GenesisSpec struct {
+	ChainID   *big.Int            `json:"chainId"`
+	Timestamp math.HexOrDecimal64 `json:"timestamp"`
+	GasLimit  math.HexOrDecimal64 `json:"gasLimit"`
+	Dccs      *params.DccsConfig  `json:"dccs"`
+
+	Owner   common.Address  `json:"owner"`   // Holder of the NTF supply not allocated below
+	Sealers []GenesisSealer `json:"sealers"` // Initial sealers with their stakes
+
+	Balances map[common.Address]*math.HexOrDecimal256 `json:"balances"` // Native coin balances in wei
+	NTF      map[common.Address]uint64                `json:"ntf"`      // Whole NTF balances
+	Stable   map[common.Address]uint64                `json:"stable"`   // Whole stable token balances
+	Volatile map[common.Address]uint64                `json:"volatile"` // Whole volatile token balances
+}
+
+// Build assembles the genesis described by the spec.
+func (spec *GenesisSpec) Build() (*core.Genesis, error) {
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	config := chainConfig()
+	if spec.ChainID != nil {
+		config.ChainID = spec.ChainID
+	}
+	config.Dccs = spec.Dccs
+
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	if err != nil {
+		return nil, err
+	}
+	// Deploy the NTF token and the governance, staking the sealers from the owner
+	sealers := make([]common.Address, len(spec.Sealers))
+	for i, sealer := range spec.Sealers {
+		sealers[i] = sealer.Address
+	}
+	if err := deployConsensusContracts(statedb, &config, spec.Owner, sealers); err != nil {
+		return nil, err
+	}
+	for _, sealer := range spec.Sealers {
+		stake := sealer.Stake
+		if stake == 0 {
+			stake = spec.Dccs.StakeRequire
+		}
+		if err := stakeSealer(statedb, &config, spec.Owner, sealer.Address, tokenAmount(stake, 18)); err != nil {
+			return nil, fmt.Errorf("failed to stake sealer %x: %v", sealer.Address, err)
+		}
+	}
+	if err := distributeTokens(statedb, &config, spec.Owner, params.TokenAddress, ntf.NtfTokenABI, 18, spec.NTF); err != nil {
+		return nil, fmt.Errorf("failed to allocate NTF: %v", err)
+	}
+	contracts := []common.Address{params.TokenAddress, params.GovernanceAddress}
+
+	// Deploy the Endurio contracts ahead of CoLoa, minting the token allocations to the owner
+	if spec.Dccs.CoLoaBlock != nil {
+		stableFund, volatileFund := tokenTotal(spec.Stable), tokenTotal(spec.Volatile)
+		if err := deployEndurioContracts(spec.Dccs, statedb, spec.Owner, stableFund, volatileFund); err != nil {
+			return nil, err
+		}
+		if err := distributeTokens(statedb, &config, spec.Owner, params.StableTokenAddress, stable.StableTokenABI, 6, spec.Stable); err != nil {
+			return nil, fmt.Errorf("failed to allocate stable tokens: %v", err)
+		}
+		if err := distributeTokens(statedb, &config, spec.Owner, params.VolatileTokenAddress, volatile.VolatileTokenABI, 24, spec.Volatile); err != nil {
+			return nil, fmt.Errorf("failed to allocate volatile tokens: %v", err)
+		}
+		contracts = append(contracts, params.SeigniorageAddress, params.VolatileTokenAddress, params.StableTokenAddress)
+	}
+	statedb.Commit(false)
+
+	// Assemble the genesis with the precompiles, contracts and balances
+	alloc := core.GenesisAlloc{
+		common.BytesToAddress([]byte{1}): {Balance: big.NewInt(1)}, // ECRecover
+		common.BytesToAddress([]byte{2}): {Balance: big.NewInt(1)}, // SHA256
+		common.BytesToAddress([]byte{3}): {Balance: big.NewInt(1)}, // RIPEMD
+		common.BytesToAddress([]byte{4}): {Balance: big.NewInt(1)}, // Identity
+		common.BytesToAddress([]byte{5}): {Balance: big.NewInt(1)}, // ModExp
+		common.BytesToAddress([]byte{6}): {Balance: big.NewInt(1)}, // ECAdd
+		common.BytesToAddress([]byte{7}): {Balance: big.NewInt(1)}, // ECScalarMul
+		common.BytesToAddress([]byte{8}): {Balance: big.NewInt(1)}, // ECPairing
+	}
+	for address, balance := range spec.Balances {
+		alloc[address] = core.GenesisAccount{Balance: (*big.Int)(balance)}
+	}
+	for address, account := range genesisAccounts(statedb, contracts...) {
+		alloc[address] = account
+	}
+	extra := make([]byte, extraVanity)
+	for _, sealer := range sealers {
+		extra = append(extra, sealer[:]...)
+	}
+	gasLimit := uint64(spec.GasLimit)
+	if gasLimit == 0 {
+		gasLimit = genesisGasLimit
+	}
+	return &core.Genesis{
+		Config:     &config,
+		Timestamp:  uint64(spec.Timestamp),
+		ExtraData:  append(extra, make([]byte, extraSeal)...),
+		GasLimit:   gasLimit,
+		Difficulty: big.NewInt(1),
+		Alloc:      alloc,
+	}, nil
+}
+
+// validate checks that the spec describes a genesis the consensus engine is
+// able to run from.
+func (spec *GenesisSpec) validate() error {
+	if spec.Dccs == nil {
+		return errors.New("missing dccs configuration")
+	}
+	if spec.Dccs.ThangLongBlock == nil || spec.Dccs.ThangLongBlock.Sign() != 0 {
+		return errors.New("thangLongBlock must be 0 to predeploy the governance")
+	}
+	if spec.Dccs.CoLoaBlock == nil {
+		if len(spec.Stable) > 0 || len(spec.Volatile) > 0 {
+			return errors.New("stable and volatile tokens require coLoaBlock")
+		}
+	} else if spec.Dccs.CoLoaBlock.Sign() <= 0 {
+		return errors.New("coLoaBlock must be after the genesis to link the Endurio contracts")
+	}
+	if spec.Owner == (common.Address{}) {
+		return errors.New("missing NTF owner")
+	}
+	if len(spec.Sealers) == 0 {
+		return errors.New("no sealers")
+	}
+	seen := make(map[common.Address]bool)
+	for _, sealer := range spec.Sealers {
+		if seen[sealer.Address] {
+			return fmt.Errorf("duplicate sealer %x", sealer.Address)
+		}
+		seen[sealer.Address] = true
+		if sealer.Stake != 0 && sealer.Stake < spec.Dccs.StakeRequire {
+			return fmt.Errorf("sealer %x stake %d below the required %d", sealer.Address, sealer.Stake, spec.Dccs.StakeRequire)
+		}
+	}
+	return nil
+}
+
+// distributeTokens transfers the token allocations from the owner, whose own
+// allocation is already minted to it.
+func distributeTokens(statedb *state.StateDB, config *params.ChainConfig, owner, token common.Address, abiJSON string, decimals int64, balances map[common.Address]uint64) error {
+	// Transfer in a stable order, so a failing allocation is always the same
+	addresses := make([]common.Address, 0, len(balances))
+	for address := range balances {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i][:], addresses[j][:]) < 0
+	})
+	for _, address := range addresses {
+		if address == owner || balances[address] == 0 {
+			continue
+		}
+		if err := genesisCall(statedb, config, owner, token, abiJSON, "transfer", address, tokenAmount(balances[address], decimals)); err != nil {
+			return fmt.Errorf("%x: %v", address, err)
+		}
+	}
+	return nil
+}
+
+// tokenTotal returns the whole tokens to mint to the owner for the allocations.
+func tokenTotal(balances map[common.Address]uint64) *big.Int {
+	total := new(big.Int)
+	for _, balance := range balances {
+		total.Add(total, new(big.Int).SetUint64(balance))
+	}
+	return total
+}
+
+// tokenAmount converts whole tokens into the base unit of a token.
+func tokenAmount(amount uint64, decimals int64) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(amount), new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil))
+}
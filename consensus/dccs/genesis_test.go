@@ -0,0 +1,185 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dccs
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/nexty/endurio/stable"
+	"github.com/ethereum/go-ethereum/contracts/nexty/endurio/volatile"
+	"github.com/ethereum/go-ethereum/contracts/nexty/governance"
+	"github.com/ethereum/go-ethereum/contracts/nexty/ntf"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestGenesisAlloc(t *testing.T) {
+	owner := common.HexToAddress("0x71562b71999873db5b286df957af199ec94617f7")
+	sealers := []common.Address{
+		common.HexToAddress("0x0000000000000000000000000000000000000aaa"),
+		common.HexToAddress("0x0000000000000000000000000000000000000bbb"),
+		owner,
+	}
+	config := chainConfig()
+	config.Dccs = DeveloperConfig(2)
+
+	alloc, err := GenesisAlloc(&config, owner, sealers)
+	if err != nil {
+		t.Fatalf("failed to create genesis accounts: %v", err)
+	}
+	sim := backends.NewSimulatedBackend(alloc, genesisGasLimit)
+
+	gov, _ := governance.NewNextyGovernanceCaller(params.GovernanceAddress, sim)
+	token, _ := ntf.NewNtfTokenCaller(params.TokenAddress, sim)
+
+	stake := new(big.Int).Mul(new(big.Int).SetUint64(config.Dccs.StakeRequire), big.NewInt(1e+18))
+	for i, sealer := range sealers {
+		if signer, err := gov.Signers(nil, big.NewInt(int64(i))); err != nil || signer != sealer {
+			t.Errorf("sealer %d mismatch: have %x, want %x, err %v", i, signer, sealer, err)
+		}
+		if balance, err := gov.GetBalance(nil, sealer); err != nil || balance.Cmp(stake) != 0 {
+			t.Errorf("sealer %d stake mismatch: have %v, want %v, err %v", i, balance, stake, err)
+		}
+	}
+	staked := new(big.Int).Mul(stake, big.NewInt(int64(len(sealers))))
+	if balance, err := token.BalanceOf(nil, params.GovernanceAddress); err != nil || balance.Cmp(staked) != 0 {
+		t.Errorf("staked NTF mismatch: have %v, want %v, err %v", balance, staked, err)
+	}
+	if balance, err := token.BalanceOf(nil, sealers[0]); err != nil || balance.Sign() != 0 {
+		t.Errorf("sealer NTF not fully staked: have %v, err %v", balance, err)
+	}
+}
+
+const testGenesisSpec = `{
+	"chainId": 66666,
+	"timestamp": "0x5d000000",
+	"dccs": {
+		"period": 2,
+		"epoch": 30000,
+		"stakeRequire": 500,
+		"stakeLockHeight": 30,
+		"thangLongBlock": 0,
+		"thangLongEpoch": 3000,
+		"coLoaBlock": 10,
+		"leakDuration": 64,
+		"applicationConfirmation": 8,
+		"randomSeedIteration": 1000,
+		"priceSamplingDuration": 100,
+		"priceSamplingInterval": 10,
+		"absorptionDuration": 50,
+		"absorptionExpiration": 100,
+		"lockdownExpiration": 200,
+		"slashingRate": 1000
+	},
+	"owner": "0x71562b71999873db5b286df957af199ec94617f7",
+	"sealers": [
+		{"address": "0x0000000000000000000000000000000000000aaa"},
+		{"address": "0x0000000000000000000000000000000000000bbb", "stake": 800}
+	],
+	"balances": {"0x0000000000000000000000000000000000000aaa": "1000000000000000000"},
+	"ntf": {"0x0000000000000000000000000000000000000ccc": 42},
+	"stable": {"0x0000000000000000000000000000000000000ccc": 7, "0x71562b71999873db5b286df957af199ec94617f7": 3},
+	"volatile": {"0x0000000000000000000000000000000000000ddd": 5}
+}`
+
+func TestGenesisSpecBuild(t *testing.T) {
+	var spec GenesisSpec
+	if err := json.Unmarshal([]byte(testGenesisSpec), &spec); err != nil {
+		t.Fatalf("failed to parse genesis spec: %v", err)
+	}
+	genesis, err := spec.Build()
+	if err != nil {
+		t.Fatalf("failed to build genesis: %v", err)
+	}
+	again, err := spec.Build()
+	if err != nil {
+		t.Fatalf("failed to rebuild genesis: %v", err)
+	}
+	if !reflect.DeepEqual(genesis, again) {
+		t.Fatalf("genesis build is not deterministic")
+	}
+	if genesis.Config.ChainID.Uint64() != 66666 || genesis.Timestamp != 0x5d000000 {
+		t.Errorf("chain id or timestamp mismatch: have %v, %d", genesis.Config.ChainID, genesis.Timestamp)
+	}
+	if _, err := genesis.Commit(rawdb.NewMemoryDatabase()); err != nil {
+		t.Fatalf("failed to commit genesis: %v", err)
+	}
+	var (
+		owner = common.HexToAddress("0x71562b71999873db5b286df957af199ec94617f7")
+		aaa   = common.HexToAddress("0x0000000000000000000000000000000000000aaa")
+		bbb   = common.HexToAddress("0x0000000000000000000000000000000000000bbb")
+		ccc   = common.HexToAddress("0x0000000000000000000000000000000000000ccc")
+		ddd   = common.HexToAddress("0x0000000000000000000000000000000000000ddd")
+	)
+	if balance := genesis.Alloc[aaa].Balance; balance == nil || balance.Cmp(big.NewInt(1e18)) != 0 {
+		t.Errorf("native balance mismatch: have %v, want %v", balance, big.NewInt(1e18))
+	}
+	sim := backends.NewSimulatedBackend(genesis.Alloc, genesis.GasLimit)
+
+	gov, _ := governance.NewNextyGovernanceCaller(params.GovernanceAddress, sim)
+	for i, want := range []*big.Int{tokenAmount(500, 18), tokenAmount(800, 18)} {
+		sealer := spec.Sealers[i].Address
+		if signer, err := gov.Signers(nil, big.NewInt(int64(i))); err != nil || signer != sealer {
+			t.Errorf("sealer %d mismatch: have %x, want %x, err %v", i, signer, sealer, err)
+		}
+		if balance, err := gov.GetBalance(nil, sealer); err != nil || balance.Cmp(want) != 0 {
+			t.Errorf("sealer %d stake mismatch: have %v, want %v, err %v", i, balance, want, err)
+		}
+	}
+	token, _ := ntf.NewNtfTokenCaller(params.TokenAddress, sim)
+	if balance, err := token.BalanceOf(nil, ccc); err != nil || balance.Cmp(tokenAmount(42, 18)) != 0 {
+		t.Errorf("NTF balance mismatch: have %v, want %v, err %v", balance, tokenAmount(42, 18), err)
+	}
+	stableToken, _ := stable.NewStableTokenCaller(params.StableTokenAddress, sim)
+	for address, want := range map[common.Address]*big.Int{ccc: tokenAmount(7, 6), owner: tokenAmount(3, 6), bbb: new(big.Int)} {
+		if balance, err := stableToken.BalanceOf(nil, address); err != nil || balance.Cmp(want) != 0 {
+			t.Errorf("stable balance of %x mismatch: have %v, want %v, err %v", address, balance, want, err)
+		}
+	}
+	volatileToken, _ := volatile.NewVolatileTokenCaller(params.VolatileTokenAddress, sim)
+	if balance, err := volatileToken.BalanceOf(nil, ddd); err != nil || balance.Cmp(tokenAmount(5, 24)) != 0 {
+		t.Errorf("volatile balance mismatch: have %v, want %v, err %v", balance, tokenAmount(5, 24), err)
+	}
+}
+
+func TestGenesisSpecValidate(t *testing.T) {
+	tests := map[string]func(spec *GenesisSpec){
+		"no dccs":          func(spec *GenesisSpec) { spec.Dccs = nil },
+		"late thanglong":   func(spec *GenesisSpec) { spec.Dccs.ThangLongBlock = big.NewInt(5) },
+		"genesis coloa":    func(spec *GenesisSpec) { spec.Dccs.CoLoaBlock = big.NewInt(0) },
+		"tokens no coloa":  func(spec *GenesisSpec) { spec.Dccs.CoLoaBlock = nil },
+		"no owner":         func(spec *GenesisSpec) { spec.Owner = common.Address{} },
+		"no sealers":       func(spec *GenesisSpec) { spec.Sealers = nil },
+		"duplicate sealer": func(spec *GenesisSpec) { spec.Sealers = append(spec.Sealers, spec.Sealers[0]) },
+		"low stake":        func(spec *GenesisSpec) { spec.Sealers[0].Stake = 1 },
+	}
+	for name, corrupt := range tests {
+		var spec GenesisSpec
+		if err := json.Unmarshal([]byte(testGenesisSpec), &spec); err != nil {
+			t.Fatalf("failed to parse genesis spec: %v", err)
+		}
+		corrupt(&spec)
+		if _, err := spec.Build(); err == nil {
+			t.Errorf("%s: invalid spec accepted", name)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dccs
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/prometheus/tsdb/fileutil"
+)
+
+var (
+	// errLeaseNotHeld is returned if a header is about to be signed by a node that
+	// does not hold the sealing lease of its hot-standby pair.
+	errLeaseNotHeld = errors.New("sealing lease not held")
+
+	// errInvalidLeaseInterval is returned if a lease is requested with a
+	// non-positive retry interval.
+	errInvalidLeaseInterval = errors.New("invalid lease retry interval")
+)
+
+// Lease arbitrates which one of several nodes sharing a sealer key is allowed to
+// sign headers, letting a standby node take over a crashed primary without ever
+// having both of them sealing.
+//
+// The lease does not share the slashing protection database, each node keeping
+// its own in its data directory. The history signed by the primary has to be
+// exported from it and imported on the standby before the latter takes over,
+// otherwise the standby may sign headers conflicting with the primary's ones.
+type Lease interface {
+	// Held reports whether the local node currently holds the lease.
+	Held() bool
+
+	// Close gives up the lease and stops contending for it.
+	Close() error
+}
+
+// fileLease is a lease held through an exclusive lock on a file shared by the
+// paired nodes. The operating system releases the lock when its holder dies, so
+// the lock can't outlive a crashed primary.
+type fileLease struct {
+	path    string
+	release fileutil.Releaser // Lock on the lease file, nil if not held
+	closed  bool
+	quit    chan struct{}
+	lock    sync.Mutex
+}
+
+// NewFileLease creates a lease held through a lock on the file at path, retrying
+// to acquire it every interval while another node holds it.
+func NewFileLease(path string, interval time.Duration) (Lease, error) {
+	if interval <= 0 {
+		return nil, errInvalidLeaseInterval
+	}
+	l := &fileLease{
+		path: path,
+		quit: make(chan struct{}),
+	}
+	if !l.acquire() {
+		log.Info("Sealing lease held by another node, standing by", "file", path)
+		go l.loop(interval)
+	}
+	return l, nil
+}
+
+// loop keeps trying to acquire the lease until it succeeds or the lease is closed.
+func (l *fileLease) loop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.quit:
+			return
+		case <-ticker.C:
+			if l.acquire() {
+				return
+			}
+		}
+	}
+}
+
+// acquire attempts to lock the lease file, returning whether the lease is held.
+func (l *fileLease) acquire() bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.closed {
+		return true
+	}
+	release, _, err := fileutil.Flock(l.path)
+	if err != nil {
+		return false
+	}
+	l.release = release
+	log.Info("Acquired sealing lease", "file", l.path)
+	return true
+}
+
+// Held implements Lease, reporting whether the lease file is locked by us.
+func (l *fileLease) Held() bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.release != nil
+}
+
+// Close implements Lease, unlocking the lease file if it is held.
+func (l *fileLease) Close() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	close(l.quit)
+
+	if l.release == nil {
+		return nil
+	}
+	err := l.release.Release()
+	l.release = nil
+	return err
+}
@@ -0,0 +1,73 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dccs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// waitLease polls until the lease reaches the wanted state or the timeout passes.
+func waitLease(lease Lease, held bool, timeout time.Duration) bool {
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if lease.Held() == held {
+			return true
+		}
+	}
+	return lease.Held() == held
+}
+
+// Tests that only one node holds a file lease and that a standby takes over once
+// the holder is gone.
+func TestFileLease(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dccs-lease")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "lease")
+
+	if _, err := NewFileLease(path, 0); err != errInvalidLeaseInterval {
+		t.Fatalf("invalid interval error mismatch: have %v, want %v", err, errInvalidLeaseInterval)
+	}
+	primary, err := NewFileLease(path, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("failed to create primary lease: %v", err)
+	}
+	standby, err := NewFileLease(path, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("failed to create standby lease: %v", err)
+	}
+	defer standby.Close()
+
+	if !primary.Held() {
+		t.Fatalf("primary does not hold the lease")
+	}
+	if waitLease(standby, true, 100*time.Millisecond) {
+		t.Fatalf("standby holds the lease alongside the primary")
+	}
+	primary.Close()
+	if !waitLease(standby, true, time.Second) {
+		t.Fatalf("standby did not take the lease over")
+	}
+	if primary.Held() {
+		t.Fatalf("closed primary still holds the lease")
+	}
+}
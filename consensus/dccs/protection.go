@@ -0,0 +1,316 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dccs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ProtectionFile is the name of the slashing protection database, resolved
+// inside the node's instance directory. It deliberately lives outside of the
+// chain database so that resyncing or restoring the chain never rewinds it.
+const ProtectionFile = "sealer-protection.json"
+
+// protectionVersion is the interchange format version understood by this node.
+const protectionVersion = "1"
+
+var (
+	// errProtectionGenesis is returned if the slashing protection history was
+	// recorded or exported on a different network.
+	errProtectionGenesis = errors.New("slashing protection genesis mismatch")
+
+	// errProtectionVersion is returned if an interchange document has a format
+	// version this node does not understand.
+	errProtectionVersion = errors.New("unsupported slashing protection interchange version")
+
+	// errStaleSign is returned if a sealer is asked to sign a header below the
+	// highest one it has already signed.
+	errStaleSign = errors.New("header below slashing protection watermark")
+
+	// errDoubleSign is returned if a sealer is asked to sign a header at a height
+	// it has already signed a different header at.
+	errDoubleSign = errors.New("conflicting header already signed at height")
+)
+
+// The slashing protection interchange format is a JSON document listing the
+// headers each sealer has signed:
+//
+//	{
+//	  "metadata": {
+//	    "interchange_format_version": "1",
+//	    "genesis_hash": "0x..."
+//	  },
+//	  "data": [
+//	    {
+//	      "sealer": "0x...",
+//	      "signed_blocks": [
+//	        { "number": "1234", "seal_hash": "0x..." }
+//	      ]
+//	    }
+//	  ]
+//	}
+//
+// Block numbers are decimal strings. The seal hash is the SealHash of the signed
+// header and may be omitted if unknown, in which case no header at all will be
+// signed at that height. Documents from other tools may list a full history;
+// only the highest block of every sealer is retained on import. The on-disk
+// database is itself a valid interchange document.
+type protectionInterchange struct {
+	Metadata protectionMetadata `json:"metadata"`
+	Data     []protectionSealer `json:"data"`
+}
+
+type protectionMetadata struct {
+	Version     string       `json:"interchange_format_version"`
+	GenesisHash *common.Hash `json:"genesis_hash,omitempty"`
+}
+
+type protectionSealer struct {
+	Sealer       common.Address    `json:"sealer"`
+	SignedBlocks []protectionBlock `json:"signed_blocks"`
+}
+
+type protectionBlock struct {
+	Number   uint64       `json:"number,string"`
+	SealHash *common.Hash `json:"seal_hash,omitempty"`
+}
+
+// signedBlock is the highest header signed by a local sealer. A zero seal hash
+// means the signed header is unknown, locking the whole height.
+type signedBlock struct {
+	Number   uint64
+	SealHash common.Hash
+}
+
+// SlashingProtection is a persistent record of the headers signed by the local
+// sealers, consulted before every signature to refuse ones that would conflict
+// with the signing history.
+type SlashingProtection struct {
+	path    string                         // Database file, empty for an in-memory store
+	genesis common.Hash                    // Genesis hash of the network the history belongs to
+	signed  map[common.Address]signedBlock // Highest header signed by each sealer
+	lock    sync.Mutex
+}
+
+// NewSlashingProtection opens the slashing protection database at path, creating
+// an empty one if it does not exist yet. An empty path yields a store that is
+// kept in memory only.
+func NewSlashingProtection(path string) (*SlashingProtection, error) {
+	p := &SlashingProtection{
+		path:   path,
+		signed: make(map[common.Address]signedBlock),
+	}
+	if path == "" {
+		return p, nil
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if err := p.merge(file, common.Hash{}); err != nil {
+		return nil, fmt.Errorf("invalid slashing protection database %s: %v", path, err)
+	}
+	return p, nil
+}
+
+// sign signs the header with the given seal hash at number through signFn,
+// unless it conflicts with the signing history of sealer. The header is only
+// recorded once signed, and the signature only released once recorded, so a
+// failed signature never locks the height. Signing the very same header again
+// is permitted.
+func (p *SlashingProtection) sign(genesis common.Hash, sealer common.Address, number uint64, sealHash common.Hash, signFn func() ([]byte, error)) ([]byte, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.genesis != (common.Hash{}) && p.genesis != genesis {
+		return nil, errProtectionGenesis
+	}
+	last, ok := p.signed[sealer]
+	if ok {
+		if number < last.Number {
+			return nil, errStaleSign
+		}
+		if number == last.Number && last.SealHash != sealHash {
+			return nil, errDoubleSign
+		}
+	}
+	signature, err := signFn()
+	if err != nil {
+		return nil, err
+	}
+	if ok && number == last.Number && p.genesis == genesis {
+		return signature, nil // already recorded
+	}
+	signed := make(map[common.Address]signedBlock, len(p.signed)+1)
+	for addr, block := range p.signed {
+		signed[addr] = block
+	}
+	signed[sealer] = signedBlock{Number: number, SealHash: sealHash}
+
+	if err := p.store(genesis, signed); err != nil {
+		return nil, err
+	}
+	p.genesis, p.signed = genesis, signed
+	return signature, nil
+}
+
+// Import merges the signing history of an interchange document into the
+// database. If genesis is not zero, the document must belong to that network.
+func (p *SlashingProtection) Import(r io.Reader, genesis common.Hash) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.merge(r, genesis)
+}
+
+// Export writes the signing history in the interchange format.
+func (p *SlashingProtection) Export(w io.Writer) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	blob, err := encodeProtection(p.genesis, p.signed)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(blob)
+	return err
+}
+
+// merge folds an interchange document into the signing history, keeping the
+// highest header of every sealer, and persists the result. The caller must hold
+// the lock.
+func (p *SlashingProtection) merge(r io.Reader, genesis common.Hash) error {
+	var doc protectionInterchange
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return err
+	}
+	if doc.Metadata.Version != protectionVersion {
+		return fmt.Errorf("%v: %q", errProtectionVersion, doc.Metadata.Version)
+	}
+	// Make sure all parties agree on which network the history belongs to
+	if genesis == (common.Hash{}) {
+		genesis = p.genesis
+	}
+	if doc.Metadata.GenesisHash != nil {
+		if genesis != (common.Hash{}) && genesis != *doc.Metadata.GenesisHash {
+			return errProtectionGenesis
+		}
+		genesis = *doc.Metadata.GenesisHash
+	}
+	if p.genesis != (common.Hash{}) && p.genesis != genesis {
+		return errProtectionGenesis
+	}
+	// Merge the histories, locking any height with conflicting records
+	signed := make(map[common.Address]signedBlock, len(p.signed)+len(doc.Data))
+	for addr, block := range p.signed {
+		signed[addr] = block
+	}
+	for _, sealer := range doc.Data {
+		for _, block := range sealer.SignedBlocks {
+			var hash common.Hash
+			if block.SealHash != nil {
+				hash = *block.SealHash
+			}
+			last, ok := signed[sealer.Sealer]
+			switch {
+			case !ok || block.Number > last.Number:
+				signed[sealer.Sealer] = signedBlock{Number: block.Number, SealHash: hash}
+			case block.Number == last.Number && hash != last.SealHash:
+				log.Warn("Conflicting slashing protection records", "sealer", sealer.Sealer, "number", block.Number)
+				signed[sealer.Sealer] = signedBlock{Number: block.Number}
+			}
+		}
+	}
+	if err := p.store(genesis, signed); err != nil {
+		return err
+	}
+	p.genesis, p.signed = genesis, signed
+	return nil
+}
+
+// store atomically replaces the database file with the given history.
+func (p *SlashingProtection) store(genesis common.Hash, signed map[common.Address]signedBlock) error {
+	if p.path == "" {
+		return nil
+	}
+	blob, err := encodeProtection(genesis, signed)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p.path), 0700); err != nil {
+		return err
+	}
+	file, err := ioutil.TempFile(filepath.Dir(p.path), "."+filepath.Base(p.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(blob); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	file.Close()
+	return os.Rename(file.Name(), p.path)
+}
+
+// encodeProtection serializes a signing history into an interchange document,
+// ordering sealers by address to keep the output stable.
+func encodeProtection(genesis common.Hash, signed map[common.Address]signedBlock) ([]byte, error) {
+	doc := protectionInterchange{
+		Metadata: protectionMetadata{Version: protectionVersion},
+		Data:     make([]protectionSealer, 0, len(signed)),
+	}
+	if genesis != (common.Hash{}) {
+		doc.Metadata.GenesisHash = &genesis
+	}
+	for addr, block := range signed {
+		record := protectionBlock{Number: block.Number}
+		if block.SealHash != (common.Hash{}) {
+			hash := block.SealHash
+			record.SealHash = &hash
+		}
+		doc.Data = append(doc.Data, protectionSealer{
+			Sealer:       addr,
+			SignedBlocks: []protectionBlock{record},
+		})
+	}
+	sort.Slice(doc.Data, func(i, j int) bool {
+		return doc.Data[i].Sealer.Hex() < doc.Data[j].Sealer.Hex()
+	})
+	return json.MarshalIndent(doc, "", "  ")
+}
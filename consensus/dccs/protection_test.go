@@ -0,0 +1,148 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dccs
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// sealWith signs a header through the slashing protection with a signer that
+// always succeeds.
+func sealWith(p *SlashingProtection, genesis common.Hash, sealer common.Address, number uint64, sealHash common.Hash) error {
+	_, err := p.sign(genesis, sealer, number, sealHash, func() ([]byte, error) {
+		return make([]byte, extraSeal), nil
+	})
+	return err
+}
+
+// Tests that the slashing protection refuses conflicting signatures and that
+// the signing history survives a restart.
+func TestSlashingProtection(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dccs-protection")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, ProtectionFile)
+
+	var (
+		genesis = common.HexToHash("0x01")
+		sealer  = common.HexToAddress("0xaa")
+		other   = common.HexToAddress("0xbb")
+		hashA   = common.HexToHash("0x0a")
+		hashB   = common.HexToHash("0x0b")
+	)
+	protection, err := NewSlashingProtection(path)
+	if err != nil {
+		t.Fatalf("failed to create protection: %v", err)
+	}
+	// A failed signature must not lock the height
+	failure := errors.New("signer unavailable")
+	if _, err := protection.sign(genesis, sealer, 10, hashB, func() ([]byte, error) { return nil, failure }); err != failure {
+		t.Fatalf("failed signature error mismatch: have %v, want %v", err, failure)
+	}
+	if err := sealWith(protection, genesis, sealer, 10, hashA); err != nil {
+		t.Fatalf("first signature refused: %v", err)
+	}
+	if err := sealWith(protection, genesis, sealer, 10, hashA); err != nil {
+		t.Fatalf("repeated signature refused: %v", err)
+	}
+	if err := sealWith(protection, genesis, sealer, 10, hashB); err != errDoubleSign {
+		t.Fatalf("double signature error mismatch: have %v, want %v", err, errDoubleSign)
+	}
+	if err := sealWith(protection, genesis, other, 10, hashB); err != nil {
+		t.Fatalf("other sealer refused: %v", err)
+	}
+	// Reopen the database and ensure the history is retained
+	protection, err = NewSlashingProtection(path)
+	if err != nil {
+		t.Fatalf("failed to reopen protection: %v", err)
+	}
+	if err := sealWith(protection, genesis, sealer, 9, hashB); err != errStaleSign {
+		t.Fatalf("stale signature error mismatch: have %v, want %v", err, errStaleSign)
+	}
+	if err := sealWith(protection, genesis, sealer, 10, hashB); err != errDoubleSign {
+		t.Fatalf("double signature error mismatch: have %v, want %v", err, errDoubleSign)
+	}
+	if err := sealWith(protection, common.HexToHash("0x02"), sealer, 11, hashB); err != errProtectionGenesis {
+		t.Fatalf("genesis error mismatch: have %v, want %v", err, errProtectionGenesis)
+	}
+	if err := sealWith(protection, genesis, sealer, 11, hashB); err != nil {
+		t.Fatalf("next signature refused: %v", err)
+	}
+}
+
+// Tests that signing histories can be moved between nodes through the
+// interchange format.
+func TestSlashingProtectionInterchange(t *testing.T) {
+	var (
+		genesis = common.HexToHash("0x01")
+		sealer  = common.HexToAddress("0xaa")
+		other   = common.HexToAddress("0xbb")
+	)
+	source, _ := NewSlashingProtection("")
+	if err := sealWith(source, genesis, sealer, 20, common.HexToHash("0x0a")); err != nil {
+		t.Fatalf("signature refused: %v", err)
+	}
+	export := new(bytes.Buffer)
+	if err := source.Export(export); err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+	// Importing into a different network must fail
+	target, _ := NewSlashingProtection("")
+	if err := target.Import(bytes.NewReader(export.Bytes()), common.HexToHash("0x02")); err != errProtectionGenesis {
+		t.Fatalf("genesis error mismatch: have %v, want %v", err, errProtectionGenesis)
+	}
+	if err := target.Import(bytes.NewReader(export.Bytes()), genesis); err != nil {
+		t.Fatalf("failed to import: %v", err)
+	}
+	if err := sealWith(target, genesis, sealer, 20, common.HexToHash("0x0b")); err != errDoubleSign {
+		t.Fatalf("double signature error mismatch: have %v, want %v", err, errDoubleSign)
+	}
+	// Import a foreign history listing several blocks, one of them conflicting
+	foreign := `{
+		"metadata": {"interchange_format_version": "1"},
+		"data": [
+			{"sealer": "0x00000000000000000000000000000000000000aa", "signed_blocks": [{"number": "20", "seal_hash": "0x000000000000000000000000000000000000000000000000000000000000000c"}]},
+			{"sealer": "0x00000000000000000000000000000000000000bb", "signed_blocks": [{"number": "5"}, {"number": "7"}]}
+		]
+	}`
+	if err := target.Import(strings.NewReader(foreign), genesis); err != nil {
+		t.Fatalf("failed to import foreign history: %v", err)
+	}
+	if err := sealWith(target, genesis, sealer, 20, common.HexToHash("0x0a")); err != errDoubleSign {
+		t.Fatalf("conflicting height not locked: have %v, want %v", err, errDoubleSign)
+	}
+	if err := sealWith(target, genesis, other, 7, common.HexToHash("0x0a")); err != errDoubleSign {
+		t.Fatalf("unknown seal hash not locked: have %v, want %v", err, errDoubleSign)
+	}
+	if err := sealWith(target, genesis, other, 6, common.HexToHash("0x0a")); err != errStaleSign {
+		t.Fatalf("stale signature error mismatch: have %v, want %v", err, errStaleSign)
+	}
+	// Unknown format versions must be rejected
+	if err := target.Import(strings.NewReader(`{"metadata": {"interchange_format_version": "2"}}`), genesis); err == nil {
+		t.Fatalf("unsupported version accepted")
+	}
+}
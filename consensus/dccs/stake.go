@@ -0,0 +1,129 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dccs
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/nexty/governance"
+	"github.com/ethereum/go-ethereum/contracts/nexty/ntf"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// StakeBackend is the chain access needed to stake a sealer: calling and
+// transacting with the consensus contracts, and waiting for the receipts.
+type StakeBackend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+}
+
+// StakeSealer stakes a sealer through the governance contract on behalf of the
+// coinbase account signing opts: the missing part of the required NTF stake is
+// approved and deposited, then the sealer is joined. The coinbase must differ
+// from the sealer and already hold the NTF. Steps already done are skipped, so
+// an interrupted staking can be resumed.
+func StakeSealer(ctx context.Context, backend StakeBackend, opts *bind.TransactOpts, sealer common.Address) error {
+	gov, err := governance.NewNextyGovernance(params.GovernanceAddress, backend)
+	if err != nil {
+		return err
+	}
+	token, err := ntf.NewNtfToken(params.TokenAddress, backend)
+	if err != nil {
+		return err
+	}
+	call := &bind.CallOpts{Context: ctx}
+	coinbase := opts.From
+
+	if coinbase == sealer {
+		return fmt.Errorf("sealer %x cannot stake itself, use a separate coinbase account", sealer)
+	}
+	// Nothing to do if the sealer already joined, fail if it did with someone else
+	joined, err := gov.SignerCoinbase(call, sealer)
+	if err != nil {
+		return err
+	}
+	if joined == coinbase {
+		log.Info("Sealer already staked", "sealer", sealer, "coinbase", coinbase)
+		return nil
+	}
+	if joined != (common.Address{}) {
+		return fmt.Errorf("sealer %x already joined by %x", sealer, joined)
+	}
+	// Deposit whatever is missing from the required stake
+	required, err := gov.StakeRequire(call)
+	if err != nil {
+		return err
+	}
+	deposited, err := gov.GetBalance(call, coinbase)
+	if err != nil {
+		return err
+	}
+	if missing := new(big.Int).Sub(required, deposited); missing.Sign() > 0 {
+		balance, err := token.BalanceOf(call, coinbase)
+		if err != nil {
+			return err
+		}
+		if balance.Cmp(missing) < 0 {
+			return fmt.Errorf("insufficient NTF: have %v, want %v", balance, missing)
+		}
+		if err := transactAndWait(ctx, backend, "approve", func() (*types.Transaction, error) {
+			return token.Approve(transactOpts(ctx, opts), params.GovernanceAddress, missing)
+		}); err != nil {
+			return err
+		}
+		if err := transactAndWait(ctx, backend, "deposit", func() (*types.Transaction, error) {
+			return gov.Deposit(transactOpts(ctx, opts), missing)
+		}); err != nil {
+			return err
+		}
+	}
+	return transactAndWait(ctx, backend, "join", func() (*types.Transaction, error) {
+		return gov.Join(transactOpts(ctx, opts), sealer)
+	})
+}
+
+// transactOpts returns a copy of the transact options bound to a context.
+func transactOpts(ctx context.Context, opts *bind.TransactOpts) *bind.TransactOpts {
+	bound := *opts
+	bound.Context = ctx
+	return &bound
+}
+
+// transactAndWait sends a transaction and waits until it is mined, failing
+// if the transaction was reverted.
+func transactAndWait(ctx context.Context, backend bind.DeployBackend, method string, transact func() (*types.Transaction, error)) error {
+	tx, err := transact()
+	if err != nil {
+		return fmt.Errorf("failed to send %s: %v", method, err)
+	}
+	log.Info("Sent staking transaction", "method", method, "hash", tx.Hash())
+
+	receipt, err := bind.WaitMined(ctx, backend, tx)
+	if err != nil {
+		return fmt.Errorf("failed to wait for %s: %v", method, err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("%s transaction %x reverted", method, tx.Hash())
+	}
+	return nil
+}
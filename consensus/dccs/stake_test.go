@@ -0,0 +1,88 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dccs
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/nexty/governance"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that a sealer outside of the genesis set is staked and joined through
+// the governance contract, and that staking it again is a no-op.
+func TestStakeSealer(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	owner := crypto.PubkeyToAddress(key.PublicKey)
+	genesisSealer := common.HexToAddress("0x0000000000000000000000000000000000000aaa")
+	sealer := common.HexToAddress("0x0000000000000000000000000000000000000bbb")
+
+	config := chainConfig()
+	config.Dccs = DeveloperConfig(2)
+
+	alloc, err := GenesisAlloc(&config, owner, []common.Address{genesisSealer})
+	if err != nil {
+		t.Fatalf("failed to create genesis accounts: %v", err)
+	}
+	alloc[owner] = core.GenesisAccount{Balance: big.NewInt(1e18)}
+	sim := backends.NewSimulatedBackend(alloc, genesisGasLimit)
+
+	// Mine the staking transactions as they come in
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(10 * time.Millisecond):
+				sim.Commit()
+			}
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	opts := bind.NewKeyedTransactor(key)
+	if err := StakeSealer(ctx, sim, opts, owner); err == nil {
+		t.Fatalf("coinbase staked itself as sealer")
+	}
+	for i := 0; i < 2; i++ {
+		if err := StakeSealer(ctx, sim, opts, sealer); err != nil {
+			t.Fatalf("staking attempt %d failed: %v", i, err)
+		}
+	}
+	gov, _ := governance.NewNextyGovernanceCaller(params.GovernanceAddress, sim)
+	if signer, err := gov.Signers(nil, big.NewInt(1)); err != nil || signer != sealer {
+		t.Errorf("sealer mismatch: have %x, want %x, err %v", signer, sealer, err)
+	}
+	if coinbase, err := gov.SignerCoinbase(nil, sealer); err != nil || coinbase != owner {
+		t.Errorf("coinbase mismatch: have %x, want %x, err %v", coinbase, owner, err)
+	}
+	stake := new(big.Int).Mul(new(big.Int).SetUint64(config.Dccs.StakeRequire), big.NewInt(1e+18))
+	if balance, err := gov.GetBalance(nil, owner); err != nil || balance.Cmp(stake) != 0 {
+		t.Errorf("stake mismatch: have %v, want %v, err %v", balance, stake, err)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the gonex library.
+//
+// The gonex library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gonex library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gonex library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package precompiled encodes the inputs of the gonex specific pre-compiled
+// contracts introduced in the CoLoa hardfork.
+//
+// All values are big endian and left padded to 32 bytes, so the inputs can be
+// built in Solidity with abi.encodePacked of uint256 and bytes32 values.
+//
+// The ethashVerify contract at 0x00..FE verifies the Ethash seal of an ETH or
+// ETC header. Its input is exactly 160 bytes:
+//
+//	[0:32]    uint256 block number
+//	[32:64]   uint256 block difficulty
+//	[64:96]   uint256 nonce
+//	[96:128]  bytes32 mix digest
+//	[128:160] bytes32 seal hash (the header hash without nonce and mix digest)
+//
+// The vdfVerify contract at 0x00..FF verifies a Wesolowski VDF output. Its input
+// is 96 bytes followed by the output (y, proof):
+//
+//	[0:32]    uint256 bit size of the discriminant
+//	[32:64]   uint256 number of iterations
+//	[64:96]   bytes32 seed
+//	[96:]     bytes   output (y, proof)
+//
+// The contract expects an output of (bitSize+16)>>2 bytes while the generator
+// produces ((bitSize+16)>>4)*4 bytes, so only the bit sizes with a remainder
+// of 0 to 3 modulo 16 can be verified, e.g. 1024 or 2048.
+//
+// Both contracts return a 32 bytes boolean word. Malformed input lengths make
+// the call fail, consuming all the gas provided.
+//
+// The ethashVerify contract costs a flat params.EthashVerifyGas. The vdfVerify
+// contract costs params.VDFVerifyBaseGas plus params.VDFVerifyPerBitGas for
+// every bit of the discriminant.
+package precompiled
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	// EthashVerifyAddress is the address of the ethashVerify pre-compiled contract.
+	EthashVerifyAddress = common.BytesToAddress([]byte{0xFE})
+
+	// VDFVerifyAddress is the address of the vdfVerify pre-compiled contract.
+	VDFVerifyAddress = common.BytesToAddress([]byte{0xFF})
+)
+
+const (
+	// EthashVerifyInputSize is the exact size of the ethashVerify input.
+	EthashVerifyInputSize = 32 * 5
+
+	// vdfVerifyHeaderSize is the size of the vdfVerify input before the output.
+	vdfVerifyHeaderSize = 32 * 3
+)
+
+var (
+	errBadEthashInputLen = errors.New("bad ethashVerify input length")
+	errBadVDFInputLen    = errors.New("bad vdfVerify input length")
+	errBadResultLen      = errors.New("bad result length")
+)
+
+// VDFOutputSize returns the size in bytes of the VDF output (y, proof) for a
+// discriminant of bitSize bits.
+func VDFOutputSize(bitSize uint64) uint64 {
+	return ((bitSize + 16) >> 4) * 4
+}
+
+// VDFBitSizeSupported returns whether the vdfVerify contract accepts the
+// output generated for a discriminant of bitSize bits.
+func VDFBitSizeSupported(bitSize uint64) bool {
+	return (bitSize+16)>>2 == VDFOutputSize(bitSize)
+}
+
+// PackVDFVerify encodes the input of the vdfVerify contract.
+func PackVDFVerify(seed common.Hash, iteration uint64, bitSize uint64, output []byte) ([]byte, error) {
+	if !VDFBitSizeSupported(bitSize) {
+		return nil, fmt.Errorf("VDF bit size %d not supported by the vdfVerify contract", bitSize)
+	}
+	if size := VDFOutputSize(bitSize); uint64(len(output)) != size {
+		return nil, fmt.Errorf("bad VDF output size: have %d, want %d for %d bits", len(output), size, bitSize)
+	}
+	input := make([]byte, 0, vdfVerifyHeaderSize+len(output))
+	input = append(input, common.BigToHash(new(big.Int).SetUint64(bitSize)).Bytes()...)
+	input = append(input, common.BigToHash(new(big.Int).SetUint64(iteration)).Bytes()...)
+	input = append(input, seed.Bytes()...)
+	return append(input, output...), nil
+}
+
+// UnpackVDFVerify decodes the input of the vdfVerify contract.
+func UnpackVDFVerify(input []byte) (seed common.Hash, iteration uint64, bitSize uint64, output []byte, err error) {
+	if len(input) < vdfVerifyHeaderSize {
+		return common.Hash{}, 0, 0, nil, errBadVDFInputLen
+	}
+	size, iter := new(big.Int).SetBytes(input[:32]), new(big.Int).SetBytes(input[32:64])
+	if !size.IsUint64() || !iter.IsUint64() || uint64(len(input)-vdfVerifyHeaderSize) != (size.Uint64()+16)>>2 {
+		return common.Hash{}, 0, 0, nil, errBadVDFInputLen
+	}
+	return common.BytesToHash(input[64:96]), iter.Uint64(), size.Uint64(), common.CopyBytes(input[vdfVerifyHeaderSize:]), nil
+}
+
+// PackEthashVerify encodes the input of the ethashVerify contract.
+func PackEthashVerify(number *big.Int, difficulty *big.Int, nonce uint64, mixDigest common.Hash, sealHash common.Hash) []byte {
+	input := make([]byte, 0, EthashVerifyInputSize)
+	input = append(input, common.BigToHash(number).Bytes()...)
+	input = append(input, common.BigToHash(difficulty).Bytes()...)
+	input = append(input, common.BigToHash(new(big.Int).SetUint64(nonce)).Bytes()...)
+	input = append(input, mixDigest.Bytes()...)
+	return append(input, sealHash.Bytes()...)
+}
+
+// PackEthashVerifyHeader encodes the input of the ethashVerify contract to
+// verify the seal of an Ethash header.
+func PackEthashVerifyHeader(header *types.Header) []byte {
+	return PackEthashVerify(header.Number, header.Difficulty, header.Nonce.Uint64(), header.MixDigest, ethash.SealHash(header))
+}
+
+// UnpackEthashVerify decodes the input of the ethashVerify contract.
+func UnpackEthashVerify(input []byte) (number *big.Int, difficulty *big.Int, nonce uint64, mixDigest common.Hash, sealHash common.Hash, err error) {
+	if len(input) != EthashVerifyInputSize {
+		return nil, nil, 0, common.Hash{}, common.Hash{}, errBadEthashInputLen
+	}
+	n := new(big.Int).SetBytes(input[64:96])
+	if !n.IsUint64() {
+		return nil, nil, 0, common.Hash{}, common.Hash{}, errors.New("nonce overflows 64 bits")
+	}
+	return new(big.Int).SetBytes(input[:32]), new(big.Int).SetBytes(input[32:64]), n.Uint64(),
+		common.BytesToHash(input[96:128]), common.BytesToHash(input[128:160]), nil
+}
+
+// UnpackResult decodes the boolean word returned by the verification contracts.
+func UnpackResult(output []byte) (bool, error) {
+	if len(output) != common.HashLength {
+		return false, errBadResultLen
+	}
+	return common.BytesToHash(output) == common.BigToHash(common.Big1), nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the gonex library.
+//
+// The gonex library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gonex library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gonex library. If not, see <http://www.gnu.org/licenses/>.
+
+package precompiled
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestVDFVerifyPacking(t *testing.T) {
+	seed := common.HexToHash("0x0123456789abcdef")
+	output := bytes.Repeat([]byte{0xab}, int(VDFOutputSize(2048)))
+
+	input, err := PackVDFVerify(seed, 1234567, 2048, output)
+	if err != nil {
+		t.Fatalf("failed to pack input: %v", err)
+	}
+	if len(input) != 96+516 {
+		t.Fatalf("input length mismatch: have %d, want %d", len(input), 96+516)
+	}
+	haveSeed, iteration, bitSize, haveOutput, err := UnpackVDFVerify(input)
+	if err != nil {
+		t.Fatalf("failed to unpack input: %v", err)
+	}
+	if haveSeed != seed || iteration != 1234567 || bitSize != 2048 || !bytes.Equal(haveOutput, output) {
+		t.Fatalf("unpacked input mismatch: %x %d %d %x", haveSeed, iteration, bitSize, haveOutput)
+	}
+	if _, err := PackVDFVerify(seed, 1234567, 2047, make([]byte, VDFOutputSize(2047))); err == nil {
+		t.Fatalf("unsupported bit size accepted")
+	}
+	if _, err := PackVDFVerify(seed, 1234567, 2048, output[1:]); err == nil {
+		t.Fatalf("short output accepted")
+	}
+}
+
+func TestEthashVerifyPacking(t *testing.T) {
+	var (
+		number     = big.NewInt(8308554)
+		difficulty = big.NewInt(2337148138724878)
+		nonce      = uint64(0xef3f3ed00272024e)
+		mixDigest  = common.HexToHash("2266f0c6e0451fa681f0fdf9887fb261ef12e1ff2932d4d0f140a6710163904e")
+		sealHash   = common.HexToHash("83e13821e9bc6c7dc74eccdca612b556135dfcd1674d404e225899aecec7f31f")
+	)
+	input := PackEthashVerify(number, difficulty, nonce, mixDigest, sealHash)
+	if len(input) != EthashVerifyInputSize {
+		t.Fatalf("input length mismatch: have %d, want %d", len(input), EthashVerifyInputSize)
+	}
+	n, d, o, m, s, err := UnpackEthashVerify(input)
+	if err != nil {
+		t.Fatalf("failed to unpack input: %v", err)
+	}
+	if n.Cmp(number) != 0 || d.Cmp(difficulty) != 0 || o != nonce || m != mixDigest || s != sealHash {
+		t.Fatalf("unpacked input mismatch: %v %v %x %x %x", n, d, o, m, s)
+	}
+}
+
+func TestUnpackResult(t *testing.T) {
+	if valid, err := UnpackResult(common.BigToHash(common.Big1).Bytes()); err != nil || !valid {
+		t.Fatalf("true result mismatch: %v %v", valid, err)
+	}
+	if valid, err := UnpackResult(make([]byte, 32)); err != nil || valid {
+		t.Fatalf("false result mismatch: %v %v", valid, err)
+	}
+	if _, err := UnpackResult(nil); err == nil {
+		t.Fatalf("empty result accepted")
+	}
+}
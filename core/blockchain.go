@@ -33,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -81,6 +82,7 @@ const (
 	maxFutureBlocks     = 256
 	maxTimeFutureBlocks = 30
 	badBlockLimit       = 10
+	historyCacheLimit   = 4
 	TriesInMemory       = 128
 
 	// BlockChainVersion ensures that an incompatible database forces a resync from scratch.
@@ -114,6 +116,8 @@ type CacheConfig struct {
 	TrieDirtyLimit      int           // Memory limit (MB) at which to start flushing dirty trie nodes to disk
 	TrieDirtyDisabled   bool          // Whether to disable trie write caching and GC altogether (archive node)
 	TrieTimeLimit       time.Duration // Time limit after which to flush the current in-memory trie to disk
+	SnapshotLimit       int           // Memory allowance (MB) to use for caching snapshot entries in memory, 0 disables snapshots
+	StateHistory        uint64        // Number of recent blocks whose state can be rebuilt from reverse diffs, 0 disables them
 }
 
 // BlockChain represents the canonical chain given a database with a genesis
@@ -154,12 +158,14 @@ type BlockChain struct {
 	currentFastBlock atomic.Value // Current head of the fast-sync chain (may be above the block chain!)
 
 	stateCache    state.Database // State database to reuse between imports (contains state cache)
+	snaps         *snapshot.Tree // Snapshot tree for fast trie leaf access
 	bodyCache     *lru.Cache     // Cache for the most recent block bodies
 	bodyRLPCache  *lru.Cache     // Cache for the most recent block bodies in RLP encoded format
 	receiptsCache *lru.Cache     // Cache for the most recent receipts per block
 	blockCache    *lru.Cache     // Cache for the most recent entire blocks
 	txLookupCache *lru.Cache     // Cache for the most recent transaction lookup data.
 	futureBlocks  *lru.Cache     // future blocks are blocks added for later processing
+	historyCache  *lru.Cache     // Cache for the state databases of the most recently rebuilt historic states
 
 	quit    chan struct{} // blockchain quit channel
 	running int32         // running must be called atomically
@@ -196,6 +202,7 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	txLookupCache, _ := lru.New(txLookupCacheLimit)
 	futureBlocks, _ := lru.New(maxFutureBlocks)
 	badBlocks, _ := lru.New(badBlockLimit)
+	historyCache, _ := lru.New(historyCacheLimit)
 
 	bc := &BlockChain{
 		chainConfig:    chainConfig,
@@ -211,6 +218,7 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 		blockCache:     blockCache,
 		txLookupCache:  txLookupCache,
 		futureBlocks:   futureBlocks,
+		historyCache:   historyCache,
 		engine:         engine,
 		vmConfig:       vmConfig,
 		badBlocks:      badBlocks,
@@ -313,6 +321,10 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 			}
 		}
 	}
+	// Load any existing snapshot, regenerating it if loading failed
+	if bc.cacheConfig.SnapshotLimit > 0 {
+		bc.snaps = snapshot.New(bc.db, bc.stateCache.TrieDB(), bc.cacheConfig.SnapshotLimit, bc.CurrentBlock().Root())
+	}
 	// Take ownership of this particular state
 	go bc.update()
 	return bc, nil
@@ -468,12 +480,18 @@ func (bc *BlockChain) SetHead(head uint64) error {
 
 			// Remove the hash <-> number mapping from the active store.
 			rawdb.DeleteHeaderNumber(db, hash)
+			rawdb.DeleteFailureReasons(db, hash, num)
 		} else {
 			// Remove relative body and receipts from the active store.
 			// The header, total difficulty and canonical hash will be
 			// removed in the hc.SetHead function.
 			rawdb.DeleteBody(db, hash, num)
 			rawdb.DeleteReceipts(db, hash, num)
+			rawdb.DeleteFailureReasons(db, hash, num)
+		}
+		// Forget the state root of the rewound block for historic state lookups
+		if header := rawdb.ReadHeader(bc.db, hash, num); header != nil {
+			bc.unindexStateRoot(db, header.Root, num)
 		}
 		// Todo(rjl493456442) txlookup, bloombits, etc
 	}
@@ -487,7 +505,14 @@ func (bc *BlockChain) SetHead(head uint64) error {
 	bc.txLookupCache.Purge()
 	bc.futureBlocks.Purge()
 
-	return bc.loadLastState()
+	if err := bc.loadLastState(); err != nil {
+		return err
+	}
+	// The snapshot cannot be rewound, regenerate it for the new head
+	if bc.snaps != nil {
+		bc.snaps.Rebuild(bc.CurrentBlock().Root())
+	}
+	return nil
 }
 
 // FastSyncCommitHead sets the current head block to the one defined by the hash
@@ -507,6 +532,10 @@ func (bc *BlockChain) FastSyncCommitHead(hash common.Hash) error {
 	headBlockGauge.Update(int64(block.NumberU64()))
 	bc.chainmu.Unlock()
 
+	// Regenerate the snapshot for the synced state
+	if bc.snaps != nil {
+		bc.snaps.Rebuild(block.Root())
+	}
 	log.Info("Committed new head block", "number", block.Number(), "hash", hash)
 	return nil
 }
@@ -545,7 +574,17 @@ func (bc *BlockChain) State() (*state.StateDB, error) {
 
 // StateAt returns a new mutable state based on a particular point in time.
 func (bc *BlockChain) StateAt(root common.Hash) (*state.StateDB, error) {
-	return state.New(root, bc.stateCache)
+	statedb, err := state.NewWithSnapshot(root, bc.stateCache, bc.snaps)
+	if err == nil || bc.cacheConfig.StateHistory == 0 {
+		return statedb, err
+	}
+	// The state is gone, try rebuilding it from the reverse diffs
+	historic, herr := bc.historicState(root)
+	if herr != nil {
+		log.Debug("Failed to rebuild historic state", "root", root, "err", herr)
+		return nil, err
+	}
+	return historic, nil
 }
 
 // StateCache returns the caching database underpinning the blockchain instance.
@@ -851,6 +890,14 @@ func (bc *BlockChain) Stop() {
 
 	bc.wg.Wait()
 
+	// Flatten the snapshot into the disk layer of the head state, which is
+	// written below too, so that the next start can reuse it.
+	if bc.snaps != nil {
+		if err := bc.snaps.Cap(bc.CurrentBlock().Root(), 0); err != nil {
+			log.Error("Failed to flatten state snapshot", "err", err)
+		}
+		bc.snaps.Close()
+	}
 	// Ensure the state of a recent block is also stored to disk before exiting.
 	// We're writing three different states to catch different restart scenarios:
 	//  - HEAD:     So we don't need to reprocess any blocks in the general case
@@ -1323,6 +1370,10 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	if err != nil {
 		return NonStatTy, err
 	}
+	state.UpdateSnapshot(root)
+	if bc.cacheConfig.StateHistory > 0 {
+		bc.writeStateDiff(block, root)
+	}
 	triedb := bc.stateCache.TrieDB()
 
 	// Checkpoint snapshot for ThangLong consensus.
@@ -1387,6 +1438,7 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	// Write other block data using a batch.
 	batch := bc.db.NewBatch()
 	rawdb.WriteReceipts(batch, block.Hash(), block.NumberU64(), receipts)
+	rawdb.WriteFailureReasons(batch, block.Hash(), block.NumberU64(), receipts)
 
 	// If the total difficulty is higher than our known, add it to the canonical chain
 	// Second clause in the if statement reduces the vulnerability to selfish mining.
@@ -1403,6 +1455,9 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 		// Write the positional metadata for transaction/receipt lookups and preimages
 		rawdb.WriteTxLookupEntries(batch, block)
 		rawdb.WritePreimages(batch, state.Preimages())
+		if bc.cacheConfig.StateHistory > 0 {
+			bc.indexStateRoot(batch, block)
+		}
 
 		status = CanonStatTy
 	} else {
@@ -1415,6 +1470,12 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	// Set new head.
 	if status == CanonStatTy {
 		bc.insert(block)
+
+		// A reorg onto blocks without snapshot layers leaves the snapshot behind
+		if bc.snaps != nil && bc.snaps.Snapshot(root) == nil {
+			log.Warn("Snapshot missing for new head, regenerating", "number", block.Number(), "root", root)
+			bc.snaps.Rebuild(root)
+		}
 	}
 	bc.futureBlocks.Remove(block.Hash())
 	return status, nil
@@ -1638,7 +1699,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, []
 		if parent == nil {
 			parent = bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
 		}
-		statedb, err := state.New(parent.Root, bc.stateCache)
+		statedb, err := state.NewWithSnapshot(parent.Root, bc.stateCache, bc.snaps)
 		if err != nil {
 			return it.index, events, coalescedLogs, err
 		}
@@ -2013,6 +2074,16 @@ func (bc *BlockChain) reorg(oldBlock, newBlock *types.Block) error {
 		}
 		rawdb.DeleteCanonicalHash(batch, i)
 	}
+	// Move the historic state roots over to the new chain, the dropped ones
+	// first as both chains may commit to the same state
+	if bc.cacheConfig.StateHistory > 0 {
+		for _, block := range oldChain {
+			bc.unindexStateRoot(batch, block.Root(), block.NumberU64())
+		}
+		for i := len(newChain) - 1; i >= 1; i-- {
+			bc.indexStateRoot(batch, newChain[i])
+		}
+	}
 	batch.Write()
 	// If any logs need to be fired, do it now. In theory we could avoid creating
 	// this goroutine if there are no events to fire, but realistcally that only
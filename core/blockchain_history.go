@@ -0,0 +1,146 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/mclock"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// writeStateDiff stores the reverse diff undoing the state transition of the
+// block. Failures are only logged, as they merely shorten the history available.
+func (bc *BlockChain) writeStateDiff(block *types.Block, root common.Hash) {
+	number := block.NumberU64()
+	if number == 0 {
+		return
+	}
+	parent := bc.GetHeader(block.ParentHash(), number-1)
+	if parent == nil {
+		return
+	}
+	diff, err := state.NewReverseDiff(bc.stateCache, parent.Root, root)
+	if err != nil {
+		log.Warn("Failed to compute reverse state diff", "number", number, "hash", block.Hash(), "err", err)
+		return
+	}
+	blob, err := rlp.EncodeToBytes(diff)
+	if err != nil {
+		log.Warn("Failed to encode reverse state diff", "number", number, "hash", block.Hash(), "err", err)
+		return
+	}
+	rawdb.WriteStateDiffRLP(bc.db, block.Hash(), number, blob)
+}
+
+// indexStateRoot maps the state root of a block becoming canonical to its
+// number for historic state lookups, and forgets the one of the block leaving
+// the history window, unless a newer block commits to the same state.
+func (bc *BlockChain) indexStateRoot(db ethdb.KeyValueWriter, block *types.Block) {
+	number := block.NumberU64()
+	rawdb.WriteStateRootNumber(db, block.Root(), number)
+
+	if number > bc.cacheConfig.StateHistory {
+		if header := bc.GetHeaderByNumber(number - bc.cacheConfig.StateHistory - 1); header != nil && header.Root != block.Root() {
+			if old := rawdb.ReadStateRootNumber(bc.db, header.Root); old != nil && *old == header.Number.Uint64() {
+				rawdb.DeleteStateRootNumber(db, header.Root)
+			}
+		}
+	}
+}
+
+// unindexStateRoot forgets the state root of a block no longer canonical,
+// unless it is mapped to another block.
+func (bc *BlockChain) unindexStateRoot(db ethdb.KeyValueWriter, root common.Hash, number uint64) {
+	if old := rawdb.ReadStateRootNumber(bc.db, root); old != nil && *old == number {
+		rawdb.DeleteStateRootNumber(db, root)
+	}
+}
+
+// historicState rebuilds a state no longer in the database, starting from the
+// nearest newer canonical state persisted to disk and undoing the transitions
+// in between with the reverse diffs of their blocks.
+func (bc *BlockChain) historicState(root common.Hash) (*state.StateDB, error) {
+	if db, ok := bc.historyCache.Get(root); ok {
+		return state.New(root, db.(state.Database))
+	}
+	number := rawdb.ReadStateRootNumber(bc.db, root)
+	if number == nil {
+		return nil, fmt.Errorf("state %x not in history", root)
+	}
+	head := bc.CurrentBlock().NumberU64()
+	if *number > head || head-*number > bc.cacheConfig.StateHistory {
+		return nil, fmt.Errorf("state of block #%d beyond history window", *number)
+	}
+	// Find the nearest newer state persisted, the ones only held in memory are
+	// of no use as they may be flushed or dereferenced any time
+	var start *types.Header
+	for n := *number + 1; n <= head; n++ {
+		header := bc.GetHeaderByNumber(n)
+		if header == nil {
+			return nil, fmt.Errorf("missing header #%d", n)
+		}
+		if has, _ := bc.db.Has(header.Root[:]); has {
+			start = header
+			break
+		}
+	}
+	if start == nil {
+		return nil, fmt.Errorf("no persisted state above block #%d", *number)
+	}
+	// Rewind the state in a dedicated database, keeping it apart from the live one
+	var (
+		begin = mclock.Now()
+		db    = state.NewDatabase(bc.db)
+	)
+	rewinder, err := state.NewRewinder(db, start.Root)
+	if err != nil {
+		return nil, err
+	}
+	for n := start.Number.Uint64(); n > *number; n-- {
+		hash := rawdb.ReadCanonicalHash(bc.db, n)
+		blob := rawdb.ReadStateDiffRLP(bc.db, hash, n)
+		if len(blob) == 0 {
+			return nil, fmt.Errorf("missing reverse state diff #%d [%x…]", n, hash[:4])
+		}
+		diff := new(state.ReverseDiff)
+		if err := rlp.DecodeBytes(blob, diff); err != nil {
+			return nil, fmt.Errorf("invalid reverse state diff #%d [%x…]: %v", n, hash[:4], err)
+		}
+		if err := rewinder.Apply(diff); err != nil {
+			return nil, fmt.Errorf("failed to undo block #%d [%x…]: %v", n, hash[:4], err)
+		}
+	}
+	rebuilt, err := rewinder.Commit()
+	if err != nil {
+		return nil, err
+	}
+	if rebuilt != root {
+		return nil, fmt.Errorf("rebuilt state root mismatch: have %x, want %x", rebuilt, root)
+	}
+	bc.historyCache.Add(root, db)
+	log.Debug("Rebuilt historic state", "number", *number, "root", root, "from", start.Number, "elapsed", common.PrettyDuration(time.Duration(mclock.Now()-begin)))
+
+	return state.New(root, db)
+}
@@ -171,6 +171,28 @@ func WriteFastTrieProgress(db ethdb.KeyValueWriter, count uint64) {
 	}
 }
 
+// ReadStateSyncJournal retrieves the serialized progress of an interrupted fast
+// sync state download.
+func ReadStateSyncJournal(db ethdb.KeyValueReader) []byte {
+	data, _ := db.Get(stateSyncJournalKey)
+	return data
+}
+
+// WriteStateSyncJournal stores the serialized progress of a fast sync state
+// download, to resume it across restarts.
+func WriteStateSyncJournal(db ethdb.KeyValueWriter, journal []byte) {
+	if err := db.Put(stateSyncJournalKey, journal); err != nil {
+		log.Crit("Failed to store state sync journal", "err", err)
+	}
+}
+
+// DeleteStateSyncJournal deletes the progress of a fast sync state download.
+func DeleteStateSyncJournal(db ethdb.KeyValueWriter) {
+	if err := db.Delete(stateSyncJournalKey); err != nil {
+		log.Crit("Failed to remove state sync journal", "err", err)
+	}
+}
+
 // ReadHeaderRLP retrieves a block header in its raw RLP database encoding.
 func ReadHeaderRLP(db ethdb.Reader, hash common.Hash, number uint64) rlp.RawValue {
 	data, _ := db.Ancient(freezerHeaderTable, number)
@@ -433,6 +455,11 @@ func ReadReceipts(db ethdb.Reader, hash common.Hash, number uint64, config *para
 		log.Error("Failed to derive block receipts fields", "hash", hash, "number", number, "err", err)
 		return nil
 	}
+	for index, reason := range ReadFailureReasons(db, hash, number) {
+		if index < uint64(len(receipts)) {
+			receipts[index].FailureReason = reason
+		}
+	}
 	return receipts
 }
 
@@ -460,6 +487,64 @@ func DeleteReceipts(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
 	}
 }
 
+// failureReasonRLP is the storage encoding of the failure reason of a transaction.
+type failureReasonRLP struct {
+	Index  uint64
+	Reason string
+}
+
+// ReadFailureReasons retrieves the failure reasons of the failed transactions
+// in a block, keyed by their index in the block.
+func ReadFailureReasons(db ethdb.Reader, hash common.Hash, number uint64) map[uint64]string {
+	data, _ := db.Get(failureReasonKey(number, hash))
+	if len(data) == 0 {
+		return nil
+	}
+	var entries []failureReasonRLP
+	if err := rlp.DecodeBytes(data, &entries); err != nil {
+		log.Error("Invalid failure reasons RLP", "hash", hash, "err", err)
+		return nil
+	}
+	reasons := make(map[uint64]string, len(entries))
+	for _, entry := range entries {
+		reasons[entry.Index] = entry.Reason
+	}
+	return reasons
+}
+
+// WriteFailureReasons stores the failure reasons of the failed transactions in
+// a block. The reasons are not part of the consensus, so nothing is stored for
+// a block without any failed transaction.
+//
+// The reasons are only known to the node executing the block: the receipts of
+// fast synced blocks, received from the network, don't carry any and such blocks
+// have no reasons stored.
+func WriteFailureReasons(db ethdb.KeyValueWriter, hash common.Hash, number uint64, receipts types.Receipts) {
+	var entries []failureReasonRLP
+	for i, receipt := range receipts {
+		if receipt.Status == types.ReceiptStatusFailed && len(receipt.FailureReason) > 0 {
+			entries = append(entries, failureReasonRLP{Index: uint64(i), Reason: receipt.FailureReason})
+		}
+	}
+	if len(entries) == 0 {
+		return
+	}
+	bytes, err := rlp.EncodeToBytes(entries)
+	if err != nil {
+		log.Crit("Failed to encode failure reasons", "err", err)
+	}
+	if err := db.Put(failureReasonKey(number, hash), bytes); err != nil {
+		log.Crit("Failed to store failure reasons", "err", err)
+	}
+}
+
+// DeleteFailureReasons removes the failure reasons associated with a block hash.
+func DeleteFailureReasons(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(failureReasonKey(number, hash)); err != nil {
+		log.Crit("Failed to delete failure reasons", "err", err)
+	}
+}
+
 // ReadBlock retrieves an entire block corresponding to the hash, assembling it
 // back from the stored header and body. If either the header or body could not
 // be retrieved nil is returned.
@@ -518,18 +603,21 @@ func WriteAncientBlock(db ethdb.AncientWriter, block *types.Block, receipts type
 // DeleteBlock removes all block data associated with a hash.
 func DeleteBlock(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
 	DeleteReceipts(db, hash, number)
+	DeleteFailureReasons(db, hash, number)
 	DeleteHeader(db, hash, number)
 	DeleteBody(db, hash, number)
 	DeleteTd(db, hash, number)
+	DeleteStateDiff(db, hash, number)
 }
 
 // DeleteBlockWithoutNumber removes all block data associated with a hash, except
-// the hash to number mapping.
+// the hash to number mapping and the failure reasons, which are not frozen.
 func DeleteBlockWithoutNumber(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
 	DeleteReceipts(db, hash, number)
 	deleteHeaderWithoutNumber(db, hash, number)
 	DeleteBody(db, hash, number)
 	DeleteTd(db, hash, number)
+	DeleteStateDiff(db, hash, number)
 }
 
 // FindCommonAncestor returns the last common ancestor of two block headers
@@ -339,6 +339,43 @@ func TestBlockReceiptStorage(t *testing.T) {
 	}
 }
 
+// Tests that the failure reasons sidecar is stored, attached to the receipts and
+// removed along with the block.
+func TestFailureReasonStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	tx1 := types.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), 1, big.NewInt(1), nil)
+	tx2 := types.NewTransaction(2, common.HexToAddress("0x2"), big.NewInt(2), 2, big.NewInt(2), nil)
+	body := &types.Body{Transactions: types.Transactions{tx1, tx2}}
+
+	receipts := types.Receipts{
+		&types.Receipt{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 1, Logs: []*types.Log{}},
+		&types.Receipt{Status: types.ReceiptStatusFailed, CumulativeGasUsed: 2, Logs: []*types.Log{}, FailureReason: "out of gas"},
+	}
+	hash := common.BytesToHash([]byte{0x03, 0x14})
+	WriteBody(db, hash, 0, body)
+	WriteReceipts(db, hash, 0, receipts)
+
+	if reasons := ReadFailureReasons(db, hash, 0); reasons != nil {
+		t.Fatalf("non existent failure reasons returned: %v", reasons)
+	}
+	WriteFailureReasons(db, hash, 0, receipts)
+	if reasons := ReadFailureReasons(db, hash, 0); len(reasons) != 1 || reasons[1] != "out of gas" {
+		t.Fatalf("failure reasons mismatch: have %v", reasons)
+	}
+	rs := ReadReceipts(db, hash, 0, params.TestChainConfig)
+	if len(rs) != 2 {
+		t.Fatalf("receipts count mismatch: have %d, want 2", len(rs))
+	}
+	if rs[0].FailureReason != "" || rs[1].FailureReason != "out of gas" {
+		t.Fatalf("receipt failure reasons mismatch: have %q, %q", rs[0].FailureReason, rs[1].FailureReason)
+	}
+	DeleteBlock(db, hash, 0)
+	if reasons := ReadFailureReasons(db, hash, 0); reasons != nil {
+		t.Fatalf("deleted failure reasons returned: %v", reasons)
+	}
+}
+
 func checkReceiptsRLP(have, want types.Receipts) error {
 	if len(have) != len(want) {
 		return fmt.Errorf("receipts sizes mismatch: have %d, want %d", len(have), len(want))
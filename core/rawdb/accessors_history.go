@@ -0,0 +1,83 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ReadStateDiffRLP retrieves the reverse state diff of a block in RLP encoding,
+// which undoes the state changes of the block.
+func ReadStateDiffRLP(db ethdb.Reader, hash common.Hash, number uint64) rlp.RawValue {
+	data, _ := db.Ancient(freezerStateDiffTable, number)
+	if len(data) == 0 {
+		data, _ = db.Get(stateDiffKey(number, hash))
+		// In the background freezer is moving data from leveldb to flatten files.
+		// So during the first check for ancient db, the data is not yet in there,
+		// but when we reach into leveldb, the data was already moved. That would
+		// result in a not found error.
+		if len(data) == 0 {
+			data, _ = db.Ancient(freezerStateDiffTable, number)
+		}
+	}
+	return data
+}
+
+// WriteStateDiffRLP stores the RLP encoded reverse state diff of a block.
+func WriteStateDiffRLP(db ethdb.KeyValueWriter, hash common.Hash, number uint64, data rlp.RawValue) {
+	if err := db.Put(stateDiffKey(number, hash), data); err != nil {
+		log.Crit("Failed to store reverse state diff", "err", err)
+	}
+}
+
+// DeleteStateDiff removes the reverse state diff of a block.
+func DeleteStateDiff(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(stateDiffKey(number, hash)); err != nil {
+		log.Crit("Failed to delete reverse state diff", "err", err)
+	}
+}
+
+// ReadStateRootNumber retrieves the number of a block committing to the given
+// state root, among the blocks with reverse state diffs.
+func ReadStateRootNumber(db ethdb.KeyValueReader, root common.Hash) *uint64 {
+	data, _ := db.Get(stateRootKey(root))
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteStateRootNumber stores the number of a block committing to the given
+// state root.
+func WriteStateRootNumber(db ethdb.KeyValueWriter, root common.Hash, number uint64) {
+	if err := db.Put(stateRootKey(root), encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store state root to number mapping", "err", err)
+	}
+}
+
+// DeleteStateRootNumber removes the block number mapped to a state root.
+func DeleteStateRootNumber(db ethdb.KeyValueWriter, root common.Hash) {
+	if err := db.Delete(stateRootKey(root)); err != nil {
+		log.Crit("Failed to delete state root to number mapping", "err", err)
+	}
+}
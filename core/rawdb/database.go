@@ -219,6 +219,33 @@ func NewLevelDBDatabaseWithFreezer(file string, cache int, handles int, freezer
 	return frdb, nil
 }
 
+// NewPersistentDatabase creates a persistent key-value database backed by the
+// given engine, without a freezer moving immutable chain segments into cold
+// storage.
+func NewPersistentDatabase(engine string, file string, cache int, handles int, namespace string) (ethdb.Database, error) {
+	db, err := NewKeyValueStore(engine, file, cache, handles, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return NewDatabase(db), nil
+}
+
+// NewPersistentDatabaseWithFreezer creates a persistent key-value database
+// backed by the given engine, with a freezer moving immutable chain segments
+// into cold storage.
+func NewPersistentDatabaseWithFreezer(engine string, file string, cache int, handles int, freezer string, namespace string) (ethdb.Database, error) {
+	kvdb, err := NewKeyValueStore(engine, file, cache, handles, namespace)
+	if err != nil {
+		return nil, err
+	}
+	frdb, err := NewDatabaseWithFreezer(kvdb, freezer, namespace)
+	if err != nil {
+		kvdb.Close()
+		return nil, err
+	}
+	return frdb, nil
+}
+
 // InspectDatabase traverses the entire database and checks the size
 // of all different categories of data.
 func InspectDatabase(db ethdb.Database) error {
@@ -235,6 +262,9 @@ func InspectDatabase(db ethdb.Database) error {
 		headerSize      common.StorageSize
 		bodySize        common.StorageSize
 		receiptSize     common.StorageSize
+		failureSize     common.StorageSize
+		stateDiffSize   common.StorageSize
+		stateRootSize   common.StorageSize
 		tdSize          common.StorageSize
 		numHashPairing  common.StorageSize
 		hashNumPairing  common.StorageSize
@@ -243,6 +273,8 @@ func InspectDatabase(db ethdb.Database) error {
 		preimageSize    common.StorageSize
 		bloomBitsSize   common.StorageSize
 		cliqueSnapsSize common.StorageSize
+		accountSnapSize common.StorageSize
+		storageSnapSize common.StorageSize
 
 		// Ancient store statistics
 		ancientHeaders  common.StorageSize
@@ -250,6 +282,7 @@ func InspectDatabase(db ethdb.Database) error {
 		ancientReceipts common.StorageSize
 		ancientHashes   common.StorageSize
 		ancientTds      common.StorageSize
+		ancientDiffs    common.StorageSize
 
 		// Les statistic
 		chtTrieNodes   common.StorageSize
@@ -279,12 +312,22 @@ func InspectDatabase(db ethdb.Database) error {
 			bodySize += size
 		case bytes.HasPrefix(key, blockReceiptsPrefix) && len(key) == (len(blockReceiptsPrefix)+8+common.HashLength):
 			receiptSize += size
+		case bytes.HasPrefix(key, failureReasonPrefix) && len(key) == (len(failureReasonPrefix)+8+common.HashLength):
+			failureSize += size
+		case bytes.HasPrefix(key, stateDiffPrefix) && len(key) == (len(stateDiffPrefix)+8+common.HashLength):
+			stateDiffSize += size
+		case bytes.HasPrefix(key, stateRootPrefix) && len(key) == (len(stateRootPrefix)+common.HashLength):
+			stateRootSize += size
 		case bytes.HasPrefix(key, txLookupPrefix) && len(key) == (len(txLookupPrefix)+common.HashLength):
 			txlookupSize += size
 		case bytes.HasPrefix(key, preimagePrefix) && len(key) == (len(preimagePrefix)+common.HashLength):
 			preimageSize += size
 		case bytes.HasPrefix(key, bloomBitsPrefix) && len(key) == (len(bloomBitsPrefix)+10+common.HashLength):
 			bloomBitsSize += size
+		case bytes.HasPrefix(key, SnapshotAccountPrefix) && len(key) == (len(SnapshotAccountPrefix)+common.HashLength):
+			accountSnapSize += size
+		case bytes.HasPrefix(key, SnapshotStoragePrefix) && len(key) == (len(SnapshotStoragePrefix)+2*common.HashLength):
+			storageSnapSize += size
 		case bytes.HasPrefix(key, []byte("clique-")) && len(key) == 7+common.HashLength:
 			cliqueSnapsSize += size
 		case bytes.HasPrefix(key, []byte("cht-")) && len(key) == 4+common.HashLength:
@@ -295,7 +338,7 @@ func InspectDatabase(db ethdb.Database) error {
 			trieSize += size
 		default:
 			var accounted bool
-			for _, meta := range [][]byte{databaseVerisionKey, headHeaderKey, headBlockKey, headFastBlockKey, fastTrieProgressKey} {
+			for _, meta := range [][]byte{databaseVerisionKey, headHeaderKey, headBlockKey, headFastBlockKey, fastTrieProgressKey, stateSyncJournalKey, snapshotRootKey, snapshotGeneratorKey} {
 				if bytes.Equal(key, meta) {
 					metadata += size
 					accounted = true
@@ -313,8 +356,8 @@ func InspectDatabase(db ethdb.Database) error {
 		}
 	}
 	// Inspect append-only file store then.
-	ancients := []*common.StorageSize{&ancientHeaders, &ancientBodies, &ancientReceipts, &ancientHashes, &ancientTds}
-	for i, category := range []string{freezerHeaderTable, freezerBodiesTable, freezerReceiptTable, freezerHashTable, freezerDifficultyTable} {
+	ancients := []*common.StorageSize{&ancientHeaders, &ancientBodies, &ancientReceipts, &ancientHashes, &ancientTds, &ancientDiffs}
+	for i, category := range []string{freezerHeaderTable, freezerBodiesTable, freezerReceiptTable, freezerHashTable, freezerDifficultyTable, freezerStateDiffTable} {
 		if size, err := db.AncientSize(category); err == nil {
 			*ancients[i] += common.StorageSize(size)
 			total += common.StorageSize(size)
@@ -325,6 +368,9 @@ func InspectDatabase(db ethdb.Database) error {
 		{"Key-Value store", "Headers", headerSize.String()},
 		{"Key-Value store", "Bodies", bodySize.String()},
 		{"Key-Value store", "Receipts", receiptSize.String()},
+		{"Key-Value store", "Failure reasons", failureSize.String()},
+		{"Key-Value store", "Reverse state diffs", stateDiffSize.String()},
+		{"Key-Value store", "State root->number", stateRootSize.String()},
 		{"Key-Value store", "Difficulties", tdSize.String()},
 		{"Key-Value store", "Block number->hash", numHashPairing.String()},
 		{"Key-Value store", "Block hash->number", hashNumPairing.String()},
@@ -333,11 +379,14 @@ func InspectDatabase(db ethdb.Database) error {
 		{"Key-Value store", "Trie nodes", trieSize.String()},
 		{"Key-Value store", "Trie preimages", preimageSize.String()},
 		{"Key-Value store", "Clique snapshots", cliqueSnapsSize.String()},
+		{"Key-Value store", "Account snapshot", accountSnapSize.String()},
+		{"Key-Value store", "Storage snapshot", storageSnapSize.String()},
 		{"Key-Value store", "Singleton metadata", metadata.String()},
 		{"Ancient store", "Headers", ancientHeaders.String()},
 		{"Ancient store", "Bodies", ancientBodies.String()},
 		{"Ancient store", "Receipts", ancientReceipts.String()},
 		{"Ancient store", "Difficulties", ancientTds.String()},
+		{"Ancient store", "Reverse state diffs", ancientDiffs.String()},
 		{"Ancient store", "Block number->hash", ancientHashes.String()},
 		{"Light client", "CHT trie nodes", chtTrieNodes.String()},
 		{"Light client", "Bloom trie nodes", bloomTrieNodes.String()},
@@ -0,0 +1,224 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// CheckCanonicalChain checks that the canonical hashes, the hash to number
+// mappings and the headers agree with each other and link up, from the genesis
+// to the head header. Every inconsistency found is logged, and their number is
+// returned.
+func CheckCanonicalChain(db ethdb.Reader) int {
+	var (
+		issues int
+		parent common.Hash
+		start  = time.Now()
+		logged = time.Now()
+		number uint64
+	)
+	for ; ; number++ {
+		hash := ReadCanonicalHash(db, number)
+		if hash == (common.Hash{}) {
+			break
+		}
+		if n := ReadHeaderNumber(db, hash); n == nil || *n != number {
+			log.Warn("Canonical hash without number mapping", "number", number, "hash", hash, "mapped", n)
+			issues++
+		}
+		header := ReadHeader(db, hash, number)
+		switch {
+		case header == nil:
+			log.Warn("Canonical header missing", "number", number, "hash", hash)
+			issues++
+		case header.Number.Uint64() != number:
+			log.Warn("Canonical header number mismatch", "number", number, "hash", hash, "have", header.Number)
+			issues++
+		case number > 0 && header.ParentHash != parent:
+			log.Warn("Canonical chain broken", "number", number, "hash", hash, "parent", header.ParentHash, "want", parent)
+			issues++
+		}
+		parent = hash
+
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Checking canonical chain", "number", number, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	// The canonical chain must end at the head header
+	head := ReadHeadHeaderHash(db)
+	switch n := ReadHeaderNumber(db, head); {
+	case n == nil:
+		log.Warn("Head header unknown", "hash", head)
+		issues++
+	case *n+1 < number:
+		log.Warn("Canonical hashes above the head header", "head", *n, "last", number-1)
+		issues++
+	case *n+1 > number:
+		log.Warn("Canonical chain ends below the head header", "head", *n, "last", int64(number)-1)
+		issues++
+	case head != parent:
+		log.Warn("Head header not canonical", "number", *n, "hash", head, "canonical", parent)
+		issues++
+	}
+	return issues
+}
+
+// CheckDanglingBlockData looks for block bodies and receipts stored without
+// their header, logging them and optionally deleting them. The numbers of
+// dangling bodies and receipts are returned.
+func CheckDanglingBlockData(db ethdb.Database, fix bool) (int, int, error) {
+	var counts [2]int
+	for i, prefix := range [][]byte{blockBodyPrefix, blockReceiptsPrefix} {
+		var (
+			batch = db.NewBatch()
+			it    = db.NewIteratorWithPrefix(prefix)
+		)
+		for it.Next() {
+			key := it.Key()
+			if len(key) != len(prefix)+8+common.HashLength {
+				continue
+			}
+			number := binary.BigEndian.Uint64(key[len(prefix):])
+			hash := common.BytesToHash(key[len(prefix)+8:])
+			if HasHeader(db, hash, number) {
+				continue
+			}
+			log.Warn("Dangling block data", "kind", DescribeKey(key).Kind, "number", number, "hash", hash)
+			counts[i]++
+
+			if fix {
+				batch.Delete(key)
+				if batch.ValueSize() > ethdb.IdealBatchSize {
+					if err := batch.Write(); err != nil {
+						it.Release()
+						return counts[0], counts[1], err
+					}
+					batch.Reset()
+				}
+			}
+		}
+		it.Release()
+		if err := it.Error(); err != nil {
+			return counts[0], counts[1], err
+		}
+		if err := batch.Write(); err != nil {
+			return counts[0], counts[1], err
+		}
+	}
+	return counts[0], counts[1], nil
+}
+
+// CheckChainHeads checks that the head header, block and fast block pointers
+// reference canonical blocks holding the data each head requires, in ascending
+// order, optionally resetting the invalid ones to the closest valid canonical
+// blocks below them. The number of invalid heads is returned.
+func CheckChainHeads(db ethdb.Database, fix bool) int {
+	// Find the top of the canonical chain, from where the heads may be rebuilt
+	var top uint64
+	for _, hash := range []common.Hash{ReadHeadHeaderHash(db), ReadHeadFastBlockHash(db), ReadHeadBlockHash(db)} {
+		if n := ReadHeaderNumber(db, hash); n != nil && *n > top {
+			top = *n
+		}
+	}
+	for ReadCanonicalHash(db, top+1) != (common.Hash{}) {
+		top++
+	}
+	canonical := func(hash common.Hash) (uint64, bool) {
+		n := ReadHeaderNumber(db, hash)
+		if n == nil || *n > top || ReadCanonicalHash(db, *n) != hash {
+			return 0, false
+		}
+		return *n, true
+	}
+	// Walk the canonical chain downwards, to the first block satisfying a check
+	closest := func(from uint64, valid func(hash common.Hash, number uint64) bool) (common.Hash, uint64) {
+		for number := from; ; number-- {
+			if hash := ReadCanonicalHash(db, number); hash != (common.Hash{}) && valid(hash, number) {
+				return hash, number
+			}
+			if number == 0 {
+				return common.Hash{}, 0
+			}
+		}
+	}
+	hasState := func(hash common.Hash, number uint64) bool {
+		header := ReadHeader(db, hash, number)
+		if header == nil {
+			return false
+		}
+		if header.Root == types.EmptyRootHash {
+			return true
+		}
+		has, _ := db.Has(header.Root.Bytes())
+		return has
+	}
+	var issues int
+
+	// The head header must be a canonical header
+	headerValid := func(hash common.Hash, number uint64) bool {
+		return HasHeader(db, hash, number)
+	}
+	headHeader := ReadHeadHeaderHash(db)
+	headHeaderNumber, ok := canonical(headHeader)
+	if !ok || !headerValid(headHeader, headHeaderNumber) {
+		issues++
+		hash, number := closest(top, headerValid)
+		log.Warn("Invalid head header", "hash", headHeader, "reset", number, "to", hash)
+		if fix {
+			WriteHeadHeaderHash(db, hash)
+		}
+		headHeader, headHeaderNumber = hash, number
+	}
+	// The head block must have a body and state, and not exceed the head header
+	blockValid := func(hash common.Hash, number uint64) bool {
+		return number <= headHeaderNumber && HasBody(db, hash, number) && hasState(hash, number)
+	}
+	headBlock := ReadHeadBlockHash(db)
+	headBlockNumber, ok := canonical(headBlock)
+	if !ok || !blockValid(headBlock, headBlockNumber) {
+		issues++
+		from := headHeaderNumber
+		if ok && headBlockNumber < from {
+			from = headBlockNumber
+		}
+		hash, number := closest(from, blockValid)
+		log.Warn("Invalid head block", "hash", headBlock, "reset", number, "to", hash)
+		if fix {
+			WriteHeadBlockHash(db, hash)
+		}
+		headBlock, headBlockNumber = hash, number
+	}
+	// The head fast block must have a body and receipts, between the other heads
+	headFast := ReadHeadFastBlockHash(db)
+	headFastNumber, ok := canonical(headFast)
+	if !ok || headFastNumber < headBlockNumber || headFastNumber > headHeaderNumber || !HasBody(db, headFast, headFastNumber) || !HasReceipts(db, headFast, headFastNumber) {
+		issues++
+		log.Warn("Invalid head fast block", "hash", headFast, "reset", headBlockNumber, "to", headBlock)
+		if fix {
+			WriteHeadFastBlockHash(db, headBlock)
+		}
+	}
+	return issues
+}
@@ -0,0 +1,128 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// makeCheckChain writes a canonical chain of the given length with all its
+// block data, and points all heads to its last block.
+func makeCheckChain(db ethdb.Database, length int) []*types.Block {
+	blocks := make([]*types.Block, length)
+	for i := range blocks {
+		header := &types.Header{Number: big.NewInt(int64(i)), Root: types.EmptyRootHash, Extra: []byte("test block")}
+		if i > 0 {
+			header.ParentHash = blocks[i-1].Hash()
+		}
+		blocks[i] = types.NewBlockWithHeader(header)
+		WriteBlock(db, blocks[i])
+		WriteReceipts(db, blocks[i].Hash(), uint64(i), nil)
+		WriteCanonicalHash(db, blocks[i].Hash(), uint64(i))
+	}
+	head := blocks[length-1].Hash()
+	WriteHeadHeaderHash(db, head)
+	WriteHeadBlockHash(db, head)
+	WriteHeadFastBlockHash(db, head)
+	return blocks
+}
+
+// Tests that the canonical chain check reports broken links and mappings.
+func TestCheckCanonicalChain(t *testing.T) {
+	db := NewMemoryDatabase()
+	blocks := makeCheckChain(db, 5)
+
+	if issues := CheckCanonicalChain(db); issues != 0 {
+		t.Fatalf("consistent chain: have %d issues, want 0", issues)
+	}
+	// Replace a canonical hash and drop a number mapping
+	WriteCanonicalHash(db, blocks[1].Hash(), 2)
+	DeleteHeaderNumber(db, blocks[3].Hash())
+
+	// Block 2 mismatches its number mapping and header, block 3 has neither a
+	// number mapping nor a link to its canonical parent
+	if issues := CheckCanonicalChain(db); issues != 4 {
+		t.Fatalf("broken chain: have %d issues, want 4", issues)
+	}
+}
+
+// Tests that bodies and receipts without a header are detected and deleted.
+func TestCheckDanglingBlockData(t *testing.T) {
+	db := NewMemoryDatabase()
+	makeCheckChain(db, 3)
+
+	orphan := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(7), Extra: []byte("orphan")})
+	WriteBody(db, orphan.Hash(), 7, orphan.Body())
+	WriteReceipts(db, orphan.Hash(), 7, nil)
+	WriteBody(db, orphan.Hash(), 8, orphan.Body())
+
+	if bodies, receipts, err := CheckDanglingBlockData(db, false); err != nil || bodies != 2 || receipts != 1 {
+		t.Fatalf("dangling data: have %d bodies, %d receipts, %v, want 2, 1, nil", bodies, receipts, err)
+	}
+	if _, _, err := CheckDanglingBlockData(db, true); err != nil {
+		t.Fatalf("failed to delete dangling data: %v", err)
+	}
+	if HasBody(db, orphan.Hash(), 7) || HasReceipts(db, orphan.Hash(), 7) {
+		t.Fatalf("dangling data not deleted")
+	}
+	if bodies, receipts, err := CheckDanglingBlockData(db, false); err != nil || bodies != 0 || receipts != 0 {
+		t.Fatalf("dangling data left: have %d bodies, %d receipts, %v, want 0, 0, nil", bodies, receipts, err)
+	}
+}
+
+// Tests that invalid chain heads are reset to the closest valid blocks.
+func TestCheckChainHeads(t *testing.T) {
+	db := NewMemoryDatabase()
+	blocks := makeCheckChain(db, 6)
+
+	if issues := CheckChainHeads(db, true); issues != 0 {
+		t.Fatalf("consistent heads: have %d issues, want 0", issues)
+	}
+	// Drop the last two bodies, and point the fast head to a side block
+	DeleteBody(db, blocks[5].Hash(), 5)
+	DeleteBody(db, blocks[4].Hash(), 4)
+
+	side := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(2), ParentHash: blocks[1].Hash(), Extra: []byte("side")})
+	WriteBlock(db, side)
+	WriteReceipts(db, side.Hash(), 2, nil)
+	WriteHeadFastBlockHash(db, side.Hash())
+
+	if issues := CheckChainHeads(db, false); issues != 2 {
+		t.Fatalf("invalid heads: have %d issues, want 2", issues)
+	}
+	if head := ReadHeadBlockHash(db); head != blocks[5].Hash() {
+		t.Fatalf("head block changed without fixing")
+	}
+	CheckChainHeads(db, true)
+
+	if head := ReadHeadHeaderHash(db); head != blocks[5].Hash() {
+		t.Errorf("head header mismatch: have %x, want %x", head, blocks[5].Hash())
+	}
+	if head := ReadHeadBlockHash(db); head != blocks[3].Hash() {
+		t.Errorf("head block mismatch: have %x, want %x", head, blocks[3].Hash())
+	}
+	if head := ReadHeadFastBlockHash(db); head != blocks[3].Hash() {
+		t.Errorf("head fast block mismatch: have %x, want %x", head, blocks[3].Hash())
+	}
+	if issues := CheckChainHeads(db, false); issues != 0 {
+		t.Fatalf("fixed heads: have %d issues, want 0", issues)
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Kinds of database entries, as classified by DescribeKey.
+const (
+	KindUnknown         = "unknown"
+	KindMetadata        = "metadata"
+	KindHeader          = "header"
+	KindTotalDifficulty = "total difficulty"
+	KindCanonicalHash   = "canonical hash"
+	KindHeaderNumber    = "header number"
+	KindBody            = "body"
+	KindReceipts        = "receipts"
+	KindFailureReasons  = "failure reasons"
+	KindStateDiff       = "reverse state diff"
+	KindStateRoot       = "state root number"
+	KindTxLookup        = "transaction lookup"
+	KindBloomBits       = "bloom bits"
+	KindAccountSnapshot = "account snapshot"
+	KindStorageSnapshot = "storage snapshot"
+	KindPreimage        = "preimage"
+	KindChainConfig     = "chain config"
+	KindTrieNode        = "trie node or code"
+)
+
+// KeyInfo describes a raw database key according to the database schema.
+type KeyInfo struct {
+	Kind   string       // Kind of the entry stored under the key
+	Number *uint64      // Block number embedded in the key, if any
+	Hash   *common.Hash // Hash embedded in the key, if any
+	Detail string       // Any other component of the key
+}
+
+// String implements fmt.Stringer.
+func (info KeyInfo) String() string {
+	parts := []string{info.Kind}
+	if info.Number != nil {
+		parts = append(parts, fmt.Sprintf("#%d", *info.Number))
+	}
+	if info.Hash != nil {
+		parts = append(parts, info.Hash.Hex())
+	}
+	if info.Detail != "" {
+		parts = append(parts, info.Detail)
+	}
+	return strings.Join(parts, " ")
+}
+
+// DescribeKey classifies a raw database key according to the database schema,
+// decoding the components of the key.
+func DescribeKey(key []byte) KeyInfo {
+	numberHash := func(prefix []byte, kind string) KeyInfo {
+		number := binary.BigEndian.Uint64(key[len(prefix):])
+		hash := common.BytesToHash(key[len(prefix)+8 : len(prefix)+8+common.HashLength])
+		return KeyInfo{Kind: kind, Number: &number, Hash: &hash}
+	}
+	prefixedHash := func(prefix []byte, kind string) KeyInfo {
+		hash := common.BytesToHash(key[len(prefix):])
+		return KeyInfo{Kind: kind, Hash: &hash}
+	}
+	switch {
+	case bytes.HasPrefix(key, headerPrefix) && len(key) == (len(headerPrefix)+8+common.HashLength+len(headerTDSuffix)) && bytes.HasSuffix(key, headerTDSuffix):
+		return numberHash(headerPrefix, KindTotalDifficulty)
+	case bytes.HasPrefix(key, headerPrefix) && len(key) == (len(headerPrefix)+8+len(headerHashSuffix)) && bytes.HasSuffix(key, headerHashSuffix):
+		number := binary.BigEndian.Uint64(key[len(headerPrefix):])
+		return KeyInfo{Kind: KindCanonicalHash, Number: &number}
+	case bytes.HasPrefix(key, headerPrefix) && len(key) == (len(headerPrefix)+8+common.HashLength):
+		return numberHash(headerPrefix, KindHeader)
+	case bytes.HasPrefix(key, headerNumberPrefix) && len(key) == (len(headerNumberPrefix)+common.HashLength):
+		return prefixedHash(headerNumberPrefix, KindHeaderNumber)
+	case bytes.HasPrefix(key, blockBodyPrefix) && len(key) == (len(blockBodyPrefix)+8+common.HashLength):
+		return numberHash(blockBodyPrefix, KindBody)
+	case bytes.HasPrefix(key, blockReceiptsPrefix) && len(key) == (len(blockReceiptsPrefix)+8+common.HashLength):
+		return numberHash(blockReceiptsPrefix, KindReceipts)
+	case bytes.HasPrefix(key, failureReasonPrefix) && len(key) == (len(failureReasonPrefix)+8+common.HashLength):
+		return numberHash(failureReasonPrefix, KindFailureReasons)
+	case bytes.HasPrefix(key, stateDiffPrefix) && len(key) == (len(stateDiffPrefix)+8+common.HashLength):
+		return numberHash(stateDiffPrefix, KindStateDiff)
+	case bytes.HasPrefix(key, stateRootPrefix) && len(key) == (len(stateRootPrefix)+common.HashLength):
+		return prefixedHash(stateRootPrefix, KindStateRoot)
+	case bytes.HasPrefix(key, txLookupPrefix) && len(key) == (len(txLookupPrefix)+common.HashLength):
+		return prefixedHash(txLookupPrefix, KindTxLookup)
+	case bytes.HasPrefix(key, preimagePrefix) && len(key) == (len(preimagePrefix)+common.HashLength):
+		return prefixedHash(preimagePrefix, KindPreimage)
+	case bytes.HasPrefix(key, configPrefix) && len(key) == (len(configPrefix)+common.HashLength):
+		return prefixedHash(configPrefix, KindChainConfig)
+	case bytes.HasPrefix(key, bloomBitsPrefix) && len(key) == (len(bloomBitsPrefix)+10+common.HashLength):
+		hash := common.BytesToHash(key[len(bloomBitsPrefix)+10:])
+		return KeyInfo{
+			Kind:   KindBloomBits,
+			Hash:   &hash,
+			Detail: fmt.Sprintf("bit %d section %d", binary.BigEndian.Uint16(key[1:]), binary.BigEndian.Uint64(key[3:])),
+		}
+	case bytes.HasPrefix(key, SnapshotAccountPrefix) && len(key) == (len(SnapshotAccountPrefix)+common.HashLength):
+		return prefixedHash(SnapshotAccountPrefix, KindAccountSnapshot)
+	case bytes.HasPrefix(key, SnapshotStoragePrefix) && len(key) == (len(SnapshotStoragePrefix)+2*common.HashLength):
+		hash := common.BytesToHash(key[len(SnapshotStoragePrefix) : len(SnapshotStoragePrefix)+common.HashLength])
+		return KeyInfo{
+			Kind:   KindStorageSnapshot,
+			Hash:   &hash,
+			Detail: fmt.Sprintf("slot %x", key[len(SnapshotStoragePrefix)+common.HashLength:]),
+		}
+	case len(key) == common.HashLength:
+		hash := common.BytesToHash(key)
+		return KeyInfo{Kind: KindTrieNode, Hash: &hash}
+	}
+	for _, meta := range [][]byte{databaseVerisionKey, headHeaderKey, headBlockKey, headFastBlockKey, fastTrieProgressKey, stateSyncJournalKey, snapshotRootKey, snapshotGeneratorKey} {
+		if bytes.Equal(key, meta) {
+			return KeyInfo{Kind: KindMetadata, Detail: string(key)}
+		}
+	}
+	return KeyInfo{Kind: KindUnknown}
+}
+
+// DescribeValue decodes a raw database value according to the kind of entry its
+// key holds, into a human readable form.
+func DescribeValue(key []byte, value []byte) (string, error) {
+	switch DescribeKey(key).Kind {
+	case KindHeader:
+		header := new(types.Header)
+		if err := rlp.DecodeBytes(value, header); err != nil {
+			return "", err
+		}
+		return marshalIndent(header)
+
+	case KindTotalDifficulty:
+		td := new(big.Int)
+		if err := rlp.DecodeBytes(value, td); err != nil {
+			return "", err
+		}
+		return td.String(), nil
+
+	case KindCanonicalHash:
+		return common.BytesToHash(value).Hex(), nil
+
+	case KindHeaderNumber, KindStateRoot:
+		if len(value) != 8 {
+			return "", fmt.Errorf("invalid block number length %d", len(value))
+		}
+		return fmt.Sprintf("%d", binary.BigEndian.Uint64(value)), nil
+
+	case KindBody:
+		body := new(types.Body)
+		if err := rlp.DecodeBytes(value, body); err != nil {
+			return "", err
+		}
+		return marshalIndent(body)
+
+	case KindReceipts:
+		var stored []*types.ReceiptForStorage
+		if err := rlp.DecodeBytes(value, &stored); err != nil {
+			return "", err
+		}
+		receipts := make([]*types.Receipt, len(stored))
+		for i, receipt := range stored {
+			receipts[i] = (*types.Receipt)(receipt)
+		}
+		return marshalIndent(receipts)
+
+	case KindTxLookup:
+		switch {
+		case len(value) < common.HashLength:
+			return fmt.Sprintf("block #%d", new(big.Int).SetBytes(value).Uint64()), nil
+		case len(value) == common.HashLength:
+			return fmt.Sprintf("block %s", common.BytesToHash(value).Hex()), nil
+		}
+		var entry LegacyTxLookupEntry
+		if err := rlp.DecodeBytes(value, &entry); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("block #%d %s, index %d", entry.BlockIndex, entry.BlockHash.Hex(), entry.Index), nil
+
+	case KindAccountSnapshot, KindStorageSnapshot:
+		var fields []interface{}
+		if err := rlp.DecodeBytes(value, &fields); err != nil {
+			// Storage slots are plain RLP strings
+			var slot []byte
+			if err := rlp.DecodeBytes(value, &slot); err != nil {
+				return "", err
+			}
+			return hexutil.Encode(slot), nil
+		}
+		return describeRLPList(fields), nil
+
+	case KindChainConfig:
+		return string(value), nil
+
+	case KindMetadata:
+		switch {
+		case bytes.Equal(key, databaseVerisionKey):
+			var version uint64
+			if err := rlp.DecodeBytes(value, &version); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d", version), nil
+		case bytes.Equal(key, fastTrieProgressKey):
+			return new(big.Int).SetBytes(value).String(), nil
+		case len(value) == common.HashLength:
+			return common.BytesToHash(value).Hex(), nil
+		}
+	}
+	return hexutil.Encode(value), nil
+}
+
+// marshalIndent renders a decoded value as indented JSON.
+func marshalIndent(v interface{}) (string, error) {
+	blob, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(blob), nil
+}
+
+// describeRLPList renders a generically decoded RLP list.
+func describeRLPList(fields []interface{}) string {
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		switch field := field.(type) {
+		case []byte:
+			parts[i] = hexutil.Encode(field)
+		case []interface{}:
+			parts[i] = describeRLPList(field)
+		}
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Tests that the keys written by the accessors are classified and decoded
+// according to the schema.
+func TestDescribeDatabase(t *testing.T) {
+	db := NewMemoryDatabase()
+	hash := common.HexToHash("0x0102030405060708091011121314151617181920212223242526272829303132")
+
+	WriteCanonicalHash(db, hash, 42)
+	WriteHeaderNumber(db, hash, 42)
+	WriteTd(db, hash, 42, big.NewInt(1337))
+	WriteHeadBlockHash(db, hash)
+
+	tests := []struct {
+		key   []byte
+		kind  string
+		value string
+	}{
+		{headerHashKey(42), KindCanonicalHash, hash.Hex()},
+		{headerNumberKey(hash), KindHeaderNumber, "42"},
+		{headerTDKey(42, hash), KindTotalDifficulty, "1337"},
+		{headBlockKey, KindMetadata, hash.Hex()},
+	}
+	for i, tt := range tests {
+		info := DescribeKey(tt.key)
+		if info.Kind != tt.kind {
+			t.Errorf("test %d: kind mismatch: have %q, want %q", i, info.Kind, tt.kind)
+		}
+		value, err := db.Get(tt.key)
+		if err != nil {
+			t.Fatalf("test %d: failed to read key: %v", i, err)
+		}
+		if desc, err := DescribeValue(tt.key, value); err != nil || desc != tt.value {
+			t.Errorf("test %d: value mismatch: have %q, %v, want %q", i, desc, err, tt.value)
+		}
+	}
+	if info := DescribeKey(headerKey(42, hash)); info.Number == nil || *info.Number != 42 || info.Hash == nil || *info.Hash != hash {
+		t.Errorf("header key components mismatch: %v", info)
+	}
+	if info := DescribeKey([]byte("unknown key")); info.Kind != KindUnknown {
+		t.Errorf("unknown key classified as %q", info.Kind)
+	}
+	if s := DescribeKey(blockBodyKey(42, hash)).String(); !strings.HasPrefix(s, KindBody+" #42 ") {
+		t.Errorf("key description mismatch: %q", s)
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/boltdb"
+	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Key-value store engines able to persist the database.
+const (
+	EngineLevelDB = "leveldb"
+	EngineBoltDB  = "boltdb"
+)
+
+// databaseEngineFile is the file within a database directory recording the
+// engine of its key-value store.
+const databaseEngineFile = "ENGINE"
+
+// ReadDatabaseEngine returns the engine of the key-value store persisted in the
+// given directory, or an empty string if there is none.
+func ReadDatabaseEngine(file string) (string, error) {
+	blob, err := ioutil.ReadFile(filepath.Join(file, databaseEngineFile))
+	if err == nil {
+		return strings.TrimSpace(string(blob)), nil
+	}
+	if !os.IsNotExist(err) {
+		return "", err
+	}
+	// Databases predating the engine records are all LevelDB ones
+	if _, err := os.Stat(filepath.Join(file, "CURRENT")); err == nil {
+		return EngineLevelDB, nil
+	}
+	return "", nil
+}
+
+// NewKeyValueStore opens the key-value store persisted in the given directory
+// with the engine it was created with, or creates one with the requested engine,
+// LevelDB by default. Requesting another engine than the existing one fails.
+func NewKeyValueStore(engine string, file string, cache int, handles int, namespace string) (ethdb.KeyValueStore, error) {
+	existing, err := ReadDatabaseEngine(file)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case engine == "" && existing == "":
+		engine = EngineLevelDB
+	case engine == "":
+		engine = existing
+	case existing != "" && existing != engine:
+		return nil, fmt.Errorf("database %s uses the %s engine, not %s", file, existing, engine)
+	}
+	var db ethdb.KeyValueStore
+	switch engine {
+	case EngineLevelDB:
+		db, err = leveldb.New(file, cache, handles, namespace)
+	case EngineBoltDB:
+		db, err = boltdb.New(file, namespace)
+	default:
+		return nil, fmt.Errorf("unknown database engine %q", engine)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Record the engine of new databases, as well as of those predating the records
+	if _, err := os.Stat(filepath.Join(file, databaseEngineFile)); os.IsNotExist(err) {
+		if err := writeDatabaseEngine(file, engine); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// writeDatabaseEngine atomically records the engine of the key-value store
+// persisted in the given directory.
+func writeDatabaseEngine(file string, engine string) error {
+	path := filepath.Join(file, databaseEngineFile)
+	if err := ioutil.WriteFile(path+".tmp", []byte(engine+"\n"), 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// ConvertDatabase copies the key-value store persisted in the given directory
+// into a store of another engine, which then replaces it. The ancient chain data
+// is left untouched.
+//
+// The new store is built aside, then its files are moved next to the ones of the
+// old store, which the engines name differently. Switching the engine record is
+// the point the new store is used from, before which an interrupted conversion
+// leaves the old store in use.
+func ConvertDatabase(file string, engine string, cache int, handles int) error {
+	existing, err := ReadDatabaseEngine(file)
+	if err != nil {
+		return err
+	}
+	switch {
+	case existing == "":
+		return fmt.Errorf("no database found in %s", file)
+	case existing == engine:
+		return fmt.Errorf("database %s already uses the %s engine", file, engine)
+	}
+	src, err := NewKeyValueStore(existing, file, cache/2, handles/2, "")
+	if err != nil {
+		return err
+	}
+	tmp := file + ".convert"
+	if err := os.RemoveAll(tmp); err != nil {
+		src.Close()
+		return err
+	}
+	dst, err := NewKeyValueStore(engine, tmp, cache/2, handles/2, "")
+	if err != nil {
+		src.Close()
+		return err
+	}
+	err = copyKeyValueStore(src, dst)
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := src.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	// Move the new store next to the old one and switch over to it
+	files, err := ioutil.ReadDir(tmp)
+	if err != nil {
+		return err
+	}
+	moved := map[string]bool{databaseEngineFile: true}
+	for _, f := range files {
+		if f.IsDir() || f.Name() == databaseEngineFile {
+			continue
+		}
+		if err := os.Rename(filepath.Join(tmp, f.Name()), filepath.Join(file, f.Name())); err != nil {
+			return err
+		}
+		moved[f.Name()] = true
+	}
+	if err := writeDatabaseEngine(file, engine); err != nil {
+		return err
+	}
+	// Delete the files of the old store, the ancient chain data is a directory
+	if files, err = ioutil.ReadDir(file); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if f.IsDir() || moved[f.Name()] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(file, f.Name())); err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(tmp)
+}
+
+// copyKeyValueStore copies all the key-value pairs of a store into another.
+func copyKeyValueStore(src ethdb.KeyValueStore, dst ethdb.KeyValueStore) error {
+	var (
+		batch  = dst.NewBatch()
+		count  uint64
+		size   common.StorageSize
+		start  = time.Now()
+		logged = time.Now()
+	)
+	it := src.NewIterator()
+	defer it.Release()
+
+	for it.Next() {
+		batch.Put(it.Key(), it.Value())
+		count++
+		size += common.StorageSize(len(it.Key()) + len(it.Value()))
+
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Copying database", "items", count, "size", size, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	log.Info("Copied database", "items", count, "size", size, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
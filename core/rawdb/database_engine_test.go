@@ -0,0 +1,118 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests that databases are reopened with the engine they were created with, and
+// that they can be converted to another engine.
+func TestDatabaseEngineConversion(t *testing.T) {
+	for _, engines := range [][2]string{{EngineLevelDB, EngineBoltDB}, {EngineBoltDB, EngineLevelDB}} {
+		from, to := engines[0], engines[1]
+
+		dir, err := ioutil.TempDir("", "engine")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		// Fill a database with the first engine and some unrelated ancient data
+		db, err := NewKeyValueStore(from, dir, 16, 16, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 5000; i++ {
+			db.Put([]byte(fmt.Sprintf("key-%d", i)), []byte(fmt.Sprintf("value-%d", i)))
+		}
+		db.Close()
+
+		ancient := filepath.Join(dir, "ancient", "headers.cidx")
+		os.MkdirAll(filepath.Dir(ancient), 0755)
+		ioutil.WriteFile(ancient, []byte("ancient"), 0644)
+
+		if engine, _ := ReadDatabaseEngine(dir); engine != from {
+			t.Fatalf("%s: engine mismatch: have %s, want %s", from, engine, from)
+		}
+		if _, err := NewKeyValueStore(to, dir, 16, 16, ""); err == nil {
+			t.Fatalf("%s: opened with engine %s", from, to)
+		}
+		// Convert the database and check the new engine is used from there on
+		if err := ConvertDatabase(dir, to, 16, 16); err != nil {
+			t.Fatalf("%s: conversion failed: %v", from, err)
+		}
+		if engine, _ := ReadDatabaseEngine(dir); engine != to {
+			t.Fatalf("%s: engine mismatch: have %s, want %s", from, engine, to)
+		}
+		if db, err = NewKeyValueStore("", dir, 16, 16, ""); err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		it := db.NewIterator()
+		for it.Next() {
+			count++
+		}
+		it.Release()
+		if count != 5000 {
+			t.Fatalf("%s: item count mismatch: have %d, want %d", from, count, 5000)
+		}
+		for i := 0; i < 5000; i++ {
+			blob, err := db.Get([]byte(fmt.Sprintf("key-%d", i)))
+			if err != nil || !bytes.Equal(blob, []byte(fmt.Sprintf("value-%d", i))) {
+				t.Fatalf("%s: item %d mismatch: have %q, %v", from, i, blob, err)
+			}
+		}
+		db.Close()
+
+		if blob, err := ioutil.ReadFile(ancient); err != nil || string(blob) != "ancient" {
+			t.Fatalf("%s: ancient data lost: %v", from, err)
+		}
+		if _, err := os.Stat(dir + ".convert"); !os.IsNotExist(err) {
+			t.Fatalf("%s: conversion directory left behind: %v", from, err)
+		}
+	}
+}
+
+// Tests that databases predating the engine records are detected as LevelDB ones.
+func TestDatabaseEngineLegacy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "engine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := NewKeyValueStore(EngineLevelDB, dir, 16, 16, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+	os.Remove(filepath.Join(dir, databaseEngineFile))
+
+	if engine, _ := ReadDatabaseEngine(dir); engine != EngineLevelDB {
+		t.Fatalf("engine mismatch: have %s, want %s", engine, EngineLevelDB)
+	}
+	if db, err = NewKeyValueStore(EngineBoltDB, dir, 16, 16, ""); err == nil {
+		db.Close()
+		t.Fatalf("legacy database opened with engine %s", EngineBoltDB)
+	}
+}
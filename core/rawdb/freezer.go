@@ -22,6 +22,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -73,6 +74,10 @@ type freezer struct {
 
 	tables       map[string]*freezerTable // Data tables for storing everything
 	instanceLock fileutil.Releaser        // File-system lock to prevent double opens
+	datadir      string                   // Directory holding the data tables
+
+	retention map[string]uint64 // Number of recent items retained per table, others kept in full
+	lock      sync.Mutex        // Lock serializing the appends with the table maintenance
 }
 
 // newFreezer creates a chain freezer that moves ancient chain data into
@@ -97,10 +102,16 @@ func newFreezer(datadir string, namespace string) (*freezer, error) {
 	if err != nil {
 		return nil, err
 	}
+	// Finish any table compaction interrupted before swapping the table in
+	if err := finishCompaction(datadir); err != nil {
+		lock.Release()
+		return nil, err
+	}
 	// Open all the supported data tables
 	freezer := &freezer{
 		tables:       make(map[string]*freezerTable),
 		instanceLock: lock,
+		datadir:      datadir,
 	}
 	for name, disableSnappy := range freezerNoSnappy {
 		table, err := newTable(datadir, name, readMeter, writeMeter, sizeGauge, disableSnappy)
@@ -178,6 +189,16 @@ func (f *freezer) AncientSize(kind string) (uint64, error) {
 // injection will be rejected. But if two injections with same number happen at
 // the same time, we can get into the trouble.
 func (f *freezer) AppendAncient(number uint64, hash, header, body, receipts, td []byte) (err error) {
+	return f.appendAncient(number, hash, header, body, receipts, td, nil)
+}
+
+// appendAncient injects all binary blobs belong to block at the end of the
+// append-only immutable table files, along with the optional reverse state
+// diff of the block.
+func (f *freezer) appendAncient(number uint64, hash, header, body, receipts, td, statediff []byte) (err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
 	// Ensure the binary blobs we are appending is continuous with freezer.
 	if atomic.LoadUint64(&f.frozen) != number {
 		return errOutOrderInsertion
@@ -214,12 +235,19 @@ func (f *freezer) AppendAncient(number uint64, hash, header, body, receipts, td
 		log.Error("Failed to append ancient difficulty", "number", f.frozen, "hash", hash, "err", err)
 		return err
 	}
+	if err := f.tables[freezerStateDiffTable].Append(f.frozen, statediff); err != nil {
+		log.Error("Failed to append ancient state diff", "number", f.frozen, "hash", hash, "err", err)
+		return err
+	}
 	atomic.AddUint64(&f.frozen, 1) // Only modify atomically
 	return nil
 }
 
 // Truncate discards any recent data above the provided threshold number.
 func (f *freezer) TruncateAncients(items uint64) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
 	if atomic.LoadUint64(&f.frozen) <= items {
 		return nil
 	}
@@ -322,9 +350,12 @@ func (f *freezer) freeze(db ethdb.KeyValueStore) {
 				log.Error("Total difficulty missing, can't freeze", "number", f.frozen, "hash", hash)
 				break
 			}
+			// Reverse state diffs are only recorded if enabled, missing ones are fine
+			statediff := ReadStateDiffRLP(nfdb, hash, f.frozen)
+
 			log.Trace("Deep froze ancient block", "number", f.frozen, "hash", hash)
 			// Inject all the components into the relevant data tables
-			if err := f.AppendAncient(f.frozen, hash[:], header, body, receipts, td); err != nil {
+			if err := f.appendAncient(f.frozen, hash[:], header, body, receipts, td, statediff); err != nil {
 				break
 			}
 			ancients = append(ancients, hash)
@@ -358,6 +389,10 @@ func (f *freezer) freeze(db ethdb.KeyValueStore) {
 		if err := batch.Write(); err != nil {
 			log.Crit("Failed to delete frozen side blocks", "err", err)
 		}
+		// Drop the ancient items beyond the retention of their tables
+		if err := f.pruneTails(); err != nil {
+			log.Error("Failed to prune ancient tables", "err", err)
+		}
 		// Log something friendly for the user
 		context := []interface{}{
 			"blocks", f.frozen - first, "elapsed", common.PrettyDuration(time.Since(start)), "number", f.frozen - 1,
@@ -374,18 +409,35 @@ func (f *freezer) freeze(db ethdb.KeyValueStore) {
 	}
 }
 
-// repair truncates all data tables to the same length.
+// repair truncates all data tables to the same length. Optional tables lagging
+// behind are padded with empty items instead, or restarted at that length if
+// they hold no items at all, e.g. when added to an existing freezer.
 func (f *freezer) repair() error {
 	min := uint64(math.MaxUint64)
-	for _, table := range f.tables {
+	for name, table := range f.tables {
 		items := atomic.LoadUint64(&table.items)
-		if min > items {
+		if min > items && !freezerOptionalTables[name] {
 			min = items
 		}
 	}
-	for _, table := range f.tables {
-		if err := table.truncate(min); err != nil {
-			return err
+	for name, table := range f.tables {
+		items := atomic.LoadUint64(&table.items)
+		switch {
+		case items >= min:
+			if err := table.truncate(min); err != nil {
+				return err
+			}
+		case items == table.tail():
+			if err := table.reset(min); err != nil {
+				return err
+			}
+		default:
+			log.Warn("Padding ancient table", "table", name, "items", items, "limit", min)
+			for ; items < min; items++ {
+				if err := table.Append(items, nil); err != nil {
+					return err
+				}
+			}
 		}
 	}
 	atomic.StoreUint64(&f.frozen, min)
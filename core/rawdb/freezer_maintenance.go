@@ -0,0 +1,371 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// freezerCompactDir is the directory a table is rewritten into while being
+	// compacted.
+	freezerCompactDir = "compact"
+
+	// freezerCompactMarker is the file listing the files of a completely rewritten
+	// table, which are pending to be moved in place of the old table.
+	freezerCompactMarker = "COMPACTED"
+
+	// FreezerStateDiffTable is the ancient table of reverse state diffs, whose
+	// retention follows the configured state history.
+	FreezerStateDiffTable = freezerStateDiffTable
+)
+
+// FreezerPrunableTables are the ancient tables whose old items may be deleted.
+// The others are needed to serve and verify the chain.
+var FreezerPrunableTables = map[string]bool{
+	freezerBodiesTable:    true,
+	freezerReceiptTable:   true,
+	freezerStateDiffTable: true,
+}
+
+// SetFreezerRetention configures the number of most recent ancient items kept in
+// the given tables of the database's freezer, dropping the older ones. Tables not
+// listed are kept in full.
+func SetFreezerRetention(db ethdb.Database, retention map[string]uint64) error {
+	f, err := freezerOf(db)
+	if err != nil {
+		return err
+	}
+	for name := range retention {
+		if !FreezerPrunableTables[name] {
+			return fmt.Errorf("ancient table %q cannot be pruned", name)
+		}
+	}
+	f.lock.Lock()
+	f.retention = retention
+	f.lock.Unlock()
+
+	return f.pruneTails()
+}
+
+// CompactFreezer rewrites the given tables of the database's freezer without
+// the ancient items beyond their retention. Contrary to the retention applied by
+// a running node, which deletes entire data files, compaction drops exactly the
+// items requested. It must not run concurrently with anything reading the chain.
+func CompactFreezer(db ethdb.Database, retention map[string]uint64) error {
+	f, err := freezerOf(db)
+	if err != nil {
+		return err
+	}
+	frozen := atomic.LoadUint64(&f.frozen)
+	for name, keep := range retention {
+		if !FreezerPrunableTables[name] {
+			return fmt.Errorf("ancient table %q cannot be pruned", name)
+		}
+		var tail uint64
+		if frozen > keep {
+			tail = frozen - keep
+		}
+		if err := f.compactTable(name, tail); err != nil {
+			return fmt.Errorf("failed to compact %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// VerifyFreezer rechecks the ancient chain segments against the header chain:
+// the hashes, parent links and difficulties of all the frozen headers, and the
+// transaction and receipt roots of the retained bodies and receipts. It returns
+// the number of blocks verified.
+func VerifyFreezer(db ethdb.Database) (uint64, error) {
+	frozen, err := db.Ancients()
+	if err != nil {
+		return 0, err
+	}
+	var (
+		parent common.Hash
+		td     = new(big.Int)
+		start  = time.Now()
+		logged = time.Now()
+	)
+	for number := uint64(0); number < frozen; number++ {
+		blob, err := db.Ancient(freezerHashTable, number)
+		if err != nil {
+			return number, fmt.Errorf("block #%d: hash missing: %v", number, err)
+		}
+		hash := common.BytesToHash(blob)
+
+		if blob, err = db.Ancient(freezerHeaderTable, number); err != nil {
+			return number, fmt.Errorf("block #%d: header missing: %v", number, err)
+		}
+		if have := crypto.Keccak256Hash(blob); have != hash {
+			return number, fmt.Errorf("block #%d: header hash mismatch: have %x, want %x", number, have, hash)
+		}
+		header := new(types.Header)
+		if err := rlp.DecodeBytes(blob, header); err != nil {
+			return number, fmt.Errorf("block #%d: invalid header: %v", number, err)
+		}
+		if header.Number.Uint64() != number {
+			return number, fmt.Errorf("block #%d: header number mismatch: have %d", number, header.Number)
+		}
+		if number > 0 && header.ParentHash != parent {
+			return number, fmt.Errorf("block #%d: parent hash mismatch: have %x, want %x", number, header.ParentHash, parent)
+		}
+		// The total difficulty must accumulate the header difficulties on top of
+		// the genesis one, which is stored as configured
+		if blob, err = db.Ancient(freezerDifficultyTable, number); err != nil {
+			return number, fmt.Errorf("block #%d: total difficulty missing: %v", number, err)
+		}
+		have := new(big.Int)
+		if err := rlp.DecodeBytes(blob, have); err != nil {
+			return number, fmt.Errorf("block #%d: invalid total difficulty: %v", number, err)
+		}
+		if number == 0 {
+			td.Set(have)
+		} else if td.Add(td, header.Difficulty); have.Cmp(td) != 0 {
+			return number, fmt.Errorf("block #%d: total difficulty mismatch: have %v, want %v", number, have, td)
+		}
+		// Bodies and receipts may have been pruned, check the ones retained
+		if ok, _ := db.HasAncient(freezerBodiesTable, number); ok {
+			if err := verifyAncientBody(db, header); err != nil {
+				return number, fmt.Errorf("block #%d: %v", number, err)
+			}
+		}
+		if ok, _ := db.HasAncient(freezerReceiptTable, number); ok {
+			if err := verifyAncientReceipts(db, header); err != nil {
+				return number, fmt.Errorf("block #%d: %v", number, err)
+			}
+		}
+		parent = hash
+
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Verifying ancient chain", "number", number, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	// The key-value store must continue where the freezer ends
+	if frozen > 0 {
+		if hash := ReadCanonicalHash(db, frozen); hash != (common.Hash{}) {
+			if header := ReadHeader(db, hash, frozen); header != nil && header.ParentHash != parent {
+				return frozen, fmt.Errorf("block #%d: not linked to the ancient chain: have parent %x, want %x", frozen, header.ParentHash, parent)
+			}
+		}
+	}
+	return frozen, nil
+}
+
+// verifyAncientBody checks the ancient body of a block against its header.
+func verifyAncientBody(db ethdb.AncientReader, header *types.Header) error {
+	blob, err := db.Ancient(freezerBodiesTable, header.Number.Uint64())
+	if err != nil {
+		return err
+	}
+	body := new(types.Body)
+	if err := rlp.DecodeBytes(blob, body); err != nil {
+		return fmt.Errorf("invalid body: %v", err)
+	}
+	if hash := types.DeriveSha(types.Transactions(body.Transactions)); hash != header.TxHash {
+		return fmt.Errorf("transaction root mismatch: have %x, want %x", hash, header.TxHash)
+	}
+	if hash := types.CalcUncleHash(body.Uncles); hash != header.UncleHash {
+		return fmt.Errorf("uncle hash mismatch: have %x, want %x", hash, header.UncleHash)
+	}
+	return nil
+}
+
+// verifyAncientReceipts checks the ancient receipts of a block against its header.
+func verifyAncientReceipts(db ethdb.AncientReader, header *types.Header) error {
+	blob, err := db.Ancient(freezerReceiptTable, header.Number.Uint64())
+	if err != nil {
+		return err
+	}
+	var stored []*types.ReceiptForStorage
+	if err := rlp.DecodeBytes(blob, &stored); err != nil {
+		return fmt.Errorf("invalid receipts: %v", err)
+	}
+	receipts := make(types.Receipts, len(stored))
+	for i, receipt := range stored {
+		receipts[i] = (*types.Receipt)(receipt)
+	}
+	if hash := types.DeriveSha(receipts); hash != header.ReceiptHash {
+		return fmt.Errorf("receipt root mismatch: have %x, want %x", hash, header.ReceiptHash)
+	}
+	return nil
+}
+
+// freezerOf returns the chain freezer backing a database.
+func freezerOf(db ethdb.Database) (*freezer, error) {
+	if frdb, ok := db.(*freezerdb); ok {
+		if f, ok := frdb.AncientStore.(*freezer); ok {
+			return f, nil
+		}
+	}
+	return nil, errNotSupported
+}
+
+// pruneTails deletes the ancient items of the tables beyond their retention.
+func (f *freezer) pruneTails() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	frozen := atomic.LoadUint64(&f.frozen)
+	for name, keep := range f.retention {
+		if frozen > keep {
+			if err := f.tables[name].truncateTail(frozen - keep); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// compactTable rewrites a table without the items below the given number, then
+// swaps it in place of the old one. Once the rewritten table is complete, the
+// swap is finished by finishCompaction even if interrupted.
+func (f *freezer) compactTable(name string, tail uint64) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	old := f.tables[name]
+	items := atomic.LoadUint64(&old.items)
+	if tail > items {
+		tail = items
+	}
+	if tail <= old.tail() {
+		return nil
+	}
+	dir := filepath.Join(f.datadir, freezerCompactDir)
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	// Seed the index of the new table with its tail and copy the items over
+	idxName := fmt.Sprintf("%s.cidx", name)
+	if old.noCompression {
+		idxName = fmt.Sprintf("%s.ridx", name)
+	}
+	meta := indexEntry{offset: uint32(tail)}
+	if err := ioutil.WriteFile(filepath.Join(dir, idxName), meta.marshallBinary(), 0644); err != nil {
+		return err
+	}
+	table, err := newCustomTable(dir, name, old.readMeter, old.writeMeter, metrics.NilGauge{}, old.maxFileSize, old.noCompression)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	for number := tail; number < items; number++ {
+		blob, err := old.Retrieve(number)
+		if err != nil {
+			table.Close()
+			return err
+		}
+		if err := table.Append(number, blob); err != nil {
+			table.Close()
+			return err
+		}
+	}
+	if err := table.Sync(); err != nil {
+		table.Close()
+		return err
+	}
+	table.Close()
+
+	// Mark the rewritten table complete, listing its files
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	list := []string{name}
+	for _, file := range files {
+		list = append(list, file.Name())
+	}
+	marker := filepath.Join(dir, freezerCompactMarker)
+	if err := ioutil.WriteFile(marker+".tmp", []byte(strings.Join(list, "\n")), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(marker+".tmp", marker); err != nil {
+		return err
+	}
+	// Swap the new table in place of the old one and reopen it
+	size, _ := old.size()
+	old.sizeGauge.Dec(int64(size))
+	if err := old.Close(); err != nil {
+		return err
+	}
+	if err := finishCompaction(f.datadir); err != nil {
+		return err
+	}
+	if f.tables[name], err = newCustomTable(f.datadir, name, old.readMeter, old.writeMeter, old.sizeGauge, old.maxFileSize, old.noCompression); err != nil {
+		return err
+	}
+	log.Info("Compacted ancient table", "table", name, "tail", tail, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// finishCompaction moves a completely rewritten table in place of the old one,
+// deleting the files of the old table. A table whose rewrite was interrupted is
+// discarded instead, leaving the old one in use.
+func finishCompaction(datadir string) error {
+	dir := filepath.Join(datadir, freezerCompactDir)
+
+	blob, err := ioutil.ReadFile(filepath.Join(dir, freezerCompactMarker))
+	if os.IsNotExist(err) {
+		return os.RemoveAll(dir)
+	}
+	if err != nil {
+		return err
+	}
+	list := strings.Split(string(blob), "\n")
+	name, files := list[0], list[1:]
+
+	keep := make(map[string]bool)
+	for _, file := range files {
+		keep[file] = true
+		if err := os.Rename(filepath.Join(dir, file), filepath.Join(datadir, file)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	old, err := filepath.Glob(filepath.Join(datadir, name+".*"))
+	if err != nil {
+		return err
+	}
+	for _, file := range old {
+		if !keep[filepath.Base(file)] {
+			if err := os.Remove(file); err != nil {
+				return err
+			}
+		}
+	}
+	return os.RemoveAll(dir)
+}
@@ -0,0 +1,238 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// checkRetrieve asserts that exactly the items in [tail, items) of the table
+// can be retrieved, each holding its test chunk.
+func checkRetrieve(t *testing.T, f *freezerTable, tail, items uint64) {
+	t.Helper()
+	for item := uint64(0); item < items+2; item++ {
+		got, err := f.Retrieve(item)
+		if item < tail || item >= items {
+			if err == nil {
+				t.Fatalf("item %d: expected error, got %x", item, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("item %d: %v", item, err)
+		}
+		if exp := getChunk(15, int(item)); !bytes.Equal(got, exp) {
+			t.Fatalf("item %d: expected %x got %x", item, exp, got)
+		}
+	}
+}
+
+// TestFreezerTruncateTail tests that deleting the tail of a table drops whole data
+// files and survives reopening the table.
+func TestFreezerTruncateTail(t *testing.T) {
+	t.Parallel()
+	rm, wm, sg := metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge()
+	fname := fmt.Sprintf("truncate-tail-%d", rand.Uint64())
+
+	// Fill the table with 30 items, 3 in each data file
+	f, err := newCustomTable(os.TempDir(), fname, rm, wm, sg, 50, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for x := 0; x < 30; x++ {
+		f.Append(uint64(x), getChunk(15, x))
+	}
+	// Item 10 lives in the data file starting at item 9
+	if err := f.truncateTail(10); err != nil {
+		t.Fatal(err)
+	}
+	if tail := f.tail(); tail != 9 {
+		t.Fatalf("tail mismatch: have %d, want %d", tail, 9)
+	}
+	checkRetrieve(t, f, 9, 30)
+	if _, err := os.Stat(filepath.Join(os.TempDir(), fmt.Sprintf("%s.0002.rdat", fname))); !os.IsNotExist(err) {
+		t.Fatalf("deleted data file still present: %v", err)
+	}
+	// The head file is never deleted
+	if err := f.truncateTail(100); err != nil {
+		t.Fatal(err)
+	}
+	if tail := f.tail(); tail != 27 {
+		t.Fatalf("tail mismatch: have %d, want %d", tail, 27)
+	}
+	f.Close()
+
+	// Reopen the table and continue appending
+	if f, err = newCustomTable(os.TempDir(), fname, rm, wm, sg, 50, true); err != nil {
+		t.Fatal(err)
+	}
+	checkRetrieve(t, f, 27, 30)
+	for x := 30; x < 35; x++ {
+		if err := f.Append(uint64(x), getChunk(15, x)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkRetrieve(t, f, 27, 35)
+
+	// Truncating the head into the deleted tail empties the table there
+	if err := f.truncate(20); err != nil {
+		t.Fatal(err)
+	}
+	checkRetrieve(t, f, 20, 20)
+	f.Close()
+
+	if f, err = newCustomTable(os.TempDir(), fname, rm, wm, sg, 50, true); err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	checkRetrieve(t, f, 20, 20)
+	if err := f.Append(20, getChunk(15, 20)); err != nil {
+		t.Fatal(err)
+	}
+	checkRetrieve(t, f, 20, 21)
+}
+
+// TestFreezerCompaction tests that compacting a table drops exactly the items
+// below the new tail.
+func TestFreezerCompaction(t *testing.T) {
+	datadir, err := ioutil.TempDir("", "freezer-compact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(datadir)
+
+	f, err := newFreezer(datadir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	for x := 0; x < 30; x++ {
+		blob := getChunk(15, x)
+		if err := f.AppendAncient(uint64(x), blob, blob, blob, blob, blob); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.compactTable(freezerReceiptTable, 10); err != nil {
+		t.Fatal(err)
+	}
+	checkRetrieve(t, f.tables[freezerReceiptTable], 10, 30)
+	checkRetrieve(t, f.tables[freezerBodiesTable], 0, 30)
+
+	if _, err := os.Stat(filepath.Join(datadir, freezerCompactDir)); !os.IsNotExist(err) {
+		t.Fatalf("compaction directory left behind: %v", err)
+	}
+	// The compacted table keeps growing along the others
+	blob := getChunk(15, 30)
+	if err := f.AppendAncient(30, blob, blob, blob, blob, blob); err != nil {
+		t.Fatal(err)
+	}
+	checkRetrieve(t, f.tables[freezerReceiptTable], 10, 31)
+}
+
+// TestFreezerCompactionRecovery tests that an interrupted compaction is finished
+// if the rewritten table is complete, and discarded otherwise.
+func TestFreezerCompactionRecovery(t *testing.T) {
+	datadir, err := ioutil.TempDir("", "freezer-compact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(datadir)
+
+	rm, wm, sg := metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge()
+	dir := filepath.Join(datadir, freezerCompactDir)
+
+	// Create the original table and a rewritten one without the first 5 items
+	f, err := newCustomTable(datadir, "test", rm, wm, sg, 50, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for x := 0; x < 10; x++ {
+		f.Append(uint64(x), getChunk(15, x))
+	}
+	f.Close()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	meta := indexEntry{offset: 5}
+	if err := ioutil.WriteFile(filepath.Join(dir, "test.ridx"), meta.marshallBinary(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if f, err = newCustomTable(dir, "test", rm, wm, sg, 50, true); err != nil {
+		t.Fatal(err)
+	}
+	for x := 5; x < 10; x++ {
+		f.Append(uint64(x), getChunk(15, x))
+	}
+	f.Close()
+
+	// Without the completion marker, the rewrite is discarded
+	if err := finishCompaction(datadir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("compaction directory left behind: %v", err)
+	}
+	if f, err = newCustomTable(datadir, "test", rm, wm, sg, 50, true); err != nil {
+		t.Fatal(err)
+	}
+	checkRetrieve(t, f, 0, 10)
+	f.Close()
+
+	// Redo the rewrite and mark it complete, with a file already moved over
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "test.ridx"), meta.marshallBinary(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if f, err = newCustomTable(dir, "test", rm, wm, sg, 50, true); err != nil {
+		t.Fatal(err)
+	}
+	for x := 5; x < 10; x++ {
+		f.Append(uint64(x), getChunk(15, x))
+	}
+	f.Close()
+
+	marker := "test\ntest.ridx\ntest.0000.rdat\ntest.0001.rdat"
+	if err := ioutil.WriteFile(filepath.Join(dir, freezerCompactMarker), []byte(marker), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(dir, "test.0000.rdat"), filepath.Join(datadir, "test.0000.rdat")); err != nil {
+		t.Fatal(err)
+	}
+	if err := finishCompaction(datadir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(datadir, "test.0003.rdat")); !os.IsNotExist(err) {
+		t.Fatalf("old data file left behind: %v", err)
+	}
+	if f, err = newCustomTable(datadir, "test", rm, wm, sg, 50, true); err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	checkRetrieve(t, f, 5, 10)
+}
@@ -23,6 +23,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"sync/atomic"
 
@@ -232,11 +233,10 @@ func (t *freezerTable) repair() error {
 	t.index.ReadAt(buffer, 0)
 	firstIndex.unmarshalBinary(buffer)
 
-	t.tailId = firstIndex.offset
-	t.itemOffset = firstIndex.filenum
+	t.tailId = firstIndex.filenum
+	t.itemOffset = firstIndex.offset
 
-	t.index.ReadAt(buffer, offsetsSize-indexEntrySize)
-	lastIndex.unmarshalBinary(buffer)
+	lastIndex = t.readLastIndex(offsetsSize)
 	t.head, err = t.openFile(lastIndex.filenum, openFreezerFileForAppend)
 	if err != nil {
 		return err
@@ -265,9 +265,7 @@ func (t *freezerTable) repair() error {
 				return err
 			}
 			offsetsSize -= indexEntrySize
-			t.index.ReadAt(buffer, offsetsSize-indexEntrySize)
-			var newLastIndex indexEntry
-			newLastIndex.unmarshalBinary(buffer)
+			newLastIndex := t.readLastIndex(offsetsSize)
 			// We might have slipped back into an earlier head-file here
 			if newLastIndex.filenum != lastIndex.filenum {
 				// Release earlier opened file
@@ -324,6 +322,20 @@ func (t *freezerTable) preopen() (err error) {
 	return err
 }
 
+// readLastIndex reads the last entry of an index of the given size. The first
+// entry describes the deleted tail, so if there are no others, the tail file is
+// the head and it is empty.
+func (t *freezerTable) readLastIndex(offsetsSize int64) indexEntry {
+	if offsetsSize <= indexEntrySize {
+		return indexEntry{filenum: t.tailId}
+	}
+	var idx indexEntry
+	buffer := make([]byte, indexEntrySize)
+	t.index.ReadAt(buffer, offsetsSize-indexEntrySize)
+	idx.unmarshalBinary(buffer)
+	return idx
+}
+
 // truncate discards any recent data above the provided threshold number.
 func (t *freezerTable) truncate(items uint64) error {
 	t.lock.Lock()
@@ -338,8 +350,17 @@ func (t *freezerTable) truncate(items uint64) error {
 	if err != nil {
 		return err
 	}
-	// Something's out of sync, truncate the table's offset index
+	// Truncating into the deleted tail leaves nothing, restart the table there
 	t.logger.Warn("Truncating freezer table", "items", t.items, "limit", items)
+	if items <= uint64(t.itemOffset) {
+		if err := t.resetNolock(items); err != nil {
+			return err
+		}
+		t.sizeGauge.Dec(int64(oldSize))
+		return nil
+	}
+	// Something's out of sync, truncate the table's offset index
+	items -= uint64(t.itemOffset)
 	if err := truncateFreezerFile(t.index, int64(items+1)*indexEntrySize); err != nil {
 		return err
 	}
@@ -370,7 +391,7 @@ func (t *freezerTable) truncate(items uint64) error {
 		return err
 	}
 	// All data files truncated, set internal counters and return
-	atomic.StoreUint64(&t.items, items)
+	atomic.StoreUint64(&t.items, items+uint64(t.itemOffset))
 	atomic.StoreUint32(&t.headBytes, expected.offset)
 
 	// Retrieve the new size and update the total size counter
@@ -383,6 +404,160 @@ func (t *freezerTable) truncate(items uint64) error {
 	return nil
 }
 
+// reset deletes all the data of the table, restarting it empty at the given
+// item number.
+func (t *freezerTable) reset(items uint64) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	oldSize, err := t.sizeNolock()
+	if err != nil {
+		return err
+	}
+	if err := t.resetNolock(items); err != nil {
+		return err
+	}
+	t.sizeGauge.Dec(int64(oldSize))
+	return nil
+}
+
+// resetNolock deletes all the data of the table, restarting it empty at the given
+// item number. The caller must hold the write lock.
+func (t *freezerTable) resetNolock(items uint64) error {
+	for num, f := range t.files {
+		delete(t.files, num)
+		f.Close()
+		if err := os.Remove(f.Name()); err != nil {
+			return err
+		}
+	}
+	if err := truncateFreezerFile(t.index, 0); err != nil {
+		return err
+	}
+	meta := indexEntry{filenum: 0, offset: uint32(items)}
+	if _, err := t.index.Write(meta.marshallBinary()); err != nil {
+		return err
+	}
+	head, err := t.openFile(0, openFreezerFileTruncated)
+	if err != nil {
+		return err
+	}
+	t.head = head
+	atomic.StoreUint32(&t.headId, 0)
+	atomic.StoreUint32(&t.headBytes, 0)
+	t.tailId = 0
+	atomic.StoreUint32(&t.itemOffset, uint32(items))
+	atomic.StoreUint64(&t.items, items)
+	return nil
+}
+
+// truncateTail discards the data files holding only items below the provided
+// threshold number. Files are deleted as a whole, so up to a file worth of items
+// below the threshold may remain, and the head file is never deleted.
+func (t *freezerTable) truncateTail(items uint64) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	// Find the file holding the new first item, stopping at the head file
+	if items <= uint64(t.itemOffset) {
+		return nil
+	}
+	if total := atomic.LoadUint64(&t.items); items >= total {
+		items = total - 1
+	}
+	buffer := make([]byte, indexEntrySize)
+	entry := func(i uint64) (indexEntry, error) {
+		var idx indexEntry
+		if _, err := t.index.ReadAt(buffer, int64(i*indexEntrySize)); err != nil {
+			return idx, err
+		}
+		idx.unmarshalBinary(buffer)
+		return idx, nil
+	}
+	last, err := entry(items - uint64(t.itemOffset) + 1)
+	if err != nil {
+		return err
+	}
+	if last.filenum == t.tailId {
+		return nil
+	}
+	// Find the first item in that file, its end being the first index pointing
+	// there (the index entries are sorted by file)
+	var searchErr error
+	first := sort.Search(int(items-uint64(t.itemOffset)+1), func(i int) bool {
+		idx, err := entry(uint64(i) + 1)
+		if err != nil && searchErr == nil {
+			searchErr = err
+		}
+		return idx.filenum >= last.filenum
+	})
+	if searchErr != nil {
+		return searchErr
+	}
+	oldSize, err := t.sizeNolock()
+	if err != nil {
+		return err
+	}
+	// Rewrite the index without the deleted items and swap it in
+	name := t.index.Name()
+	index, err := os.Create(name + ".tmp")
+	if err != nil {
+		return err
+	}
+	meta := indexEntry{filenum: last.filenum, offset: t.itemOffset + uint32(first)}
+	if _, err := index.Write(meta.marshallBinary()); err != nil {
+		index.Close()
+		return err
+	}
+	stat, err := t.index.Stat()
+	if err != nil {
+		index.Close()
+		return err
+	}
+	start := int64(first+1) * indexEntrySize
+	if _, err := io.Copy(index, io.NewSectionReader(t.index, start, stat.Size()-start)); err != nil {
+		index.Close()
+		return err
+	}
+	if err := index.Sync(); err != nil {
+		index.Close()
+		return err
+	}
+	index.Close()
+	if err := os.Rename(name+".tmp", name); err != nil {
+		return err
+	}
+	t.index.Close()
+	if t.index, err = openFreezerFileForAppend(name); err != nil {
+		return err
+	}
+	// Delete the data files no longer referenced
+	for num := t.tailId; num < last.filenum; num++ {
+		if f, ok := t.files[num]; ok {
+			delete(t.files, num)
+			f.Close()
+			if err := os.Remove(f.Name()); err != nil {
+				return err
+			}
+		}
+	}
+	t.tailId = last.filenum
+	atomic.StoreUint32(&t.itemOffset, meta.offset)
+
+	newSize, err := t.sizeNolock()
+	if err != nil {
+		return err
+	}
+	t.sizeGauge.Dec(int64(oldSize - newSize))
+	t.logger.Info("Deleted freezer table tail", "items", meta.offset, "files", last.filenum)
+	return nil
+}
+
+// tail returns the number of the first item still stored in the table.
+func (t *freezerTable) tail() uint64 {
+	return uint64(atomic.LoadUint32(&t.itemOffset))
+}
+
 // Close closes all opened files.
 func (t *freezerTable) Close() error {
 	t.lock.Lock()
@@ -529,7 +704,10 @@ func (t *freezerTable) getBounds(item uint64) (uint32, uint32, uint32, error) {
 		return 0, 0, 0, err
 	}
 	endIdx.unmarshalBinary(buffer)
-	if startIdx.filenum != endIdx.filenum {
+	if item == 0 || startIdx.filenum != endIdx.filenum {
+		// The first entry of the index describes the deleted tail, the first
+		// item always starts at the beginning of its data-file.
+		//
 		// If a piece of data 'crosses' a data-file,
 		// it's actually in one piece on the second data-file.
 		// We return a zero-indexEntry for the second file as start
@@ -582,7 +760,7 @@ func (t *freezerTable) Retrieve(item uint64) ([]byte, error) {
 // has returns an indicator whether the specified number data
 // exists in the freezer table.
 func (t *freezerTable) has(number uint64) bool {
-	return atomic.LoadUint64(&t.items) > number
+	return atomic.LoadUint64(&t.items) > number && t.tail() <= number
 }
 
 // size returns the total data size in the freezer table.
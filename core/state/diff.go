@@ -0,0 +1,107 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the gonex library.
+//
+// The gonex library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gonex library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gonex library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// BalanceDiff is the change of an account balance.
+type BalanceDiff struct {
+	From *hexutil.Big `json:"from"`
+	To   *hexutil.Big `json:"to"`
+}
+
+// Uint64Diff is the change of an account nonce or MRU number.
+type Uint64Diff struct {
+	From hexutil.Uint64 `json:"from"`
+	To   hexutil.Uint64 `json:"to"`
+}
+
+// HashDiff is the change of an account code hash or storage slot.
+type HashDiff struct {
+	From common.Hash `json:"from"`
+	To   common.Hash `json:"to"`
+}
+
+// AccountDiff is the change of an account between two states, unchanged fields
+// are left nil.
+type AccountDiff struct {
+	Balance   *BalanceDiff              `json:"balance,omitempty"`
+	Nonce     *Uint64Diff               `json:"nonce,omitempty"`
+	MRUNumber *Uint64Diff               `json:"mruNumber,omitempty"`
+	CodeHash  *HashDiff                 `json:"codeHash,omitempty"`
+	Storage   map[common.Hash]*HashDiff `json:"storage,omitempty"`
+}
+
+// Diff is the set of accounts changed between two states.
+type Diff map[common.Address]*AccountDiff
+
+// DiffFrom returns the changes made by the state on top of the state with the
+// given root, which must be available in the state database. Only the accounts
+// and storage slots accessed by the state are compared, so the root should be
+// the one the state was created from.
+//
+// Changes made directly to the state objects are included, as long as they are
+// finalised, e.g. by IntermediateRoot.
+func (self *StateDB) DiffFrom(root common.Hash) (Diff, error) {
+	origin, err := New(root, self.db)
+	if err != nil {
+		return nil, err
+	}
+	diff := make(Diff)
+	for addr, obj := range self.stateObjects {
+		account := new(AccountDiff)
+		if from, to := origin.GetBalance(addr), self.GetBalance(addr); from.Cmp(to) != 0 {
+			account.Balance = &BalanceDiff{From: (*hexutil.Big)(new(big.Int).Set(from)), To: (*hexutil.Big)(new(big.Int).Set(to))}
+		}
+		if from, to := origin.GetNonce(addr), self.GetNonce(addr); from != to {
+			account.Nonce = &Uint64Diff{From: hexutil.Uint64(from), To: hexutil.Uint64(to)}
+		}
+		if from, to := origin.GetMRUNumber(addr), self.GetMRUNumber(addr); from != to {
+			account.MRUNumber = &Uint64Diff{From: hexutil.Uint64(from), To: hexutil.Uint64(to)}
+		}
+		if from, to := origin.GetCodeHash(addr), self.GetCodeHash(addr); from != to {
+			account.CodeHash = &HashDiff{From: from, To: to}
+		}
+		// Collect the accessed slots first, as reading them fills the origin cache
+		var keys []common.Hash
+		for _, storage := range []Storage{obj.originStorage, obj.pendingStorage, obj.dirtyStorage} {
+			for key := range storage {
+				keys = append(keys, key)
+			}
+		}
+		for _, key := range keys {
+			if _, done := account.Storage[key]; done {
+				continue
+			}
+			if from, to := origin.GetState(addr, key), self.GetState(addr, key); from != to {
+				if account.Storage == nil {
+					account.Storage = make(map[common.Hash]*HashDiff)
+				}
+				account.Storage[key] = &HashDiff{From: from, To: to}
+			}
+		}
+		if account.Balance != nil || account.Nonce != nil || account.MRUNumber != nil || account.CodeHash != nil || len(account.Storage) > 0 {
+			diff[addr] = account
+		}
+	}
+	return diff, origin.Error()
+}
@@ -0,0 +1,77 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the gonex library.
+//
+// The gonex library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gonex library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gonex library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+)
+
+// Tests that the state diff reports the changed balances, nonces, code and
+// storage slots, including the changes made directly on the state objects.
+func TestStateDiff(t *testing.T) {
+	var (
+		db       = NewDatabase(rawdb.NewMemoryDatabase())
+		state, _ = New(common.Hash{}, db)
+		alice    = common.BytesToAddress([]byte{0x01})
+		bob      = common.BytesToAddress([]byte{0x02})
+		carol    = common.BytesToAddress([]byte{0x03})
+		slot     = common.BytesToHash([]byte{0x01})
+		other    = common.BytesToHash([]byte{0x02})
+	)
+	state.SetBalance(alice, big.NewInt(100))
+	state.SetState(alice, slot, common.BytesToHash([]byte{0xaa}))
+	state.SetState(alice, other, common.BytesToHash([]byte{0xbb}))
+	state.SetBalance(bob, big.NewInt(200))
+	root, _ := state.Commit(false)
+
+	state, _ = New(root, db)
+	state.SubBalance(alice, big.NewInt(10))
+	state.SetNonce(alice, 1)
+	state.SetState(alice, slot, common.BytesToHash([]byte{0xcc}))
+	state.GetState(alice, other)
+	state.GetBalance(bob)
+	state.SetCode(carol, []byte{0x60})
+	state.GetOrNewStateObject(bob).SetBalance(big.NewInt(300))
+	state.IntermediateRoot(false)
+
+	diff, err := state.DiffFrom(root)
+	if err != nil {
+		t.Fatalf("failed to diff state: %v", err)
+	}
+	if len(diff) != 3 {
+		t.Fatalf("changed account count mismatch: have %d, want 3", len(diff))
+	}
+	if d := diff[alice]; d.Balance == nil || d.Balance.From.ToInt().Int64() != 100 || d.Balance.To.ToInt().Int64() != 90 {
+		t.Errorf("alice balance diff mismatch: %+v", d.Balance)
+	}
+	if d := diff[alice]; d.Nonce == nil || d.Nonce.From != 0 || d.Nonce.To != 1 {
+		t.Errorf("alice nonce diff mismatch: %+v", d.Nonce)
+	}
+	if d := diff[alice]; len(d.Storage) != 1 || d.Storage[slot] == nil || d.Storage[slot].To != common.BytesToHash([]byte{0xcc}) {
+		t.Errorf("alice storage diff mismatch: %+v", d.Storage)
+	}
+	if d := diff[bob]; d.Balance == nil || d.Balance.To.ToInt().Int64() != 300 || d.Storage != nil {
+		t.Errorf("bob diff mismatch: %+v", d)
+	}
+	if d := diff[carol]; d.CodeHash == nil || d.CodeHash.From != (common.Hash{}) {
+		t.Errorf("carol code hash diff mismatch: %+v", d.CodeHash)
+	}
+}
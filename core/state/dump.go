@@ -31,6 +31,7 @@ import (
 type DumpAccount struct {
 	Balance   string                 `json:"balance"`
 	Nonce     uint64                 `json:"nonce"`
+	MRUNumber uint64                 `json:"mruNumber,omitempty"`
 	Root      string                 `json:"root"`
 	CodeHash  string                 `json:"codeHash"`
 	Code      string                 `json:"code,omitempty"`
@@ -67,6 +68,7 @@ func (self iterativeDump) onAccount(addr common.Address, account DumpAccount) {
 	dumpAccount := &DumpAccount{
 		Balance:   account.Balance,
 		Nonce:     account.Nonce,
+		MRUNumber: account.MRUNumber,
 		Root:      account.Root,
 		CodeHash:  account.CodeHash,
 		Code:      account.Code,
@@ -98,10 +100,11 @@ func (self *StateDB) dump(c collector, excludeCode, excludeStorage, excludeMissi
 		addr := common.BytesToAddress(self.trie.GetKey(it.Key))
 		obj := newObject(nil, addr, data)
 		account := DumpAccount{
-			Balance:  data.Balance.String(),
-			Nonce:    data.Nonce,
-			Root:     common.Bytes2Hex(data.Root[:]),
-			CodeHash: common.Bytes2Hex(data.CodeHash),
+			Balance:   data.Balance.String(),
+			Nonce:     data.Nonce,
+			MRUNumber: data.MRUNumber,
+			Root:      common.Bytes2Hex(data.Root[:]),
+			CodeHash:  common.Bytes2Hex(data.CodeHash),
 		}
 		if emptyAddress == addr {
 			// Preimage missing
@@ -0,0 +1,263 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// ReverseDiff holds what a state transition overwrote, so that it can be undone
+// without the trie nodes of the state before it. Accounts and storage slots are
+// keyed by their hashes, like in the tries.
+type ReverseDiff struct {
+	Accounts []ReverseAccount // Accounts changed by the transition, ordered by hash
+	Codes    [][]byte         // Contract codes the transition dropped from the state
+}
+
+// ReverseAccount is an account changed by a state transition.
+type ReverseAccount struct {
+	Hash    common.Hash
+	Account []byte        // Consensus encoding before the transition, empty if the account didn't exist
+	Storage []ReverseSlot // Storage slots changed by the transition, ordered by hash
+}
+
+// ReverseSlot is a storage slot changed by a state transition.
+type ReverseSlot struct {
+	Hash  common.Hash
+	Value []byte // RLP encoded value before the transition, empty if the slot was empty
+}
+
+// NewReverseDiff computes the reverse diff of the transition from the parent to
+// the child state, which must both be available in the state database.
+func NewReverseDiff(db Database, parent, root common.Hash) (*ReverseDiff, error) {
+	diff := new(ReverseDiff)
+	if parent == root {
+		return diff, nil
+	}
+	accounts, err := diffTries(db.TrieDB(), parent, root)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range accounts {
+		var (
+			prev, post         Account
+			prevRoot, postRoot = emptyRoot, emptyRoot
+			prevCode, postCode = emptyCode, emptyCode
+		)
+		if change.prev != nil {
+			if err := rlp.DecodeBytes(change.prev, &prev); err != nil {
+				return nil, err
+			}
+			prevRoot, prevCode = prev.Root, common.BytesToHash(prev.CodeHash)
+		}
+		if change.post != nil {
+			if err := rlp.DecodeBytes(change.post, &post); err != nil {
+				return nil, err
+			}
+			postRoot, postCode = post.Root, common.BytesToHash(post.CodeHash)
+		}
+		account := ReverseAccount{Hash: change.key, Account: change.prev}
+		if prevRoot != postRoot {
+			slots, err := diffTries(db.TrieDB(), prevRoot, postRoot)
+			if err != nil {
+				return nil, err
+			}
+			for _, slot := range slots {
+				account.Storage = append(account.Storage, ReverseSlot{Hash: slot.key, Value: slot.prev})
+			}
+		}
+		diff.Accounts = append(diff.Accounts, account)
+
+		// Keep the code of the account around if it goes away, as nothing else
+		// may hold it once the state before is gone
+		if prevCode != emptyCode && prevCode != postCode {
+			code, err := db.ContractCode(change.key, prevCode)
+			if err != nil {
+				return nil, err
+			}
+			diff.Codes = append(diff.Codes, code)
+		}
+	}
+	return diff, nil
+}
+
+// leafChange is a trie leaf differing between two tries, nil values marking
+// absent leaves.
+type leafChange struct {
+	key        common.Hash
+	prev, post []byte
+}
+
+// diffTries returns the leaves differing between the tries of the given roots,
+// ordered by key.
+func diffTries(db *trie.Database, prevRoot, postRoot common.Hash) ([]*leafChange, error) {
+	prev, err := trie.New(prevRoot, db)
+	if err != nil {
+		return nil, err
+	}
+	post, err := trie.New(postRoot, db)
+	if err != nil {
+		return nil, err
+	}
+	changes := make(map[common.Hash]*leafChange)
+
+	// Leaves only present in the old trie were changed or deleted
+	it, _ := trie.NewDifferenceIterator(post.NodeIterator(nil), prev.NodeIterator(nil))
+	for it.Next(true) {
+		if it.Leaf() {
+			key := common.BytesToHash(it.LeafKey())
+			changes[key] = &leafChange{key: key, prev: common.CopyBytes(it.LeafBlob())}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	// Leaves only present in the new trie were changed or created
+	it, _ = trie.NewDifferenceIterator(prev.NodeIterator(nil), post.NodeIterator(nil))
+	for it.Next(true) {
+		if it.Leaf() {
+			key := common.BytesToHash(it.LeafKey())
+			if change := changes[key]; change != nil {
+				change.post = common.CopyBytes(it.LeafBlob())
+			} else {
+				changes[key] = &leafChange{key: key, post: common.CopyBytes(it.LeafBlob())}
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	sorted := make([]*leafChange, 0, len(changes))
+	for _, change := range changes {
+		sorted = append(sorted, change)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].key[:], sorted[j].key[:]) < 0
+	})
+	return sorted, nil
+}
+
+// Rewinder rebuilds a past state by undoing the state transitions leading from
+// it to a state available in the database, one reverse diff at a time. The
+// tries are modified in memory, and only committed to the trie database of the
+// rewinder, so a dedicated state database should be used to keep the rebuilt
+// state apart from the live one.
+type Rewinder struct {
+	db      Database
+	trie    *trie.Trie
+	storage map[common.Hash]*trie.Trie // Storage tries modified so far
+}
+
+// NewRewinder creates a rewinder starting from the state with the given root.
+func NewRewinder(db Database, root common.Hash) (*Rewinder, error) {
+	tr, err := trie.New(root, db.TrieDB())
+	if err != nil {
+		return nil, err
+	}
+	return &Rewinder{
+		db:      db,
+		trie:    tr,
+		storage: make(map[common.Hash]*trie.Trie),
+	}, nil
+}
+
+// Apply undoes the state transition of the reverse diff.
+func (r *Rewinder) Apply(diff *ReverseDiff) error {
+	for _, code := range diff.Codes {
+		r.db.TrieDB().InsertBlob(crypto.Keccak256Hash(code), code)
+	}
+	for _, account := range diff.Accounts {
+		if err := r.applyAccount(&account); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Rewinder) applyAccount(account *ReverseAccount) error {
+	// Rewind the storage first, it must end up at the storage root of the account
+	want := emptyRoot
+	if len(account.Account) > 0 {
+		var prev Account
+		if err := rlp.DecodeBytes(account.Account, &prev); err != nil {
+			return err
+		}
+		want = prev.Root
+	}
+	storage := r.storage[account.Hash]
+	if storage == nil && len(account.Storage) > 0 {
+		enc, err := r.trie.TryGet(account.Hash[:])
+		if err != nil {
+			return err
+		}
+		root := emptyRoot
+		if len(enc) > 0 {
+			var post Account
+			if err := rlp.DecodeBytes(enc, &post); err != nil {
+				return err
+			}
+			root = post.Root
+		}
+		if storage, err = trie.New(root, r.db.TrieDB()); err != nil {
+			return err
+		}
+		r.storage[account.Hash] = storage
+	}
+	for _, slot := range account.Storage {
+		var err error
+		if len(slot.Value) == 0 {
+			err = storage.TryDelete(slot.Hash[:])
+		} else {
+			err = storage.TryUpdate(slot.Hash[:], slot.Value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if storage != nil {
+		if root := storage.Hash(); root != want {
+			return fmt.Errorf("storage root mismatch for account %x: have %x, want %x", account.Hash, root, want)
+		}
+		if want == emptyRoot {
+			delete(r.storage, account.Hash)
+		}
+	}
+	// Restore the account itself
+	if len(account.Account) == 0 {
+		return r.trie.TryDelete(account.Hash[:])
+	}
+	return r.trie.TryUpdate(account.Hash[:], account.Account)
+}
+
+// Commit writes the rebuilt tries into the trie database of the rewinder, and
+// returns the root of the rebuilt state.
+func (r *Rewinder) Commit() (common.Hash, error) {
+	for _, storage := range r.storage {
+		if _, err := storage.Commit(nil); err != nil {
+			return common.Hash{}, err
+		}
+	}
+	r.storage = make(map[common.Hash]*trie.Trie)
+	return r.trie.Commit(nil)
+}
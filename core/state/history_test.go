@@ -0,0 +1,219 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Tests that past states are rebuilt from the last one and the reverse diffs of
+// the transitions in between, without any of their trie nodes.
+func TestRewindState(t *testing.T) {
+	var (
+		db    = rawdb.NewMemoryDatabase()
+		sdb   = NewDatabase(db)
+		roots = []common.Hash{emptyRoot}
+		diffs []*ReverseDiff
+	)
+	transitions := []func(s *StateDB){
+		// Create accounts, contracts and storage
+		func(s *StateDB) {
+			for i := byte(1); i <= 8; i++ {
+				addr := common.BytesToAddress([]byte{i})
+				s.SetBalance(addr, big.NewInt(int64(i)))
+				s.SetCode(addr, []byte{i})
+				for j := byte(0); j < i; j++ {
+					s.SetState(addr, common.Hash{j}, common.Hash{i})
+				}
+			}
+		},
+		// Modify, clear and add storage slots, and MRU numbers
+		func(s *StateDB) {
+			addr := common.BytesToAddress([]byte{4})
+			s.SetState(addr, common.Hash{0}, common.Hash{})
+			s.SetState(addr, common.Hash{1}, common.Hash{0xff})
+			s.SetState(addr, common.Hash{9}, common.Hash{0xff})
+			s.SetMRUNumber(common.BytesToAddress([]byte{5}), 100)
+		},
+		// Destruct contracts, one of them recreated with new storage and code
+		func(s *StateDB) {
+			s.Suicide(common.BytesToAddress([]byte{6}))
+			s.Suicide(common.BytesToAddress([]byte{7}))
+			s.Finalise(true)
+
+			addr := common.BytesToAddress([]byte{7})
+			s.CreateAccount(addr)
+			s.SetCode(addr, []byte{0x77})
+			s.SetState(addr, common.Hash{0x77}, common.Hash{0x77})
+		},
+		// Empty the storage of a contract
+		func(s *StateDB) {
+			addr := common.BytesToAddress([]byte{3})
+			for j := byte(0); j < 3; j++ {
+				s.SetState(addr, common.Hash{j}, common.Hash{})
+			}
+		},
+	}
+	for _, transition := range transitions {
+		parent := roots[len(roots)-1]
+		statedb, _ := New(parent, sdb)
+		transition(statedb)
+		root, err := statedb.Commit(true)
+		if err != nil {
+			t.Fatalf("failed to commit state: %v", err)
+		}
+		diff, err := NewReverseDiff(sdb, parent, root)
+		if err != nil {
+			t.Fatalf("failed to compute reverse diff: %v", err)
+		}
+		// Make sure the diff survives storage
+		blob, err := rlp.EncodeToBytes(diff)
+		if err != nil {
+			t.Fatalf("failed to encode reverse diff: %v", err)
+		}
+		diff = new(ReverseDiff)
+		if err := rlp.DecodeBytes(blob, diff); err != nil {
+			t.Fatalf("failed to decode reverse diff: %v", err)
+		}
+		roots, diffs = append(roots, root), append(diffs, diff)
+	}
+	if len(diffs[2].Codes) != 2 {
+		t.Fatalf("dropped codes mismatch: have %d, want 2", len(diffs[2].Codes))
+	}
+	// Copy the last state alone into a new database
+	head := roots[len(roots)-1]
+	sdb.TrieDB().Commit(head, false)
+
+	pruned := rawdb.NewMemoryDatabase()
+	statedb, _ := New(head, NewDatabase(db))
+	it := NewNodeIterator(statedb)
+	for it.Next() {
+		if it.Hash != (common.Hash{}) {
+			blob, _ := db.Get(it.Hash[:])
+			pruned.Put(it.Hash[:], blob)
+		}
+	}
+	if it.Error != nil {
+		t.Fatalf("failed to iterate state: %v", it.Error)
+	}
+	// Rewind to every past state, and check it against the original one
+	for target := len(roots) - 2; target >= 0; target-- {
+		rewound := NewDatabase(pruned)
+		rewinder, err := NewRewinder(rewound, head)
+		if err != nil {
+			t.Fatalf("failed to create rewinder: %v", err)
+		}
+		for i := len(diffs) - 1; i >= target; i-- {
+			if err := rewinder.Apply(diffs[i]); err != nil {
+				t.Fatalf("state %d: failed to apply diff %d: %v", target, i, err)
+			}
+		}
+		root, err := rewinder.Commit()
+		if err != nil {
+			t.Fatalf("state %d: failed to commit: %v", target, err)
+		}
+		if root != roots[target] {
+			t.Fatalf("state %d: root mismatch: have %x, want %x", target, root, roots[target])
+		}
+		have, err := New(root, rewound)
+		if err != nil {
+			t.Fatalf("state %d: failed to open: %v", target, err)
+		}
+		want, _ := New(root, sdb)
+		for i := byte(1); i <= 8; i++ {
+			addr := common.BytesToAddress([]byte{i})
+			if have.GetBalance(addr).Cmp(want.GetBalance(addr)) != 0 || have.GetMRUNumber(addr) != want.GetMRUNumber(addr) {
+				t.Errorf("state %d: account %x mismatch", target, addr)
+			}
+			if !bytes.Equal(have.GetCode(addr), want.GetCode(addr)) {
+				t.Errorf("state %d: code of account %x mismatch", target, addr)
+			}
+			for j := byte(0); j < 10; j++ {
+				if have.GetState(addr, common.Hash{j}) != want.GetState(addr, common.Hash{j}) {
+					t.Errorf("state %d: slot %x of account %x mismatch", target, j, addr)
+				}
+			}
+		}
+	}
+}
+
+// Tests that rewinding with the reverse diff of a sibling transition, as left
+// behind by a side chain, fails the root check instead of yielding a bogus state.
+func TestRewindStateSideDiff(t *testing.T) {
+	var (
+		db  = rawdb.NewMemoryDatabase()
+		sdb = NewDatabase(db)
+	)
+	commit := func(parent common.Hash, transition func(s *StateDB)) (common.Hash, *ReverseDiff) {
+		statedb, _ := New(parent, sdb)
+		transition(statedb)
+		root, err := statedb.Commit(true)
+		if err != nil {
+			t.Fatalf("failed to commit state: %v", err)
+		}
+		diff, err := NewReverseDiff(sdb, parent, root)
+		if err != nil {
+			t.Fatalf("failed to compute reverse diff: %v", err)
+		}
+		return root, diff
+	}
+	genesis, _ := commit(emptyRoot, func(s *StateDB) {
+		for i := byte(1); i <= 3; i++ {
+			s.SetBalance(common.BytesToAddress([]byte{i}), big.NewInt(int64(i)))
+		}
+	})
+	canon, canonDiff := commit(genesis, func(s *StateDB) {
+		s.SetBalance(common.BytesToAddress([]byte{1}), big.NewInt(100))
+		s.SetState(common.BytesToAddress([]byte{1}), common.Hash{1}, common.Hash{1})
+	})
+	_, sideDiff := commit(genesis, func(s *StateDB) {
+		s.SetBalance(common.BytesToAddress([]byte{2}), big.NewInt(200))
+	})
+	head, headDiff := commit(canon, func(s *StateDB) {
+		s.SetBalance(common.BytesToAddress([]byte{3}), big.NewInt(300))
+	})
+	sdb.TrieDB().Commit(head, false)
+
+	rewind := func(diffs ...*ReverseDiff) common.Hash {
+		rewinder, err := NewRewinder(NewDatabase(db), head)
+		if err != nil {
+			t.Fatalf("failed to create rewinder: %v", err)
+		}
+		for i, diff := range diffs {
+			if err := rewinder.Apply(diff); err != nil {
+				t.Fatalf("failed to apply diff %d: %v", i, err)
+			}
+		}
+		root, err := rewinder.Commit()
+		if err != nil {
+			t.Fatalf("failed to commit: %v", err)
+		}
+		return root
+	}
+	if root := rewind(headDiff, canonDiff); root != genesis {
+		t.Fatalf("canonical rewind root mismatch: have %x, want %x", root, genesis)
+	}
+	if root := rewind(headDiff, sideDiff); root == genesis {
+		t.Fatalf("side diff rewind passed the root check")
+	}
+}
@@ -0,0 +1,327 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package pruner implements offline pruning of the stale state of a full node.
+package pruner
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/steakknife/bloomfilter"
+)
+
+// bloomFile is the name of the state bloom filter, committed to the data
+// directory once the live state is marked. Its presence means a sweep was
+// started and must be carried to the end before the state can be used again.
+const bloomFile = "statebloom.bf.gz"
+
+var (
+	// errNoRecentState is returned if none of the recent blocks has its state
+	// present in the database, so there is nothing to retain.
+	errNoRecentState = errors.New("no recent state found to retain")
+
+	// errNoDataDir is returned if pruning is requested for an ephemeral node,
+	// lacking a data directory to commit the progress into.
+	errNoDataDir = errors.New("state pruning requires a data directory")
+
+	// errFastSyncing is returned if pruning is requested while a fast sync is
+	// still downloading the state, which the pruner would partly delete.
+	errFastSyncing = errors.New("fast sync in progress, state pruning unavailable")
+)
+
+// stateBloomHasher is a wrapper around a byte blob to satisfy the interface API
+// requirements of the bloom library used. It's used to convert a trie hash or
+// contract code hash into a 64 bit mini hash.
+type stateBloomHasher []byte
+
+func (f stateBloomHasher) Write(p []byte) (n int, err error) { panic("not implemented") }
+func (f stateBloomHasher) Sum(b []byte) []byte               { panic("not implemented") }
+func (f stateBloomHasher) Reset()                            { panic("not implemented") }
+func (f stateBloomHasher) BlockSize() int                    { panic("not implemented") }
+func (f stateBloomHasher) Size() int                         { return 8 }
+func (f stateBloomHasher) Sum64() uint64                     { return binary.BigEndian.Uint64(f) }
+
+// Pruner deletes the state that is no longer reachable from the recent blocks.
+//
+// Pruning is done offline in two phases. The trie nodes and contract codes of
+// the retained states are first marked in a bloom filter, which is committed to
+// disk. Every hash keyed entry of the database missing from the filter is then
+// swept away. Filter false positives only leave a few stale entries behind. A
+// sweep interrupted by a crash is resumed from the committed filter, either by
+// the next pruning run or when the node is started.
+type Pruner struct {
+	db      ethdb.Database
+	datadir string
+	bloom   *bloomfilter.Filter
+}
+
+// NewPruner creates a pruner for the database, committing its progress to the
+// data directory and marking the live state in a bloom filter of the given
+// size in megabytes.
+func NewPruner(db ethdb.Database, datadir string, bloomSize uint64) (*Pruner, error) {
+	if datadir == "" {
+		return nil, errNoDataDir
+	}
+	bloom, err := bloomfilter.New(bloomSize*1024*1024*8, 4)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Allocated state bloom", "size", common.StorageSize(bloomSize*1024*1024))
+
+	return &Pruner{
+		db:      db,
+		datadir: datadir,
+		bloom:   bloom,
+	}, nil
+}
+
+// Prune retains the states of the recent blocks present in the database, along
+// with the genesis state, and deletes all other state.
+func (p *Pruner) Prune() error {
+	// If a previous run was interrupted while sweeping, finish it first
+	if _, err := os.Stat(filepath.Join(p.datadir, bloomFile)); err == nil {
+		log.Info("Resuming interrupted state pruning")
+		return RecoverPruning(p.datadir, p.db)
+	}
+	if fastSyncing(p.db) {
+		return errFastSyncing
+	}
+	roots, err := p.retainedRoots()
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	for _, root := range roots {
+		if err := p.mark(root); err != nil {
+			return err
+		}
+	}
+	log.Info("Marked live state", "roots", len(roots), "elapsed", common.PrettyDuration(time.Since(start)))
+
+	// Commit the filter, from here on the sweep must be finished
+	if err := commitBloom(p.datadir, p.bloom); err != nil {
+		return err
+	}
+	return finishPruning(p.datadir, p.db, p.bloom)
+}
+
+// fastSyncing reports whether a fast sync was started but not completed: either
+// its state download left a journal behind, or the fast synced blocks are ahead
+// of the full ones, missing their state.
+func fastSyncing(db ethdb.Database) bool {
+	if len(rawdb.ReadStateSyncJournal(db)) > 0 {
+		return true
+	}
+	fast := rawdb.ReadHeadFastBlockHash(db)
+	if fast == (common.Hash{}) {
+		return false
+	}
+	fastNumber := rawdb.ReadHeaderNumber(db, fast)
+	if fastNumber == nil {
+		return false
+	}
+	number := rawdb.ReadHeaderNumber(db, rawdb.ReadHeadBlockHash(db))
+	return number == nil || *fastNumber > *number
+}
+
+// RecoverPruning finishes a pruning run interrupted while sweeping, if any. The
+// state is incomplete until then, so it must be called before the database is
+// used.
+func RecoverPruning(datadir string, db ethdb.Database) error {
+	if datadir == "" {
+		return nil
+	}
+	path := filepath.Join(datadir, bloomFile)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	bloom, _, err := bloomfilter.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to load state bloom %s: %v", path, err)
+	}
+	log.Info("Loaded state bloom of interrupted pruning", "path", path)
+	return finishPruning(datadir, db, bloom)
+}
+
+// retainedRoots collects the state roots of the recent blocks present in the
+// database, plus the genesis one.
+func (p *Pruner) retainedRoots() ([]common.Hash, error) {
+	var roots []common.Hash
+
+	hash := rawdb.ReadHeadBlockHash(p.db)
+	for i := 0; i < core.TriesInMemory && hash != (common.Hash{}); i++ {
+		number := rawdb.ReadHeaderNumber(p.db, hash)
+		if number == nil {
+			break
+		}
+		header := rawdb.ReadHeader(p.db, hash, *number)
+		if header == nil {
+			break
+		}
+		if ok, _ := p.db.Has(header.Root[:]); ok {
+			roots = append(roots, header.Root)
+		}
+		if *number == 0 {
+			break
+		}
+		hash = header.ParentHash
+	}
+	if len(roots) == 0 {
+		return nil, errNoRecentState
+	}
+	if genesis := rawdb.ReadCanonicalHash(p.db, 0); genesis != (common.Hash{}) {
+		if header := rawdb.ReadHeader(p.db, genesis, 0); header != nil {
+			if ok, _ := p.db.Has(header.Root[:]); ok {
+				roots = append(roots, header.Root)
+			}
+		}
+	}
+	return roots, nil
+}
+
+// mark adds every trie node and contract code reachable from the state root to
+// the bloom filter.
+func (p *Pruner) mark(root common.Hash) error {
+	statedb, err := state.New(root, state.NewDatabase(p.db))
+	if err != nil {
+		return err
+	}
+	var (
+		nodes  int
+		start  = time.Now()
+		logged = time.Now()
+	)
+	it := state.NewNodeIterator(statedb)
+	for it.Next() {
+		if it.Hash != (common.Hash{}) {
+			p.bloom.Add(stateBloomHasher(it.Hash[:]))
+			nodes++
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Marking live state", "root", root, "nodes", nodes, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if it.Error != nil {
+		return fmt.Errorf("failed to iterate state %x: %v", root, it.Error)
+	}
+	log.Info("Marked state", "root", root, "nodes", nodes, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// commitBloom atomically writes the bloom filter into the data directory.
+func commitBloom(datadir string, bloom *bloomfilter.Filter) error {
+	file, err := ioutil.TempFile(datadir, bloomFile+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := bloom.WriteTo(file); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	file.Close()
+	return os.Rename(file.Name(), filepath.Join(datadir, bloomFile))
+}
+
+// finishPruning sweeps the state missing from the bloom filter, removes the
+// filter and compacts the database.
+func finishPruning(datadir string, db ethdb.Database, bloom *bloomfilter.Filter) error {
+	if err := sweep(db, bloom); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(datadir, bloomFile)); err != nil {
+		return err
+	}
+	// Reclaim the space, sweeping only left tombstones in the database
+	start := time.Now()
+	for b := 0x00; b <= 0xf0; b += 0x10 {
+		var (
+			from = []byte{byte(b)}
+			to   = []byte{byte(b + 0x10)}
+		)
+		if b == 0xf0 {
+			to = nil
+		}
+		log.Info("Compacting database", "range", fmt.Sprintf("%#x-%#x", from, to), "elapsed", common.PrettyDuration(time.Since(start)))
+		if err := db.Compact(from, to); err != nil {
+			return err
+		}
+	}
+	log.Info("State pruning finished", "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// sweep deletes every trie node and contract code missing from the bloom filter.
+// Both are keyed by their bare 32 byte hash, unlike all other database entries.
+func sweep(db ethdb.Database, bloom *bloomfilter.Filter) error {
+	var (
+		count  int
+		size   common.StorageSize
+		start  = time.Now()
+		logged = time.Now()
+		batch  = db.NewBatch()
+	)
+	it := db.NewIterator()
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if len(key) != common.HashLength || bloom.Contains(stateBloomHasher(key)) {
+			continue
+		}
+		count++
+		size += common.StorageSize(len(key) + len(it.Value()))
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Pruning stale state", "nodes", count, "size", size, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	log.Info("Pruned stale state", "nodes", count, "size", size, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pruner
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// makeTestChain creates a database with a genesis state, a stale state that no
+// block refers to and a head block with its own state. It returns the genesis
+// and stale state roots, along with a contract code only the stale state uses.
+func makeTestChain(t *testing.T) (ethdb.Database, common.Hash, common.Hash, common.Hash) {
+	db := rawdb.NewMemoryDatabase()
+	genesis := (&core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: core.GenesisAlloc{
+			common.HexToAddress("0x01"): {Balance: big.NewInt(1), Code: []byte{0x01}},
+		},
+	}).MustCommit(db)
+
+	// Derive the stale and head states from the genesis one
+	sdb := state.NewDatabase(db)
+	commit := func(root common.Hash, salt byte) common.Hash {
+		statedb, _ := state.New(root, sdb)
+		for i := byte(0); i < 16; i++ {
+			addr := common.BytesToAddress([]byte{salt, i})
+			statedb.SetBalance(addr, big.NewInt(int64(i)+1))
+			statedb.SetState(addr, common.Hash{i}, common.Hash{salt})
+			statedb.SetCode(addr, []byte{salt, i})
+		}
+		root, err := statedb.Commit(true)
+		if err != nil {
+			t.Fatalf("failed to commit state: %v", err)
+		}
+		if err := sdb.TrieDB().Commit(root, false); err != nil {
+			t.Fatalf("failed to flush state: %v", err)
+		}
+		return root
+	}
+	stale := commit(genesis.Root(), 0xaa)
+	live := commit(genesis.Root(), 0xbb)
+
+	header := &types.Header{
+		ParentHash: genesis.Hash(),
+		Number:     big.NewInt(1),
+		Root:       live,
+		Difficulty: big.NewInt(1),
+	}
+	rawdb.WriteHeader(db, header)
+	rawdb.WriteCanonicalHash(db, header.Hash(), 1)
+	rawdb.WriteHeadBlockHash(db, header.Hash())
+
+	// The code of the stale accounts is only referenced by the stale state
+	code := crypto.Keccak256Hash([]byte{0xaa, 0x00})
+	return db, genesis.Root(), stale, code
+}
+
+// checkState iterates over the state, failing if any node or code is missing.
+func checkState(t *testing.T, db ethdb.Database, root common.Hash) {
+	statedb, err := state.New(root, state.NewDatabase(db))
+	if err != nil {
+		t.Fatalf("state %x missing: %v", root, err)
+	}
+	it := state.NewNodeIterator(statedb)
+	for it.Next() {
+	}
+	if it.Error != nil {
+		t.Fatalf("state %x incomplete: %v", root, it.Error)
+	}
+}
+
+// Tests that pruning retains the recent and genesis states and deletes the rest.
+func TestPrune(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pruner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, genesis, stale, code := makeTestChain(t)
+	live := rawdb.ReadHeader(db, rawdb.ReadHeadBlockHash(db), 1).Root
+
+	pruner, err := NewPruner(db, dir, 1)
+	if err != nil {
+		t.Fatalf("failed to create pruner: %v", err)
+	}
+	if err := pruner.Prune(); err != nil {
+		t.Fatalf("failed to prune: %v", err)
+	}
+	checkState(t, db, live)
+	checkState(t, db, genesis)
+
+	if ok, _ := db.Has(stale[:]); ok {
+		t.Errorf("stale state root not pruned")
+	}
+	if ok, _ := db.Has(code[:]); ok {
+		t.Errorf("stale contract code not pruned")
+	}
+	if _, err := os.Stat(filepath.Join(dir, bloomFile)); !os.IsNotExist(err) {
+		t.Errorf("state bloom not removed: %v", err)
+	}
+}
+
+// Tests that pruning is refused while a fast sync is incomplete.
+func TestPruneFastSyncing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pruner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, _, stale, _ := makeTestChain(t)
+	pruner, err := NewPruner(db, dir, 1)
+	if err != nil {
+		t.Fatalf("failed to create pruner: %v", err)
+	}
+	// Pretend the state of a fast synced block ahead of the head is downloading
+	header := &types.Header{
+		ParentHash: rawdb.ReadHeadBlockHash(db),
+		Number:     big.NewInt(2),
+		Difficulty: big.NewInt(1),
+	}
+	rawdb.WriteHeader(db, header)
+	rawdb.WriteHeadFastBlockHash(db, header.Hash())
+
+	if err := pruner.Prune(); err != errFastSyncing {
+		t.Fatalf("pruning error mismatch: have %v, want %v", err, errFastSyncing)
+	}
+	// Catch the full head up, but leave a state download journal behind
+	rawdb.WriteHeadBlockHash(db, header.Hash())
+	rawdb.WriteStateSyncJournal(db, []byte{0x01})
+
+	if err := pruner.Prune(); err != errFastSyncing {
+		t.Fatalf("pruning error mismatch: have %v, want %v", err, errFastSyncing)
+	}
+	if ok, _ := db.Has(stale[:]); !ok {
+		t.Errorf("state pruned during fast sync")
+	}
+}
+
+// Tests that a pruning interrupted after committing its bloom filter is finished
+// on recovery.
+func TestRecoverPruning(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pruner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, genesis, stale, _ := makeTestChain(t)
+	live := rawdb.ReadHeader(db, rawdb.ReadHeadBlockHash(db), 1).Root
+
+	// Mark the live state and commit the filter, but crash before sweeping
+	pruner, err := NewPruner(db, dir, 1)
+	if err != nil {
+		t.Fatalf("failed to create pruner: %v", err)
+	}
+	roots, err := pruner.retainedRoots()
+	if err != nil {
+		t.Fatalf("failed to collect roots: %v", err)
+	}
+	for _, root := range roots {
+		if err := pruner.mark(root); err != nil {
+			t.Fatalf("failed to mark state: %v", err)
+		}
+	}
+	if err := commitBloom(dir, pruner.bloom); err != nil {
+		t.Fatalf("failed to commit bloom: %v", err)
+	}
+	if err := RecoverPruning(dir, db); err != nil {
+		t.Fatalf("failed to recover pruning: %v", err)
+	}
+	checkState(t, db, live)
+	checkState(t, db, genesis)
+
+	if ok, _ := db.Has(stale[:]); ok {
+		t.Errorf("stale state root not pruned")
+	}
+	// Recovering again without a pending pruning must be a noop
+	if err := RecoverPruning(dir, db); err != nil {
+		t.Fatalf("failed to recover without pending pruning: %v", err)
+	}
+}
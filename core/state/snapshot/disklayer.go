@@ -0,0 +1,165 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/allegro/bigcache"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// diskLayer is a low level persistent snapshot built on top of a key-value store.
+type diskLayer struct {
+	diskdb ethdb.KeyValueStore // Key-value store containing the base snapshot
+	cache  *bigcache.BigCache  // Cache to avoid hitting the disk for direct access
+	root   common.Hash         // Root hash of the base snapshot
+	stale  bool                // Signals that the layer became stale (state progressed)
+
+	genMarker []byte // Last item generated, nil once the snapshot is complete
+	lock      sync.RWMutex
+}
+
+// Root returns  root hash for which this snapshot was made.
+func (dl *diskLayer) Root() common.Hash {
+	return dl.root
+}
+
+// Parent always returns nil as there's no layer below the disk.
+func (dl *diskLayer) Parent() snapshot {
+	return nil
+}
+
+// Stale return whether this layer has become stale (was flattened across) or if
+// it's still live.
+func (dl *diskLayer) Stale() bool {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	return dl.stale
+}
+
+// marker returns the generation progress of the layer.
+func (dl *diskLayer) marker() []byte {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	return dl.genMarker
+}
+
+// setMarker updates the generation progress of the layer.
+func (dl *diskLayer) setMarker(marker []byte) {
+	dl.lock.Lock()
+	defer dl.lock.Unlock()
+
+	dl.genMarker = marker
+}
+
+// Account directly retrieves the account trie entry associated with a particular
+// hash in the snapshot.
+func (dl *diskLayer) Account(hash common.Hash) ([]byte, error) {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	if dl.stale {
+		return nil, ErrSnapshotStale
+	}
+	if !accountCovered(dl.genMarker, hash) {
+		return nil, ErrNotCoveredYet
+	}
+	if blob, ok := dl.cacheGet(hash[:]); ok {
+		return blob, nil
+	}
+	blob := rawdb.ReadAccountSnapshot(dl.diskdb, hash)
+	dl.cacheSet(hash[:], blob)
+	return blob, nil
+}
+
+// Storage directly retrieves the storage trie entry associated with a particular
+// hash, within a particular account.
+func (dl *diskLayer) Storage(accountHash, storageHash common.Hash) ([]byte, error) {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	if dl.stale {
+		return nil, ErrSnapshotStale
+	}
+	if !storageCovered(dl.genMarker, accountHash, storageHash) {
+		return nil, ErrNotCoveredYet
+	}
+	key := append(accountHash[:], storageHash[:]...)
+	if blob, ok := dl.cacheGet(key); ok {
+		return blob, nil
+	}
+	blob := rawdb.ReadStorageSnapshot(dl.diskdb, accountHash, storageHash)
+	dl.cacheSet(key, blob)
+	return blob, nil
+}
+
+// cacheGet retrieves an item from the read cache, if any is configured.
+func (dl *diskLayer) cacheGet(key []byte) ([]byte, bool) {
+	if dl.cache == nil {
+		return nil, false
+	}
+	blob, err := dl.cache.Get(string(key))
+	if err != nil {
+		return nil, false
+	}
+	return blob, true
+}
+
+// cacheSet inserts an item into the read cache, if any is configured. Missing
+// items are cached too, as empty entries.
+func (dl *diskLayer) cacheSet(key []byte, blob []byte) {
+	if dl.cache != nil {
+		dl.cache.Set(string(key), blob)
+	}
+}
+
+// accountCovered reports whether the generator already produced the account
+// with the given hash, according to its progress marker.
+func accountCovered(marker []byte, hash common.Hash) bool {
+	if marker == nil {
+		return true
+	}
+	if len(marker) == 0 {
+		return false
+	}
+	return bytes.Compare(hash[:], marker[:common.HashLength]) <= 0
+}
+
+// storageCovered reports whether the generator already produced the storage
+// slot with the given hash, according to its progress marker.
+func storageCovered(marker []byte, accountHash, storageHash common.Hash) bool {
+	if marker == nil {
+		return true
+	}
+	if len(marker) == 0 {
+		return false
+	}
+	if cmp := bytes.Compare(accountHash[:], marker[:common.HashLength]); cmp != 0 {
+		return cmp < 0
+	}
+	// The marker is within the account, which is done unless it names a slot
+	if len(marker) == common.HashLength {
+		return true
+	}
+	return bytes.Compare(storageHash[:], marker[common.HashLength:]) <= 0
+}
@@ -0,0 +1,247 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var (
+	// emptyRoot is the known root hash of an empty trie.
+	emptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+
+	// genBatchTime is the time the generator may hold the snapshot tree locked
+	// for a single batch of accounts and slots.
+	genBatchTime = 50 * time.Millisecond
+
+	// genRetryDelay is the time waited before resuming a generation interrupted
+	// by a missing trie node.
+	genRetryDelay = time.Second
+)
+
+// generate is the background thread that regenerates the disk layer from the
+// state trie, in batches short enough not to stall the block import. If wipe is
+// set, any previous snapshot data is deleted first.
+func (t *Tree) generate(quit chan struct{}, done chan struct{}, wipe bool) {
+	defer close(done)
+
+	if wipe {
+		if !t.wipe(quit) {
+			return
+		}
+		blob, _ := rlp.EncodeToBytes(generatorProgress{Marker: []byte{}})
+		rawdb.WriteSnapshotGenerator(t.diskdb, blob)
+	}
+	var (
+		start  = time.Now()
+		logged = time.Now()
+	)
+	for {
+		select {
+		case <-quit:
+			return
+		default:
+		}
+		finished, err := t.generateBatch()
+		if err != nil {
+			log.Debug("Snapshot generation stalled", "err", err)
+			select {
+			case <-quit:
+				return
+			case <-time.After(genRetryDelay):
+			}
+			continue
+		}
+		if finished {
+			log.Info("Generated state snapshot", "elapsed", common.PrettyDuration(time.Since(start)))
+			return
+		}
+		if time.Since(logged) > 8*time.Second {
+			t.lock.RLock()
+			marker := t.disk.marker()
+			t.lock.RUnlock()
+
+			log.Info("Generating state snapshot", "at", fmt.Sprintf("%#x", marker), "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+}
+
+// wipe deletes all the account and storage snapshot entries from the database,
+// returning false if it was interrupted.
+func (t *Tree) wipe(quit chan struct{}) bool {
+	for _, prefix := range [][]byte{rawdb.SnapshotAccountPrefix, rawdb.SnapshotStoragePrefix} {
+		keylen := len(prefix) + common.HashLength
+		if bytes.Equal(prefix, rawdb.SnapshotStoragePrefix) {
+			keylen += common.HashLength
+		}
+		batch := t.diskdb.NewBatch()
+		it := t.diskdb.NewIteratorWithPrefix(prefix)
+		for it.Next() {
+			// Other data may share the single byte prefix, skip anything else
+			if len(it.Key()) != keylen {
+				continue
+			}
+			batch.Delete(it.Key())
+			if batch.ValueSize() > ethdb.IdealBatchSize {
+				select {
+				case <-quit:
+					it.Release()
+					return false
+				default:
+				}
+				if err := batch.Write(); err != nil {
+					log.Crit("Failed to wipe state snapshot", "err", err)
+				}
+				batch.Reset()
+			}
+		}
+		it.Release()
+		if err := batch.Write(); err != nil {
+			log.Crit("Failed to wipe state snapshot", "err", err)
+		}
+	}
+	return true
+}
+
+// generateBatch generates the snapshot entries following the progress marker of
+// the disk layer, until either the state is exhausted or the batch time is up.
+// The entries and the new marker are persisted atomically. The snapshot tree is
+// locked meanwhile, so flattening diffs cannot race with the generation.
+func (t *Tree) generateBatch() (bool, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	dl := t.disk
+	marker := dl.marker()
+	if marker == nil {
+		return true, nil
+	}
+	accTrie, err := trie.NewSecure(dl.root, t.triedb)
+	if err != nil {
+		return false, err
+	}
+	var (
+		deadline = time.Now().Add(genBatchTime)
+		batch    = t.diskdb.NewBatch()
+		next     []byte
+		origin   []byte
+	)
+	if len(marker) > 0 {
+		origin = marker[:common.HashLength]
+	}
+	it := trie.NewIterator(accTrie.NodeIterator(origin))
+	for it.Next() {
+		accountHash := common.BytesToHash(it.Key)
+
+		// Skip or resume the account the previous batch stopped at
+		var from []byte
+		if bytes.Equal(it.Key, origin) {
+			if len(marker) == common.HashLength {
+				continue
+			}
+			from = marker[common.HashLength:]
+		} else {
+			rawdb.WriteAccountSnapshot(batch, accountHash, it.Value)
+		}
+		slot, err := t.generateStorage(batch, accountHash, it.Value, from, deadline)
+		if err != nil {
+			return false, err
+		}
+		if slot != nil {
+			next = append(common.CopyBytes(it.Key), slot...)
+			break
+		}
+		if time.Now().After(deadline) {
+			next = common.CopyBytes(it.Key)
+			break
+		}
+	}
+	if it.Err != nil {
+		return false, it.Err
+	}
+	progress := generatorProgress{Done: next == nil, Marker: next}
+	blob, err := rlp.EncodeToBytes(progress)
+	if err != nil {
+		return false, err
+	}
+	rawdb.WriteSnapshotGenerator(batch, blob)
+	if err := batch.Write(); err != nil {
+		return false, err
+	}
+	dl.setMarker(next)
+	return next == nil, nil
+}
+
+// generateStorage generates the storage entries of an account, starting after
+// the given slot. If the deadline passes before the storage is exhausted, the
+// last slot generated is returned.
+func (t *Tree) generateStorage(batch ethdb.Batch, accountHash common.Hash, account []byte, from []byte, deadline time.Time) ([]byte, error) {
+	root, err := storageRoot(account)
+	if err != nil {
+		return nil, err
+	}
+	if root == emptyRoot {
+		return nil, nil
+	}
+	storeTrie, err := trie.NewSecure(root, t.triedb)
+	if err != nil {
+		return nil, err
+	}
+	it := trie.NewIterator(storeTrie.NodeIterator(from))
+	for it.Next() {
+		if bytes.Equal(it.Key, from) {
+			continue
+		}
+		rawdb.WriteStorageSnapshot(batch, accountHash, common.BytesToHash(it.Key), it.Value)
+		if time.Now().After(deadline) {
+			return common.CopyBytes(it.Key), nil
+		}
+	}
+	return nil, it.Err
+}
+
+// storageRoot extracts the storage trie root from the RLP of an account, which
+// is its third field.
+func storageRoot(account []byte) (common.Hash, error) {
+	content, _, err := rlp.SplitList(account)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	for i := 0; i < 2; i++ {
+		if _, content, err = rlp.SplitString(content); err != nil {
+			return common.Hash{}, err
+		}
+	}
+	root, _, err := rlp.SplitString(content)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if len(root) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid storage root length %d", len(root))
+	}
+	return common.BytesToHash(root), nil
+}
@@ -0,0 +1,470 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package snapshot implements a flat key-value view of the account and storage
+// tries, sparing state reads the walk down the Merkle trie.
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/allegro/bigcache"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var (
+	// ErrSnapshotStale is returned from data accessors if the underlying snapshot
+	// layer had been invalidated due to the chain progressing forward far enough
+	// to not maintain the layer's original state.
+	ErrSnapshotStale = errors.New("snapshot stale")
+
+	// ErrNotCoveredYet is returned from data accessors if the underlying snapshot
+	// is being generated currently and the requested data item is not yet in the
+	// range of accounts covered.
+	ErrNotCoveredYet = errors.New("not covered yet")
+)
+
+// Snapshot represents the functionality supported by a snapshot storage layer.
+// Entries are returned in the same encoding as the values of the tries they
+// mirror, an empty entry meaning the item does not exist.
+type Snapshot interface {
+	// Root returns the root hash for which this snapshot was made.
+	Root() common.Hash
+
+	// Account directly retrieves the account trie entry associated with a
+	// particular hash in the snapshot.
+	Account(hash common.Hash) ([]byte, error)
+
+	// Storage directly retrieves the storage trie entry associated with a
+	// particular hash, within a particular account.
+	Storage(accountHash, storageHash common.Hash) ([]byte, error)
+}
+
+// snapshot is the internal version of the snapshot data layer that supports
+// some additional methods compared to the public API.
+type snapshot interface {
+	Snapshot
+
+	// Parent returns the subsequent layer of a snapshot, or nil if the base was
+	// reached.
+	Parent() snapshot
+
+	// Stale returns whether this layer has become stale (was flattened across) or
+	// if it's still live.
+	Stale() bool
+}
+
+// generatorProgress is the persisted state of the snapshot generation.
+type generatorProgress struct {
+	Wiping bool   // Whether the previous snapshot is still being deleted
+	Done   bool   // Whether the snapshot is fully generated
+	Marker []byte // Last account, or account and slot, generated
+}
+
+// Tree is an Ethereum state snapshot tree. It consists of one persistent base
+// layer backed by a key-value store, on top of which arbitrarily many in-memory
+// diff layers are topped, one for each block. The memory diffs can form a tree
+// with branching, but the disk layer is singleton and common to all. If a reorg
+// goes deeper than the disk layer, everything needs to be deleted.
+//
+// The disk layer is generated in the background from the state trie, and kept
+// up to date by flattening the bottom-most diff layers into it. Until fully
+// generated, it only serves the accounts it already covers.
+type Tree struct {
+	diskdb ethdb.KeyValueStore      // Persistent database to store the snapshot
+	triedb *trie.Database           // In-memory cache to access the trie through
+	cache  int                      // Megabytes permitted to use for read caches
+	layers map[common.Hash]snapshot // Collection of all known layers
+	disk   *diskLayer               // Persistent base layer all others build on
+	lock   sync.RWMutex
+
+	genQuit chan struct{} // Quit channel to stop the running generator
+	genDone chan struct{} // Channel closed once the running generator stops
+}
+
+// New attempts to load an already existing snapshot from a persistent key-value
+// store (with a number of memory layers from a journal), ensuring that the head
+// of the snapshot matches the expected one. If the snapshot is missing or does
+// not match, it is rebuilt in the background.
+func New(diskdb ethdb.KeyValueStore, triedb *trie.Database, cache int, root common.Hash) *Tree {
+	t := &Tree{
+		diskdb: diskdb,
+		triedb: triedb,
+		cache:  cache,
+		layers: make(map[common.Hash]snapshot),
+	}
+	var progress generatorProgress
+	if rawdb.ReadSnapshotRoot(diskdb) != root {
+		log.Info("Snapshot does not match the head state, rebuilding", "root", root)
+		t.rebuild(root)
+		return t
+	}
+	if err := rlp.DecodeBytes(rawdb.ReadSnapshotGenerator(diskdb), &progress); err != nil || progress.Wiping {
+		log.Info("Snapshot generation progress unavailable, rebuilding", "root", root)
+		t.rebuild(root)
+		return t
+	}
+	t.disk = &diskLayer{
+		diskdb: diskdb,
+		cache:  newCache(cache),
+		root:   root,
+	}
+	if !progress.Done {
+		t.disk.genMarker = append([]byte{}, progress.Marker...)
+		log.Info("Resuming snapshot generation", "root", root, "marker", fmt.Sprintf("%#x", progress.Marker))
+		t.startGeneration(false)
+	}
+	t.layers[root] = t.disk
+	return t
+}
+
+// newCache creates the read cache of the disk layer, if any is permitted.
+func newCache(size int) *bigcache.BigCache {
+	if size <= 0 {
+		return nil
+	}
+	cache, _ := bigcache.NewBigCache(bigcache.Config{
+		Shards:             1024,
+		LifeWindow:         0,
+		MaxEntriesInWindow: size * 1024,
+		MaxEntrySize:       512,
+		HardMaxCacheSize:   size,
+	})
+	return cache
+}
+
+// Snapshot retrieves a snapshot belonging to the given block root, or nil if no
+// snapshot is maintained for that block.
+func (t *Tree) Snapshot(root common.Hash) Snapshot {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if layer, ok := t.layers[root]; ok {
+		return layer
+	}
+	return nil
+}
+
+// Update adds a new snapshot into the tree, if that can be linked to an existing
+// old parent. It is disallowed to insert a disk layer (the origin of all).
+func (t *Tree) Update(blockRoot common.Hash, parentRoot common.Hash, destructs map[common.Hash]struct{}, accounts map[common.Hash][]byte, storage map[common.Hash]map[common.Hash][]byte) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	// Blocks leaving the state untouched, or reimported ones, have nothing to add
+	if _, ok := t.layers[blockRoot]; ok {
+		return nil
+	}
+	parent, ok := t.layers[parentRoot]
+	if !ok {
+		return fmt.Errorf("parent [%#x] snapshot missing", parentRoot)
+	}
+	t.layers[blockRoot] = newDiffLayer(parent, blockRoot, destructs, accounts, storage)
+	return nil
+}
+
+// Cap traverses downwards the snapshot tree from a head block hash until the
+// number of allowed layers are crossed. All layers beyond the permitted number
+// are flattened downwards into the disk layer, and every layer not building on
+// top of the new disk layer is dropped.
+func (t *Tree) Cap(root common.Hash, layers int) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	snap, ok := t.layers[root]
+	if !ok {
+		return fmt.Errorf("snapshot [%#x] missing", root)
+	}
+	var chain []*diffLayer
+	for layer := snap; layer != nil; layer = layer.Parent() {
+		diff, ok := layer.(*diffLayer)
+		if !ok {
+			break
+		}
+		chain = append(chain, diff)
+	}
+	if len(chain) <= layers {
+		return nil
+	}
+	// Flatten the surplus layers into the disk, the bottom-most first
+	for i := len(chain) - 1; i >= layers; i-- {
+		t.flatten(chain[i])
+	}
+	if layers > 0 {
+		chain[layers-1].setParent(t.disk)
+	}
+	// Drop all the layers forking off below the new disk layer
+	for root, layer := range t.layers {
+		if !t.descends(layer) {
+			if diff, ok := layer.(*diffLayer); ok {
+				diff.markStale()
+			}
+			delete(t.layers, root)
+		}
+	}
+	return nil
+}
+
+// descends reports whether the layer builds upon the current disk layer.
+func (t *Tree) descends(layer snapshot) bool {
+	for layer != nil {
+		if layer == snapshot(t.disk) {
+			return true
+		}
+		if layer.Stale() {
+			return false
+		}
+		layer = layer.Parent()
+	}
+	return false
+}
+
+// flatten writes the content of a diff layer sitting directly on the disk layer
+// into the database, replacing the disk layer with one at the diff's root. Only
+// the items already covered by the generator are written, the others will be
+// generated from the trie of the new disk layer.
+func (t *Tree) flatten(diff *diffLayer) {
+	// Invalidate the current disk layer before touching its data
+	base := t.disk
+	base.lock.Lock()
+	base.stale = true
+	marker := base.genMarker
+	base.lock.Unlock()
+
+	batch := t.diskdb.NewBatch()
+	for hash := range diff.destructs {
+		if !accountCovered(marker, hash) {
+			continue
+		}
+		rawdb.DeleteAccountSnapshot(batch, hash)
+		base.cacheSet(hash[:], nil)
+
+		it := rawdb.IterateStorageSnapshots(t.diskdb, hash)
+		for it.Next() {
+			if key := it.Key(); len(key) == 1+2*common.HashLength {
+				batch.Delete(key)
+				base.cacheSet(key[1:], nil)
+			}
+		}
+		it.Release()
+	}
+	for hash, data := range diff.accounts {
+		if !accountCovered(marker, hash) {
+			continue
+		}
+		if len(data) == 0 {
+			rawdb.DeleteAccountSnapshot(batch, hash)
+		} else {
+			rawdb.WriteAccountSnapshot(batch, hash, data)
+		}
+		base.cacheSet(hash[:], data)
+	}
+	for accountHash, slots := range diff.storage {
+		for storageHash, data := range slots {
+			if !storageCovered(marker, accountHash, storageHash) {
+				continue
+			}
+			if len(data) == 0 {
+				rawdb.DeleteStorageSnapshot(batch, accountHash, storageHash)
+			} else {
+				rawdb.WriteStorageSnapshot(batch, accountHash, storageHash, data)
+			}
+			base.cacheSet(append(accountHash[:], storageHash[:]...), data)
+		}
+	}
+	rawdb.WriteSnapshotRoot(batch, diff.root)
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to write flattened snapshot", "err", err)
+	}
+	t.disk = &diskLayer{
+		diskdb:    t.diskdb,
+		cache:     base.cache,
+		root:      diff.root,
+		genMarker: marker,
+	}
+	diff.markStale()
+
+	delete(t.layers, base.root)
+	t.layers[diff.root] = t.disk
+}
+
+// Rebuild drops all the snapshot layers and regenerates the disk layer from the
+// state trie of the given root in the background, e.g. after the chain had been
+// rewound below the disk layer.
+func (t *Tree) Rebuild(root common.Hash) {
+	t.stopGeneration()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.rebuild(root)
+}
+
+// rebuild replaces all layers with an empty disk layer at the given root and
+// starts regenerating it. The caller must hold the lock.
+func (t *Tree) rebuild(root common.Hash) {
+	for _, layer := range t.layers {
+		switch layer := layer.(type) {
+		case *diffLayer:
+			layer.markStale()
+		case *diskLayer:
+			layer.lock.Lock()
+			layer.stale = true
+			layer.lock.Unlock()
+		}
+	}
+	// Persist the rebuild first, so a crash resumes it instead of reusing the data
+	blob, _ := rlp.EncodeToBytes(generatorProgress{Wiping: true})
+	batch := t.diskdb.NewBatch()
+	rawdb.WriteSnapshotRoot(batch, root)
+	rawdb.WriteSnapshotGenerator(batch, blob)
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to reset snapshot generator", "err", err)
+	}
+	t.disk = &diskLayer{
+		diskdb:    t.diskdb,
+		cache:     newCache(t.cache),
+		root:      root,
+		genMarker: []byte{},
+	}
+	t.layers = map[common.Hash]snapshot{root: t.disk}
+	t.startGeneration(true)
+}
+
+// Close stops the background snapshot generation. The progress made so far is
+// persisted and resumed on the next start.
+func (t *Tree) Close() {
+	t.stopGeneration()
+}
+
+// startGeneration launches the background generator of the disk layer.
+func (t *Tree) startGeneration(wipe bool) {
+	t.genQuit = make(chan struct{})
+	t.genDone = make(chan struct{})
+	go t.generate(t.genQuit, t.genDone, wipe)
+}
+
+// stopGeneration terminates the background generator, if any is running.
+func (t *Tree) stopGeneration() {
+	if t.genQuit == nil {
+		return
+	}
+	close(t.genQuit)
+	<-t.genDone
+	t.genQuit, t.genDone = nil, nil
+}
+
+// diffLayer represents a collection of modifications made to a state snapshot
+// after running a block on top. It contains one sorted list for the account trie
+// and one-one list for each storage tries.
+type diffLayer struct {
+	parent snapshot    // Parent snapshot modified by this one, never nil
+	root   common.Hash // Root hash to which this snapshot diff belongs to
+	stale  uint32      // Signals that the layer became stale (state progressed)
+
+	destructs map[common.Hash]struct{}               // Keyed markers for deleted (and potentially) recreated accounts
+	accounts  map[common.Hash][]byte                 // Keyed accounts for direct retrival (nil means deleted)
+	storage   map[common.Hash]map[common.Hash][]byte // Keyed storage slots for direct retrival. one per account (nil means deleted)
+
+	lock sync.RWMutex
+}
+
+// newDiffLayer creates a new diff on top of an existing snapshot, whether that's
+// a low level persistent database or a hierarchical diff already.
+func newDiffLayer(parent snapshot, root common.Hash, destructs map[common.Hash]struct{}, accounts map[common.Hash][]byte, storage map[common.Hash]map[common.Hash][]byte) *diffLayer {
+	return &diffLayer{
+		parent:    parent,
+		root:      root,
+		destructs: destructs,
+		accounts:  accounts,
+		storage:   storage,
+	}
+}
+
+// Root returns the root hash for which this snapshot was made.
+func (dl *diffLayer) Root() common.Hash {
+	return dl.root
+}
+
+// Parent returns the subsequent layer of a diff layer.
+func (dl *diffLayer) Parent() snapshot {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	return dl.parent
+}
+
+// setParent relinks the diff layer onto a new parent, once the old one has been
+// flattened into the disk layer.
+func (dl *diffLayer) setParent(parent snapshot) {
+	dl.lock.Lock()
+	defer dl.lock.Unlock()
+
+	dl.parent = parent
+}
+
+// Stale return whether this layer has become stale (was flattened across) or if
+// it's still live.
+func (dl *diffLayer) Stale() bool {
+	return atomic.LoadUint32(&dl.stale) != 0
+}
+
+// markStale invalidates the layer, failing all further data accesses.
+func (dl *diffLayer) markStale() {
+	atomic.StoreUint32(&dl.stale, 1)
+}
+
+// Account directly retrieves the account trie entry associated with a particular
+// hash in the snapshot, falling through to the parent layers if the diff does not
+// touch it.
+func (dl *diffLayer) Account(hash common.Hash) ([]byte, error) {
+	if dl.Stale() {
+		return nil, ErrSnapshotStale
+	}
+	if data, ok := dl.accounts[hash]; ok {
+		return data, nil
+	}
+	if _, destructed := dl.destructs[hash]; destructed {
+		return nil, nil
+	}
+	return dl.Parent().Account(hash)
+}
+
+// Storage directly retrieves the storage trie entry associated with a particular
+// hash, within a particular account, falling through to the parent layers if
+// the diff does not touch it.
+func (dl *diffLayer) Storage(accountHash, storageHash common.Hash) ([]byte, error) {
+	if dl.Stale() {
+		return nil, ErrSnapshotStale
+	}
+	if slots, ok := dl.storage[accountHash]; ok {
+		if data, ok := slots[storageHash]; ok {
+			return data, nil
+		}
+	}
+	if _, destructed := dl.destructs[accountHash]; destructed {
+		return nil, nil
+	}
+	return dl.Parent().Storage(accountHash, storageHash)
+}
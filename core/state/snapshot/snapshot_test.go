@@ -0,0 +1,302 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// testAccount is the trie encoding of an account, without the gonex extension.
+type testAccount struct {
+	Nonce    uint64
+	Balance  *big.Int
+	Root     common.Hash
+	CodeHash []byte
+}
+
+// makeTestState creates a state of 32 accounts, each with as many storage slots
+// as its index, returning the state root.
+func makeTestState(t *testing.T, triedb *trie.Database) common.Hash {
+	accTrie, _ := trie.NewSecure(common.Hash{}, triedb)
+	for i := 0; i < 32; i++ {
+		storeTrie, _ := trie.NewSecure(common.Hash{}, triedb)
+		for j := 0; j < i; j++ {
+			value, _ := rlp.EncodeToBytes([]byte{byte(i), byte(j + 1)})
+			storeTrie.Update(common.Hash{byte(j)}.Bytes(), value)
+		}
+		root, err := storeTrie.Commit(nil)
+		if err != nil {
+			t.Fatalf("failed to commit storage: %v", err)
+		}
+		account, _ := rlp.EncodeToBytes(testAccount{
+			Nonce:    uint64(i),
+			Balance:  big.NewInt(int64(i)),
+			Root:     root,
+			CodeHash: crypto.Keccak256(nil),
+		})
+		accTrie.Update(common.Address{byte(i)}.Bytes(), account)
+	}
+	root, err := accTrie.Commit(func(leaf []byte, parent common.Hash) error {
+		var account testAccount
+		if err := rlp.DecodeBytes(leaf, &account); err == nil && account.Root != emptyRoot {
+			triedb.Reference(account.Root, parent)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to commit accounts: %v", err)
+	}
+	return root
+}
+
+// waitGeneration blocks until the background generation of the tree finishes.
+func waitGeneration(tree *Tree) {
+	if tree.genDone != nil {
+		<-tree.genDone
+	}
+}
+
+// checkSnapshot verifies that the snapshot contains exactly the state of the
+// given root.
+func checkSnapshot(t *testing.T, snap Snapshot, triedb *trie.Database, root common.Hash) {
+	accTrie, err := trie.NewSecure(root, triedb)
+	if err != nil {
+		t.Fatalf("state %x missing: %v", root, err)
+	}
+	it := trie.NewIterator(accTrie.NodeIterator(nil))
+	for it.Next() {
+		accountHash := common.BytesToHash(it.Key)
+		blob, err := snap.Account(accountHash)
+		if err != nil {
+			t.Fatalf("account %x: failed to read: %v", accountHash, err)
+		}
+		if !bytes.Equal(blob, it.Value) {
+			t.Fatalf("account %x: snapshot mismatch: have %x, want %x", accountHash, blob, it.Value)
+		}
+		var account testAccount
+		if err := rlp.DecodeBytes(it.Value, &account); err != nil {
+			t.Fatalf("account %x: failed to decode: %v", accountHash, err)
+		}
+		storeTrie, _ := trie.NewSecure(account.Root, triedb)
+		sit := trie.NewIterator(storeTrie.NodeIterator(nil))
+		for sit.Next() {
+			blob, err := snap.Storage(accountHash, common.BytesToHash(sit.Key))
+			if err != nil {
+				t.Fatalf("slot %x/%x: failed to read: %v", accountHash, sit.Key, err)
+			}
+			if !bytes.Equal(blob, sit.Value) {
+				t.Fatalf("slot %x/%x: snapshot mismatch: have %x, want %x", accountHash, sit.Key, blob, sit.Value)
+			}
+		}
+	}
+}
+
+// countEntries counts the account and storage entries of the persisted snapshot.
+func countEntries(db ethdb.Iteratee) (accounts int, slots int) {
+	it := db.NewIteratorWithPrefix(rawdb.SnapshotAccountPrefix)
+	for it.Next() {
+		if len(it.Key()) == 1+common.HashLength {
+			accounts++
+		}
+	}
+	it.Release()
+
+	it = db.NewIteratorWithPrefix(rawdb.SnapshotStoragePrefix)
+	for it.Next() {
+		if len(it.Key()) == 1+2*common.HashLength {
+			slots++
+		}
+	}
+	it.Release()
+	return accounts, slots
+}
+
+// Tests that the snapshot is generated from the state trie in the background,
+// and that the generation resumes from its persisted progress.
+func TestGeneration(t *testing.T) {
+	defer func(old time.Duration) { genBatchTime = old }(genBatchTime)
+	genBatchTime = 0 // Generate a single item per batch
+
+	var (
+		diskdb = rawdb.NewMemoryDatabase()
+		triedb = trie.NewDatabase(diskdb)
+		root   = makeTestState(t, triedb)
+	)
+	tree := New(diskdb, triedb, 1, root)
+	waitGeneration(tree)
+	checkSnapshot(t, tree.Snapshot(root), triedb, root)
+
+	if accounts, slots := countEntries(diskdb); accounts != 32 || slots != 31*32/2 {
+		t.Fatalf("snapshot entries mismatch: have %d/%d, want %d/%d", accounts, slots, 32, 31*32/2)
+	}
+	// Interrupt a regeneration halfway and make sure a new tree resumes it
+	tree.Rebuild(root)
+	tree.Close()
+
+	tree = New(diskdb, triedb, 1, root)
+	waitGeneration(tree)
+	checkSnapshot(t, tree.Snapshot(root), triedb, root)
+
+	// A snapshot of a different state must be regenerated
+	tree = New(diskdb, triedb, 1, common.Hash{0x01})
+	tree.Close()
+	if have := rawdb.ReadSnapshotRoot(diskdb); have != (common.Hash{0x01}) {
+		t.Fatalf("snapshot root mismatch: have %x, want %x", have, common.Hash{0x01})
+	}
+}
+
+// Tests that diff layers shadow their parents and are flattened into the disk
+// layer when capped.
+func TestDiffLayers(t *testing.T) {
+	var (
+		diskdb = rawdb.NewMemoryDatabase()
+		triedb = trie.NewDatabase(diskdb)
+		root   = makeTestState(t, triedb)
+	)
+	tree := New(diskdb, triedb, 1, root)
+	waitGeneration(tree)
+
+	var (
+		destructed = crypto.Keccak256Hash(common.Address{byte(3)}.Bytes())
+		updated    = crypto.Keccak256Hash(common.Address{byte(4)}.Bytes())
+		created    = common.Hash{0xff}
+		slot       = crypto.Keccak256Hash(common.Hash{0x00}.Bytes())
+	)
+	// Destruct an account, update another one and create a third on top
+	if err := tree.Update(common.Hash{0x01}, root,
+		map[common.Hash]struct{}{destructed: {}},
+		map[common.Hash][]byte{updated: {0x01}},
+		map[common.Hash]map[common.Hash][]byte{updated: {slot: nil}},
+	); err != nil {
+		t.Fatalf("failed to add first layer: %v", err)
+	}
+	if err := tree.Update(common.Hash{0x02}, common.Hash{0x01}, nil,
+		map[common.Hash][]byte{created: {0x02}},
+		map[common.Hash]map[common.Hash][]byte{created: {slot: {0x03}}},
+	); err != nil {
+		t.Fatalf("failed to add second layer: %v", err)
+	}
+	if err := tree.Update(common.Hash{0x03}, common.Hash{0xee}, nil, nil, nil); err == nil {
+		t.Fatalf("layer without parent accepted")
+	}
+	check := func(snap Snapshot, account common.Hash, slot common.Hash, wantAccount []byte, wantSlot []byte) {
+		t.Helper()
+		if blob, err := snap.Account(account); err != nil || !bytes.Equal(blob, wantAccount) {
+			t.Errorf("account %x: have %x/%v, want %x", account, blob, err, wantAccount)
+		}
+		if blob, err := snap.Storage(account, slot); err != nil || !bytes.Equal(blob, wantSlot) {
+			t.Errorf("slot %x/%x: have %x/%v, want %x", account, slot, blob, err, wantSlot)
+		}
+	}
+	head := tree.Snapshot(common.Hash{0x02})
+	check(head, destructed, slot, nil, nil)
+	check(head, updated, slot, []byte{0x01}, nil)
+	check(head, created, slot, []byte{0x02}, []byte{0x03})
+
+	// Flatten the first layer and check the disk and the layer above it
+	if err := tree.Cap(common.Hash{0x02}, 1); err != nil {
+		t.Fatalf("failed to cap tree: %v", err)
+	}
+	if _, ok := tree.layers[root]; ok {
+		t.Fatalf("flattened disk layer still referenced")
+	}
+	if have := rawdb.ReadSnapshotRoot(diskdb); have != (common.Hash{0x01}) {
+		t.Fatalf("disk root mismatch: have %x, want %x", have, common.Hash{0x01})
+	}
+	if blob := rawdb.ReadAccountSnapshot(diskdb, destructed); blob != nil {
+		t.Errorf("destructed account still on disk")
+	}
+	it := rawdb.IterateStorageSnapshots(diskdb, destructed)
+	if it.Next() {
+		t.Errorf("destructed storage still on disk")
+	}
+	it.Release()
+	check(head, destructed, slot, nil, nil)
+	check(head, updated, slot, []byte{0x01}, nil)
+	check(head, created, slot, []byte{0x02}, []byte{0x03})
+
+	// Flatten everything and make sure the old layers are unusable
+	if err := tree.Cap(common.Hash{0x02}, 0); err != nil {
+		t.Fatalf("failed to cap tree: %v", err)
+	}
+	if _, err := head.Account(updated); err != ErrSnapshotStale {
+		t.Errorf("flattened layer error mismatch: have %v, want %v", err, ErrSnapshotStale)
+	}
+	check(tree.Snapshot(common.Hash{0x02}), created, slot, []byte{0x02}, []byte{0x03})
+	if len(tree.layers) != 1 {
+		t.Errorf("layer count mismatch: have %d, want 1", len(tree.layers))
+	}
+}
+
+// Tests that flattening a diff into a partially generated disk layer only writes
+// the items already covered by the generator.
+func TestFlattenDuringGeneration(t *testing.T) {
+	diskdb := rawdb.NewMemoryDatabase()
+	tree := &Tree{
+		diskdb: diskdb,
+		layers: make(map[common.Hash]snapshot),
+	}
+	tree.disk = &diskLayer{
+		diskdb:    diskdb,
+		root:      common.Hash{0x01},
+		genMarker: append(common.Hash{0x80}.Bytes(), common.Hash{0x80}.Bytes()...),
+	}
+	tree.layers[tree.disk.root] = tree.disk
+
+	var (
+		covered   = common.Hash{0x10}
+		partial   = common.Hash{0x80}
+		uncovered = common.Hash{0x90}
+		low       = common.Hash{0x70}
+		high      = common.Hash{0x90}
+	)
+	tree.Update(common.Hash{0x02}, common.Hash{0x01}, nil,
+		map[common.Hash][]byte{covered: {0x01}, partial: {0x02}, uncovered: {0x03}},
+		map[common.Hash]map[common.Hash][]byte{partial: {low: {0x04}, high: {0x05}}},
+	)
+	if err := tree.Cap(common.Hash{0x02}, 0); err != nil {
+		t.Fatalf("failed to cap tree: %v", err)
+	}
+	if blob := rawdb.ReadAccountSnapshot(diskdb, covered); !bytes.Equal(blob, []byte{0x01}) {
+		t.Errorf("covered account mismatch: have %x, want %x", blob, []byte{0x01})
+	}
+	if blob := rawdb.ReadAccountSnapshot(diskdb, partial); !bytes.Equal(blob, []byte{0x02}) {
+		t.Errorf("partially covered account mismatch: have %x, want %x", blob, []byte{0x02})
+	}
+	if blob := rawdb.ReadAccountSnapshot(diskdb, uncovered); blob != nil {
+		t.Errorf("uncovered account written: %x", blob)
+	}
+	if blob := rawdb.ReadStorageSnapshot(diskdb, partial, low); !bytes.Equal(blob, []byte{0x04}) {
+		t.Errorf("covered slot mismatch: have %x, want %x", blob, []byte{0x04})
+	}
+	if blob := rawdb.ReadStorageSnapshot(diskdb, partial, high); blob != nil {
+		t.Errorf("uncovered slot written: %x", blob)
+	}
+	if _, err := tree.Snapshot(common.Hash{0x02}).Account(uncovered); err != ErrNotCoveredYet {
+		t.Errorf("uncovered account error mismatch: have %v, want %v", err, ErrNotCoveredYet)
+	}
+}
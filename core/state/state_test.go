@@ -82,6 +82,22 @@ func (s *StateSuite) TestDump(c *checker.C) {
 	}
 }
 
+func (s *StateSuite) TestDumpMRUNumber(c *checker.C) {
+	obj1 := s.state.GetOrNewStateObject(toAddr([]byte{0x01}))
+	obj1.SetMRUNumber(7)
+	obj2 := s.state.GetOrNewStateObject(toAddr([]byte{0x02}))
+	obj2.AddBalance(big.NewInt(1))
+	s.state.Commit(false)
+
+	dump := s.state.RawDump(true, true, true)
+	if mru := dump.Accounts[toAddr([]byte{0x01})].MRUNumber; mru != 7 {
+		c.Errorf("dumped MRU number mismatch: have %d, want 7", mru)
+	}
+	if mru := dump.Accounts[toAddr([]byte{0x02})].MRUNumber; mru != 0 {
+		c.Errorf("dumped MRU number mismatch: have %d, want 0", mru)
+	}
+}
+
 func (s *StateSuite) SetUpTest(c *checker.C) {
 	s.db = rawdb.NewMemoryDatabase()
 	s.state, _ = New(common.Hash{}, NewDatabase(s.db))
@@ -21,10 +21,13 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"runtime"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
@@ -46,6 +49,10 @@ var (
 	emptyCode = crypto.Keccak256Hash(nil)
 )
 
+// snapshotLayers is the number of diff layers kept in memory on top of the disk
+// snapshot, one less than the recent tries the blockchain keeps in memory.
+const snapshotLayers = 127
+
 type proofList [][]byte
 
 func (n *proofList) Put(key []byte, value []byte) error {
@@ -66,6 +73,12 @@ type StateDB struct {
 	db   Database
 	trie Trie
 
+	snaps         *snapshot.Tree
+	snap          snapshot.Snapshot
+	snapDestructs map[common.Hash]struct{}
+	snapAccounts  map[common.Hash][]byte
+	snapStorage   map[common.Hash]map[common.Hash][]byte
+
 	// This map holds 'live' objects, which will get modified while processing a state transition.
 	stateObjects        map[common.Address]*stateObject
 	stateObjectsPending map[common.Address]struct{} // State objects finalized but not yet written to the trie
@@ -107,11 +120,17 @@ type StateDB struct {
 
 // Create a new state from a given trie.
 func New(root common.Hash, db Database) (*StateDB, error) {
+	return NewWithSnapshot(root, db, nil)
+}
+
+// NewWithSnapshot creates a new state from a given trie, reading the accounts
+// and storage slots through the snapshot tree wherever it covers them.
+func NewWithSnapshot(root common.Hash, db Database, snaps *snapshot.Tree) (*StateDB, error) {
 	tr, err := db.OpenTrie(root)
 	if err != nil {
 		return nil, err
 	}
-	return &StateDB{
+	sdb := &StateDB{
 		db:                  db,
 		trie:                tr,
 		stateObjects:        make(map[common.Address]*stateObject),
@@ -120,7 +139,23 @@ func New(root common.Hash, db Database) (*StateDB, error) {
 		logs:                make(map[common.Hash][]*types.Log),
 		preimages:           make(map[common.Hash][]byte),
 		journal:             newJournal(),
-	}, nil
+		snaps:               snaps,
+	}
+	if snaps != nil {
+		sdb.resetSnapshot(root)
+	}
+	return sdb, nil
+}
+
+// resetSnapshot picks the snapshot layer of the given root, if the tree holds
+// one, and clears the modifications recorded for it.
+func (self *StateDB) resetSnapshot(root common.Hash) {
+	self.snapDestructs, self.snapAccounts, self.snapStorage = nil, nil, nil
+	if self.snap = self.snaps.Snapshot(root); self.snap != nil {
+		self.snapDestructs = make(map[common.Hash]struct{})
+		self.snapAccounts = make(map[common.Hash][]byte)
+		self.snapStorage = make(map[common.Hash]map[common.Hash][]byte)
+	}
 }
 
 // setError remembers the first non-nil error it is called with.
@@ -151,6 +186,9 @@ func (self *StateDB) Reset(root common.Hash) error {
 	self.logs = make(map[common.Hash][]*types.Log)
 	self.logSize = 0
 	self.preimages = make(map[common.Hash][]byte)
+	if self.snaps != nil {
+		self.resetSnapshot(root)
+	}
 	self.clearJournalAndRefund()
 	return nil
 }
@@ -453,6 +491,11 @@ func (s *StateDB) updateStateObject(obj *stateObject) {
 		panic(fmt.Errorf("can't encode object at %x: %v", addr[:], err))
 	}
 	s.setError(s.trie.TryUpdate(addr[:], data))
+
+	// Record the account for the snapshot layer of this state transition
+	if s.snap != nil {
+		s.snapAccounts[obj.addrHash] = data
+	}
 }
 
 // deleteStateObject removes the given object from the state trie.
@@ -489,8 +532,17 @@ func (s *StateDB) getDeletedStateObject(addr common.Address) *stateObject {
 	if metrics.EnabledExpensive {
 		defer func(start time.Time) { s.AccountReads += time.Since(start) }(time.Now())
 	}
-	// Load the object from the database
-	enc, err := s.trie.TryGet(addr[:])
+	// Load the object from the snapshot if available, falling back to the trie
+	var (
+		enc []byte
+		err error
+	)
+	if s.snap != nil {
+		enc, err = s.snap.Account(crypto.Keccak256Hash(addr[:]))
+	}
+	if s.snap == nil || err != nil {
+		enc, err = s.trie.TryGet(addr[:])
+	}
 	if len(enc) == 0 {
 		s.setError(err)
 		return nil
@@ -524,12 +576,24 @@ func (self *StateDB) GetOrNewStateObject(addr common.Address) *stateObject {
 func (self *StateDB) createObject(addr common.Address) (newobj, prev *stateObject) {
 	prev = self.getDeletedStateObject(addr) // Note, prev might have been deleted, we need that!
 
+	// The storage of an overwritten account is gone, the snapshot has to drop it
+	var (
+		prevdestruct bool
+		prevstorage  map[common.Hash][]byte
+	)
+	if self.snap != nil && prev != nil {
+		if _, prevdestruct = self.snapDestructs[prev.addrHash]; !prevdestruct {
+			self.snapDestructs[prev.addrHash] = struct{}{}
+		}
+		prevstorage = self.snapStorage[prev.addrHash]
+		delete(self.snapStorage, prev.addrHash)
+	}
 	newobj = newObject(self, addr, Account{})
 	newobj.setNonce(0) // sets the object to dirty
 	if prev == nil {
 		self.journal.append(createObjectChange{account: &addr})
 	} else {
-		self.journal.append(resetObjectChange{prev: prev})
+		self.journal.append(resetObjectChange{prev: prev, prevdestruct: prevdestruct, prevstorage: prevstorage})
 	}
 	self.setStateObject(newobj)
 	return newobj, prev
@@ -596,6 +660,26 @@ func (self *StateDB) Copy() *StateDB {
 		logSize:             self.logSize,
 		preimages:           make(map[common.Hash][]byte, len(self.preimages)),
 		journal:             newJournal(),
+		snaps:               self.snaps,
+		snap:                self.snap,
+	}
+	if self.snap != nil {
+		state.snapDestructs = make(map[common.Hash]struct{}, len(self.snapDestructs))
+		for hash := range self.snapDestructs {
+			state.snapDestructs[hash] = struct{}{}
+		}
+		state.snapAccounts = make(map[common.Hash][]byte, len(self.snapAccounts))
+		for hash, data := range self.snapAccounts {
+			state.snapAccounts[hash] = data
+		}
+		state.snapStorage = make(map[common.Hash]map[common.Hash][]byte, len(self.snapStorage))
+		for hash, slots := range self.snapStorage {
+			cpy := make(map[common.Hash][]byte, len(slots))
+			for key, data := range slots {
+				cpy[key] = data
+			}
+			state.snapStorage[hash] = cpy
+		}
 	}
 	// Copy the dirty states, logs, and preimages
 	for addr := range self.journal.dirties {
@@ -688,6 +772,13 @@ func (s *StateDB) Finalise(deleteEmptyObjects bool) {
 		}
 		if obj.suicided || (deleteEmptyObjects && obj.empty()) {
 			obj.deleted = true
+
+			// Drop the account and its storage from the snapshot layer too
+			if s.snap != nil {
+				s.snapDestructs[obj.addrHash] = struct{}{}
+				delete(s.snapAccounts, obj.addrHash)
+				delete(s.snapStorage, obj.addrHash)
+			}
 		} else {
 			obj.finalise()
 		}
@@ -705,15 +796,29 @@ func (s *StateDB) IntermediateRoot(deleteEmptyObjects bool) common.Hash {
 	// Finalise all the dirty storage states and write them into the tries
 	s.Finalise(deleteEmptyObjects)
 
+	// Write the pending storage changes into the tries, then hash the storage
+	// tries concurrently, as they are independent of each other
+	updated := make([]*stateObject, 0, len(s.stateObjectsPending))
 	for addr := range s.stateObjectsPending {
 		obj := s.stateObjects[addr]
 		if obj.deleted {
 			s.deleteStateObject(obj)
 		} else {
-			obj.updateRoot(s.db)
-			s.updateStateObject(obj)
+			obj.updateTrie(s.db)
+			updated = append(updated, obj)
 		}
 	}
+	start := time.Now()
+	forEachObject(updated, func(obj *stateObject) error {
+		obj.data.Root = obj.trie.Hash()
+		return nil
+	})
+	if metrics.EnabledExpensive {
+		s.StorageHashes += time.Since(start)
+	}
+	for _, obj := range updated {
+		s.updateStateObject(obj)
+	}
 	if len(s.stateObjectsPending) > 0 {
 		s.stateObjectsPending = make(map[common.Address]struct{})
 	}
@@ -746,6 +851,7 @@ func (s *StateDB) Commit(deleteEmptyObjects bool) (common.Hash, error) {
 	s.IntermediateRoot(deleteEmptyObjects)
 
 	// Commit objects to the trie, measuring the elapsed time
+	committed := make([]*stateObject, 0, len(s.stateObjectsDirty))
 	for addr := range s.stateObjectsDirty {
 		if obj := s.stateObjects[addr]; !obj.deleted {
 			// Write any contract code associated with the state object
@@ -753,12 +859,21 @@ func (s *StateDB) Commit(deleteEmptyObjects bool) (common.Hash, error) {
 				s.db.TrieDB().InsertBlob(common.BytesToHash(obj.CodeHash()), obj.code)
 				obj.dirtyCode = false
 			}
-			// Write any storage changes in the state object to its storage trie
-			if err := obj.CommitTrie(s.db); err != nil {
-				return common.Hash{}, err
-			}
+			// Make sure the storage trie is open and up to date
+			obj.updateTrie(s.db)
+			committed = append(committed, obj)
 		}
 	}
+	// Write the storage tries concurrently
+	start := time.Now()
+	if err := forEachObject(committed, func(obj *stateObject) error {
+		return obj.commitTrie()
+	}); err != nil {
+		return common.Hash{}, err
+	}
+	if metrics.EnabledExpensive {
+		s.StorageCommits += time.Since(start)
+	}
 	if len(s.stateObjectsDirty) > 0 {
 		s.stateObjectsDirty = make(map[common.Address]struct{})
 	}
@@ -766,7 +881,7 @@ func (s *StateDB) Commit(deleteEmptyObjects bool) (common.Hash, error) {
 	if metrics.EnabledExpensive {
 		defer func(start time.Time) { s.AccountCommits += time.Since(start) }(time.Now())
 	}
-	return s.trie.Commit(func(leaf []byte, parent common.Hash) error {
+	root, err := s.trie.Commit(func(leaf []byte, parent common.Hash) error {
 		var account Account
 		if err := rlp.DecodeBytes(leaf, &account); err != nil {
 			return nil
@@ -780,4 +895,68 @@ func (s *StateDB) Commit(deleteEmptyObjects bool) (common.Hash, error) {
 		}
 		return nil
 	})
+	return root, err
+}
+
+// UpdateSnapshot pushes the state transition since the snapshot layer the state
+// was opened at into the snapshot tree, as the layer of the committed root. It
+// must only be called for the final commit of an imported block: commits done
+// while the block is still being processed, e.g. by the consensus engine, and
+// the ones of replayed blocks accumulate into no layer of their own.
+func (s *StateDB) UpdateSnapshot(root common.Hash) {
+	if s.snap == nil {
+		return
+	}
+	// The layers are capped one below the tries kept in memory, so the disk layer
+	// can always be generated.
+	if parent := s.snap.Root(); parent != root {
+		if err := s.snaps.Update(root, parent, s.snapDestructs, s.snapAccounts, s.snapStorage); err != nil {
+			log.Warn("Failed to update snapshot tree", "from", parent, "to", root, "err", err)
+		}
+		if err := s.snaps.Cap(root, snapshotLayers); err != nil {
+			log.Warn("Failed to cap snapshot tree", "root", root, "layers", snapshotLayers, "err", err)
+		}
+	}
+	s.snap, s.snapDestructs, s.snapAccounts, s.snapStorage = nil, nil, nil, nil
+}
+
+// forEachObject runs fn on every state object, spreading the objects across the
+// CPUs, and returns the first error encountered. The objects must be distinct,
+// and fn must only touch the object it is given.
+func forEachObject(objects []*stateObject, fn func(obj *stateObject) error) error {
+	threads := runtime.GOMAXPROCS(0)
+	if threads > len(objects) {
+		threads = len(objects)
+	}
+	if threads <= 1 {
+		for _, obj := range objects {
+			if err := fn(obj); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, threads)
+	)
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := i; j < len(objects); j += threads {
+				if errs[i] = fn(objects[j]); errs[i] != nil {
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -33,6 +33,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
@@ -146,6 +147,67 @@ func TestIntermediateLeaks(t *testing.T) {
 	}
 }
 
+// Tests that hashing and committing many storage tries at once, concurrently,
+// produces the same state as handling the accounts one at a time.
+func TestParallelCommit(t *testing.T) {
+	modify := func(state *StateDB, i byte) {
+		addr := common.Address{i}
+		state.SetBalance(addr, big.NewInt(int64(i)+1))
+		for j := 0; j < 150; j++ {
+			state.SetState(addr, common.Hash{i, byte(j)}, common.Hash{byte(j), i})
+		}
+	}
+	// Create the state in one go, hashing and committing everything concurrently
+	parDb := rawdb.NewMemoryDatabase()
+	parState, _ := New(common.Hash{}, NewDatabase(parDb))
+	for i := byte(0); i < 64; i++ {
+		modify(parState, i)
+	}
+	parRoot, err := parState.Commit(false)
+	if err != nil {
+		t.Fatalf("failed to commit parallel state: %v", err)
+	}
+	// Create the same state account by account, hashing each one on its own
+	seqDb := rawdb.NewMemoryDatabase()
+	seqState, _ := New(common.Hash{}, NewDatabase(seqDb))
+	for i := byte(0); i < 64; i++ {
+		modify(seqState, i)
+		seqState.IntermediateRoot(false)
+	}
+	seqRoot, err := seqState.Commit(false)
+	if err != nil {
+		t.Fatalf("failed to commit sequential state: %v", err)
+	}
+	if parRoot != seqRoot {
+		t.Fatalf("root mismatch: have %x, want %x", parRoot, seqRoot)
+	}
+	// Flush both states and make sure they hold the same nodes
+	if err := parState.Database().TrieDB().Commit(parRoot, false); err != nil {
+		t.Fatalf("failed to flush parallel state: %v", err)
+	}
+	if err := seqState.Database().TrieDB().Commit(seqRoot, false); err != nil {
+		t.Fatalf("failed to flush sequential state: %v", err)
+	}
+	entries := 0
+	it := parDb.NewIterator()
+	for it.Next() {
+		entries++
+	}
+	it.Release()
+
+	it = seqDb.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		if blob, _ := parDb.Get(it.Key()); !bytes.Equal(blob, it.Value()) {
+			t.Errorf("entry %x mismatch", it.Key())
+		}
+		entries--
+	}
+	if entries != 0 {
+		t.Errorf("entry count mismatch: %d extra entries", entries)
+	}
+}
+
 // TestCopy tests that copying a statedb object indeed makes the original and
 // the copy independent of each other. This test is a regression test against
 // https://github.com/ethereum/go-ethereum/pull/15549.
@@ -681,3 +743,63 @@ func TestDeleteCreateRevert(t *testing.T) {
 		t.Fatalf("self-destructed contract came alive")
 	}
 }
+
+// Tests that the state read through the snapshot tree, after a block of changes
+// was committed into it, matches the state read from the trie. Commits done while
+// processing the block must not push layers of their own.
+func TestSnapshotReads(t *testing.T) {
+	diskdb := rawdb.NewMemoryDatabase()
+	db := NewDatabase(diskdb)
+
+	state, _ := New(common.Hash{}, db)
+	for i := byte(0); i < 8; i++ {
+		addr := common.BytesToAddress([]byte{i})
+		state.SetBalance(addr, big.NewInt(int64(i)+1))
+		state.SetState(addr, common.Hash{i}, common.Hash{i + 1})
+		state.SetState(addr, common.Hash{0xff}, common.Hash{0xff})
+	}
+	root, _ := state.Commit(false)
+
+	snaps := snapshot.New(diskdb, db.TrieDB(), 1, root)
+	defer snaps.Close()
+
+	// Destruct, recreate, update and create accounts in a block on top
+	state, _ = NewWithSnapshot(root, db, snaps)
+	state.Suicide(common.BytesToAddress([]byte{0}))
+	state.Suicide(common.BytesToAddress([]byte{2}))
+	state.Finalise(true)
+
+	mid, _ := state.Commit(false)
+	if snaps.Snapshot(mid) != nil {
+		t.Fatalf("snapshot layer pushed for a mid-block commit")
+	}
+
+	state.CreateAccount(common.BytesToAddress([]byte{2}))
+	state.SetBalance(common.BytesToAddress([]byte{2}), big.NewInt(5))
+	state.SetState(common.BytesToAddress([]byte{2}), common.Hash{0x10}, common.Hash{0x10})
+	state.SetState(common.BytesToAddress([]byte{1}), common.Hash{1}, common.Hash{})
+	state.SetState(common.BytesToAddress([]byte{1}), common.Hash{0xff}, common.Hash{0x01})
+	state.SetBalance(common.BytesToAddress([]byte{9}), big.NewInt(9))
+	root, _ = state.Commit(false)
+	state.UpdateSnapshot(root)
+
+	snapState, _ := NewWithSnapshot(root, db, snaps)
+	if snapState.snap == nil {
+		t.Fatalf("snapshot layer missing for committed state")
+	}
+	trieState, _ := New(root, db)
+	for i := byte(0); i < 10; i++ {
+		addr := common.BytesToAddress([]byte{i})
+		if have, want := snapState.Exist(addr), trieState.Exist(addr); have != want {
+			t.Errorf("account %d: existence mismatch: have %v, want %v", i, have, want)
+		}
+		if have, want := snapState.GetBalance(addr), trieState.GetBalance(addr); have.Cmp(want) != 0 {
+			t.Errorf("account %d: balance mismatch: have %v, want %v", i, have, want)
+		}
+		for _, key := range []common.Hash{{i}, {0x10}, {0xff}} {
+			if have, want := snapState.GetState(addr, key), trieState.GetState(addr, key); have != want {
+				t.Errorf("account %d: slot %x mismatch: have %x, want %x", i, key, have, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,439 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package statefile implements a portable binary format for the state of a
+// block, which can be exported by one node and imported by another.
+//
+// A state file is a stream of RLP items. It starts with a manifest naming the
+// block and its state root, followed by one entry per account in the order of
+// the account trie. Each account is preceded by its contract code, unless an
+// earlier account shares it, and followed by its storage slots in the order of
+// its storage trie. The file ends with an entry holding the keccak256 checksum
+// of all the preceding items, so that truncated or damaged files are detected.
+//
+// Accounts are keyed by the hashes of their addresses and slots by the hashes
+// of their keys, so the file doesn't depend on the preimages being known. The
+// account values are in their consensus encoding, MRU numbers included, which
+// lets the importer rebuild the exact same tries.
+package statefile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// Magic is the string identifying state files.
+	Magic = "gonex-state"
+
+	// Version is the version of the state file format written by Export.
+	Version = 1
+)
+
+// Kinds of entries following the manifest.
+const (
+	entryAccount  = iota // Key is the account hash, Value its consensus encoding
+	entryStorage         // Key is the slot hash, Value its RLP encoded value
+	entryCode            // Key is the code hash, Value the code
+	entryChecksum        // Key is the checksum of all preceding items
+)
+
+const (
+	// commitInterval is the number of trie insertions after which a trie under
+	// construction is committed, to bound the memory used by the importer.
+	commitInterval = 65536
+
+	// dirtyLimit is the size of the trie nodes the importer holds in memory
+	// before flushing them to disk.
+	dirtyLimit = 256 * 1024 * 1024
+)
+
+var (
+	emptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+	emptyCode = crypto.Keccak256Hash(nil)
+
+	// errNotStateFile is returned if the stream doesn't start with a manifest.
+	errNotStateFile = errors.New("not a state file")
+
+	// errChecksumMismatch is returned if the file content doesn't match the
+	// checksum it ends with.
+	errChecksumMismatch = errors.New("state file checksum mismatch")
+)
+
+// Manifest describes the state held by a state file.
+type Manifest struct {
+	Magic   string
+	Version uint64
+	Number  uint64      // Number of the block the state belongs to
+	Hash    common.Hash // Hash of the block the state belongs to
+	Root    common.Hash // State root of the block
+}
+
+// Stats are the numbers of entries of a state file.
+type Stats struct {
+	Accounts uint64
+	Slots    uint64
+	Codes    uint64
+	Size     common.StorageSize
+}
+
+// entry is an item of a state file following the manifest.
+type entry struct {
+	Kind  uint8
+	Key   common.Hash
+	Value []byte
+}
+
+// writer encodes the items of a state file, accumulating their checksum.
+type writer struct {
+	w      io.Writer
+	hasher hash.Hash
+	stats  Stats
+}
+
+func (w *writer) write(item interface{}) error {
+	blob, err := rlp.EncodeToBytes(item)
+	if err != nil {
+		return err
+	}
+	w.hasher.Write(blob)
+	w.stats.Size += common.StorageSize(len(blob))
+	_, err = w.w.Write(blob)
+	return err
+}
+
+// Export writes the state of the given block to w.
+func Export(w io.Writer, db ethdb.Database, header *types.Header) (*Stats, error) {
+	var (
+		sdb    = state.NewDatabase(db)
+		out    = &writer{w: w, hasher: sha3.NewLegacyKeccak256()}
+		codes  = make(map[common.Hash]struct{})
+		start  = time.Now()
+		logged = time.Now()
+	)
+	accTrie, err := sdb.OpenTrie(header.Root)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &Manifest{Magic: Magic, Version: Version, Number: header.Number.Uint64(), Hash: header.Hash(), Root: header.Root}
+	if err := out.write(manifest); err != nil {
+		return nil, err
+	}
+	accIt := trie.NewIterator(accTrie.NodeIterator(nil))
+	for accIt.Next() {
+		var account state.Account
+		if err := rlp.DecodeBytes(accIt.Value, &account); err != nil {
+			return nil, fmt.Errorf("invalid account %x: %v", accIt.Key, err)
+		}
+		accHash := common.BytesToHash(accIt.Key)
+
+		// Write the code first, so the importer can check it along the account
+		if codeHash := common.BytesToHash(account.CodeHash); codeHash != emptyCode {
+			if _, ok := codes[codeHash]; !ok {
+				code, err := sdb.ContractCode(accHash, codeHash)
+				if err != nil {
+					return nil, fmt.Errorf("missing code %x of account %x: %v", codeHash, accHash, err)
+				}
+				if err := out.write(&entry{Kind: entryCode, Key: codeHash, Value: code}); err != nil {
+					return nil, err
+				}
+				codes[codeHash] = struct{}{}
+				out.stats.Codes++
+			}
+		}
+		if err := out.write(&entry{Kind: entryAccount, Key: accHash, Value: accIt.Value}); err != nil {
+			return nil, err
+		}
+		out.stats.Accounts++
+
+		if account.Root != emptyRoot {
+			storageTrie, err := sdb.OpenStorageTrie(accHash, account.Root)
+			if err != nil {
+				return nil, err
+			}
+			storageIt := trie.NewIterator(storageTrie.NodeIterator(nil))
+			for storageIt.Next() {
+				if err := out.write(&entry{Kind: entryStorage, Key: common.BytesToHash(storageIt.Key), Value: storageIt.Value}); err != nil {
+					return nil, err
+				}
+				out.stats.Slots++
+
+				if time.Since(logged) > 8*time.Second {
+					log.Info("Exporting state", "accounts", out.stats.Accounts, "slots", out.stats.Slots, "codes", out.stats.Codes, "size", out.stats.Size, "elapsed", common.PrettyDuration(time.Since(start)))
+					logged = time.Now()
+				}
+			}
+			if storageIt.Err != nil {
+				return nil, fmt.Errorf("failed to iterate storage of account %x: %v", accHash, storageIt.Err)
+			}
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Exporting state", "accounts", out.stats.Accounts, "slots", out.stats.Slots, "codes", out.stats.Codes, "size", out.stats.Size, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if accIt.Err != nil {
+		return nil, fmt.Errorf("failed to iterate state %x: %v", header.Root, accIt.Err)
+	}
+	// Seal the file with the checksum of everything written before
+	if err := out.write(&entry{Kind: entryChecksum, Key: common.BytesToHash(out.hasher.Sum(nil))}); err != nil {
+		return nil, err
+	}
+	log.Info("Exported state", "number", manifest.Number, "hash", manifest.Hash, "root", header.Root, "accounts", out.stats.Accounts, "slots", out.stats.Slots, "codes", out.stats.Codes, "size", out.stats.Size, "elapsed", common.PrettyDuration(time.Since(start)))
+	return &out.stats, nil
+}
+
+// importer rebuilds the tries of a state file.
+type importer struct {
+	triedb *trie.Database
+	codes  map[common.Hash]struct{}
+
+	accTrie    *trie.Trie
+	accHash    common.Hash // Hash of the last account imported
+	accRoot    common.Hash // Storage root of the last account imported
+	accPending int         // Accounts inserted since the last commit
+
+	storageTrie    *trie.Trie  // Storage trie of the last account, if any
+	storageHash    common.Hash // Hash of the last slot imported
+	storagePending int         // Slots inserted since the last commit
+
+	stats Stats
+}
+
+// commitAccounts commits the account trie, referencing the storage tries and
+// codes of the accounts inserted since the last commit, and reopens it so that
+// the committed nodes can be released from memory.
+func (im *importer) commitAccounts() error {
+	root, err := im.accTrie.Commit(func(leaf []byte, parent common.Hash) error {
+		var account state.Account
+		if err := rlp.DecodeBytes(leaf, &account); err != nil {
+			return nil
+		}
+		if account.Root != emptyRoot {
+			im.triedb.Reference(account.Root, parent)
+		}
+		if code := common.BytesToHash(account.CodeHash); code != emptyCode {
+			im.triedb.Reference(code, parent)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if im.accTrie, err = trie.New(root, im.triedb); err != nil {
+		return err
+	}
+	im.accPending = 0
+	return im.capMemory()
+}
+
+// commitStorage commits the storage trie of the last account, reopening it if
+// more slots are to be inserted.
+func (im *importer) commitStorage(reopen bool) (common.Hash, error) {
+	root, err := im.storageTrie.Commit(nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if reopen {
+		if im.storageTrie, err = trie.New(root, im.triedb); err != nil {
+			return common.Hash{}, err
+		}
+	}
+	im.storagePending = 0
+	return root, im.capMemory()
+}
+
+// capMemory flushes trie nodes to disk if too many are held in memory.
+func (im *importer) capMemory() error {
+	if size, _ := im.triedb.Size(); size > dirtyLimit {
+		return im.triedb.Cap(dirtyLimit / 2)
+	}
+	return nil
+}
+
+// finishAccount checks the storage root of the last account imported.
+func (im *importer) finishAccount() error {
+	root := emptyRoot
+	if im.storageTrie != nil {
+		var err error
+		if root, err = im.commitStorage(false); err != nil {
+			return err
+		}
+		im.storageTrie = nil
+	}
+	if root != im.accRoot {
+		return fmt.Errorf("storage root mismatch for account %x: have %x, want %x", im.accHash, root, im.accRoot)
+	}
+	return nil
+}
+
+func (im *importer) importEntry(e *entry) error {
+	switch e.Kind {
+	case entryCode:
+		if crypto.Keccak256Hash(e.Value) != e.Key {
+			return fmt.Errorf("code hash mismatch: have %x, want %x", crypto.Keccak256Hash(e.Value), e.Key)
+		}
+		im.triedb.InsertBlob(e.Key, e.Value)
+		im.codes[e.Key] = struct{}{}
+		im.stats.Codes++
+
+	case entryAccount:
+		if im.stats.Accounts > 0 {
+			if err := im.finishAccount(); err != nil {
+				return err
+			}
+			if bytes.Compare(e.Key[:], im.accHash[:]) <= 0 {
+				return fmt.Errorf("account %x out of order after %x", e.Key, im.accHash)
+			}
+		}
+		var account state.Account
+		if err := rlp.DecodeBytes(e.Value, &account); err != nil {
+			return fmt.Errorf("invalid account %x: %v", e.Key, err)
+		}
+		if code := common.BytesToHash(account.CodeHash); code != emptyCode {
+			if _, ok := im.codes[code]; !ok {
+				return fmt.Errorf("missing code %x of account %x", code, e.Key)
+			}
+		}
+		if err := im.accTrie.TryUpdate(e.Key[:], e.Value); err != nil {
+			return err
+		}
+		im.accHash, im.accRoot = e.Key, account.Root
+		im.stats.Accounts++
+
+		if im.accPending++; im.accPending >= commitInterval {
+			return im.commitAccounts()
+		}
+
+	case entryStorage:
+		if im.stats.Accounts == 0 {
+			return fmt.Errorf("slot %x without account", e.Key)
+		}
+		if im.storageTrie == nil {
+			im.storageTrie, _ = trie.New(common.Hash{}, im.triedb)
+		} else if bytes.Compare(e.Key[:], im.storageHash[:]) <= 0 {
+			return fmt.Errorf("slot %x of account %x out of order after %x", e.Key, im.accHash, im.storageHash)
+		}
+		if err := im.storageTrie.TryUpdate(e.Key[:], e.Value); err != nil {
+			return err
+		}
+		im.storageHash = e.Key
+		im.stats.Slots++
+
+		if im.storagePending++; im.storagePending >= commitInterval {
+			_, err := im.commitStorage(true)
+			return err
+		}
+
+	default:
+		return fmt.Errorf("unknown entry kind %d", e.Kind)
+	}
+	return nil
+}
+
+// Import reads a state file from r, rebuilding its tries into the database. The
+// state is only committed once the checksum and the state root of the file are
+// verified. Some trie nodes may be flushed before, which are then left
+// unreferenced in the database if the import fails.
+func Import(r io.Reader, db ethdb.Database) (*Manifest, *Stats, error) {
+	var (
+		stream = rlp.NewStream(r, 0)
+		hasher = sha3.NewLegacyKeccak256()
+		start  = time.Now()
+		logged = time.Now()
+	)
+	// Read the manifest and make sure the file format is known
+	blob, err := stream.Raw()
+	if err != nil {
+		return nil, nil, err
+	}
+	manifest := new(Manifest)
+	if err := rlp.DecodeBytes(blob, manifest); err != nil || manifest.Magic != Magic {
+		return nil, nil, errNotStateFile
+	}
+	if manifest.Version != Version {
+		return nil, nil, fmt.Errorf("unsupported state file version %d", manifest.Version)
+	}
+	hasher.Write(blob)
+
+	im := &importer{
+		triedb: trie.NewDatabase(db),
+		codes:  make(map[common.Hash]struct{}),
+	}
+	im.accTrie, _ = trie.New(common.Hash{}, im.triedb)
+	im.stats.Size = common.StorageSize(len(blob))
+
+	for {
+		blob, err := stream.Raw()
+		if err == io.EOF {
+			return nil, nil, fmt.Errorf("state file truncated: %v", io.ErrUnexpectedEOF)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		e := new(entry)
+		if err := rlp.DecodeBytes(blob, e); err != nil {
+			return nil, nil, err
+		}
+		im.stats.Size += common.StorageSize(len(blob))
+
+		if e.Kind == entryChecksum {
+			if e.Key != common.BytesToHash(hasher.Sum(nil)) {
+				return nil, nil, errChecksumMismatch
+			}
+			break
+		}
+		hasher.Write(blob)
+		if err := im.importEntry(e); err != nil {
+			return nil, nil, err
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Importing state", "accounts", im.stats.Accounts, "slots", im.stats.Slots, "codes", im.stats.Codes, "size", im.stats.Size, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	// All entries read, verify the rebuilt state and commit it
+	if im.stats.Accounts > 0 {
+		if err := im.finishAccount(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := im.commitAccounts(); err != nil {
+		return nil, nil, err
+	}
+	if root := im.accTrie.Hash(); root != manifest.Root {
+		return nil, nil, fmt.Errorf("state root mismatch: have %x, want %x", root, manifest.Root)
+	}
+	if err := im.triedb.Commit(manifest.Root, false); err != nil {
+		return nil, nil, err
+	}
+	log.Info("Imported state", "number", manifest.Number, "hash", manifest.Hash, "root", manifest.Root, "accounts", im.stats.Accounts, "slots", im.stats.Slots, "codes", im.stats.Codes, "size", im.stats.Size, "elapsed", common.PrettyDuration(time.Since(start)))
+	return manifest, &im.stats, nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package statefile
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// makeTestState creates a database holding a state with plain accounts,
+// contracts sharing code, storage and MRU numbers, returning the header of a
+// block committing to it.
+func makeTestState(t *testing.T) (ethdb.Database, *types.Header) {
+	db := rawdb.NewMemoryDatabase()
+	sdb := state.NewDatabase(db)
+	statedb, _ := state.New(common.Hash{}, sdb)
+
+	for i := byte(0); i < 64; i++ {
+		addr := common.BytesToAddress([]byte{i})
+		statedb.SetBalance(addr, big.NewInt(int64(i)+1))
+		statedb.SetNonce(addr, uint64(i))
+		if i%3 == 0 {
+			statedb.SetMRUNumber(addr, uint64(i)*1000)
+		}
+		if i%4 == 0 {
+			statedb.SetCode(addr, []byte{i % 8})
+			for j := byte(0); j < i; j++ {
+				statedb.SetState(addr, common.Hash{j}, common.Hash{i, j})
+			}
+		}
+	}
+	root, err := statedb.Commit(true)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	if err := sdb.TrieDB().Commit(root, false); err != nil {
+		t.Fatalf("failed to flush state: %v", err)
+	}
+	return db, &types.Header{Number: big.NewInt(42), Root: root}
+}
+
+// Tests that an exported state is imported into an identical state.
+func TestExportImport(t *testing.T) {
+	db, header := makeTestState(t)
+
+	var file bytes.Buffer
+	exported, err := Export(&file, db, header)
+	if err != nil {
+		t.Fatalf("failed to export state: %v", err)
+	}
+	if exported.Accounts != 64 || exported.Codes != 2 || exported.Slots != 480 {
+		t.Fatalf("export stats mismatch: have %d accounts, %d codes, %d slots, want 64, 2, 480", exported.Accounts, exported.Codes, exported.Slots)
+	}
+	target := rawdb.NewMemoryDatabase()
+	manifest, imported, err := Import(bytes.NewReader(file.Bytes()), target)
+	if err != nil {
+		t.Fatalf("failed to import state: %v", err)
+	}
+	if manifest.Root != header.Root || manifest.Number != 42 || manifest.Hash != header.Hash() {
+		t.Fatalf("manifest mismatch: have %+v", manifest)
+	}
+	if *imported != *exported {
+		t.Fatalf("import stats mismatch: have %+v, want %+v", imported, exported)
+	}
+	// Every account must be readable from the imported database
+	src, _ := state.New(header.Root, state.NewDatabase(db))
+	dst, err := state.New(header.Root, state.NewDatabase(target))
+	if err != nil {
+		t.Fatalf("failed to open imported state: %v", err)
+	}
+	for i := byte(0); i < 64; i++ {
+		addr := common.BytesToAddress([]byte{i})
+		if dst.GetBalance(addr).Cmp(src.GetBalance(addr)) != 0 || dst.GetNonce(addr) != src.GetNonce(addr) || dst.GetMRUNumber(addr) != src.GetMRUNumber(addr) {
+			t.Errorf("account %x mismatch", addr)
+		}
+		if !bytes.Equal(dst.GetCode(addr), src.GetCode(addr)) {
+			t.Errorf("code of account %x mismatch", addr)
+		}
+		for j := byte(0); j < i; j++ {
+			if have, want := dst.GetState(addr, common.Hash{j}), src.GetState(addr, common.Hash{j}); have != want {
+				t.Errorf("slot %x of account %x mismatch: have %x, want %x", j, addr, have, want)
+			}
+		}
+	}
+}
+
+// Tests that damaged or truncated state files are rejected.
+func TestImportCorrupted(t *testing.T) {
+	db, header := makeTestState(t)
+
+	var file bytes.Buffer
+	if _, err := Export(&file, db, header); err != nil {
+		t.Fatalf("failed to export state: %v", err)
+	}
+	blob := file.Bytes()
+
+	// Flip a byte of the last slot value, which the checksum detects
+	damaged := common.CopyBytes(blob)
+	damaged[len(damaged)-37] ^= 0x01
+	if _, _, err := Import(bytes.NewReader(damaged), rawdb.NewMemoryDatabase()); err == nil {
+		t.Errorf("damaged file imported")
+	}
+	// Drop the checksum at the end of the file
+	if _, _, err := Import(bytes.NewReader(blob[:len(blob)-36]), rawdb.NewMemoryDatabase()); err == nil {
+		t.Errorf("truncated file imported")
+	}
+	// Reject files of other formats
+	if _, _, err := Import(bytes.NewReader([]byte{0xc0}), rawdb.NewMemoryDatabase()); err != errNotStateFile {
+		t.Errorf("unexpected error for foreign file: have %v, want %v", err, errNotStateFile)
+	}
+}
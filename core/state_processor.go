@@ -61,8 +61,16 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 		header  = block.Header()
 		allLogs []*types.Log
 		gp      = new(GasPool).AddGas(block.GasLimit())
+
+		txs      types.Transactions
+		receipts types.Receipts
+		err      error
 	)
-	txs, receipts, err := p.engine.Initialize(p.bc, header, statedb)
+	if initializer, ok := p.engine.(SystemInitializer); ok {
+		txs, receipts, err = initializer.InitializeWithConfig(p.bc, header, statedb, cfg)
+	} else {
+		txs, receipts, err = p.engine.Initialize(p.bc, header, statedb)
+	}
 	if err != nil {
 		log.Error("Error on block initialization", "err", err)
 	}
@@ -114,7 +122,7 @@ func ApplyTransaction(config *params.ChainConfig, bc consensus.ChainReader, auth
 	// about the transaction and calling mechanisms.
 	vmenv := vm.NewEVM(context, statedb, config, cfg)
 	// Apply the transaction to the current state (included in the env)
-	_, gas, failed, err := ApplyMessage(vmenv, msg, gp)
+	ret, gas, failed, err := ApplyMessage(vmenv, msg, gp)
 	if err != nil {
 		return nil, err
 	}
@@ -132,6 +140,9 @@ func ApplyTransaction(config *params.ChainConfig, bc consensus.ChainReader, auth
 	receipt := types.NewReceipt(root, failed, *usedGas)
 	receipt.TxHash = tx.Hash()
 	receipt.GasUsed = gas
+	if failed {
+		receipt.FailureReason = vmenv.Failure(ret)
+	}
 	// if the transaction created a contract, store the creation address in the receipt.
 	if msg.To() == nil {
 		receipt.ContractAddress = crypto.CreateAddress(vmenv.Context.Origin, tx.Nonce())
@@ -17,6 +17,7 @@
 package core
 
 import (
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -49,3 +50,12 @@ type Processor interface {
 	// the processor (coinbase) and any included uncles.
 	Process(block *types.Block, statedb *state.StateDB, cfg vm.Config) (types.Receipts, []*types.Log, uint64, error)
 }
+
+// SystemInitializer is an optional interface for the consensus engines which
+// execute system transactions on block initialization.
+type SystemInitializer interface {
+	// InitializeWithConfig runs the block initialization as consensus.Engine's
+	// Initialize does, executing the system transactions with the given EVM
+	// configuration, e.g. to trace them.
+	InitializeWithConfig(chain consensus.ChainReader, header *types.Header, state *state.StateDB, cfg vm.Config) (types.Transactions, types.Receipts, error)
+}
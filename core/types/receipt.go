@@ -66,6 +66,11 @@ type Receipt struct {
 	BlockHash        common.Hash `json:"blockHash,omitempty"`
 	BlockNumber      *big.Int    `json:"blockNumber,omitempty"`
 	TransactionIndex uint        `json:"transactionIndex"`
+
+	// Failure information: This field is not part of the consensus nor the receipt
+	// storage encoding, it is kept in a sidecar index of the chain database. It is
+	// only available for blocks executed locally, not for fast synced ones.
+	FailureReason string `json:"failureReason,omitempty"`
 }
 
 type receiptMarshaling struct {
@@ -171,7 +176,7 @@ func (r *Receipt) statusEncoding() []byte {
 // Size returns the approximate memory used by all internal contents. It is used
 // to approximate and limit the memory consumption of various caches.
 func (r *Receipt) Size() common.StorageSize {
-	size := common.StorageSize(unsafe.Sizeof(*r)) + common.StorageSize(len(r.PostState))
+	size := common.StorageSize(unsafe.Sizeof(*r)) + common.StorageSize(len(r.PostState)) + common.StorageSize(len(r.FailureReason))
 
 	size += common.StorageSize(len(r.Logs)) * common.StorageSize(unsafe.Sizeof(Log{}))
 	for _, log := range r.Logs {
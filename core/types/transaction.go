@@ -125,6 +125,14 @@ func (tx *Transaction) Protected() bool {
 	return isProtectedV(tx.data.V)
 }
 
+// IsSystem reports whether the transaction is a system transaction, i.e. an
+// unsigned transaction executed by the consensus engine on block initialization
+// on behalf of SystemSigner. Such transactions are never accepted from the
+// network, as their sender can't be recovered from the signature.
+func (tx *Transaction) IsSystem() bool {
+	return tx.data.V.Sign() == 0 && tx.data.R.Sign() == 0 && tx.data.S.Sign() == 0
+}
+
 func isProtectedV(V *big.Int) bool {
 	if V.BitLen() <= 8 {
 		v := V.Uint64()
@@ -259,6 +259,10 @@ func deriveChainId(v *big.Int) *big.Int {
 	return v.Div(v, big.NewInt(2))
 }
 
+// SystemSigner is the signer of the system transactions, executed by the
+// consensus engine from the zero address without signature, nonce or fee.
+var SystemSigner = ConsensusSigner{Address: params.ZeroAddress}
+
 // ConsensusSigner implements SignerInterface for consensus use.
 type ConsensusSigner struct {
 	Address common.Address
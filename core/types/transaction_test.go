@@ -118,6 +118,38 @@ func TestRecipientNormal(t *testing.T) {
 	}
 }
 
+// Tests that unsigned transactions are marked as system transactions, sent by
+// the system signer, and that they survive an RLP round trip.
+func TestSystemTransaction(t *testing.T) {
+	if !emptyTx.IsSystem() {
+		t.Error("unsigned transaction not marked as system transaction")
+	}
+	if rightvrsTx.IsSystem() {
+		t.Error("signed transaction marked as system transaction")
+	}
+	txb, err := rlp.EncodeToBytes(emptyTx)
+	if err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+	tx, err := decodeTx(txb)
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if !tx.IsSystem() {
+		t.Error("decoded system transaction not marked as system transaction")
+	}
+	from, err := Sender(SystemSigner, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != SystemSigner.Address {
+		t.Errorf("system transaction sender mismatch: have %x, want %x", from, SystemSigner.Address)
+	}
+	if _, err := Sender(HomesteadSigner{}, tx); err == nil {
+		t.Error("recovered sender of an unsigned transaction")
+	}
+}
+
 // Tests that transactions can be correctly sorted according to their price in
 // decreasing order, but at the same time with increasing nonces when issued by
 // the same account.
@@ -0,0 +1,80 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the gonex library.
+//
+// The gonex library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gonex library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the gonex library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build gofuzz
+// +build gofuzz
+
+package vm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fuzzMaxNanosPerGas is the slowest execution time per gas tolerated for the
+// pre-compiled contracts, any slower input is considered underpriced.
+const fuzzMaxNanosPerGas = 100
+
+// FuzzVDFVerify is the go-fuzz entry point for the vdfVerify contract. The data
+// is reshaped into a well formed input so that the verifier itself is reached.
+//
+// This returns 1 for a verified input, 0 otherwise, and panics on an input
+// which takes longer to verify than the gas paid for it.
+func FuzzVDFVerify(data []byte) int {
+	if len(data) < 36 {
+		return 0
+	}
+	bitSize := uint64(binary.BigEndian.Uint16(data[:2]))
+	outputLen := int((bitSize + 16) >> 2)
+
+	input := make([]byte, 0, 96+outputLen)
+	input = append(input, common.LeftPadBytes(data[:2], 32)...)
+	input = append(input, common.LeftPadBytes(data[2:4], 32)...)
+	input = append(input, data[4:36]...)
+	input = append(input, common.RightPadBytes(data[36:], outputLen)[:outputLen]...)
+
+	return fuzzPrecompiled(&vdfVerify{}, input)
+}
+
+// FuzzEthashVerify is the go-fuzz entry point for the ethashVerify contract.
+//
+// This returns 1 for a verified input, 0 otherwise, and panics on an input
+// which takes longer to verify than the gas paid for it.
+func FuzzEthashVerify(data []byte) int {
+	return fuzzPrecompiled(&ethashVerify{}, common.RightPadBytes(data, 32*5)[:32*5])
+}
+
+// fuzzPrecompiled runs a pre-compiled contract and cross checks its execution
+// time against its required gas.
+func fuzzPrecompiled(p PrecompiledContract, input []byte) int {
+	gas := p.RequiredGas(input)
+
+	start := time.Now()
+	output, err := p.Run(input)
+	elapsed := time.Since(start)
+
+	if gas < math.MaxUint64/fuzzMaxNanosPerGas && uint64(elapsed) > gas*fuzzMaxNanosPerGas {
+		panic(fmt.Sprintf("underpriced input %x: %d gas took %v", input, gas, elapsed))
+	}
+	if err != nil || common.BytesToHash(output) != common.BytesToHash(true32Byte) {
+		return 0
+	}
+	return 1
+}
@@ -17,8 +17,11 @@
 package vm
 
 import (
+	"bytes"
 	"errors"
+	"math/big"
 
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -32,3 +35,26 @@ var (
 	ErrTraceLimitReached        = errors.New("the number of logs reached the specified limit")
 	ErrNoCompatibleInterpreter  = errors.New("no compatible interpreter")
 )
+
+// revertSelector is the method id of the Solidity Error(string) revert payload.
+var revertSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+
+// UnpackRevert decodes the reason string from the return data of a contract
+// reverted with the Solidity Error(string) payload.
+func UnpackRevert(data []byte) (string, bool) {
+	if len(data) < len(revertSelector)+64 || !bytes.Equal(data[:len(revertSelector)], revertSelector) {
+		return "", false
+	}
+	data = data[len(revertSelector):]
+
+	offset := new(big.Int).SetBytes(data[:32])
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(data)-32) {
+		return "", false
+	}
+	start := offset.Uint64() + 32
+	size := new(big.Int).SetBytes(data[start-32 : start])
+	if !size.IsUint64() || size.Uint64() > uint64(len(data))-start {
+		return "", false
+	}
+	return string(data[start : start+size.Uint64()]), true
+}
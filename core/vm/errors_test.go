@@ -0,0 +1,113 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestUnpackRevert(t *testing.T) {
+	tests := []struct {
+		input  string
+		reason string
+		ok     bool
+	}{
+		{"", "", false},
+		{"08c379a1" + "0000000000000000000000000000000000000000000000000000000000000020", "", false},
+		// Error("revert reason")
+		{"08c379a0" +
+			"0000000000000000000000000000000000000000000000000000000000000020" +
+			"000000000000000000000000000000000000000000000000000000000000000d" +
+			"72657665727420726561736f6e00000000000000000000000000000000000000", "revert reason", true},
+		// Error("") with an out of bound string length
+		{"08c379a0" +
+			"0000000000000000000000000000000000000000000000000000000000000020" +
+			"00000000000000000000000000000000000000000000000000000000000000ff", "", false},
+		// Error("") with an out of bound offset
+		{"08c379a0" +
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff" +
+			"0000000000000000000000000000000000000000000000000000000000000000", "", false},
+	}
+	for i, test := range tests {
+		reason, ok := UnpackRevert(common.Hex2Bytes(test.input))
+		if reason != test.reason || ok != test.ok {
+			t.Errorf("test %d: have (%q, %v), want (%q, %v)", i, reason, ok, test.reason, test.ok)
+		}
+	}
+}
+
+func TestFailure(t *testing.T) {
+	reverted := common.Hex2Bytes("08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"000000000000000000000000000000000000000000000000000000000000000d" +
+		"72657665727420726561736f6e00000000000000000000000000000000000000")
+
+	evm := new(EVM)
+	if reason := evm.Failure(nil); reason != "" {
+		t.Errorf("undecodable failure reason mismatch: have %q, want empty", reason)
+	}
+	evm.LogFailure("logged reason")
+	if reason := evm.Failure(nil); reason != "logged reason" {
+		t.Errorf("logged failure reason mismatch: have %q, want %q", reason, "logged reason")
+	}
+	if reason := evm.Failure(reverted); reason != "revert reason" {
+		t.Errorf("revert failure reason mismatch: have %q, want %q", reason, "revert reason")
+	}
+}
+
+// Tests that the failure of a nested call caught by its caller doesn't leak into
+// the failure reason of the caller.
+func TestFailureNested(t *testing.T) {
+	tests := []struct {
+		code   string // executed after calling the failing contract
+		reason string
+	}{
+		{"0x00", ""}, // STOP
+		{"0x60006000fd", params.ErrorLogRevertUnknown}, // REVERT(0, 0)
+		{"0x60016000fd", params.ErrorLogRevertUnknown}, // REVERT(0, 1)
+	}
+	for i, tt := range tests {
+		var (
+			caller = common.BytesToAddress([]byte("caller"))
+			callee = common.BytesToAddress([]byte{0xbb})
+		)
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+		statedb.SetCode(callee, []byte{0xfe}) // INVALID
+
+		// POP(CALL(GAS, 0xbb, 0, 0, 0, 0, 0)) followed by the test code
+		statedb.SetCode(caller, append(hexutil.MustDecode("0x6000600060006000600060bb5af150"), hexutil.MustDecode(tt.code)...))
+
+		vmctx := Context{
+			CanTransfer: func(StateDB, common.Address, *big.Int) bool { return true },
+			Transfer:    func(StateDB, common.Address, common.Address, *big.Int) {},
+			BlockNumber: new(big.Int),
+		}
+		vmenv := NewEVM(vmctx, statedb, params.AllEthashProtocolChanges, Config{})
+
+		ret, _, _ := vmenv.Call(AccountRef(common.Address{}), caller, nil, 100000, new(big.Int))
+		if reason := vmenv.Failure(ret); reason != tt.reason {
+			t.Errorf("test %d: failure reason mismatch: have %q, want %q", i, reason, tt.reason)
+		}
+	}
+}
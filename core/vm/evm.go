@@ -100,7 +100,7 @@ type Context struct {
 	Time        *big.Int       // Provides information for TIME
 	Difficulty  *big.Int       // Provides information for DIFFICULTY
 
-	// TODO
+	// FailureReason is the last failure logged during the execution
 	FailureReason string
 }
 
@@ -182,6 +182,22 @@ func (evm *EVM) LogFailure(reason string) {
 	evm.FailureReason = reason
 }
 
+// Failure returns the reason of a failed execution given its return data. The
+// Solidity revert reason takes precedence over the logged failure, and the
+// reason is empty if neither is available.
+func (evm *EVM) Failure(ret []byte) string {
+	if reason, ok := UnpackRevert(ret); ok {
+		return reason
+	}
+	return evm.FailureReason
+}
+
+// restoreFailure resets the failure reason to the one of the calling frame once
+// a nested frame returned, its failure being handled by the caller.
+func (evm *EVM) restoreFailure(reason string) {
+	evm.FailureReason = reason
+}
+
 func (evm *EVM) IgnoreNonce() bool {
 	return evm.vmConfig.IgnoreNonce
 }
@@ -210,6 +226,10 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 	if evm.vmConfig.NoRecursion && evm.depth > 0 {
 		return nil, gas, nil
 	}
+	// The failure of a nested frame is handled by its caller
+	if evm.depth > 0 {
+		defer evm.restoreFailure(evm.FailureReason)
+	}
 
 	// Fail if we're trying to execute above the call depth limit
 	if evm.depth > int(params.CallCreateDepth) {
@@ -292,6 +312,9 @@ func (evm *EVM) ExecCall(caller ContractRef, code []byte, gas uint64, value *big
 	if evm.vmConfig.NoRecursion && evm.depth > 0 {
 		return nil, gas, nil
 	}
+	if evm.depth > 0 {
+		defer evm.restoreFailure(evm.FailureReason)
+	}
 	to := caller.Address()
 
 	// Fail if we're trying to execute above the call depth limit
@@ -357,6 +380,9 @@ func (evm *EVM) CallCode(caller ContractRef, addr common.Address, input []byte,
 	if evm.vmConfig.NoRecursion && evm.depth > 0 {
 		return nil, gas, nil
 	}
+	if evm.depth > 0 {
+		defer evm.restoreFailure(evm.FailureReason)
+	}
 
 	// Fail if we're trying to execute above the call depth limit
 	if evm.depth > int(params.CallCreateDepth) {
@@ -397,6 +423,9 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []by
 	if evm.vmConfig.NoRecursion && evm.depth > 0 {
 		return nil, gas, nil
 	}
+	if evm.depth > 0 {
+		defer evm.restoreFailure(evm.FailureReason)
+	}
 	// Fail if we're trying to execute above the call depth limit
 	if evm.depth > int(params.CallCreateDepth) {
 		evm.LogFailure(params.ErrorLogDepth)
@@ -430,6 +459,9 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 	if evm.vmConfig.NoRecursion && evm.depth > 0 {
 		return nil, gas, nil
 	}
+	if evm.depth > 0 {
+		defer evm.restoreFailure(evm.FailureReason)
+	}
 	// Fail if we're trying to execute above the call depth limit
 	if evm.depth > int(params.CallCreateDepth) {
 		evm.LogFailure(params.ErrorLogDepth)
@@ -478,6 +510,9 @@ func (c *codeAndHash) Hash() common.Hash {
 
 // create creates a new contract using code as deployment code.
 func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64, value *big.Int, address common.Address) ([]byte, common.Address, uint64, error) {
+	if evm.depth > 0 {
+		defer evm.restoreFailure(evm.FailureReason)
+	}
 	// Depth check execution. Fail if we're trying to execute above the
 	// limit.
 	if evm.depth > int(params.CallCreateDepth) {
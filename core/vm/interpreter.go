@@ -301,7 +301,9 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 		case err != nil:
 			return nil, err
 		case operation.reverts:
-			if len(res) == 0 {
+			if reason, ok := UnpackRevert(res); ok {
+				in.evm.LogFailure(reason)
+			} else {
 				in.evm.LogFailure(params.ErrorLogRevertUnknown)
 			}
 			return res, errExecutionReverted
@@ -536,3 +536,83 @@ func (api *PrivateDebugAPI) getModifiedAccounts(startBlock, endBlock *types.Bloc
 	}
 	return dirty, nil
 }
+
+// MRUChange is a change of the most recently used block number of an account,
+// made by a block.
+type MRUChange struct {
+	Block hexutil.Uint64 `json:"block"`
+	From  hexutil.Uint64 `json:"from"`
+	To    hexutil.Uint64 `json:"to"`
+}
+
+// MruHistoryMaxBlocks is the maximum number of blocks to be scanned per call
+const MruHistoryMaxBlocks = 4096
+
+// MruHistory returns the changes of the most recently used block number of an
+// account, which decides the parity of its transactions, made by the blocks in
+// the range specified. The states of all the blocks in the range are required,
+// and at most MruHistoryMaxBlocks blocks are scanned.
+//
+// With one parameter, returns the changes up to the current block.
+func (api *PrivateDebugAPI) MruHistory(address common.Address, startNum uint64, endNum *uint64) ([]MRUChange, error) {
+	end := api.eth.blockchain.CurrentBlock().NumberU64()
+	if endNum != nil {
+		end = *endNum
+	}
+	if startNum > end {
+		return nil, fmt.Errorf("start block height (%d) must not be greater than end block height (%d)", startNum, end)
+	}
+	if end-startNum >= MruHistoryMaxBlocks {
+		return nil, fmt.Errorf("block range (%d-%d) exceeds the maximum of %d blocks", startNum, end, MruHistoryMaxBlocks)
+	}
+	var (
+		triedb  = api.eth.BlockChain().StateCache().TrieDB()
+		changes = []MRUChange{}
+		prev    uint64
+	)
+	readMRU := func(number uint64) (uint64, error) {
+		header := api.eth.blockchain.GetHeaderByNumber(number)
+		if header == nil {
+			return 0, fmt.Errorf("block %d not found", number)
+		}
+		tr, err := trie.NewSecure(header.Root, triedb)
+		if err != nil {
+			return 0, mruStateError(number, err)
+		}
+		enc, err := tr.TryGet(address.Bytes())
+		if err != nil || len(enc) == 0 {
+			return 0, mruStateError(number, err)
+		}
+		var account state.Account
+		if err := rlp.DecodeBytes(enc, &account); err != nil {
+			return 0, err
+		}
+		return account.MRUNumber, nil
+	}
+	if startNum > 0 {
+		var err error
+		if prev, err = readMRU(startNum - 1); err != nil {
+			return nil, err
+		}
+	}
+	for number := startNum; number <= end; number++ {
+		mru, err := readMRU(number)
+		if err != nil {
+			return nil, err
+		}
+		if mru != prev {
+			changes = append(changes, MRUChange{Block: hexutil.Uint64(number), From: hexutil.Uint64(prev), To: hexutil.Uint64(mru)})
+			prev = mru
+		}
+	}
+	return changes, nil
+}
+
+// mruStateError reports the state of a block missing from the database, as on
+// non-archive nodes, with a clear error.
+func mruStateError(number uint64, err error) error {
+	if _, ok := err.(*trie.MissingNodeError); ok {
+		return fmt.Errorf("state of block %d not available", number)
+	}
+	return err
+}
@@ -57,9 +57,10 @@ const (
 // TraceConfig holds extra parameters to trace functions.
 type TraceConfig struct {
 	*vm.LogConfig
-	Tracer  *string
-	Timeout *string
-	Reexec  *uint64
+	Tracer         *string
+	Timeout        *string
+	Reexec         *uint64
+	Initialization bool // Prepend a frame for the block initialization to the block traces
 }
 
 // StdTraceConfig holds extra parameters to standard-json trace functions.
@@ -71,8 +72,9 @@ type StdTraceConfig struct {
 
 // txTraceResult is the result of a single transaction trace.
 type txTraceResult struct {
-	Result interface{} `json:"result,omitempty"` // Trace results produced by the tracer
-	Error  string      `json:"error,omitempty"`  // Trace failure produced by the tracer
+	Result    interface{} `json:"result,omitempty"`    // Trace results produced by the tracer
+	StateDiff state.Diff  `json:"stateDiff,omitempty"` // State changes of the block initialization
+	Error     string      `json:"error,omitempty"`     // Trace failure produced by the tracer
 }
 
 // blockTraceTask represents a single block trace task when an entire chain is
@@ -143,6 +145,13 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 	if !supported {
 		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
 	}
+	// Reject a malformed tracer upfront instead of failing every block with it
+	_, cancel, err := newTracer(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	cancel()
+
 	sub := notifier.CreateSubscription()
 
 	// Ensure we have a valid starting state before doing any work
@@ -202,9 +211,29 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 			// Fetch and execute the next block trace tasks
 			for task := range tasks {
 				signer := types.MakeSigner(api.eth.blockchain.Config(), task.block.Number())
+				txs := task.block.Transactions()
 
+				// Trace the block initialization, reporting it for its system transactions
+				sysTxs, res, err := api.traceInitialize(ctx, task.block, task.statedb, config)
+				if err != nil {
+					log.Warn("Tracing failed", "block", task.block.NumberU64(), "err", err)
+					for i := range txs {
+						task.results[i] = &txTraceResult{Error: err.Error()}
+					}
+					txs = nil
+				}
+				for i := range sysTxs {
+					task.results[i] = &txTraceResult{Result: res}
+				}
+				var frame *txTraceResult
+				if err == nil && config != nil && config.Initialization {
+					frame = api.initializationFrame(task.block, task.statedb, res)
+				}
 				// Trace all the transactions contained within
-				for i, tx := range task.block.Transactions() {
+				for i, tx := range txs {
+					if i < len(sysTxs) {
+						continue
+					}
 					msg, _ := tx.AsMessage(signer)
 					vmctx := core.NewEVMContext(msg, task.block.Header(), api.eth.blockchain, nil)
 
@@ -218,6 +247,9 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 					task.statedb.Finalise(api.eth.blockchain.Config().IsEIP158(task.block.Number()))
 					task.results[i] = &txTraceResult{Result: res}
 				}
+				if frame != nil {
+					task.results = append([]*txTraceResult{frame}, task.results...)
+				}
 				// Stream the result back to the user or abort on teardown
 				select {
 				case results <- task:
@@ -458,6 +490,15 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 	if err != nil {
 		return nil, err
 	}
+	// Run the block initialization, reporting its trace for the system transactions
+	sysTxs, sysResult, err := api.traceInitialize(ctx, block, statedb, config)
+	if err != nil {
+		return nil, err
+	}
+	var frame *txTraceResult
+	if config != nil && config.Initialization {
+		frame = api.initializationFrame(block, statedb, sysResult)
+	}
 	// Execute all the transaction contained within the block concurrently
 	var (
 		signer = types.MakeSigner(api.eth.blockchain.Config(), block.Number())
@@ -491,9 +532,15 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 			}
 		}()
 	}
+	for i := range sysTxs {
+		results[i] = &txTraceResult{Result: sysResult}
+	}
 	// Feed the transactions into the tracers and return
 	var failed error
 	for i, tx := range txs {
+		if i < len(sysTxs) {
+			continue
+		}
 		// Send the trace task over for execution
 		jobs <- &txTraceTask{statedb: statedb.Copy(), index: i}
 
@@ -517,9 +564,55 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 	if failed != nil {
 		return nil, failed
 	}
+	if frame != nil {
+		results = append([]*txTraceResult{frame}, results...)
+	}
 	return results, nil
 }
 
+// initializationFrame assembles the trace frame of a block initialization run on
+// top of the given state, holding the trace of the system transactions and the
+// state changes made by the consensus engine.
+func (api *PrivateDebugAPI) initializationFrame(block *types.Block, statedb *state.StateDB, result interface{}) *txTraceResult {
+	parent := api.eth.blockchain.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return &txTraceResult{Error: fmt.Sprintf("parent %#x not found", block.ParentHash())}
+	}
+	diff, err := statedb.DiffFrom(parent.Root)
+	if err != nil {
+		return &txTraceResult{Error: err.Error()}
+	}
+	return &txTraceResult{Result: result, StateDiff: diff}
+}
+
+// StateDiff returns the state changes made by the consensus engine on the block
+// initialization, before executing the transactions, such as the stablecoin
+// supply absorption.
+func (api *PrivateDebugAPI) StateDiff(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (state.Diff, error) {
+	block, err := api.eth.APIBackend.BlockByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, errors.New("block not found")
+	}
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis is not initialized")
+	}
+	parent := api.eth.blockchain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent %#x not found", block.ParentHash())
+	}
+	statedb, err := api.computeStateDB(parent, defaultTraceReexec)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := api.initializeBlock(block, statedb, vm.Config{}); err != nil {
+		return nil, err
+	}
+	return statedb.DiffFrom(parent.Root())
+}
+
 // standardTraceBlockToFile configures a new tracer which uses standard JSON output,
 // and traces either a full block or an individual transaction. The return value will
 // be one filename per transaction traced.
@@ -563,8 +656,44 @@ func (api *PrivateDebugAPI) standardTraceBlockToFile(ctx context.Context, block
 	var (
 		signer = types.MakeSigner(api.eth.blockchain.Config(), block.Number())
 		dumps  []string
+
+		txs    = block.Transactions()
+		sysTxs int
+	)
+	// Run the block initialization, traced as its first system transaction
+	traceInit := txHash == (common.Hash{})
+	for sysTxs < len(txs) && txs[sysTxs].IsSystem() {
+		if txs[sysTxs].Hash() == txHash {
+			traceInit = true
+		}
+		sysTxs++
+	}
+	var (
+		vmConf vm.Config
+		dump   *os.File
+		writer *bufio.Writer
 	)
-	for i, tx := range block.Transactions() {
+	if sysTxs > 0 && traceInit {
+		if dump, writer, vmConf, err = newStandardTraceFile(block, 0, txs[0], &logConfig); err != nil {
+			return nil, err
+		}
+		dumps = append(dumps, dump.Name())
+	}
+	_, _, err = api.initializeBlock(block, statedb, vmConf)
+	if writer != nil {
+		writer.Flush()
+	}
+	if dump != nil {
+		dump.Close()
+		log.Info("Wrote standard trace", "file", dump.Name())
+	}
+	if err != nil || (dump != nil && txHash != (common.Hash{})) {
+		return dumps, err
+	}
+	for i, tx := range txs {
+		if i < sysTxs {
+			continue
+		}
 		// Prepare the trasaction for un-traced execution
 		var (
 			msg, _ = tx.AsMessage(signer)
@@ -577,22 +706,10 @@ func (api *PrivateDebugAPI) standardTraceBlockToFile(ctx context.Context, block
 		)
 		// If the transaction needs tracing, swap out the configs
 		if tx.Hash() == txHash || txHash == (common.Hash{}) {
-			// Generate a unique temporary file to dump it into
-			prefix := fmt.Sprintf("block_%#x-%d-%#x-", block.Hash().Bytes()[:4], i, tx.Hash().Bytes()[:4])
-
-			dump, err = ioutil.TempFile(os.TempDir(), prefix)
-			if err != nil {
+			if dump, writer, vmConf, err = newStandardTraceFile(block, i, tx, &logConfig); err != nil {
 				return nil, err
 			}
 			dumps = append(dumps, dump.Name())
-
-			// Swap out the noop logger to the standard tracer
-			writer = bufio.NewWriter(dump)
-			vmConf = vm.Config{
-				Debug:                   true,
-				Tracer:                  vm.NewJSONLogger(&logConfig, writer),
-				EnablePreimageRecording: true,
-			}
 		}
 		// Execute the transaction and flush any traces to disk
 		vmenv := vm.NewEVM(vmctx, statedb, api.eth.blockchain.Config(), vmConf)
@@ -619,6 +736,24 @@ func (api *PrivateDebugAPI) standardTraceBlockToFile(ctx context.Context, block
 	return dumps, nil
 }
 
+// newStandardTraceFile generates a unique temporary file to dump the standard
+// trace of a transaction into, and the EVM configuration writing to it.
+func newStandardTraceFile(block *types.Block, index int, tx *types.Transaction, logConfig *vm.LogConfig) (*os.File, *bufio.Writer, vm.Config, error) {
+	prefix := fmt.Sprintf("block_%#x-%d-%#x-", block.Hash().Bytes()[:4], index, tx.Hash().Bytes()[:4])
+
+	dump, err := ioutil.TempFile(os.TempDir(), prefix)
+	if err != nil {
+		return nil, nil, vm.Config{}, err
+	}
+	// Swap out the noop logger to the standard tracer
+	writer := bufio.NewWriter(dump)
+	return dump, writer, vm.Config{
+		Debug:                   true,
+		Tracer:                  vm.NewJSONLogger(logConfig, writer),
+		EnablePreimageRecording: true,
+	}, nil
+}
+
 // containsTx reports whether the transaction with a certain hash
 // is contained within the specified block.
 func containsTx(block *types.Block, hash common.Hash) bool {
@@ -715,6 +850,15 @@ func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, hash common.Ha
 	if err != nil {
 		return nil, err
 	}
+	// System transactions are traced with the block initialization
+	if tx.IsSystem() {
+		block := api.eth.blockchain.GetBlockByHash(blockHash)
+		if block == nil {
+			return nil, fmt.Errorf("block %#x not found", blockHash)
+		}
+		_, res, err := api.traceInitialize(ctx, block, statedb, config)
+		return res, err
+	}
 	// Trace the transaction and return
 	return api.traceTx(ctx, msg, vmctx, statedb, config)
 }
@@ -723,56 +867,140 @@ func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, hash common.Ha
 // executes the given message in the provided environment. The return value will
 // be tracer dependent.
 func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, vmctx vm.Context, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
-	// Assemble the structured logger or the JavaScript tracer
+	tracer, cancel, err := newTracer(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	// Run the transaction with tracing enabled.
+	vmenv := vm.NewEVM(vmctx, statedb, api.eth.blockchain.Config(), vm.Config{Debug: true, Tracer: tracer})
+
+	ret, gas, failed, err := core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.Gas()))
+	if err != nil {
+		return nil, fmt.Errorf("tracing failed: %v", err)
+	}
+	var reason string
+	if failed {
+		reason = vmenv.Failure(ret)
+	}
+	return traceResult(tracer, gas, failed, ret, reason)
+}
+
+// traceInitialize configures a new tracer according to the provided configuration,
+// and runs the initialization of the block on top of its parent state, tracing
+// the system transactions executed by the consensus engine. The returned trace
+// is nil if no system transaction was executed.
+func (api *PrivateDebugAPI) traceInitialize(ctx context.Context, block *types.Block, statedb *state.StateDB, config *TraceConfig) (types.Transactions, interface{}, error) {
+	tracer, cancel, err := newTracer(ctx, config)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cancel()
+
+	txs, receipts, err := api.initializeBlock(block, statedb, vm.Config{Debug: true, Tracer: tracer})
+	if err != nil || len(txs) == 0 {
+		return nil, nil, err
+	}
+	var (
+		gas    uint64
+		failed bool
+		reason string
+	)
+	for _, receipt := range receipts {
+		gas += receipt.GasUsed
+		if receipt.Status == types.ReceiptStatusFailed {
+			failed, reason = true, receipt.FailureReason
+		}
+	}
+	res, err := traceResult(tracer, gas, failed, nil, reason)
+	if err != nil {
+		return nil, nil, err
+	}
+	return txs, res, nil
+}
+
+// initializeBlock runs the consensus engine initialization of the block on top
+// of its parent state, executing the system transactions with the given EVM
+// configuration. The system transactions are checked against the block ones.
+func (api *PrivateDebugAPI) initializeBlock(block *types.Block, statedb *state.StateDB, cfg vm.Config) (types.Transactions, types.Receipts, error) {
 	var (
-		tracer vm.Tracer
-		err    error
+		header   = block.Header()
+		txs      types.Transactions
+		receipts types.Receipts
+		err      error
 	)
+	if initializer, ok := api.eth.engine.(core.SystemInitializer); ok {
+		txs, receipts, err = initializer.InitializeWithConfig(api.eth.blockchain, header, statedb, cfg)
+	} else {
+		txs, receipts, err = api.eth.engine.Initialize(api.eth.blockchain, header, statedb)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("block initialization failed: %v", err)
+	}
+	if len(txs) > len(block.Transactions()) {
+		return nil, nil, fmt.Errorf("system transaction %#x not found in block", txs[len(block.Transactions())].Hash())
+	}
+	for i, tx := range txs {
+		if tx.Hash() != block.Transactions()[i].Hash() {
+			return nil, nil, fmt.Errorf("system transaction %#x mismatch: have %#x", tx.Hash(), block.Transactions()[i].Hash())
+		}
+	}
+	return txs, receipts, nil
+}
+
+// newTracer assembles the structured logger or the JavaScript tracer according
+// to the provided configuration. The returned function must be called to release
+// the tracer resources once the tracing is done.
+func newTracer(ctx context.Context, config *TraceConfig) (vm.Tracer, context.CancelFunc, error) {
 	switch {
 	case config != nil && config.Tracer != nil:
 		// Define a meaningful timeout of a single transaction trace
+		var err error
 		timeout := defaultTraceTimeout
 		if config.Timeout != nil {
 			if timeout, err = time.ParseDuration(*config.Timeout); err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		}
 		// Constuct the JavaScript tracer to execute with
-		if tracer, err = tracers.New(*config.Tracer); err != nil {
-			return nil, err
+		tracer, err := tracers.New(*config.Tracer)
+		if err != nil {
+			return nil, nil, err
 		}
 		// Handle timeouts and RPC cancellations
 		deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
 		go func() {
 			<-deadlineCtx.Done()
-			tracer.(*tracers.Tracer).Stop(errors.New("execution timeout"))
+			tracer.Stop(errors.New("execution timeout"))
 		}()
-		defer cancel()
+		return tracer, cancel, nil
 
 	case config == nil:
-		tracer = vm.NewStructLogger(nil)
+		return vm.NewStructLogger(nil), func() {}, nil
 
 	default:
-		tracer = vm.NewStructLogger(config.LogConfig)
+		return vm.NewStructLogger(config.LogConfig), func() {}, nil
 	}
-	// Run the transaction with tracing enabled.
-	vmenv := vm.NewEVM(vmctx, statedb, api.eth.blockchain.Config(), vm.Config{Debug: true, Tracer: tracer})
+}
 
-	ret, gas, failed, err := core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.Gas()))
-	if err != nil {
-		return nil, fmt.Errorf("tracing failed: %v", err)
-	}
+// traceResult formats the output of a tracer after the execution it traced.
+func traceResult(tracer vm.Tracer, gas uint64, failed bool, ret []byte, reason string) (interface{}, error) {
 	// Depending on the tracer type, format and return the output
 	switch tracer := tracer.(type) {
 	case *vm.StructLogger:
 		return &ethapi.ExecutionResult{
-			Gas:         gas,
-			Failed:      failed,
-			ReturnValue: fmt.Sprintf("%x", ret),
-			StructLogs:  ethapi.FormatLogs(tracer.StructLogs()),
+			Gas:           gas,
+			Failed:        failed,
+			FailureReason: reason,
+			ReturnValue:   fmt.Sprintf("%x", ret),
+			StructLogs:    ethapi.FormatLogs(tracer.StructLogs()),
 		}, nil
 
 	case *tracers.Tracer:
+		if failed {
+			tracer.CaptureFailure(reason)
+		}
 		return tracer.GetResult()
 
 	default:
@@ -799,11 +1027,22 @@ func (api *PrivateDebugAPI) computeTxEnv(blockHash common.Hash, txIndex int, ree
 	if txIndex == 0 && len(block.Transactions()) == 0 {
 		return nil, vm.Context{}, statedb, nil
 	}
-
+	// System transactions are executed by the block initialization, return its
+	// parent state for them.
+	if txIndex < len(block.Transactions()) && block.Transactions()[txIndex].IsSystem() {
+		return nil, vm.Context{}, statedb, nil
+	}
+	sysTxs, _, err := api.initializeBlock(block, statedb, vm.Config{})
+	if err != nil {
+		return nil, vm.Context{}, nil, err
+	}
 	// Recompute transactions up to the target index.
 	signer := types.MakeSigner(api.eth.blockchain.Config(), block.Number())
 
 	for idx, tx := range block.Transactions() {
+		if idx < len(sysTxs) {
+			continue
+		}
 		// Assemble the transaction call message and return if the requested offset
 		msg, _ := tx.AsMessage(signer)
 		context := core.NewEVMContext(msg, block.Header(), api.eth.blockchain, nil)
@@ -36,11 +36,13 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state/pruner"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/filters"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
+	"github.com/ethereum/go-ethereum/eth/snap"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
@@ -74,6 +76,7 @@ type Ethereum struct {
 	txPool          *core.TxPool
 	blockchain      *core.BlockChain
 	protocolManager *ProtocolManager
+	snapSyncer      *snap.Syncer
 	lesServer       LesServer
 
 	// DB interfaces
@@ -125,17 +128,51 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		log.Warn("Sanitizing invalid miner gas price", "provided", config.Miner.GasPrice, "updated", DefaultConfig.Miner.GasPrice)
 		config.Miner.GasPrice = new(big.Int).Set(DefaultConfig.Miner.GasPrice)
 	}
+	if config.Miner.LeaseInterval <= 0 {
+		log.Warn("Sanitizing invalid sealing lease interval", "provided", config.Miner.LeaseInterval, "updated", DefaultConfig.Miner.LeaseInterval)
+		config.Miner.LeaseInterval = DefaultConfig.Miner.LeaseInterval
+	}
 	if config.NoPruning && config.TrieDirtyCache > 0 {
 		config.TrieCleanCache += config.TrieDirtyCache
 		config.TrieDirtyCache = 0
 	}
 	log.Info("Allocated trie memory caches", "clean", common.StorageSize(config.TrieCleanCache)*1024*1024, "dirty", common.StorageSize(config.TrieDirtyCache)*1024*1024)
+	if config.SnapshotCache > 0 {
+		log.Info("Allocated state snapshot cache", "size", common.StorageSize(config.SnapshotCache)*1024*1024)
+	}
 
 	// Assemble the Ethereum object
 	chainDb, err := ctx.OpenDatabaseWithFreezer("chaindata", config.DatabaseCache, config.DatabaseHandles, config.DatabaseFreezer, "eth/db/chaindata/")
 	if err != nil {
 		return nil, err
 	}
+	// Drop the ancient chain data beyond the configured retention, including the
+	// reverse state diffs outside the state history window
+	retention := config.DatabaseRetention
+	if _, err := chainDb.Ancients(); err == nil {
+		if _, ok := retention[rawdb.FreezerStateDiffTable]; !ok {
+			retention = make(map[string]uint64)
+			for name, items := range config.DatabaseRetention {
+				retention[name] = items
+			}
+			retention[rawdb.FreezerStateDiffTable] = config.StateHistory
+		}
+	}
+	if len(retention) > 0 {
+		if err := rawdb.SetFreezerRetention(chainDb, retention); err != nil {
+			return nil, err
+		}
+	}
+	if len(config.DatabaseRetention) > 0 {
+		log.Info("Limited ancient chain data retention", "tables", config.DatabaseRetention)
+	}
+	if config.StateHistory > 0 {
+		log.Info("Keeping reverse state diffs", "blocks", config.StateHistory)
+	}
+	// Finish any state pruning interrupted while sweeping before touching the state
+	if err := pruner.RecoverPruning(ctx.ResolvePath(""), chainDb); err != nil {
+		return nil, err
+	}
 	chainConfig, genesisHash, genesisErr := core.SetupGenesisBlockWithOverride(chainDb, config.Genesis, config.OverrideIstanbul)
 	if _, ok := genesisErr.(*params.ConfigCompatError); genesisErr != nil && !ok {
 		return nil, genesisErr
@@ -156,6 +193,23 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		bloomIndexer:   NewBloomIndexer(chainDb, params.BloomBitsBlocks, params.BloomConfirms),
 	}
 
+	if engine, ok := eth.engine.(*dccs.Dccs); ok {
+		protection, err := dccs.NewSlashingProtection(ctx.ResolvePath(dccs.ProtectionFile))
+		if err != nil {
+			return nil, err
+		}
+		engine.SetProtection(protection)
+
+		// Contend for the sealing lease if running as part of a hot-standby pair
+		if config.Miner.LeaseFile != "" {
+			lease, err := dccs.NewFileLease(ctx.ResolvePath(config.Miner.LeaseFile), config.Miner.LeaseInterval)
+			if err != nil {
+				return nil, err
+			}
+			engine.SetLease(lease)
+		}
+	}
+
 	bcVersion := rawdb.ReadDatabaseVersion(chainDb)
 	var dbVer = "<nil>"
 	if bcVersion != nil {
@@ -183,6 +237,8 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 			TrieDirtyLimit:      config.TrieDirtyCache,
 			TrieDirtyDisabled:   config.NoPruning,
 			TrieTimeLimit:       config.TrieTimeout,
+			SnapshotLimit:       config.SnapshotCache,
+			StateHistory:        config.StateHistory,
 		}
 	)
 	eth.blockchain, err = core.NewBlockChain(chainDb, cacheConfig, chainConfig, eth.engine, vmConfig, eth.shouldPreserve, config.RollbackNumber)
@@ -212,6 +268,9 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 	if eth.protocolManager, err = NewProtocolManager(chainConfig, checkpoint, config.SyncMode, config.NetworkId, eth.eventMux, eth.txPool, eth.engine, eth.blockchain, chainDb, cacheLimit, config.Whitelist); err != nil {
 		return nil, err
 	}
+	eth.snapSyncer = snap.NewSyncer(chainDb)
+	eth.protocolManager.downloader.SetSnapSyncer(eth.snapSyncer)
+
 	eth.miner = miner.New(eth, &config.Miner, chainConfig, eth.EventMux(), eth.engine, eth.isLocalBlock)
 	eth.miner.SetExtra(makeExtraData(config.Miner.ExtraData))
 
@@ -530,6 +589,7 @@ func (s *Ethereum) Protocols() []p2p.Protocol {
 		protos[i] = s.protocolManager.makeProtocol(vsn)
 		protos[i].Attributes = []enr.Entry{s.currentEthEntry()}
 	}
+	protos = append(protos, snap.MakeProtocols(s.blockchain, s.snapSyncer)...)
 	if s.lesServer != nil {
 		protos = append(protos, s.lesServer.Protocols()...)
 	}
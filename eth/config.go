@@ -51,10 +51,11 @@ var DefaultConfig = Config{
 	TrieDirtyCache:     256,
 	TrieTimeout:        60 * time.Minute,
 	Miner: miner.Config{
-		GasFloor: 8000000,
-		GasCeil:  8000000,
-		GasPrice: big.NewInt(params.GWei),
-		Recommit: time.Second,
+		GasFloor:      8000000,
+		GasCeil:       8000000,
+		GasPrice:      big.NewInt(params.GWei),
+		Recommit:      time.Second,
+		LeaseInterval: 1500 * time.Millisecond,
 	},
 	TxPool: core.DefaultTxPoolConfig,
 	GPO: gasprice.Config{
@@ -117,10 +118,13 @@ type Config struct {
 	DatabaseHandles    int  `toml:"-"`
 	DatabaseCache      int
 	DatabaseFreezer    string
+	DatabaseRetention  map[string]uint64 `toml:",omitempty"` // Number of recent items kept in the ancient tables, the others are dropped
 
 	TrieCleanCache int
 	TrieDirtyCache int
 	TrieTimeout    time.Duration
+	SnapshotCache  int    // Megabytes of the state snapshot read cache, 0 disables the snapshot
+	StateHistory   uint64 `toml:",omitempty"` // Number of recent blocks whose state can be rebuilt from reverse diffs
 
 	// Mining options
 	Miner miner.Config
@@ -21,6 +21,7 @@ import (
 	"sync"
 
 	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -125,6 +126,21 @@ type SyncingResult struct {
 	Status  ethereum.SyncProgress `json:"status"`
 }
 
+// StateSyncProgress gives progress indications of the state download of a fast
+// sync. The percentage is estimated from the parts of the state trie completed.
+type StateSyncProgress struct {
+	Root       common.Hash `json:"root"`       // Root of the state being synced
+	Processed  uint64      `json:"processed"`  // Number of state entries downloaded
+	Pending    uint64      `json:"pending"`    // Number of state entries known to be pending
+	Percentage float64     `json:"percentage"` // Estimated percentage of the state synced
+}
+
+// StateSyncProgress returns the progress of the state download of the current
+// or last fast sync.
+func (api *PublicDownloaderAPI) StateSyncProgress() StateSyncProgress {
+	return api.d.StateProgress()
+}
+
 // uninstallSyncSubscriptionRequest uninstalles a syncing subscription in the API event loop.
 type uninstallSyncSubscriptionRequest struct {
 	c           chan interface{}
@@ -110,6 +110,7 @@ type Downloader struct {
 
 	stateDB    ethdb.Database  // Database to state sync into (and deduplicate via)
 	stateBloom *trie.SyncBloom // Bloom filter for fast trie node existence checks
+	snapSyncer SnapSyncer      // Range based state downloader to run before the trie sync
 
 	// Statistics
 	syncStatsChainOrigin uint64 // Origin block number where syncing started at
@@ -277,6 +278,20 @@ func (d *Downloader) Progress() ethereum.SyncProgress {
 	}
 }
 
+// StateProgress retrieves the progress of the state download of the current or
+// last fast sync, along with an estimate of its completion.
+func (d *Downloader) StateProgress() StateSyncProgress {
+	d.syncStatsLock.RLock()
+	defer d.syncStatsLock.RUnlock()
+
+	return StateSyncProgress{
+		Root:       d.syncStatsState.root,
+		Processed:  d.syncStatsState.processed,
+		Pending:    d.syncStatsState.pending,
+		Percentage: d.syncStatsState.completed * 100,
+	}
+}
+
 // Synchronising returns whether the downloader is currently retrieving blocks.
 func (d *Downloader) Synchronising() bool {
 	return atomic.LoadInt32(&d.synchronising) > 0
@@ -458,6 +473,18 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 			origin = 0
 		} else {
 			pivot = height - uint64(fsMinFullBlocks)
+
+			// Resume the state sync of an interrupted fast sync, unless its pivot
+			// became stale in the meantime
+			if journal := d.readStateSyncJournal(); journal != nil {
+				if journal.Number < pivot && height <= journal.Number+2*uint64(fsMinFullBlocks) {
+					log.Info("Resuming interrupted fast sync", "pivot", journal.Number, "root", journal.Progress.Root)
+					pivot = journal.Number
+				} else {
+					log.Info("Discarding stale state sync journal", "pivot", journal.Number, "height", height)
+					d.dropStateSyncJournal()
+				}
+			}
 			if pivot <= origin {
 				origin = pivot - 1
 			}
@@ -517,7 +544,7 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 		func() error { return d.processHeaders(origin+1, pivot, td) },
 	}
 	if d.mode == FastSync {
-		fetchers = append(fetchers, func() error { return d.processFastSyncContent(latest) })
+		fetchers = append(fetchers, func() error { return d.processFastSyncContent(latest, pivot) })
 	} else if d.mode == FullSync {
 		fetchers = append(fetchers, d.processFullSyncContent)
 	}
@@ -1575,10 +1602,15 @@ func (d *Downloader) importBlockResults(results []*fetchResult) error {
 
 // processFastSyncContent takes fetch results from the queue and writes them to the
 // database. It also controls the synchronisation of state nodes of the pivot block.
-func (d *Downloader) processFastSyncContent(latest *types.Header) error {
+func (d *Downloader) processFastSyncContent(latest *types.Header, pivot uint64) error {
 	// Start syncing state of the reported head block. This should get us most of
-	// the state of the pivot block.
-	sync := d.syncState(latest.Root)
+	// the state of the pivot block. If an interrupted sync of the pivot state is
+	// resumed, pick it up right away instead.
+	number, root := latest.Number.Uint64(), latest.Root
+	if journal := d.readStateSyncJournal(); journal != nil && journal.Number == pivot {
+		number, root = journal.Number, journal.Progress.Root
+	}
+	sync := d.syncState(number, root)
 	defer sync.Cancel()
 	closeOnErr := func(s *stateSync) {
 		if err := s.Wait(); err != nil && err != errCancelStateFetch && err != errCanceled {
@@ -1586,12 +1618,6 @@ func (d *Downloader) processFastSyncContent(latest *types.Header) error {
 		}
 	}
 	go closeOnErr(sync)
-	// Figure out the ideal pivot block. Note, that this goalpost may move if the
-	// sync takes long enough for the chain head to move significantly.
-	pivot := uint64(0)
-	if height := latest.Number.Uint64(); height > uint64(fsMinFullBlocks) {
-		pivot = height - uint64(fsMinFullBlocks)
-	}
 	// To cater for moving pivot points, track the pivot block and subsequently
 	// accumulated download results separately.
 	var (
@@ -1638,7 +1664,7 @@ func (d *Downloader) processFastSyncContent(latest *types.Header) error {
 			if oldPivot != P {
 				sync.Cancel()
 
-				sync = d.syncState(P.Header.Root)
+				sync = d.syncState(P.Header.Number.Uint64(), P.Header.Root)
 				defer sync.Cancel()
 				go closeOnErr(sync)
 				oldPivot = P
@@ -29,9 +29,11 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
 )
 
@@ -1656,3 +1658,82 @@ func testCheckpointEnforcement(t *testing.T, protocol int, mode SyncMode) {
 		assertOwnChain(t, tester, chain.len())
 	}
 }
+
+// Tests that a fast sync interrupted during its state download resumes from its
+// journal, keeping the pivot whose state it was syncing, and reports the state
+// download complete in the end.
+func TestFastSyncStateResume(t *testing.T) {
+	t.Parallel()
+
+	tester := newTester()
+	defer tester.terminate()
+
+	chain := testChainBase.shorten(blockCacheItems - 15)
+	tester.newPeer("peer", 64, chain)
+
+	// Sync part of the state of a block a bit older than the ideal pivot, and
+	// persist its journal as an interrupted sync would
+	number := uint64(chain.len()-1-fsMinFullBlocks) - 1
+	root := chain.headerm[chain.chain[number]].Root
+
+	sched := state.NewStateSync(root, tester.stateDb, trie.NewSyncBloom(1, nil))
+	hash := sched.Missing(1)[0]
+	blob, _ := tester.peerDb.Get(hash[:])
+	if _, _, err := sched.Process([]trie.SyncResult{{Hash: hash, Data: blob}}); err != nil {
+		t.Fatalf("failed to process state root: %v", err)
+	}
+	progress := sched.Progress()
+	if len(progress.Nodes) == 0 {
+		t.Fatalf("no pending nodes in progress")
+	}
+	journal, _ := rlp.EncodeToBytes(&stateSyncJournal{Number: number, Progress: progress})
+	rawdb.WriteStateSyncJournal(tester.stateDb, journal)
+
+	// Synchronise and check that the state of the journal was synced
+	if err := tester.sync("peer", nil, FastSync); err != nil {
+		t.Fatalf("failed to synchronise blocks: %v", err)
+	}
+	assertOwnChain(t, tester, chain.len())
+
+	if have := tester.downloader.StateProgress(); have.Root != root || have.Percentage != 100 {
+		t.Fatalf("state progress mismatch: have %x at %v%%, want %x at 100%%", have.Root, have.Percentage, root)
+	}
+	if _, err := trie.NewSecure(root, trie.NewDatabase(tester.stateDb)); err != nil {
+		t.Fatalf("pivot state missing: %v", err)
+	}
+	if blob := rawdb.ReadStateSyncJournal(tester.stateDb); len(blob) != 0 {
+		t.Fatalf("journal not deleted after state sync")
+	}
+}
+
+// Tests that the journal of a fast sync whose pivot became stale is discarded,
+// syncing the state of a fresh pivot instead.
+func TestFastSyncStaleJournal(t *testing.T) {
+	t.Parallel()
+
+	tester := newTester()
+	defer tester.terminate()
+
+	// Skip the bloom initialization as if the journal was to be resumed
+	tester.downloader.stateBloom = trie.NewSyncBloom(1, nil)
+
+	chain := testChainBase.shorten(blockCacheItems - 15)
+	tester.newPeer("peer", 64, chain)
+
+	stale := chain.headerm[chain.chain[1]].Root
+	journal, _ := rlp.EncodeToBytes(&stateSyncJournal{Number: 1, Progress: &trie.SyncProgress{Root: stale}})
+	rawdb.WriteStateSyncJournal(tester.stateDb, journal)
+
+	if err := tester.sync("peer", nil, FastSync); err != nil {
+		t.Fatalf("failed to synchronise blocks: %v", err)
+	}
+	assertOwnChain(t, tester, chain.len())
+
+	root := chain.headerm[chain.chain[chain.len()-1-fsMinFullBlocks]].Root
+	if have := tester.downloader.StateProgress(); have.Root != root {
+		t.Fatalf("state progress root mismatch: have %x, want %x", have.Root, root)
+	}
+	if blob := rawdb.ReadStateSyncJournal(tester.stateDb); len(blob) != 0 {
+		t.Fatalf("stale journal not deleted")
+	}
+}
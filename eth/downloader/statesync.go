@@ -27,10 +27,15 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
 	"golang.org/x/crypto/sha3"
 )
 
+// stateJournalInterval is the minimum time between two persists of the state
+// sync progress.
+var stateJournalInterval = time.Minute
+
 // stateReq represents a batch of state fetch requests grouped together into
 // a single data retrieval network packet.
 type stateReq struct {
@@ -51,16 +56,65 @@ func (req *stateReq) timedOut() bool {
 // stateSyncStats is a collection of progress stats to report during a state trie
 // sync to RPC requests as well as to display in user logs.
 type stateSyncStats struct {
-	processed  uint64 // Number of state entries processed
-	duplicate  uint64 // Number of state entries downloaded twice
-	unexpected uint64 // Number of non-requested state entries received
-	pending    uint64 // Number of still pending state entries
+	processed  uint64      // Number of state entries processed
+	duplicate  uint64      // Number of state entries downloaded twice
+	unexpected uint64      // Number of non-requested state entries received
+	pending    uint64      // Number of still pending state entries
+	root       common.Hash // Root of the state being synced
+	completed  float64     // Estimated fraction of the state synced
+}
+
+// stateSyncJournal is the progress of a state sync, persisted periodically to
+// resume it after a restart.
+type stateSyncJournal struct {
+	Number   uint64             // Number of the block whose state is synced
+	Progress *trie.SyncProgress // Progress of the state trie sync
 }
 
-// syncState starts downloading state with the given root hash.
-func (d *Downloader) syncState(root common.Hash) *stateSync {
+// readStateSyncJournal loads the progress of an interrupted state sync, if any.
+func (d *Downloader) readStateSyncJournal() *stateSyncJournal {
+	blob := rawdb.ReadStateSyncJournal(d.stateDB)
+	if len(blob) == 0 {
+		return nil
+	}
+	journal := new(stateSyncJournal)
+	if err := rlp.DecodeBytes(blob, journal); err != nil {
+		log.Warn("Failed to decode state sync journal", "err", err)
+		d.dropStateSyncJournal()
+		return nil
+	}
+	return journal
+}
+
+// dropStateSyncJournal deletes the progress of an interrupted state sync which
+// can't be resumed. The state bloom was not initialized from the database in
+// the hope of resuming it, so it is initialized now.
+func (d *Downloader) dropStateSyncJournal() {
+	rawdb.DeleteStateSyncJournal(d.stateDB)
+	if d.stateBloom != nil {
+		d.stateBloom.Load(d.stateDB)
+	}
+}
+
+// SnapSyncer downloads a state by ranges of leaves, which is much faster than
+// node by node but can't follow a state changing under it. It runs before the
+// trie sync, which then only heals the parts it didn't complete.
+type SnapSyncer interface {
+	// Sync downloads the state with the given root, adding the nodes written to
+	// the bloom filter, until it completes, fails or cancel is closed.
+	Sync(root common.Hash, bloom *trie.SyncBloom, cancel <-chan struct{}) error
+}
+
+// SetSnapSyncer sets the range based state downloader to use for fast sync. It
+// must be called before any sync starts.
+func (d *Downloader) SetSnapSyncer(syncer SnapSyncer) {
+	d.snapSyncer = syncer
+}
+
+// syncState starts downloading the state of the given block.
+func (d *Downloader) syncState(number uint64, root common.Hash) *stateSync {
 	// Create the state sync
-	s := newStateSync(d, root)
+	s := newStateSync(d, number, root)
 	select {
 	case d.stateSyncStart <- s:
 	case <-d.quitCh:
@@ -215,12 +269,15 @@ func (d *Downloader) runStateSync(s *stateSync) *stateSync {
 type stateSync struct {
 	d *Downloader // Downloader instance to access and manage current peerset
 
+	number uint64                     // Number of the block whose state is synced
+	root   common.Hash                // State root being synced
 	sched  *trie.Sync                 // State trie sync scheduler defining the tasks
 	keccak hash.Hash                  // Keccak256 hasher to verify deliveries with
 	tasks  map[common.Hash]*stateTask // Set of tasks currently queued for retrieval
 
 	numUncommitted   int
 	bytesUncommitted int
+	journalled       time.Time // Time the progress was last persisted
 
 	deliver    chan *stateReq // Delivery channel multiplexing peer responses
 	cancel     chan struct{}  // Channel to signal a termination request
@@ -237,15 +294,17 @@ type stateTask struct {
 
 // newStateSync creates a new state trie download scheduler. This method does not
 // yet start the sync. The user needs to call run to initiate.
-func newStateSync(d *Downloader, root common.Hash) *stateSync {
+func newStateSync(d *Downloader, number uint64, root common.Hash) *stateSync {
 	return &stateSync{
-		d:       d,
-		sched:   state.NewStateSync(root, d.stateDB, d.stateBloom),
-		keccak:  sha3.NewLegacyKeccak256(),
-		tasks:   make(map[common.Hash]*stateTask),
-		deliver: make(chan *stateReq),
-		cancel:  make(chan struct{}),
-		done:    make(chan struct{}),
+		d:          d,
+		number:     number,
+		root:       root,
+		keccak:     sha3.NewLegacyKeccak256(),
+		tasks:      make(map[common.Hash]*stateTask),
+		journalled: time.Now(),
+		deliver:    make(chan *stateReq),
+		cancel:     make(chan struct{}),
+		done:       make(chan struct{}),
 	}
 }
 
@@ -253,6 +312,41 @@ func newStateSync(d *Downloader, root common.Hash) *stateSync {
 // it finishes, and finally notifying any goroutines waiting for the loop to
 // finish.
 func (s *stateSync) run() {
+	// Resume the trie sync of an interrupted sync of the same state, otherwise
+	// download the bulk of the state by ranges first if possible
+	var progress *trie.SyncProgress
+	if journal := s.d.readStateSyncJournal(); journal != nil {
+		if journal.Progress.Root == s.root {
+			progress = journal.Progress
+		} else {
+			log.Info("Discarding state sync journal of another state", "root", journal.Progress.Root)
+			s.d.dropStateSyncJournal()
+		}
+	}
+	if progress == nil && s.d.snapSyncer != nil {
+		if err := s.d.snapSyncer.Sync(s.root, s.d.stateBloom, s.cancel); err != nil {
+			select {
+			case <-s.cancel:
+				s.err = errCancelStateFetch
+				close(s.done)
+				return
+			default:
+				log.Info("Snap state sync failed, falling back to trie sync", "root", s.root, "err", err)
+			}
+		}
+	}
+	// Schedule the trie sync, skipping whatever is already in the database
+	s.sched = state.NewStateSync(s.root, s.d.stateDB, s.d.stateBloom)
+	if progress != nil {
+		if err := s.sched.Restore(progress); err != nil {
+			log.Warn("Failed to resume state sync", "root", s.root, "err", err)
+			s.sched = state.NewStateSync(s.root, s.d.stateDB, s.d.stateBloom)
+		} else {
+			log.Info("Resumed state sync", "number", s.number, "root", s.root, "pending", s.sched.Pending(), "completed", fmt.Sprintf("%.2f%%", s.sched.Completed()*100))
+		}
+	}
+	s.updateStats(0, 0, 0, 0)
+
 	s.err = s.loop()
 	close(s.done)
 }
@@ -350,6 +444,17 @@ func (s *stateSync) commit(force bool) error {
 	if err := s.sched.Commit(b); err != nil {
 		return err
 	}
+	// Persist the progress along with the nodes, for a restart to pick it up
+	if s.sched.Pending() == 0 {
+		rawdb.DeleteStateSyncJournal(b)
+	} else if force || time.Since(s.journalled) > stateJournalInterval {
+		blob, err := rlp.EncodeToBytes(&stateSyncJournal{Number: s.number, Progress: s.sched.Progress()})
+		if err != nil {
+			return err
+		}
+		rawdb.WriteStateSyncJournal(b, blob)
+		s.journalled = time.Now()
+	}
 	if err := b.Write(); err != nil {
 		return fmt.Errorf("DB write error: %v", err)
 	}
@@ -483,12 +588,14 @@ func (s *stateSync) updateStats(written, duplicate, unexpected int, duration tim
 	defer s.d.syncStatsLock.Unlock()
 
 	s.d.syncStatsState.pending = uint64(s.sched.Pending())
+	s.d.syncStatsState.root = s.root
+	s.d.syncStatsState.completed = s.sched.Completed()
 	s.d.syncStatsState.processed += uint64(written)
 	s.d.syncStatsState.duplicate += uint64(duplicate)
 	s.d.syncStatsState.unexpected += uint64(unexpected)
 
 	if written > 0 || duplicate > 0 || unexpected > 0 {
-		log.Info("Imported new state entries", "count", written, "elapsed", common.PrettyDuration(duration), "processed", s.d.syncStatsState.processed, "pending", s.d.syncStatsState.pending, "retry", len(s.tasks), "duplicate", s.d.syncStatsState.duplicate, "unexpected", s.d.syncStatsState.unexpected)
+		log.Info("Imported new state entries", "count", written, "elapsed", common.PrettyDuration(duration), "processed", s.d.syncStatsState.processed, "pending", s.d.syncStatsState.pending, "completed", fmt.Sprintf("%.2f%%", s.d.syncStatsState.completed*100), "retry", len(s.tasks), "duplicate", s.d.syncStatsState.duplicate, "unexpected", s.d.syncStatsState.unexpected)
 	}
 	if written > 0 {
 		rawdb.WriteFastTrieProgress(s.d.stateDB, s.d.syncStatsState.processed)
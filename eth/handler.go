@@ -30,6 +30,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/forkid"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/fetcher"
@@ -150,7 +151,15 @@ func NewProtocolManager(config *params.ChainConfig, checkpoint *params.TrustedCh
 	// bloom when it's done.
 	var stateBloom *trie.SyncBloom
 	if atomic.LoadUint32(&manager.fastSync) == 1 {
-		stateBloom = trie.NewSyncBloom(uint64(cacheLimit), chaindb)
+		if len(rawdb.ReadStateSyncJournal(chaindb)) > 0 {
+			// An interrupted state sync resumes from its journal, checking few nodes
+			// against the database, so skip iterating all of it for the bloom. The
+			// downloader initializes the bloom if the journal turns out stale.
+			log.Info("Found state sync journal, deferring sync bloom initialization")
+			stateBloom = trie.NewSyncBloom(uint64(cacheLimit), nil)
+		} else {
+			stateBloom = trie.NewSyncBloom(uint64(cacheLimit), chaindb)
+		}
 	}
 	manager.downloader = downloader.New(manager.checkpointNumber, chaindb, stateBloom, manager.eventMux, blockchain, nil, manager.removePeer)
 
@@ -0,0 +1,327 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snap
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/light"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+const (
+	// softResponseLimit is the target maximum size of a response, whatever the
+	// peer asks for.
+	softResponseLimit = 2 * 1024 * 1024
+
+	// maxStorageLookups is the maximum number of account storages served in one
+	// response.
+	maxStorageLookups = 1024
+
+	// maxCodeLookups is the maximum number of codes served in one response.
+	maxCodeLookups = 1024
+)
+
+// Backend is the state provider the protocol serves ranges from.
+type Backend interface {
+	// StateCache returns the state database, recent states included.
+	StateCache() state.Database
+}
+
+// MakeProtocols constructs the p2p protocols of snap. The peers are registered
+// with the syncer, if any, for downloading state from them.
+func MakeProtocols(backend Backend, syncer *Syncer) []p2p.Protocol {
+	protos := make([]p2p.Protocol, len(ProtocolVersions))
+	for i, version := range ProtocolVersions {
+		version := version // Closure
+
+		protos[i] = p2p.Protocol{
+			Name:    protocolName,
+			Version: version,
+			Length:  protocolLengths[version],
+			Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+				return handle(backend, syncer, NewPeer(version, p, rw))
+			},
+		}
+	}
+	return protos
+}
+
+// handle is the callback invoked to manage the life cycle of a snap peer. When
+// this function terminates, the peer is disconnected.
+func handle(backend Backend, syncer *Syncer, peer *Peer) error {
+	peer.Log().Debug("Snap peer connected", "name", peer.Name())
+
+	if syncer != nil {
+		if err := syncer.Register(peer); err != nil {
+			peer.Log().Error("Snap peer registration failed", "err", err)
+			return err
+		}
+		defer syncer.Unregister(peer.ID())
+	}
+	for {
+		if err := handleMessage(backend, syncer, peer); err != nil {
+			peer.Log().Debug("Snap message handling failed", "err", err)
+			return err
+		}
+	}
+}
+
+// handleMessage is invoked whenever an inbound message is received from a remote
+// peer. The remote connection is torn down upon returning any error.
+func handleMessage(backend Backend, syncer *Syncer, peer *Peer) error {
+	msg, err := peer.rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	if msg.Size > protocolMaxMsgSize {
+		return errResp(errMsgTooLarge, "%v > %v", msg.Size, protocolMaxMsgSize)
+	}
+	defer msg.Discard()
+
+	switch msg.Code {
+	case GetAccountRangeMsg:
+		var req GetAccountRangePacket
+		if err := msg.Decode(&req); err != nil {
+			return errResp(errDecode, "msg %v: %v", msg, err)
+		}
+		return p2p.Send(peer.rw, AccountRangeMsg, ServiceGetAccountRange(backend, &req))
+
+	case AccountRangeMsg:
+		var res AccountRangePacket
+		if err := msg.Decode(&res); err != nil {
+			return errResp(errDecode, "msg %v: %v", msg, err)
+		}
+		if syncer == nil {
+			return nil
+		}
+		return syncer.OnAccounts(peer, &res)
+
+	case GetStorageRangesMsg:
+		var req GetStorageRangesPacket
+		if err := msg.Decode(&req); err != nil {
+			return errResp(errDecode, "msg %v: %v", msg, err)
+		}
+		return p2p.Send(peer.rw, StorageRangesMsg, ServiceGetStorageRanges(backend, &req))
+
+	case StorageRangesMsg:
+		var res StorageRangesPacket
+		if err := msg.Decode(&res); err != nil {
+			return errResp(errDecode, "msg %v: %v", msg, err)
+		}
+		if syncer == nil {
+			return nil
+		}
+		return syncer.OnStorage(peer, &res)
+
+	case GetByteCodesMsg:
+		var req GetByteCodesPacket
+		if err := msg.Decode(&req); err != nil {
+			return errResp(errDecode, "msg %v: %v", msg, err)
+		}
+		return p2p.Send(peer.rw, ByteCodesMsg, ServiceGetByteCodes(backend, &req))
+
+	case ByteCodesMsg:
+		var res ByteCodesPacket
+		if err := msg.Decode(&res); err != nil {
+			return errResp(errDecode, "msg %v: %v", msg, err)
+		}
+		if syncer == nil {
+			return nil
+		}
+		return syncer.OnByteCodes(peer, &res)
+
+	default:
+		return errResp(errInvalidMsgCode, "%v", msg.Code)
+	}
+}
+
+// responseLimit caps the size of a response to the soft limit.
+func responseLimit(requested uint64) uint64 {
+	if requested > softResponseLimit {
+		return softResponseLimit
+	}
+	return requested
+}
+
+// ServiceGetAccountRange assembles the response to an account range request.
+// States not available are answered with an empty response.
+func ServiceGetAccountRange(backend Backend, req *GetAccountRangePacket) *AccountRangePacket {
+	res := &AccountRangePacket{ID: req.ID}
+
+	tr, err := trie.New(req.Root, backend.StateCache().TrieDB())
+	if err != nil {
+		return res
+	}
+	var (
+		limit = responseLimit(req.Bytes)
+		size  uint64
+		last  []byte
+	)
+	it := trie.NewIterator(tr.NodeIterator(req.Origin[:]))
+	for size < limit && it.Next() {
+		last = it.Key
+		res.Accounts = append(res.Accounts, &AccountData{
+			Hash: common.BytesToHash(it.Key),
+			Body: common.CopyBytes(it.Value),
+		})
+		size += uint64(common.HashLength + len(it.Value))
+
+		if bytes.Compare(it.Key, req.Limit[:]) >= 0 {
+			break
+		}
+	}
+	if it.Err != nil {
+		return &AccountRangePacket{ID: req.ID}
+	}
+	// Prove the origin and the last account, which bounds the range
+	proof := light.NewNodeSet()
+	if err := tr.Prove(req.Origin[:], 0, proof); err != nil {
+		return &AccountRangePacket{ID: req.ID}
+	}
+	if last != nil {
+		if err := tr.Prove(last, 0, proof); err != nil {
+			return &AccountRangePacket{ID: req.ID}
+		}
+	}
+	for _, blob := range proof.NodeList() {
+		res.Proof = append(res.Proof, blob)
+	}
+	return res
+}
+
+// ServiceGetStorageRanges assembles the response to a storage ranges request.
+// States not available are answered with an empty response.
+func ServiceGetStorageRanges(backend Backend, req *GetStorageRangesPacket) *StorageRangesPacket {
+	res := &StorageRangesPacket{ID: req.ID}
+
+	triedb := backend.StateCache().TrieDB()
+	accTrie, err := trie.New(req.Root, triedb)
+	if err != nil {
+		return res
+	}
+	var (
+		limit = responseLimit(req.Bytes)
+		size  uint64
+	)
+	for i, account := range req.Accounts {
+		if size >= limit || i >= maxStorageLookups {
+			break
+		}
+		// Open the storage trie of the account
+		blob, err := accTrie.TryGet(account[:])
+		if err != nil || len(blob) == 0 {
+			return &StorageRangesPacket{ID: req.ID}
+		}
+		var acc state.Account
+		if err := rlp.DecodeBytes(blob, &acc); err != nil {
+			return &StorageRangesPacket{ID: req.ID}
+		}
+		stTrie, err := trie.New(acc.Root, triedb)
+		if err != nil {
+			return &StorageRangesPacket{ID: req.ID}
+		}
+		// Gather the slots of the account within the range
+		var origin, stop []byte
+		if i == 0 {
+			origin = req.Origin
+		}
+		if i == len(req.Accounts)-1 {
+			stop = req.Limit
+		}
+		var (
+			slots     []*StorageData
+			last      []byte
+			truncated bool
+		)
+		it := trie.NewIterator(stTrie.NodeIterator(origin))
+		for it.Next() {
+			if size >= limit {
+				truncated = true
+				break
+			}
+			last = it.Key
+			slots = append(slots, &StorageData{
+				Hash: common.BytesToHash(it.Key),
+				Body: common.CopyBytes(it.Value),
+			})
+			size += uint64(common.HashLength + len(it.Value))
+
+			if len(stop) > 0 && bytes.Compare(it.Key, stop) >= 0 {
+				truncated = true
+				break
+			}
+		}
+		if it.Err != nil {
+			return &StorageRangesPacket{ID: req.ID}
+		}
+		// Leave the account to a later request if the limit hit before any slot
+		if truncated && len(slots) == 0 {
+			break
+		}
+		res.Slots = append(res.Slots, slots)
+
+		// A partial range must be proven, and ends the response
+		if truncated || len(origin) > 0 {
+			proof := light.NewNodeSet()
+			if len(origin) == 0 {
+				origin = common.Hash{}.Bytes()
+			}
+			if err := stTrie.Prove(origin, 0, proof); err != nil {
+				return &StorageRangesPacket{ID: req.ID}
+			}
+			if last != nil {
+				if err := stTrie.Prove(last, 0, proof); err != nil {
+					return &StorageRangesPacket{ID: req.ID}
+				}
+			}
+			for _, blob := range proof.NodeList() {
+				res.Proof = append(res.Proof, blob)
+			}
+			break
+		}
+	}
+	return res
+}
+
+// ServiceGetByteCodes assembles the response to a contract codes request, with
+// the codes in the order they were requested. The response stops at the first
+// code not found, so every code stays at the position of its hash.
+func ServiceGetByteCodes(backend Backend, req *GetByteCodesPacket) *ByteCodesPacket {
+	res := &ByteCodesPacket{ID: req.ID}
+
+	var (
+		limit = responseLimit(req.Bytes)
+		size  uint64
+	)
+	for i, hash := range req.Hashes {
+		if size >= limit || i >= maxCodeLookups {
+			break
+		}
+		code, err := backend.StateCache().ContractCode(common.Hash{}, hash)
+		if err != nil || len(code) == 0 {
+			break
+		}
+		res.Codes = append(res.Codes, code)
+		size += uint64(len(code))
+	}
+	return res
+}
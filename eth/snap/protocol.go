@@ -0,0 +1,131 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package snap implements the snap protocol, an eth sub-protocol serving the
+// state as contiguous ranges of accounts and storage slots along with the merkle
+// proofs of their edges, which lets a state be downloaded leaf by leaf instead
+// of node by node.
+package snap
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Constants to match up protocol versions and messages
+const (
+	snap1 = 1
+)
+
+// protocolName is the official short name of the protocol used during capability negotiation.
+const protocolName = "snap"
+
+// ProtocolVersions are the supported versions of the snap protocol (first is primary).
+var ProtocolVersions = []uint{snap1}
+
+// protocolLengths are the number of implemented message corresponding to different protocol versions.
+var protocolLengths = map[uint]uint64{snap1: 6}
+
+const protocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
+
+// snap protocol message codes
+const (
+	GetAccountRangeMsg  = 0x00
+	AccountRangeMsg     = 0x01
+	GetStorageRangesMsg = 0x02
+	StorageRangesMsg    = 0x03
+	GetByteCodesMsg     = 0x04
+	ByteCodesMsg        = 0x05
+)
+
+var (
+	errMsgTooLarge    = errors.New("message too long")
+	errDecode         = errors.New("invalid message")
+	errInvalidMsgCode = errors.New("invalid message code")
+)
+
+func errResp(err error, format string, v ...interface{}) error {
+	return fmt.Errorf("%v - %v", err, fmt.Sprintf(format, v...))
+}
+
+// GetAccountRangePacket requests the accounts of a state from origin on, up to
+// the first one at or past limit, within a soft limit of bytes.
+type GetAccountRangePacket struct {
+	ID     uint64      // Request ID to match up responses with
+	Root   common.Hash // Root of the account trie to serve
+	Origin common.Hash // Hash of the first account to retrieve
+	Limit  common.Hash // Hash of the account to stop after
+	Bytes  uint64      // Soft limit at which to stop returning data
+}
+
+// AccountRangePacket is the response to GetAccountRangePacket, proven by the
+// paths of the origin and of the last account returned.
+type AccountRangePacket struct {
+	ID       uint64         // ID of the request this is a response for
+	Accounts []*AccountData // Accounts in the requested range, ordered by hash
+	Proof    [][]byte       // Trie nodes proving the edges of the range
+}
+
+// AccountData is an account of an account range.
+type AccountData struct {
+	Hash common.Hash  // Hash of the account address
+	Body rlp.RawValue // Consensus encoding of the account
+}
+
+// GetStorageRangesPacket requests the storage slots of several accounts of a
+// state. The origin and limit only apply to the first and last accounts, so a
+// large storage trie is requested alone, range after range.
+type GetStorageRangesPacket struct {
+	ID       uint64        // Request ID to match up responses with
+	Root     common.Hash   // Root of the account trie holding the accounts
+	Accounts []common.Hash // Hashes of the accounts whose storage to retrieve
+	Origin   []byte        // Hash of the first slot of the first account, empty for the start
+	Limit    []byte        // Hash of the slot of the last account to stop after, empty for the end
+	Bytes    uint64        // Soft limit at which to stop returning data
+}
+
+// StorageRangesPacket is the response to GetStorageRangesPacket. The slots of
+// all but the last account served are complete. Those of the last one are
+// proven by the paths of the origin and the last slot if they start past the
+// origin or stop short of the end.
+type StorageRangesPacket struct {
+	ID    uint64           // ID of the request this is a response for
+	Slots [][]*StorageData // Slots of the served accounts, in the requested order
+	Proof [][]byte         // Trie nodes proving the edges of the last range
+}
+
+// StorageData is a slot of a storage range.
+type StorageData struct {
+	Hash common.Hash // Hash of the slot key
+	Body []byte      // RLP encoded value of the slot
+}
+
+// GetByteCodesPacket requests contract codes by hash.
+type GetByteCodesPacket struct {
+	ID     uint64        // Request ID to match up responses with
+	Hashes []common.Hash // Hashes of the codes to retrieve
+	Bytes  uint64        // Soft limit at which to stop returning data
+}
+
+// ByteCodesPacket is the response to GetByteCodesPacket, holding the codes in
+// the requested order up to the first one not found.
+type ByteCodesPacket struct {
+	ID    uint64   // ID of the request this is a response for
+	Codes [][]byte // Codes requested, up to the first one not found
+}
@@ -0,0 +1,845 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snap
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/light"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var (
+	// emptyRoot is the known root hash of an empty trie.
+	emptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+
+	// emptyCode is the known hash of the empty EVM bytecode.
+	emptyCode = crypto.Keccak256Hash(nil)
+)
+
+const (
+	// maxRequestSize is the soft limit of the data requested at once from a peer.
+	maxRequestSize = 512 * 1024
+
+	// maxStorageBatch is the maximum number of accounts whose storage is
+	// requested at once.
+	maxStorageBatch = 64
+
+	// maxCodeBatch is the maximum number of contract codes requested at once.
+	maxCodeBatch = 128
+
+	// accountChunks is the number of chunks the account hash space is split
+	// into, to be downloaded concurrently. There is one chunk per first nibble
+	// of the account hashes, so that every chunk is a subtrie of the state root.
+	accountChunks = 16
+)
+
+var (
+	// requestTimeout is the time allowance for a peer to answer a request.
+	requestTimeout = 10 * time.Second
+
+	// commitInterval is the number of leaves inserted into a trie after which
+	// it is committed to the trie database, bounding the memory it takes.
+	commitInterval = 65536
+)
+
+var (
+	errNoPeers   = errors.New("no peers serving the state")
+	errCancelled = errors.New("sync cancelled")
+)
+
+// accountTask is a chunk of the account hash space to download.
+type accountTask struct {
+	next      common.Hash // Next account to download
+	last      common.Hash // Last account of the chunk
+	req       *request    // Request in flight, nil if none
+	done      bool        // Whether all the accounts of the chunk are in
+	pend      int         // Number of storages and codes of the chunk still missing
+	persisted bool        // Whether the account subtrie of the chunk was written
+}
+
+// storageTask is the storage of an account to download.
+type storageTask struct {
+	chunk   *accountTask // Account chunk waiting for the storage
+	account common.Hash  // Hash of the account owning the storage
+	root    common.Hash  // Storage root of the account
+	next    common.Hash  // Next slot to download
+	builder *trieBuilder // Storage trie rebuilt so far, nil if not started
+	req     *request     // Request in flight, nil if none
+}
+
+// request is a request in flight to a peer.
+type request struct {
+	id   uint64
+	peer string
+
+	account *accountTask   // Account chunk requested
+	storage []*storageTask // Storages requested
+	codes   []common.Hash  // Contract codes requested
+
+	timer  *time.Timer
+	cancel chan struct{} // Closed when the sync stops, to drop late responses
+}
+
+// response is a response delivered for a request.
+type response struct {
+	req    *request
+	packet interface{} // *AccountRangePacket, *StorageRangesPacket or *ByteCodesPacket
+}
+
+// Syncer downloads a state by ranges of accounts and storage slots from snap
+// peers, checking each range against the state root with the proofs of its
+// edges, and writes the tries rebuilt from the leaves. It doesn't heal states
+// changing under it; the node by node trie sync is meant to run after it,
+// fetching whatever is still missing.
+//
+// The trie sync skips any node already in the database along with everything
+// below it, so only complete subtries are ever written: storage tries once
+// their root is verified, and the account subtrie of a chunk once all of its
+// accounts, storages and codes are in. Whatever else was downloaded is dropped
+// if the sync is interrupted.
+type Syncer struct {
+	db ethdb.KeyValueStore // Database to write the state into
+
+	peers   map[string]*Peer    // Snap peers to download from
+	reqs    map[uint64]*request // Requests in flight
+	nextID  uint64              // ID of the next request
+	update  chan struct{}       // Notification of peers joining
+	deliver chan *response      // Responses delivered by the peers
+	timeout chan *request       // Requests timed out
+	lock    sync.Mutex          // Protects the peers and the requests
+
+	syncLock sync.Mutex // Prevents concurrent syncs
+}
+
+// NewSyncer creates a state syncer writing into the given database.
+func NewSyncer(db ethdb.KeyValueStore) *Syncer {
+	return &Syncer{
+		db:      db,
+		peers:   make(map[string]*Peer),
+		reqs:    make(map[uint64]*request),
+		update:  make(chan struct{}, 1),
+		deliver: make(chan *response),
+		timeout: make(chan *request),
+	}
+}
+
+// Register adds a peer to download state from.
+func (s *Syncer) Register(peer *Peer) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.peers[peer.ID()]; ok {
+		return errors.New("already registered")
+	}
+	s.peers[peer.ID()] = peer
+
+	select {
+	case s.update <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Unregister removes a peer, its requests in flight are timed out.
+func (s *Syncer) Unregister(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.peers[id]; !ok {
+		return errors.New("not registered")
+	}
+	delete(s.peers, id)
+
+	for _, req := range s.reqs {
+		if req.peer == id {
+			req.timer.Reset(0)
+		}
+	}
+	return nil
+}
+
+// OnAccounts delivers an account range from a peer.
+func (s *Syncer) OnAccounts(peer *Peer, res *AccountRangePacket) error {
+	return s.onResponse(peer, res.ID, res)
+}
+
+// OnStorage delivers storage ranges from a peer.
+func (s *Syncer) OnStorage(peer *Peer, res *StorageRangesPacket) error {
+	return s.onResponse(peer, res.ID, res)
+}
+
+// OnByteCodes delivers contract codes from a peer.
+func (s *Syncer) OnByteCodes(peer *Peer, res *ByteCodesPacket) error {
+	return s.onResponse(peer, res.ID, res)
+}
+
+// onResponse hands a response over to the sync, unless it is unsolicited or
+// comes too late, in which case it is dropped.
+func (s *Syncer) onResponse(peer *Peer, id uint64, packet interface{}) error {
+	s.lock.Lock()
+	req := s.reqs[id]
+	if req == nil || req.peer != peer.ID() {
+		s.lock.Unlock()
+		peer.Log().Debug("Dropping unrequested snap response", "reqid", id)
+		return nil
+	}
+	delete(s.reqs, id)
+	req.timer.Stop()
+	s.lock.Unlock()
+
+	select {
+	case s.deliver <- &response{req: req, packet: packet}:
+	case <-req.cancel:
+	}
+	return nil
+}
+
+// Sync downloads the state with the given root, until it completes, the peers
+// can't provide it anymore, or cancel is closed. The nodes written to disk are
+// added to the bloom filter if any, so that the trie sync can tell they exist.
+func (s *Syncer) Sync(root common.Hash, bloom *trie.SyncBloom, cancel <-chan struct{}) error {
+	s.syncLock.Lock()
+	defer s.syncLock.Unlock()
+
+	if root == emptyRoot {
+		return nil
+	}
+	run := newSyncRun(s, root, bloom)
+	defer run.close()
+
+	return run.loop(cancel)
+}
+
+// syncRun is the state of a sync in progress.
+type syncRun struct {
+	s      *Syncer
+	root   common.Hash
+	db     ethdb.KeyValueStore
+	triedb *trie.Database
+
+	accountTasks []*accountTask
+	storageTasks []*storageTask
+	codeTasks    map[common.Hash]struct{}       // Codes waiting to be requested
+	codeOwners   map[common.Hash][]*accountTask // Account chunks waiting for each code
+	accounts     *trieBuilder                   // Account trie rebuilt so far
+
+	idle      map[string]bool // Peers without requests in flight
+	stateless map[string]bool // Peers not serving the state
+	pending   int             // Number of requests in flight
+	cancel    chan struct{}   // Closed when the sync stops
+
+	accountsSynced, slotsSynced, codesSynced uint64
+	start, logged                            time.Time
+}
+
+func newSyncRun(s *Syncer, root common.Hash, bloom *trie.SyncBloom) *syncRun {
+	db := s.db
+	if bloom != nil {
+		db = &bloomDB{KeyValueStore: s.db, bloom: bloom}
+	}
+	triedb := trie.NewDatabase(db)
+	run := &syncRun{
+		s:          s,
+		root:       root,
+		db:         db,
+		triedb:     triedb,
+		codeTasks:  make(map[common.Hash]struct{}),
+		codeOwners: make(map[common.Hash][]*accountTask),
+		accounts:   newTrieBuilder(triedb),
+		idle:       make(map[string]bool),
+		stateless:  make(map[string]bool),
+		cancel:     make(chan struct{}),
+		start:      time.Now(),
+		logged:     time.Now(),
+	}
+	// Split the account hash space into chunks to download concurrently
+	step := new(big.Int).Div(new(big.Int).Lsh(common.Big1, 256), big.NewInt(accountChunks))
+	for i := 0; i < accountChunks; i++ {
+		next := new(big.Int).Mul(step, big.NewInt(int64(i)))
+		last := new(big.Int).Sub(new(big.Int).Add(next, step), common.Big1)
+		run.accountTasks = append(run.accountTasks, &accountTask{
+			next: common.BigToHash(next),
+			last: common.BigToHash(last),
+		})
+	}
+	return run
+}
+
+// close drops the requests in flight. The tries not complete yet are only held
+// in memory, so they are dropped too.
+func (r *syncRun) close() {
+	close(r.cancel)
+
+	r.s.lock.Lock()
+	for id, req := range r.s.reqs {
+		req.timer.Stop()
+		delete(r.s.reqs, id)
+	}
+	r.s.lock.Unlock()
+}
+
+// loop assigns requests to the idle peers and processes the responses, until
+// the state is complete.
+func (r *syncRun) loop(cancel <-chan struct{}) error {
+	log.Info("Starting snap state sync", "root", r.root)
+	for !r.complete() {
+		if !r.assign() && r.pending == 0 {
+			return errNoPeers
+		}
+		select {
+		case <-cancel:
+			return errCancelled
+
+		case <-r.s.update:
+			// New peers joined, assign them requests
+
+		case res := <-r.s.deliver:
+			r.pending--
+			r.idle[res.req.peer] = true
+			if err := r.process(res); err != nil {
+				log.Debug("Invalid snap response", "peer", res.req.peer, "err", err)
+				r.stateless[res.req.peer] = true
+				r.revert(res.req)
+			}
+			if err := r.persist(); err != nil {
+				return err
+			}
+			r.report(false)
+
+		case req := <-r.s.timeout:
+			r.pending--
+			r.idle[req.peer] = true
+			r.stateless[req.peer] = true
+			r.revert(req)
+		}
+	}
+	// All the leaves are in, the account trie must match the state root
+	root, err := r.accounts.commit()
+	if err != nil {
+		return err
+	}
+	if root != r.root {
+		return fmt.Errorf("synced state root mismatch: have %x, want %x", root, r.root)
+	}
+	if err := r.triedb.Commit(root, false); err != nil {
+		return err
+	}
+	r.report(true)
+	return nil
+}
+
+// complete returns whether the whole state was downloaded.
+func (r *syncRun) complete() bool {
+	for _, task := range r.accountTasks {
+		if !task.done {
+			return false
+		}
+	}
+	return len(r.storageTasks) == 0 && len(r.codeTasks) == 0 && r.pending == 0
+}
+
+// assign sends requests to the idle peers, code requests first, then storage
+// and accounts, so that the queues stay short. It returns whether any peer is
+// able to serve the state.
+func (r *syncRun) assign() bool {
+	r.s.lock.Lock()
+	defer r.s.lock.Unlock()
+
+	usable := false
+	for id, peer := range r.s.peers {
+		if r.stateless[id] {
+			continue
+		}
+		usable = true
+		if idle, known := r.idle[id]; known && !idle {
+			continue
+		}
+		r.idle[id] = true
+
+		req := &request{id: r.s.nextID, peer: id, cancel: r.cancel}
+		var err error
+		switch {
+		case len(r.unrequestedCodes()) > 0:
+			req.codes = r.unrequestedCodes()
+			if len(req.codes) > maxCodeBatch {
+				req.codes = req.codes[:maxCodeBatch]
+			}
+			for _, hash := range req.codes {
+				delete(r.codeTasks, hash)
+			}
+			err = peer.RequestByteCodes(req.id, req.codes, maxRequestSize)
+
+		case r.nextStorage() != nil:
+			req.storage = r.nextStorage()
+			var (
+				accounts = make([]common.Hash, len(req.storage))
+				origin   []byte
+			)
+			for i, task := range req.storage {
+				accounts[i], task.req = task.account, req
+			}
+			if task := req.storage[0]; task.builder != nil {
+				origin = task.next[:]
+			}
+			err = peer.RequestStorageRanges(req.id, r.root, accounts, origin, nil, maxRequestSize)
+
+		case r.nextAccounts() != nil:
+			task := r.nextAccounts()
+			req.account, task.req = task, req
+			err = peer.RequestAccountRange(req.id, r.root, task.next, task.last, maxRequestSize)
+
+		default:
+			continue
+		}
+		if err != nil {
+			r.stateless[id] = true
+			r.revert(req)
+			continue
+		}
+		r.s.nextID++
+		r.s.reqs[req.id] = req
+		r.idle[id] = false
+		r.pending++
+
+		req.timer = time.AfterFunc(requestTimeout, func() {
+			r.s.lock.Lock()
+			_, pending := r.s.reqs[req.id]
+			delete(r.s.reqs, req.id)
+			r.s.lock.Unlock()
+
+			if pending {
+				select {
+				case r.s.timeout <- req:
+				case <-req.cancel:
+				}
+			}
+		})
+	}
+	return usable
+}
+
+// unrequestedCodes returns the contract codes waiting to be requested.
+func (r *syncRun) unrequestedCodes() []common.Hash {
+	codes := make([]common.Hash, 0, len(r.codeTasks))
+	for hash := range r.codeTasks {
+		codes = append(codes, hash)
+	}
+	return codes
+}
+
+// nextStorage returns the next storages to request: a started storage alone, or
+// a batch of storages not started yet.
+func (r *syncRun) nextStorage() []*storageTask {
+	var batch []*storageTask
+	for _, task := range r.storageTasks {
+		if task.req != nil {
+			continue
+		}
+		if task.builder != nil {
+			if len(batch) == 0 {
+				return []*storageTask{task}
+			}
+			continue
+		}
+		if batch = append(batch, task); len(batch) == maxStorageBatch {
+			break
+		}
+	}
+	return batch
+}
+
+// nextAccounts returns the next account chunk to request.
+func (r *syncRun) nextAccounts() *accountTask {
+	for _, task := range r.accountTasks {
+		if !task.done && task.req == nil {
+			return task
+		}
+	}
+	return nil
+}
+
+// revert puts the tasks of a failed request back in the queues.
+func (r *syncRun) revert(req *request) {
+	if req.account != nil {
+		req.account.req = nil
+	}
+	for _, task := range req.storage {
+		task.req = nil
+	}
+	for _, hash := range req.codes {
+		r.codeTasks[hash] = struct{}{}
+	}
+}
+
+// process verifies and stores a response, requeuing what it lacks.
+func (r *syncRun) process(res *response) error {
+	switch packet := res.packet.(type) {
+	case *AccountRangePacket:
+		return r.processAccounts(res.req, packet)
+	case *StorageRangesPacket:
+		return r.processStorage(res.req, packet)
+	case *ByteCodesPacket:
+		return r.processCodes(res.req, packet)
+	}
+	return fmt.Errorf("unexpected response %T", res.packet)
+}
+
+// proofDB makes up a proof database from the trie nodes of a response.
+func proofDB(nodes [][]byte) ethdb.KeyValueReader {
+	list := make(light.NodeList, len(nodes))
+	for i, node := range nodes {
+		list[i] = node
+	}
+	return list.NodeSet()
+}
+
+func (r *syncRun) processAccounts(req *request, res *AccountRangePacket) error {
+	task := req.account
+	task.req = nil
+
+	keys, vals := make([][]byte, len(res.Accounts)), make([][]byte, len(res.Accounts))
+	for i, account := range res.Accounts {
+		keys[i], vals[i] = account.Hash[:], account.Body
+	}
+	// An empty response without proof means the peer doesn't have the state
+	if len(keys) == 0 && len(res.Proof) == 0 {
+		return errors.New("state not available")
+	}
+	var last []byte
+	if len(keys) > 0 {
+		last = keys[len(keys)-1]
+	}
+	more, err := trie.VerifyRangeProof(r.root, task.next[:], last, keys, vals, proofDB(res.Proof))
+	if err != nil {
+		return err
+	}
+	// Rebuild the account trie with the accounts of the chunk, and queue their
+	// storage and code for download
+	for i, key := range keys {
+		if bytes.Compare(key, task.last[:]) > 0 {
+			more = false
+			break
+		}
+		var account state.Account
+		if err := rlp.DecodeBytes(vals[i], &account); err != nil {
+			return err
+		}
+		if err := r.accounts.update(key, vals[i]); err != nil {
+			return err
+		}
+		if account.Root != emptyRoot {
+			if has, _ := r.db.Has(account.Root[:]); !has {
+				r.storageTasks = append(r.storageTasks, &storageTask{
+					chunk:   task,
+					account: common.BytesToHash(key),
+					root:    account.Root,
+				})
+				task.pend++
+			}
+		}
+		if code := common.BytesToHash(account.CodeHash); code != emptyCode {
+			if owners, pending := r.codeOwners[code]; pending {
+				r.codeOwners[code] = append(owners, task)
+				task.pend++
+			} else if has, _ := r.db.Has(code[:]); !has {
+				r.codeTasks[code] = struct{}{}
+				r.codeOwners[code] = []*accountTask{task}
+				task.pend++
+			}
+		}
+		r.accountsSynced++
+	}
+	if !more || bytes.Compare(last, task.last[:]) >= 0 {
+		task.done = true
+	} else {
+		task.next = incHash(common.BytesToHash(last))
+	}
+	return nil
+}
+
+func (r *syncRun) processStorage(req *request, res *StorageRangesPacket) error {
+	for _, task := range req.storage {
+		task.req = nil
+	}
+	if len(res.Slots) == 0 {
+		return errors.New("state not available")
+	}
+	if len(res.Slots) > len(req.storage) {
+		return fmt.Errorf("too many storage ranges: have %d, want at most %d", len(res.Slots), len(req.storage))
+	}
+	// Drop the completed storages from the queue, even if a later range fails
+	done := make(map[*storageTask]bool)
+	defer func() {
+		tasks := r.storageTasks[:0]
+		for _, task := range r.storageTasks {
+			if !done[task] {
+				tasks = append(tasks, task)
+			}
+		}
+		r.storageTasks = tasks
+	}()
+	for i, slots := range res.Slots {
+		task := req.storage[i]
+
+		keys, vals := make([][]byte, len(slots)), make([][]byte, len(slots))
+		for j, slot := range slots {
+			keys[j], vals[j] = slot.Hash[:], slot.Body
+		}
+		// Only the last range may be partial, and comes with a proof then
+		var (
+			more bool
+			err  error
+		)
+		if i < len(res.Slots)-1 || len(res.Proof) == 0 {
+			if task.builder != nil {
+				return errors.New("missing storage proof")
+			}
+			_, err = trie.VerifyRangeProof(task.root, nil, nil, keys, vals, nil)
+		} else {
+			var last []byte
+			if len(keys) > 0 {
+				last = keys[len(keys)-1]
+			}
+			more, err = trie.VerifyRangeProof(task.root, task.next[:], last, keys, vals, proofDB(res.Proof))
+		}
+		if err != nil {
+			return err
+		}
+		if task.builder == nil {
+			task.builder = newTrieBuilder(r.triedb)
+		}
+		for j, key := range keys {
+			if err := task.builder.update(key, vals[j]); err != nil {
+				return err
+			}
+		}
+		r.slotsSynced += uint64(len(keys))
+
+		if more {
+			task.next = incHash(common.BytesToHash(keys[len(keys)-1]))
+			continue
+		}
+		root, err := task.builder.commit()
+		if err != nil {
+			return err
+		}
+		if root != task.root {
+			return fmt.Errorf("storage root mismatch for account %x: have %x, want %x", task.account, root, task.root)
+		}
+		if err := r.triedb.Commit(root, false); err != nil {
+			return err
+		}
+		done[task] = true
+		task.chunk.pend--
+	}
+	return nil
+}
+
+func (r *syncRun) processCodes(req *request, res *ByteCodesPacket) error {
+	if len(res.Codes) == 0 {
+		return errors.New("codes not available")
+	}
+	requested := make(map[common.Hash]bool, len(req.codes))
+	for _, hash := range req.codes {
+		requested[hash] = true
+	}
+	batch := r.db.NewBatch()
+	for _, code := range res.Codes {
+		hash := crypto.Keccak256Hash(code)
+		if !requested[hash] {
+			continue
+		}
+		delete(requested, hash)
+		batch.Put(hash[:], code)
+		r.codesSynced++
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	for _, hash := range req.codes {
+		if !requested[hash] {
+			for _, owner := range r.codeOwners[hash] {
+				owner.pend--
+			}
+			delete(r.codeOwners, hash)
+		}
+	}
+	// Requeue the codes the peer didn't deliver
+	for hash := range requested {
+		r.codeTasks[hash] = struct{}{}
+	}
+	return nil
+}
+
+// persist writes the account subtries of the chunks whose accounts, storages and
+// codes are all in. The subtrie of a chunk hangs off the state root at the first
+// nibble of its accounts, which is only the case once the root branches out.
+func (r *syncRun) persist() error {
+	ready := false
+	for _, task := range r.accountTasks {
+		if task.done && task.pend == 0 && !task.persisted {
+			ready = true
+		}
+	}
+	if !ready {
+		return nil
+	}
+	if _, err := r.accounts.commit(); err != nil {
+		return err
+	}
+	var subtries []common.Hash
+	it := r.accounts.trie.NodeIterator(nil)
+	for it.Next(len(it.Path()) == 0) {
+		path := it.Path()
+		if len(path) != 1 || it.Hash() == (common.Hash{}) {
+			continue
+		}
+		task := r.accountTasks[path[0]]
+		if task.done && task.pend == 0 && !task.persisted {
+			subtries = append(subtries, it.Hash())
+			task.persisted = true
+		}
+	}
+	if it.Error() != nil {
+		return it.Error()
+	}
+	for _, hash := range subtries {
+		if err := r.triedb.Commit(hash, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// report logs the progress of the sync from time to time, or at once if forced.
+func (r *syncRun) report(force bool) {
+	if !force && time.Since(r.logged) < 8*time.Second {
+		return
+	}
+	r.logged = time.Now()
+
+	done := 0
+	for _, task := range r.accountTasks {
+		if task.done {
+			done++
+		}
+	}
+	log.Info("Snap state sync in progress", "root", r.root, "chunks", fmt.Sprintf("%d/%d", done, accountChunks),
+		"accounts", r.accountsSynced, "slots", r.slotsSynced, "codes", r.codesSynced,
+		"elapsed", common.PrettyDuration(time.Since(r.start)))
+}
+
+// incHash returns the hash following the given one.
+func incHash(h common.Hash) common.Hash {
+	for i := len(h) - 1; i >= 0; i-- {
+		h[i]++
+		if h[i] != 0 {
+			break
+		}
+	}
+	return h
+}
+
+// trieBuilder rebuilds a trie from its leaves, inserted in any order, committing
+// it to the trie database from time to time so that memory use stays bounded.
+type trieBuilder struct {
+	db      *trie.Database
+	trie    *trie.Trie
+	pending int // Leaves inserted since the last commit
+}
+
+func newTrieBuilder(db *trie.Database) *trieBuilder {
+	tr, _ := trie.New(common.Hash{}, db)
+	return &trieBuilder{db: db, trie: tr}
+}
+
+// update inserts a leaf into the trie.
+func (b *trieBuilder) update(key, value []byte) error {
+	if err := b.trie.TryUpdate(key, value); err != nil {
+		return err
+	}
+	if b.pending++; b.pending >= commitInterval {
+		_, err := b.commit()
+		return err
+	}
+	return nil
+}
+
+// commit writes the trie into the trie database, and reopens it so that the
+// committed nodes are released from memory.
+func (b *trieBuilder) commit() (common.Hash, error) {
+	root, err := b.trie.Commit(nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if b.trie, err = trie.New(root, b.db); err != nil {
+		return common.Hash{}, err
+	}
+	b.pending = 0
+	return root, nil
+}
+
+// bloomDB adds the keys of everything written in batches, which is how the trie
+// database writes nodes, to a sync bloom.
+type bloomDB struct {
+	ethdb.KeyValueStore
+	bloom *trie.SyncBloom
+}
+
+// NewBatch creates a batch adding its keys to the bloom as they are written.
+func (db *bloomDB) NewBatch() ethdb.Batch {
+	return &bloomBatch{Batch: db.KeyValueStore.NewBatch(), bloom: db.bloom}
+}
+
+type bloomBatch struct {
+	ethdb.Batch
+	bloom *trie.SyncBloom
+	keys  [][]byte
+}
+
+func (b *bloomBatch) Put(key []byte, value []byte) error {
+	b.keys = append(b.keys, common.CopyBytes(key))
+	return b.Batch.Put(key, value)
+}
+
+func (b *bloomBatch) Write() error {
+	if err := b.Batch.Write(); err != nil {
+		return err
+	}
+	for _, key := range b.keys {
+		b.bloom.Add(key)
+	}
+	b.keys = b.keys[:0]
+	return nil
+}
+
+func (b *bloomBatch) Reset() {
+	b.Batch.Reset()
+	b.keys = b.keys[:0]
+}
@@ -0,0 +1,351 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snap
+
+import (
+	"bytes"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// testBackend serves the states of a database.
+type testBackend struct {
+	db state.Database
+}
+
+func (b *testBackend) StateCache() state.Database { return b.db }
+
+// cancelBackend serves the states of a database, and cancels a sync once it
+// served a number of requests.
+type cancelBackend struct {
+	testBackend
+	served int
+	limit  int
+	cancel chan struct{}
+	lock   sync.Mutex
+}
+
+func (b *cancelBackend) StateCache() state.Database {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.served++; b.served == b.limit {
+		close(b.cancel)
+	}
+	return b.db
+}
+
+// makeTestState creates a state with plain accounts, contracts and a contract
+// with a storage too large for a single response.
+func makeTestState(t *testing.T) (ethdb.Database, common.Hash) {
+	db := rawdb.NewMemoryDatabase()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+
+	for i := 0; i < 1000; i++ {
+		addr := common.BigToAddress(big.NewInt(int64(i)))
+		statedb.AddBalance(addr, big.NewInt(int64(i+1)))
+		statedb.SetNonce(addr, uint64(i))
+
+		if i%10 == 0 {
+			statedb.SetCode(addr, []byte{byte(i), byte(i >> 8), 0x60, 0x00})
+			for j := 0; j < i%30+1; j++ {
+				statedb.SetState(addr, common.BigToHash(big.NewInt(int64(j))), common.BigToHash(big.NewInt(int64(i+j+1))))
+			}
+		}
+	}
+	large := common.HexToAddress("0x1000")
+	statedb.SetCode(large, []byte{0x60, 0x01})
+	for j := 0; j < 20000; j++ {
+		statedb.SetState(large, crypto.Keccak256Hash(big.NewInt(int64(j)).Bytes()), common.BigToHash(big.NewInt(int64(j+1))))
+	}
+	root, err := statedb.Commit(false)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	if err := statedb.Database().TrieDB().Commit(root, false); err != nil {
+		t.Fatalf("failed to flush state: %v", err)
+	}
+	return db, root
+}
+
+// connectPeer links a syncer to a peer serving the given backend.
+func connectPeer(syncer *Syncer, backend Backend, id byte) func() {
+	local, remote := p2p.MsgPipe()
+
+	server := NewPeer(snap1, p2p.NewPeer(enode.ID{id}, "server", nil), remote)
+	client := NewPeer(snap1, p2p.NewPeer(enode.ID{id}, "client", nil), local)
+
+	go handle(backend, nil, server)
+	go handle(&testBackend{db: state.NewDatabase(rawdb.NewMemoryDatabase())}, syncer, client)
+
+	return func() {
+		local.Close()
+		remote.Close()
+	}
+}
+
+// checkState verifies that a state is complete in the database.
+func checkState(t *testing.T, db ethdb.Database, src ethdb.Database, root common.Hash) {
+	srcState, _ := state.New(root, state.NewDatabase(src))
+	dstState, err := state.New(root, state.NewDatabase(db))
+	if err != nil {
+		t.Fatalf("failed to open synced state: %v", err)
+	}
+	for i := 0; i <= 1000; i++ {
+		addr := common.BigToAddress(big.NewInt(int64(i)))
+		if i == 1000 {
+			addr = common.HexToAddress("0x1000")
+		}
+		if have, want := dstState.GetBalance(addr), srcState.GetBalance(addr); have.Cmp(want) != 0 {
+			t.Fatalf("account %x balance mismatch: have %v, want %v", addr, have, want)
+		}
+		if have, want := dstState.GetCode(addr), srcState.GetCode(addr); !bytes.Equal(have, want) {
+			t.Fatalf("account %x code mismatch: have %x, want %x", addr, have, want)
+		}
+		if err := dstState.Error(); err != nil {
+			t.Fatalf("account %x: %v", addr, err)
+		}
+	}
+	// Iterate over every node of the synced state, which fails on a missing one
+	nodes := 0
+	it := state.NewNodeIterator(dstState)
+	for it.Next() {
+		nodes++
+	}
+	if it.Error != nil {
+		t.Fatalf("synced state incomplete after %d nodes: %v", nodes, it.Error)
+	}
+}
+
+func TestSync(t *testing.T) {
+	src, root := makeTestState(t)
+	backend := &testBackend{db: state.NewDatabase(src)}
+
+	db := rawdb.NewMemoryDatabase()
+	syncer := NewSyncer(db)
+	defer connectPeer(syncer, backend, 1)()
+	defer connectPeer(syncer, backend, 2)()
+
+	waitPeers(t, syncer, 2)
+	if err := syncer.Sync(root, nil, nil); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	checkState(t, db, src, root)
+}
+
+// Tests that the sync gets around peers not having the state.
+func TestSyncStatelessPeer(t *testing.T) {
+	src, root := makeTestState(t)
+	backend := &testBackend{db: state.NewDatabase(src)}
+	empty := &testBackend{db: state.NewDatabase(rawdb.NewMemoryDatabase())}
+
+	db := rawdb.NewMemoryDatabase()
+	syncer := NewSyncer(db)
+	defer connectPeer(syncer, empty, 1)()
+	defer connectPeer(syncer, backend, 2)()
+
+	waitPeers(t, syncer, 2)
+	if err := syncer.Sync(root, nil, nil); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	checkState(t, db, src, root)
+}
+
+// Tests that the sync fails when no peer has the state.
+func TestSyncNoPeers(t *testing.T) {
+	_, root := makeTestState(t)
+	empty := &testBackend{db: state.NewDatabase(rawdb.NewMemoryDatabase())}
+
+	syncer := NewSyncer(rawdb.NewMemoryDatabase())
+	if err := syncer.Sync(root, nil, nil); err != errNoPeers {
+		t.Fatalf("sync error mismatch: have %v, want %v", err, errNoPeers)
+	}
+	defer connectPeer(syncer, empty, 1)()
+
+	waitPeers(t, syncer, 1)
+	if err := syncer.Sync(root, nil, nil); err != errNoPeers {
+		t.Fatalf("sync error mismatch: have %v, want %v", err, errNoPeers)
+	}
+}
+
+// Tests that the nodes written by the sync are added to the bloom filter.
+func TestSyncBloom(t *testing.T) {
+	src, root := makeTestState(t)
+	backend := &testBackend{db: state.NewDatabase(src)}
+
+	db := rawdb.NewMemoryDatabase()
+	bloom := trie.NewSyncBloom(1, db)
+	defer bloom.Close()
+
+	syncer := NewSyncer(db)
+	defer connectPeer(syncer, backend, 1)()
+
+	waitPeers(t, syncer, 1)
+	if err := syncer.Sync(root, bloom, nil); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if !bloom.Contains(root[:]) {
+		t.Fatalf("state root missing from the bloom")
+	}
+}
+
+// Tests that a sync cancelled midway leaves no partial trie behind, so that the
+// trie sync heals the state into a complete one.
+func TestSyncCancelHeal(t *testing.T) {
+	// Commit the tries often, so that partial ones are around when cancelling
+	defer func(interval int) { commitInterval = interval }(commitInterval)
+	commitInterval = 16
+
+	src, root := makeTestState(t)
+	backend := &cancelBackend{
+		testBackend: testBackend{db: state.NewDatabase(src)},
+		limit:       8,
+		cancel:      make(chan struct{}),
+	}
+	db := rawdb.NewMemoryDatabase()
+	syncer := NewSyncer(db)
+	defer connectPeer(syncer, backend, 1)()
+
+	waitPeers(t, syncer, 1)
+	if err := syncer.Sync(root, nil, backend.cancel); err != errCancelled {
+		t.Fatalf("sync error mismatch: have %v, want %v", err, errCancelled)
+	}
+	// Heal the state node by node, skipping whatever the sync wrote
+	bloom := trie.NewSyncBloom(1, db)
+	defer bloom.Close()
+
+	sched := state.NewStateSync(root, db, bloom)
+	for sched.Pending() > 0 {
+		var results []trie.SyncResult
+		for _, hash := range sched.Missing(256) {
+			data, err := src.Get(hash[:])
+			if err != nil {
+				t.Fatalf("failed to retrieve node %x: %v", hash, err)
+			}
+			results = append(results, trie.SyncResult{Hash: hash, Data: data})
+		}
+		if _, _, err := sched.Process(results); err != nil {
+			t.Fatalf("failed to process heal results: %v", err)
+		}
+		batch := db.NewBatch()
+		if err := sched.Commit(batch); err != nil {
+			t.Fatalf("failed to commit heal results: %v", err)
+		}
+		if err := batch.Write(); err != nil {
+			t.Fatalf("failed to write heal results: %v", err)
+		}
+	}
+	checkState(t, db, src, root)
+}
+
+// waitPeers waits until the syncer has the given number of peers.
+func waitPeers(t *testing.T, syncer *Syncer, n int) {
+	for i := 0; i < 100; i++ {
+		syncer.lock.Lock()
+		have := len(syncer.peers)
+		syncer.lock.Unlock()
+
+		if have == n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("peers not registered")
+}
+
+// Tests that served account ranges verify against the state root, and that a
+// range with a gap doesn't.
+func TestServiceAccountRange(t *testing.T) {
+	src, root := makeTestState(t)
+	backend := &testBackend{db: state.NewDatabase(src)}
+
+	res := ServiceGetAccountRange(backend, &GetAccountRangePacket{
+		Root:  root,
+		Limit: common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"),
+		Bytes: 4096,
+	})
+	if len(res.Accounts) == 0 || len(res.Proof) == 0 {
+		t.Fatalf("empty response: %d accounts, %d proof nodes", len(res.Accounts), len(res.Proof))
+	}
+	keys, vals := make([][]byte, len(res.Accounts)), make([][]byte, len(res.Accounts))
+	for i, account := range res.Accounts {
+		keys[i], vals[i] = account.Hash[:], account.Body
+	}
+	more, err := trie.VerifyRangeProof(root, common.Hash{}.Bytes(), keys[len(keys)-1], keys, vals, proofDB(res.Proof))
+	if err != nil {
+		t.Fatalf("failed to verify range: %v", err)
+	}
+	if !more {
+		t.Fatalf("truncated range reported complete")
+	}
+	// Drop an account in the middle
+	keys = append(keys[:1:1], keys[2:]...)
+	vals = append(vals[:1:1], vals[2:]...)
+	if _, err := trie.VerifyRangeProof(root, common.Hash{}.Bytes(), keys[len(keys)-1], keys, vals, proofDB(res.Proof)); err == nil {
+		t.Fatalf("gapped range verified")
+	}
+	// Ranges of an unknown state are empty
+	res = ServiceGetAccountRange(backend, &GetAccountRangePacket{Root: common.HexToHash("0x01"), Bytes: 4096})
+	if len(res.Accounts) != 0 || len(res.Proof) != 0 {
+		t.Fatalf("unknown state served: %d accounts, %d proof nodes", len(res.Accounts), len(res.Proof))
+	}
+}
+
+// Tests that served codes stop at the first one not found, keeping every code
+// at the position of its hash.
+func TestServiceByteCodes(t *testing.T) {
+	src, _ := makeTestState(t)
+	backend := &testBackend{db: state.NewDatabase(src)}
+
+	codes := [][]byte{{0x60, 0x01}, {0x00, 0x00, 0x60, 0x00}, {0x0a, 0x00, 0x60, 0x00}}
+	res := ServiceGetByteCodes(backend, &GetByteCodesPacket{
+		Hashes: []common.Hash{crypto.Keccak256Hash(codes[0]), crypto.Keccak256Hash(codes[1]), common.HexToHash("0x01"), crypto.Keccak256Hash(codes[2])},
+		Bytes:  4096,
+	})
+	if len(res.Codes) != 2 {
+		t.Fatalf("served codes mismatch: have %d, want 2", len(res.Codes))
+	}
+	for i, code := range res.Codes {
+		if !bytes.Equal(code, codes[i]) {
+			t.Errorf("code %d mismatch: have %x, want %x", i, code, codes[i])
+		}
+	}
+}
+
+func TestIncHash(t *testing.T) {
+	tests := []struct{ in, out common.Hash }{
+		{common.Hash{}, common.HexToHash("0x01")},
+		{common.HexToHash("0xff"), common.HexToHash("0x0100")},
+		{common.HexToHash("0x01ffff"), common.HexToHash("0x020000")},
+	}
+	for i, tt := range tests {
+		if have := incHash(tt.in); have != tt.out {
+			t.Errorf("test %d: have %x, want %x", i, have, tt.out)
+		}
+	}
+}
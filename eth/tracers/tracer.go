@@ -597,6 +597,12 @@ func (jst *Tracer) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, er
 	return nil
 }
 
+// CaptureFailure records the reason of a failed execution, exposed to the
+// Javascript 'result' function as ctx.failureReason.
+func (jst *Tracer) CaptureFailure(reason string) {
+	jst.ctx["failureReason"] = reason
+}
+
 // GetResult calls the Javascript 'result' function and returns its value, or any accumulated error
 func (jst *Tracer) GetResult() (json.RawMessage, error) {
 	// Transform the context into a JavaScript object and inject into the state
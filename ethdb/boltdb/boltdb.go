@@ -0,0 +1,456 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// +build !js
+
+// Package boltdb implements the key-value database layer based on bbolt, a pure
+// Go B+tree store. Contrary to LevelDB, it never compacts in the background, so
+// writes are not stalled by compactions.
+package boltdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// dataFile is the name of the bbolt file within the database directory.
+	dataFile = "bolt.db"
+
+	// iteratorChunk is the number of items an iterator reads within a single
+	// transaction. Transactions are kept short, since the database can't grow
+	// while reads are pending, which would deadlock iterations writing to the
+	// database as they go.
+	iteratorChunk = 1024
+
+	// metricsGatheringInterval specifies the interval to retrieve the bbolt
+	// transaction stats to report to the user.
+	metricsGatheringInterval = 3 * time.Second
+)
+
+var (
+	// bucket is the single bucket holding all the key-value pairs.
+	bucket = []byte("ethdb")
+
+	// errNotFound is returned if a key is requested that is not found in the
+	// provided database.
+	errNotFound = errors.New("not found")
+)
+
+// Database is a persistent key-value store. Apart from basic data storage
+// functionality it also supports batch writes and iterating over the keyspace in
+// binary-alphabetical order.
+type Database struct {
+	fn string   // filename for reporting
+	db *bolt.DB // bbolt instance
+
+	diskSizeGauge  metrics.Gauge // Gauge for tracking the size of the database file
+	diskWriteMeter metrics.Meter // Meter for measuring the effective amount of data written
+	writeTimeMeter metrics.Meter // Meter for measuring the time spent writing to disk
+
+	quitLock sync.Mutex      // Mutex protecting the quit channel access
+	quitChan chan chan error // Quit channel to stop the metrics collection before closing the database
+
+	log log.Logger // Contextual logger tracking the database path
+}
+
+// New returns a wrapped bbolt object stored within the given directory. The
+// namespace is the prefix that the metrics reporting should use for surfacing
+// internal stats.
+//
+// Every write transaction is synced to disk before returning, so a crash of the
+// operating system never leaves a torn database behind.
+func New(file string, namespace string) (*Database, error) {
+	if err := os.MkdirAll(file, 0700); err != nil {
+		return nil, err
+	}
+	logger := log.New("database", file)
+
+	db, err := bolt.Open(filepath.Join(file, dataFile), 0600, &bolt.Options{
+		Timeout:      time.Second,
+		FreelistType: bolt.FreelistMapType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+	logger.Info("Opened bbolt database")
+
+	// Assemble the wrapper with all the registered metrics
+	bdb := &Database{
+		fn:       file,
+		db:       db,
+		log:      logger,
+		quitChan: make(chan chan error),
+	}
+	bdb.diskSizeGauge = metrics.NewRegisteredGauge(namespace+"disk/size", nil)
+	bdb.diskWriteMeter = metrics.NewRegisteredMeter(namespace+"disk/write", nil)
+	bdb.writeTimeMeter = metrics.NewRegisteredMeter(namespace+"disk/writetime", nil)
+
+	// Start up the metrics gathering and return
+	go bdb.meter(metricsGatheringInterval)
+	return bdb, nil
+}
+
+// Close stops the metrics collection, flushes any pending data to disk and closes
+// all io accesses to the underlying key-value store.
+func (db *Database) Close() error {
+	db.quitLock.Lock()
+	defer db.quitLock.Unlock()
+
+	if db.quitChan != nil {
+		errc := make(chan error)
+		db.quitChan <- errc
+		if err := <-errc; err != nil {
+			db.log.Error("Metrics collection failed", "err", err)
+		}
+		db.quitChan = nil
+	}
+	return db.db.Close()
+}
+
+// Has retrieves if a key is present in the key-value store.
+func (db *Database) Has(key []byte) (bool, error) {
+	var has bool
+	err := db.db.View(func(tx *bolt.Tx) error {
+		has = tx.Bucket(bucket).Get(key) != nil
+		return nil
+	})
+	return has, err
+}
+
+// Get retrieves the given key if it's present in the key-value store.
+func (db *Database) Get(key []byte) ([]byte, error) {
+	var dat []byte
+	err := db.db.View(func(tx *bolt.Tx) error {
+		// Values are only valid during the transaction, copy them out
+		if v := tx.Bucket(bucket).Get(key); v != nil {
+			dat = append([]byte{}, v...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if dat == nil {
+		return nil, errNotFound
+	}
+	return dat, nil
+}
+
+// Put inserts the given value into the key-value store.
+func (db *Database) Put(key []byte, value []byte) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put(key, nonNil(value))
+	})
+}
+
+// Delete removes the key from the key-value store.
+func (db *Database) Delete(key []byte) error {
+	return db.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Delete(key)
+	})
+}
+
+// NewBatch creates a write-only key-value store that buffers changes to its host
+// database until a final write is called.
+func (db *Database) NewBatch() ethdb.Batch {
+	return &batch{
+		db: db.db,
+	}
+}
+
+// NewIterator creates a binary-alphabetical iterator over the entire keyspace
+// contained within the bbolt database.
+func (db *Database) NewIterator() ethdb.Iterator {
+	return db.newIterator(nil, nil)
+}
+
+// NewIteratorWithStart creates a binary-alphabetical iterator over a subset of
+// database content starting at a particular initial key (or after, if it does
+// not exist).
+func (db *Database) NewIteratorWithStart(start []byte) ethdb.Iterator {
+	return db.newIterator(start, nil)
+}
+
+// NewIteratorWithPrefix creates a binary-alphabetical iterator over a subset
+// of database content with a particular key prefix.
+func (db *Database) NewIteratorWithPrefix(prefix []byte) ethdb.Iterator {
+	return db.newIterator(prefix, prefix)
+}
+
+// newIterator creates an iterator starting at the given key, limited to the keys
+// with the given prefix.
+func (db *Database) newIterator(start []byte, prefix []byte) *iterator {
+	return &iterator{
+		db:     db.db,
+		next:   common.CopyBytes(start),
+		prefix: common.CopyBytes(prefix),
+		pos:    -1,
+	}
+}
+
+// Stat returns a particular internal stat of the database.
+func (db *Database) Stat(property string) (string, error) {
+	stats := db.db.Stats()
+	return fmt.Sprintf("Pages(free):%d Pages(pending):%d Tx(read):%d Tx(open):%d Pages(written):%d Write(time):%v",
+		stats.FreePageN, stats.PendingPageN, stats.TxN, stats.OpenTxN, stats.TxStats.Write, stats.TxStats.WriteTime), nil
+}
+
+// Compact is a noop for bbolt, whose B+tree pages are reused as data is deleted
+// and overwritten, without compactions.
+func (db *Database) Compact(start []byte, limit []byte) error {
+	return nil
+}
+
+// Path returns the path to the database directory.
+func (db *Database) Path() string {
+	return db.fn
+}
+
+// meter periodically retrieves the bbolt transaction stats and reports them to
+// the metrics subsystem.
+func (db *Database) meter(refresh time.Duration) {
+	var (
+		written   int
+		writeTime time.Duration
+		errc      chan error
+		merr      error
+	)
+	for errc == nil && merr == nil {
+		stats := db.db.Stats()
+		if db.diskWriteMeter != nil {
+			db.diskWriteMeter.Mark(int64((stats.TxStats.Write - written) * os.Getpagesize()))
+		}
+		if db.writeTimeMeter != nil {
+			db.writeTimeMeter.Mark(int64(stats.TxStats.WriteTime - writeTime))
+		}
+		written, writeTime = stats.TxStats.Write, stats.TxStats.WriteTime
+
+		if db.diskSizeGauge != nil {
+			merr = db.db.View(func(tx *bolt.Tx) error {
+				db.diskSizeGauge.Update(tx.Size())
+				return nil
+			})
+		}
+		// Sleep a bit, then repeat the stats collection
+		select {
+		case errc = <-db.quitChan:
+			// Quit requesting, stop hammering the database
+		case <-time.After(refresh):
+			// Timeout, gather a new set of stats
+		}
+	}
+	if errc == nil {
+		errc = <-db.quitChan
+	}
+	errc <- merr
+}
+
+// nonNil returns an empty value for a nil one, as bbolt doesn't store nil ones.
+func nonNil(value []byte) []byte {
+	if value == nil {
+		return []byte{}
+	}
+	return value
+}
+
+// keyvalue is a key-value tuple tagged with a deletion field to allow creating
+// bbolt write batches.
+type keyvalue struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// batch is a write-only bbolt batch that commits changes to its host database
+// when Write is called. A batch cannot be used concurrently.
+type batch struct {
+	db     *bolt.DB
+	writes []keyvalue
+	size   int
+}
+
+// Put inserts the given value into the batch for later committing.
+func (b *batch) Put(key, value []byte) error {
+	b.writes = append(b.writes, keyvalue{common.CopyBytes(key), common.CopyBytes(value), false})
+	b.size += len(value)
+	return nil
+}
+
+// Delete inserts the a key removal into the batch for later committing.
+func (b *batch) Delete(key []byte) error {
+	b.writes = append(b.writes, keyvalue{common.CopyBytes(key), nil, true})
+	b.size += 1
+	return nil
+}
+
+// ValueSize retrieves the amount of data queued up for writing.
+func (b *batch) ValueSize() int {
+	return b.size
+}
+
+// Write flushes any accumulated data to disk, in a single transaction.
+func (b *batch) Write() error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucket)
+		for _, keyvalue := range b.writes {
+			if keyvalue.delete {
+				if err := bucket.Delete(keyvalue.key); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := bucket.Put(keyvalue.key, nonNil(keyvalue.value)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	b.writes = b.writes[:0]
+	b.size = 0
+}
+
+// Replay replays the batch contents.
+func (b *batch) Replay(w ethdb.KeyValueWriter) error {
+	for _, keyvalue := range b.writes {
+		if keyvalue.delete {
+			if err := w.Delete(keyvalue.key); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := w.Put(keyvalue.key, keyvalue.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// iterator can walk over the (potentially partial) keyspace of a bbolt database.
+// Items are read in chunks, each within a short transaction, so an iteration is
+// not isolated from the writes done meanwhile.
+type iterator struct {
+	db     *bolt.DB
+	next   []byte // Key to resume reading the next chunk at, nil when exhausted
+	prefix []byte // Prefix the keys must have to be iterated
+
+	keys   [][]byte
+	values [][]byte
+	pos    int
+	done   bool
+	err    error
+}
+
+// Next moves the iterator to the next key/value pair. It returns whether the
+// iterator is exhausted.
+func (it *iterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.pos+1 < len(it.keys) {
+		it.pos++
+		return true
+	}
+	if it.done {
+		it.keys, it.values, it.pos = nil, nil, -1
+		return false
+	}
+	if it.err = it.fill(); it.err != nil || len(it.keys) == 0 {
+		it.keys, it.values, it.pos = nil, nil, -1
+		return false
+	}
+	it.pos = 0
+	return true
+}
+
+// fill reads the next chunk of items.
+func (it *iterator) fill() error {
+	it.keys, it.values = it.keys[:0], it.values[:0]
+
+	return it.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucket).Cursor()
+
+		k, v := c.Seek(it.next)
+		for ; k != nil && len(it.keys) < iteratorChunk; k, v = c.Next() {
+			if !bytes.HasPrefix(k, it.prefix) {
+				break
+			}
+			it.keys = append(it.keys, common.CopyBytes(k))
+			it.values = append(it.values, common.CopyBytes(v))
+		}
+		// Resume right after the last key read, unless nothing is left
+		if k == nil || !bytes.HasPrefix(k, it.prefix) {
+			it.done = true
+		} else {
+			it.next = append(common.CopyBytes(it.keys[len(it.keys)-1]), 0)
+		}
+		return nil
+	})
+}
+
+// Error returns any accumulated error. Exhausting all the key/value pairs
+// is not considered to be an error.
+func (it *iterator) Error() error {
+	return it.err
+}
+
+// Key returns the key of the current key/value pair, or nil if done. The caller
+// should not modify the contents of the returned slice, and its contents may
+// change on the next call to Next.
+func (it *iterator) Key() []byte {
+	if it.pos < 0 {
+		return nil
+	}
+	return it.keys[it.pos]
+}
+
+// Value returns the value of the current key/value pair, or nil if done. The
+// caller should not modify the contents of the returned slice, and its contents
+// may change on the next call to Next.
+func (it *iterator) Value() []byte {
+	if it.pos < 0 {
+		return nil
+	}
+	return it.values[it.pos]
+}
+
+// Release releases associated resources. Release should always succeed and can
+// be called multiple times without causing error.
+func (it *iterator) Release() {
+	it.keys, it.values, it.pos, it.done = nil, nil, -1, true
+}
@@ -0,0 +1,95 @@
+// Copyright 2019 The gonex Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package boltdb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/dbtest"
+)
+
+func TestBoltDB(t *testing.T) {
+	dir, err := ioutil.TempDir("", "boltdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var dbs []*Database
+	defer func() {
+		for _, db := range dbs {
+			db.Close()
+		}
+	}()
+	t.Run("DatabaseSuite", func(t *testing.T) {
+		dbtest.TestDatabaseSuite(t, func() ethdb.KeyValueStore {
+			db, err := New(fmt.Sprintf("%s/%d", dir, len(dbs)), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			dbs = append(dbs, db)
+			return db
+		})
+	})
+}
+
+// Tests that iterations spanning several chunks can write to the database as
+// they go, without deadlocking on the growth of the database.
+func TestBoltDBIterateAndWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "boltdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := New(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	items := 3*iteratorChunk + 10
+	for i := 0; i < items; i++ {
+		if err := db.Put([]byte(fmt.Sprintf("a%06d", i)), []byte{byte(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	it := db.NewIteratorWithPrefix([]byte("a"))
+	defer it.Release()
+
+	count := 0
+	for it.Next() {
+		if want := fmt.Sprintf("a%06d", count); string(it.Key()) != want {
+			t.Fatalf("key %d mismatch: have %s, want %s", count, it.Key(), want)
+		}
+		// Write enough for the database to grow while iterating
+		if err := db.Put(append([]byte("b"), it.Key()...), make([]byte, 4096)); err != nil {
+			t.Fatal(err)
+		}
+		count++
+	}
+	if err := it.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if count != items {
+		t.Fatalf("item count mismatch: have %d, want %d", count, items)
+	}
+}
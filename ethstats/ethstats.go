@@ -43,6 +43,7 @@ import (
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/gorilla/websocket"
+	lru "github.com/hashicorp/golang-lru"
 )
 
 const (
@@ -81,6 +82,8 @@ type Service struct {
 
 	pongCh chan struct{} // Pong notifications are fed into this channel
 	histCh chan []uint64 // History request block numbers are fed into this channel
+
+	dccsStats *lru.Cache // DCCS stats of the recently reported blocks, computed once per block
 }
 
 // New returns a monitoring service ready for stats reporting.
@@ -98,6 +101,7 @@ func New(url string, ethServ *eth.Ethereum, lesServ *les.LightEthereum) (*Servic
 	} else {
 		engine = lesServ.Engine()
 	}
+	dccsStats, _ := lru.New(historyUpdateRange * 2)
 	return &Service{
 		eth:    ethServ,
 		les:    lesServ,
@@ -107,6 +111,8 @@ func New(url string, ethServ *eth.Ethereum, lesServ *les.LightEthereum) (*Servic
 		host:   parts[4],
 		pongCh: make(chan struct{}),
 		histCh: make(chan []uint64, 1),
+
+		dccsStats: dccsStats,
 	}, nil
 }
 
@@ -488,6 +494,7 @@ type blockStats struct {
 	ToAbsorb   string         `json:"toAbsorb"`
 	Absorbed   string         `json:"absorbed"`
 	SupplySTB  string         `json:"supplySTB"`
+	Dccs       *dccs.Stats    `json:"dccs,omitempty"` // versioned DCCS stats extension
 }
 
 // txStats is the information to report about individual transactions.
@@ -534,6 +541,8 @@ func (s *Service) assembleBlockStats(block *types.Block) *blockStats {
 		txs    []txStats
 		uncles []*types.Header
 
+		stats *dccs.Stats
+
 		price       string
 		priceMedian string
 		toAbsorb    string
@@ -555,14 +564,17 @@ func (s *Service) assembleBlockStats(block *types.Block) *blockStats {
 		uncles = block.Uncles()
 
 		if s.eth.BlockChain().Config().IsCoLoa(header.Number) {
-			d, _ := s.engine.(*dccs.Dccs)
-			c := dccs.NewContext(d, s.eth.BlockChain())
-			number := header.Number.Uint64()
-			price = c.BlockPriceStat(number)
-			priceMedian = c.MedianPriceStat(number)
-			toAbsorb = dccs.RemainToAbsorbStat(s.eth.BlockChain(), number)
-			absorbed = dccs.AbsorbedStat(s.eth.BlockChain(), number)
-			supplySTB = dccs.StableSupplyStat(s.eth.BlockChain(), number)
+			if stats = s.assembleDccsStats(header); stats != nil {
+				// legacy fields, kept for the dashboards not reading the extension
+				price = string(stats.Price)
+				if len(price) == 0 && len(stats.PriceError) > 0 {
+					price = "0"
+				}
+				priceMedian = string(stats.PriceMedian)
+				toAbsorb = stableAmountStat(stats.RemainToAbsorb)
+				absorbed = stableAmountStat(stats.Absorbed)
+				supplySTB = stableAmountStat(stats.StableSupply)
+			}
 		}
 	} else {
 		// Light nodes would need on-demand lookups for transactions/uncles, skip
@@ -606,7 +618,42 @@ func (s *Service) assembleBlockStats(block *types.Block) *blockStats {
 		ToAbsorb:   toAbsorb,
 		Absorbed:   absorbed,
 		SupplySTB:  supplySTB,
+		Dccs:       stats,
+	}
+}
+
+// assembleDccsStats returns the DCCS stats of a block, computing them only once
+// per block, so the state of every block is opened once. The cached stats of
+// the parent block are reused for the absorbed supply.
+func (s *Service) assembleDccsStats(header *types.Header) *dccs.Stats {
+	if stats, ok := s.dccsStats.Get(header.Hash()); ok {
+		return stats.(*dccs.Stats)
+	}
+	engine, ok := s.engine.(*dccs.Dccs)
+	if !ok {
+		return nil
+	}
+	var parent *dccs.Stats
+	if stats, ok := s.dccsStats.Get(header.ParentHash); ok {
+		parent = stats.(*dccs.Stats)
+	}
+	statedb, err := s.eth.BlockChain().StateAt(header.Root)
+	if err != nil {
+		log.Debug("Failed to open block state for DCCS stats", "number", header.Number, "err", err)
+		statedb = nil
+	}
+	stats := dccs.NewContext(engine, s.eth.BlockChain()).BlockStats(header, statedb, parent)
+	s.dccsStats.Add(header.Hash(), stats)
+	return stats
+}
+
+// stableAmountStat formats a stable token amount in the legacy display unit,
+// empty if the amount is not available.
+func stableAmountStat(amount *big.Int) string {
+	if amount == nil {
+		return ""
 	}
+	return new(big.Rat).SetFrac(amount, big.NewInt(1000000)).FloatString(6)
 }
 
 // reportHistory retrieves the most recent batch of blocks and reports it to the
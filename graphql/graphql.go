@@ -72,6 +72,14 @@ func (a *Account) TransactionCount(ctx context.Context) (hexutil.Uint64, error)
 	return hexutil.Uint64(state.GetNonce(a.address)), nil
 }
 
+func (a *Account) MRUNumber(ctx context.Context) (hexutil.Uint64, error) {
+	state, err := a.getState(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(state.GetMRUNumber(a.address)), nil
+}
+
 func (a *Account) Code(ctx context.Context) (hexutil.Bytes, error) {
 	state, err := a.getState(ctx)
 	if err != nil {
@@ -214,7 +222,9 @@ func (t *Transaction) From(ctx context.Context, args BlockNumberArgs) (*Account,
 		return nil, err
 	}
 	var signer types.Signer = types.HomesteadSigner{}
-	if tx.Protected() {
+	if tx.IsSystem() {
+		signer = types.SystemSigner
+	} else if tx.Protected() {
 		signer = types.NewEIP155Signer(tx.ChainId())
 	}
 	from, _ := types.Sender(signer, tx)
@@ -226,6 +236,14 @@ func (t *Transaction) From(ctx context.Context, args BlockNumberArgs) (*Account,
 	}, nil
 }
 
+func (t *Transaction) IsSystemTx(ctx context.Context) (bool, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return false, err
+	}
+	return tx.IsSystem(), nil
+}
+
 func (t *Transaction) Block(ctx context.Context) (*Block, error) {
 	if _, err := t.resolve(ctx); err != nil {
 		return nil, err
@@ -268,6 +286,14 @@ func (t *Transaction) Status(ctx context.Context) (*hexutil.Uint64, error) {
 	return &ret, nil
 }
 
+func (t *Transaction) FailureReason(ctx context.Context) (*string, error) {
+	receipt, err := t.getReceipt(ctx)
+	if err != nil || receipt == nil || len(receipt.FailureReason) == 0 {
+		return nil, err
+	}
+	return &receipt.FailureReason, nil
+}
+
 func (t *Transaction) GasUsed(ctx context.Context) (*hexutil.Uint64, error) {
 	receipt, err := t.getReceipt(ctx)
 	if err != nil || receipt == nil {
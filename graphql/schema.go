@@ -46,6 +46,10 @@ const schema string = `
         # or in the case of a contract, the number of contracts created. Otherwise
         # known as the nonce.
         transactionCount: Long!
+        # MRUNumber is the most recently used block number of this account, which
+        # decides the priority of its transactions. It is 0 for accounts which
+        # have never sent a transaction since the ThangLong fork.
+        mruNumber: Long!
         # Code contains the smart contract code for this account, if the account
         # is a (non-self-destructed) contract.
         code: Bytes!
@@ -79,8 +83,12 @@ const schema string = `
         # be null if the transaction has not yet been mined.
         index: Int
         # From is the account that sent this transaction - this will always be
-        # an externally owned account.
+        # an externally owned account, or the zero address for system
+        # transactions.
         from(block: Long): Account!
+        # IsSystemTx is true if this is an unsigned, zero-fee transaction
+        # executed by the consensus engine on block initialization.
+        isSystemTx: Boolean!
         # To is the account the transaction was sent to. This is null for
         # contract-creating transactions.
         to(block: Long): Account
@@ -101,6 +109,10 @@ const schema string = `
         # running out of gas). If the transaction has not yet been mined, this
         # field will be null.
         status: Long
+        # FailureReason is the reason of the transaction failure, including the
+        # Solidity revert reason. This field is null if the transaction has not
+        # failed, has not yet been mined, or was not executed by this node.
+        failureReason: String
         # GasUsed is the amount of gas that was used processing this transaction.
         # If the transaction has not yet been mined, this field will be null.
         gasUsed: Long
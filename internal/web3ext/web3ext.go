@@ -458,6 +458,12 @@ web3._extend({
 			params: 2,
 			inputFormatter: [null, null]
 		}),
+		new web3._extend.Method({
+			name: 'stateDiff',
+			call: 'debug_stateDiff',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'traceTransaction',
 			call: 'debug_traceTransaction',
@@ -486,6 +492,12 @@ web3._extend({
 			params: 2,
 			inputFormatter: [null, null],
 		}),
+		new web3._extend.Method({
+			name: 'mruHistory',
+			call: 'debug_mruHistory',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, null],
+		}),
 		new web3._extend.Method({
 			name: 'getModifiedAccountsByHash',
 			call: 'debug_getModifiedAccountsByHash',
@@ -589,6 +601,10 @@ web3._extend({
 				return formatted;
 			}
 		}),
+		new web3._extend.Property({
+			name: 'stateSyncProgress',
+			getter: 'eth_stateSyncProgress'
+		}),
 	]
 });
 `
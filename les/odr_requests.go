@@ -42,6 +42,7 @@ var (
 	errDataHashMismatch    = errors.New("data hash mismatch")
 	errCHTHashMismatch     = errors.New("cht hash mismatch")
 	errCHTNumberMismatch   = errors.New("cht number mismatch")
+	errHeaderHashMismatch  = errors.New("header hash mismatch")
 	errUselessNodes        = errors.New("useless nodes in merkle proof nodeset")
 )
 
@@ -64,6 +65,8 @@ func LesRequest(req light.OdrRequest) LesOdrRequest {
 		return (*CodeRequest)(r)
 	case *light.ChtRequest:
 		return (*ChtRequest)(r)
+	case *light.HeadersRequest:
+		return (*HeadersRequest)(r)
 	case *light.BloomRequest:
 		return (*BloomRequest)(r)
 	case *light.TxStatusRequest:
@@ -404,6 +407,60 @@ func (r *ChtRequest) Validate(db ethdb.Database, msg *Msg) error {
 	return nil
 }
 
+// ODR request type for requesting ancestor headers by hash, see LesOdrRequest interface
+type HeadersRequest light.HeadersRequest
+
+// GetCost returns the cost of the given ODR request according to the serving
+// peer's cost table (implementation of LesOdrRequest)
+func (r *HeadersRequest) GetCost(peer *peer) uint64 {
+	return peer.GetRequestCost(GetBlockHeadersMsg, int(r.Amount))
+}
+
+// CanSend tells if a certain peer is suitable for serving the given request
+func (r *HeadersRequest) CanSend(peer *peer) bool {
+	peer.lock.RLock()
+	defer peer.lock.RUnlock()
+
+	return peer.headInfo.Number >= r.Number
+}
+
+// Request sends an ODR request to the LES network (implementation of LesOdrRequest)
+func (r *HeadersRequest) Request(reqID uint64, peer *peer) error {
+	peer.Log().Debug("Requesting ancestor headers", "hash", r.Hash, "amount", r.Amount)
+	return peer.RequestHeadersByHash(reqID, r.GetCost(peer), r.Hash, int(r.Amount), 0, true)
+}
+
+// Valid processes an ODR request reply message from the LES network
+// returns true and stores results in memory if the message was a valid reply
+// to the request (implementation of LesOdrRequest)
+func (r *HeadersRequest) Validate(db ethdb.Database, msg *Msg) error {
+	log.Debug("Validating ancestor headers", "hash", r.Hash, "amount", r.Amount)
+
+	// Ensure we have a correct message with a chain of headers
+	if msg.MsgType != MsgBlockHeaders {
+		return errInvalidMessageType
+	}
+	headers := msg.Obj.([]*types.Header)
+	if len(headers) == 0 || uint64(len(headers)) > r.Amount {
+		return errInvalidEntryCount
+	}
+	// Only the chain reaching the genesis may be shorter than requested
+	if uint64(len(headers)) < r.Amount && headers[len(headers)-1].Number.Sign() != 0 {
+		return errInvalidEntryCount
+	}
+	// Verify the headers are linked to the requested hash
+	hash := r.Hash
+	for _, header := range headers {
+		if header.Hash() != hash {
+			return errHeaderHashMismatch
+		}
+		hash = header.ParentHash
+	}
+	// Verifications passed, store and return
+	r.Headers = headers
+	return nil
+}
+
 type BloomReq struct {
 	BloomTrieNum, BitIdx, SectionIndex, FromLevel uint64
 }
@@ -174,6 +174,15 @@ func (rm *retrieveManager) deliver(peer distPeer, msg *Msg) error {
 	return errResp(ErrUnexpectedResponse, "reqID = %v", msg.ReqID)
 }
 
+// requested tells whether the given reqID belongs to a pending retrieval
+func (rm *retrieveManager) requested(reqID uint64) bool {
+	rm.lock.RLock()
+	defer rm.lock.RUnlock()
+
+	_, ok := rm.sentReqs[reqID]
+	return ok
+}
+
 // frozen is called by the LES protocol manager when a server has suspended its service and we
 // should not expect an answer for the requests already sent there
 func (rm *retrieveManager) frozen(peer distPeer) {
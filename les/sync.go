@@ -169,7 +169,17 @@ func (h *clientHandler) synchronise(peer *peer) {
 		//
 		// For the clique consensus engine, the start header is the block header
 		// of the latest epoch covered by checkpoint.
-		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		//
+		// For the dccs consensus engine, the start header is the checkpoint block
+		// header of the latest epoch before CoLoa, or the latest block header after
+		// it. The headers needed to rebuild the sealer set are fetched as well: the
+		// snapshot header of the epoch, or the sealing queue window and the anchor
+		// chain, which could take a while.
+		timeout := time.Second * 5
+		if h.backend.chainConfig.Dccs != nil {
+			timeout = time.Minute
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
 		defer cancel()
 		if !checkpoint.Empty() && !h.backend.blockchain.SyncCheckpoint(ctx, checkpoint) {
 			log.Debug("Sync checkpoint failed")
@@ -468,7 +468,9 @@ func (lc *LightChain) Config() *params.ChainConfig { return lc.hc.Config() }
 // the checkpoint provided by the remote peer.
 //
 // Note if we are running the clique, fetches the last epoch snapshot header
-// which covered by checkpoint.
+// which covered by checkpoint. If the consensus engine is able to bootstrap
+// from a checkpoint (e.g. dccs), the start header is chosen by the engine which
+// also fetches any older headers it needs to verify the chain after it.
 func (lc *LightChain) SyncCheckpoint(ctx context.Context, checkpoint *params.TrustedCheckpoint) bool {
 	// Ensure the remote checkpoint head is ahead of us
 	head := lc.CurrentHeader().Number.Uint64()
@@ -477,11 +479,22 @@ func (lc *LightChain) SyncCheckpoint(ctx context.Context, checkpoint *params.Tru
 	if clique := lc.hc.Config().Clique; clique != nil {
 		latest -= latest % clique.Epoch // epoch snapshot for clique
 	}
+	syncer, bootstrap := lc.engine.(consensus.CheckpointSyncer)
+	if bootstrap {
+		latest = syncer.CheckpointHead(latest)
+	}
 	if head >= latest {
 		return true
 	}
 	// Retrieve the latest useful header and update to it
 	if header, err := GetHeaderByNumber(ctx, lc.odr, latest); header != nil && err == nil {
+		if bootstrap {
+			retriever := &odrHeaderRetriever{ctx: ctx, odr: lc.odr}
+			if err := syncer.FetchCheckpointHeaders(lc.hc, header, retriever); err != nil {
+				log.Warn("Failed to retrieve checkpoint headers", "number", header.Number, "hash", header.Hash(), "err", err)
+				return false
+			}
+		}
 		lc.chainmu.Lock()
 		defer lc.chainmu.Unlock()
 
@@ -542,3 +555,22 @@ func (lc *LightChain) DisableCheckFreq() {
 func (lc *LightChain) EnableCheckFreq() {
 	atomic.StoreInt32(&lc.disableCheckFreq, 0)
 }
+
+// odrHeaderRetriever retrieves the headers missing from the light client through
+// the ODR backend for the consensus engine.
+type odrHeaderRetriever struct {
+	ctx context.Context
+	odr OdrBackend
+}
+
+// HeaderByNumber implements consensus.HeaderRetriever, retrieving a header proven
+// by the trusted CHT.
+func (r *odrHeaderRetriever) HeaderByNumber(number uint64) (*types.Header, error) {
+	return GetHeaderByNumber(r.ctx, r.odr, number)
+}
+
+// Ancestors implements consensus.HeaderRetriever, retrieving a chain of headers
+// linked to a known hash.
+func (r *odrHeaderRetriever) Ancestors(hash common.Hash, number uint64, amount uint64) ([]*types.Header, error) {
+	return GetAncestorHeaders(r.ctx, r.odr, hash, number, amount)
+}
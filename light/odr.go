@@ -160,6 +160,24 @@ func (req *ChtRequest) StoreResult(db ethdb.Database) {
 	}
 }
 
+// HeadersRequest is the ODR request type for retrieving a chain of ancestors of
+// a header, newest first
+type HeadersRequest struct {
+	OdrRequest
+	Hash    common.Hash // Hash of the newest header in the chain
+	Number  uint64      // Number of the newest header, or an upper bound of it
+	Amount  uint64      // Number of headers to retrieve
+	Headers []*types.Header
+}
+
+// StoreResult stores the retrieved data in local database. The headers are not
+// marked canonical, their chain being only linked to the requested hash.
+func (req *HeadersRequest) StoreResult(db ethdb.Database) {
+	for _, header := range req.Headers {
+		rawdb.WriteHeader(db, header)
+	}
+}
+
 // BloomRequest is the ODR request type for retrieving bloom filters from a CHT structure
 type BloomRequest struct {
 	OdrRequest
@@ -88,6 +88,19 @@ func (odr *testOdr) Retrieve(ctx context.Context, req OdrRequest) error {
 		req.Proof = nodes
 	case *CodeRequest:
 		req.Data, _ = odr.sdb.Get(req.Hash[:])
+	case *HeadersRequest:
+		for hash := req.Hash; uint64(len(req.Headers)) < req.Amount; {
+			number := rawdb.ReadHeaderNumber(odr.sdb, hash)
+			if number == nil {
+				break
+			}
+			header := rawdb.ReadHeader(odr.sdb, hash, *number)
+			req.Headers = append(req.Headers, header)
+			if *number == 0 {
+				break
+			}
+			hash = header.ParentHash
+		}
 	}
 	req.StoreResult(odr.ldb)
 	return nil
@@ -207,6 +220,82 @@ func odrContractCall(ctx context.Context, db ethdb.Database, bc *core.BlockChain
 	return res, nil
 }
 
+func TestOdrGetAncestorHeaders(t *testing.T) {
+	var (
+		sdb     = rawdb.NewMemoryDatabase()
+		ldb     = rawdb.NewMemoryDatabase()
+		gspec   = core.Genesis{Alloc: core.GenesisAlloc{testBankAddress: {Balance: testBankFunds}}}
+		genesis = gspec.MustCommit(sdb)
+	)
+	gspec.MustCommit(ldb)
+	blockchain, _ := core.NewBlockChain(sdb, nil, params.TestChainConfig, ethash.NewFullFaker(), vm.Config{}, nil, nil)
+	gchain, _ := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), sdb, 4, testChainGen)
+	if _, err := blockchain.InsertChain(gchain); err != nil {
+		t.Fatal(err)
+	}
+	odr := &testOdr{sdb: sdb, ldb: ldb, indexerConfig: TestClientIndexerConfig}
+	head := gchain[3].Header()
+
+	// Expect the retrieval to fail without ODR
+	odr.disable = true
+	if _, err := GetAncestorHeaders(context.Background(), odr, head.Hash(), 4, 3); err == nil {
+		t.Fatalf("retrieved ancestors without ODR")
+	}
+	// Expect the ancestors of a canonical head to be retrieved and stored as canonical
+	odr.disable = false
+	rawdb.WriteHeader(ldb, head)
+	rawdb.WriteCanonicalHash(ldb, head.Hash(), head.Number.Uint64())
+
+	headers, err := GetAncestorHeaders(context.Background(), odr, head.Hash(), 4, 3)
+	if err != nil {
+		t.Fatalf("failed to retrieve ancestors: %v", err)
+	}
+	if len(headers) != 3 {
+		t.Fatalf("ancestors count mismatch: have %d, want 3", len(headers))
+	}
+	for i, header := range headers {
+		want := gchain[3-i].Header()
+		if header.Hash() != want.Hash() {
+			t.Errorf("ancestor %d mismatch: have %x, want %x", i, header.Hash(), want.Hash())
+		}
+		if hash := rawdb.ReadCanonicalHash(ldb, want.Number.Uint64()); hash != want.Hash() {
+			t.Errorf("ancestor %d not canonical: have %x, want %x", i, hash, want.Hash())
+		}
+	}
+	// Expect the chain to stop at genesis, served from the local database
+	odr.disable = true
+	headers, err = GetAncestorHeaders(context.Background(), odr, headers[2].Hash(), 2, 5)
+	if err == nil {
+		t.Fatalf("retrieved missing genesis ancestors without ODR")
+	}
+	odr.disable = false
+	if headers, err = GetAncestorHeaders(context.Background(), odr, head.Hash(), 4, 10); err != nil {
+		t.Fatalf("failed to retrieve ancestors: %v", err)
+	}
+	if len(headers) != 5 || headers[4].Hash() != genesis.Hash() {
+		t.Errorf("ancestors mismatch: have %d headers, want 5 down to genesis", len(headers))
+	}
+	// Expect the ancestors of a side chain header not to be stored as canonical
+	fork, _ := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), sdb, 2, func(i int, block *core.BlockGen) {
+		block.SetCoinbase(common.Address{0x01})
+	})
+	for _, block := range fork {
+		rawdb.WriteHeader(sdb, block.Header())
+	}
+	if headers, err = GetAncestorHeaders(context.Background(), odr, fork[1].Hash(), 2, 2); err != nil {
+		t.Fatalf("failed to retrieve side chain ancestors: %v", err)
+	}
+	for i, header := range headers {
+		want := fork[1-i].Header()
+		if header.Hash() != want.Hash() {
+			t.Errorf("side chain ancestor %d mismatch: have %x, want %x", i, header.Hash(), want.Hash())
+		}
+		if hash := rawdb.ReadCanonicalHash(ldb, want.Number.Uint64()); hash != gchain[1-i].Hash() {
+			t.Errorf("side chain ancestor %d stored as canonical", i)
+		}
+	}
+}
+
 func testChainGen(i int, block *core.BlockGen) {
 	signer := types.HomesteadSigner{}
 	switch i {
@@ -257,7 +346,7 @@ func testChainOdr(t *testing.T, protocol int, fn odrTestFn) {
 	)
 	gspec.MustCommit(ldb)
 	// Assemble the test environment
-	blockchain, _ := core.NewBlockChain(sdb, nil, params.TestChainConfig, ethash.NewFullFaker(), vm.Config{}, nil)
+	blockchain, _ := core.NewBlockChain(sdb, nil, params.TestChainConfig, ethash.NewFullFaker(), vm.Config{}, nil, nil)
 	gchain, _ := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), sdb, 4, testChainGen)
 	if _, err := blockchain.InsertChain(gchain); err != nil {
 		t.Fatal(err)
@@ -30,6 +30,10 @@ import (
 
 var sha3Nil = crypto.Keccak256Hash(nil)
 
+// maxAncestorsFetch is the number of ancestor headers retrieved by a single
+// request, the limit of the les protocol.
+const maxAncestorsFetch = 192
+
 func GetHeaderByNumber(ctx context.Context, odr OdrBackend, number uint64) (*types.Header, error) {
 	db := odr.Database()
 	hash := rawdb.ReadCanonicalHash(db, number)
@@ -79,6 +83,59 @@ func GetUntrustedHeaderByNumber(ctx context.Context, odr OdrBackend, number uint
 	return r.Header, nil
 }
 
+// GetAncestorHeaders retrieves a chain of headers, newest first, starting with
+// the header of the given hash and walking backwards along the parent links. The
+// number is the block number of the requested header, or an upper bound of it
+// when unknown.
+//
+// Note the retrieved headers are only stored as canonical once their chain is
+// proven so, either by a local canonical header or by the trusted CHT.
+func GetAncestorHeaders(ctx context.Context, odr OdrBackend, hash common.Hash, number uint64, amount uint64) ([]*types.Header, error) {
+	db := odr.Database()
+	headers := make([]*types.Header, 0, amount)
+	canonical := false
+	for uint64(len(headers)) < amount {
+		// Use the locally available headers first
+		var header *types.Header
+		if num := rawdb.ReadHeaderNumber(db, hash); num != nil {
+			header = rawdb.ReadHeader(db, hash, *num)
+		}
+		if header != nil {
+			// The parents of a canonical header are canonical too
+			if !canonical {
+				canonical = rawdb.ReadCanonicalHash(db, header.Number.Uint64()) == hash
+			}
+			headers = append(headers, header)
+		} else {
+			r := &HeadersRequest{Hash: hash, Number: number, Amount: amount - uint64(len(headers))}
+			if r.Amount > maxAncestorsFetch {
+				r.Amount = maxAncestorsFetch
+			}
+			if err := odr.Retrieve(ctx, r); err != nil {
+				return nil, err
+			}
+			if !canonical {
+				first := r.Headers[0]
+				if trusted, err := GetHeaderByNumber(ctx, odr, first.Number.Uint64()); err == nil && trusted.Hash() == first.Hash() {
+					canonical = true
+				}
+			}
+			if canonical {
+				for _, header := range r.Headers {
+					rawdb.WriteCanonicalHash(db, header.Hash(), header.Number.Uint64())
+				}
+			}
+			headers = append(headers, r.Headers...)
+		}
+		last := headers[len(headers)-1]
+		if last.Number.Sign() == 0 {
+			break
+		}
+		hash, number = last.ParentHash, last.Number.Uint64()-1
+	}
+	return headers, nil
+}
+
 func GetCanonicalHash(ctx context.Context, odr OdrBackend, number uint64) (common.Hash, error) {
 	hash := rawdb.ReadCanonicalHash(odr.Database(), number)
 	if (hash != common.Hash{}) {
@@ -163,6 +220,21 @@ func GetBlockReceipts(ctx context.Context, odr OdrBackend, hash common.Hash, num
 	return receipts, nil
 }
 
+// GetHeaderReceipts retrieves the receipts of a block, verified against the
+// receipt root of the given header which may not be stored yet. The receipts
+// lack the fields derived from the block body.
+func GetHeaderReceipts(ctx context.Context, odr OdrBackend, header *types.Header) (types.Receipts, error) {
+	hash, number := header.Hash(), header.Number.Uint64()
+	if receipts := rawdb.ReadRawReceipts(odr.Database(), hash, number); receipts != nil {
+		return receipts, nil
+	}
+	r := &ReceiptsRequest{Hash: hash, Number: number, Header: header}
+	if err := odr.Retrieve(ctx, r); err != nil {
+		return nil, err
+	}
+	return r.Receipts, nil
+}
+
 // GetBlockLogs retrieves the logs generated by the transactions included in a
 // block given by its hash.
 func GetBlockLogs(ctx context.Context, odr OdrBackend, hash common.Hash, number uint64) ([][]*types.Log, error) {
@@ -99,8 +99,6 @@ var (
 
 // trustedCheckpoints associates each known checkpoint with the genesis hash of the chain it belongs to
 var trustedCheckpoints = map[common.Hash]*params.TrustedCheckpoint{
-	params.MainnetGenesisHash: params.MainnetTrustedCheckpoint,
-	params.TestnetGenesisHash: params.TestnetTrustedCheckpoint,
 	params.RinkebyGenesisHash: params.RinkebyTrustedCheckpoint,
 	params.GoerliGenesisHash:  params.GoerliTrustedCheckpoint,
 }
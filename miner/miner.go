@@ -51,6 +51,9 @@ type Config struct {
 	GasPrice  *big.Int       // Minimum gas price for mining a transaction
 	Recommit  time.Duration  // The time interval for miner to re-create mining work.
 	Noverify  bool           // Disable remote mining solution verification(only useful in ethash).
+
+	LeaseFile     string        `toml:",omitempty"` // Lock file shared by a hot-standby sealer pair (only useful in dccs).
+	LeaseInterval time.Duration `toml:",omitempty"` // Interval at which the standby sealer retries to acquire the lease.
 }
 
 // Miner creates blocks and searches for proof-of-work values.
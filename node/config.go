@@ -70,6 +70,12 @@ type Config struct {
 	// in memory.
 	DataDir string
 
+	// DBEngine is the key-value store engine of the databases created within the
+	// data directory, either "leveldb" (the default) or "boltdb". Databases that
+	// already exist keep their own engine: they are opened with it if none is
+	// configured, and refused to be opened with a different one.
+	DBEngine string `toml:",omitempty"`
+
 	// Configuration of peer-to-peer networking.
 	P2P p2p.Config
 
@@ -51,19 +51,16 @@ var (
 )
 
 // TrustedCheckpoints associates each known checkpoint with the genesis hash of
-// the chain it belongs to.
+// the chain it belongs to. No checkpoint is published for the Nexty networks yet.
 var TrustedCheckpoints = map[common.Hash]*TrustedCheckpoint{
-	MainnetGenesisHash: MainnetTrustedCheckpoint,
-	TestnetGenesisHash: TestnetTrustedCheckpoint,
 	RinkebyGenesisHash: RinkebyTrustedCheckpoint,
 	GoerliGenesisHash:  GoerliTrustedCheckpoint,
 }
 
 // CheckpointOracles associates each known checkpoint oracles with the genesis hash of
-// the chain it belongs to.
+// the chain it belongs to. The oracles of the Nexty networks are to be deployed with
+// `checkpoint-admin deploy --dccs`, none is published yet.
 var CheckpointOracles = map[common.Hash]*CheckpointOracleConfig{
-	MainnetGenesisHash: MainnetCheckpointOracle,
-	TestnetGenesisHash: TestnetCheckpointOracle,
 	RinkebyGenesisHash: RinkebyCheckpointOracle,
 	GoerliGenesisHash:  GoerliCheckpointOracle,
 }
@@ -106,27 +103,6 @@ var (
 		},
 	}
 
-	// MainnetTrustedCheckpoint contains the light client trusted checkpoint for the main network.
-	MainnetTrustedCheckpoint = &TrustedCheckpoint{
-		SectionIndex: 561,
-		SectionHead:  common.HexToHash("0x6b87a31980a1fe764e5db97ea7c841b9d6db88fe8cd0506937532bae7eab783c"),
-		CHTRoot:      common.Hash{}, // TODO: will calculate later when using LES
-		BloomRoot:    common.Hash{}, // TODO: will calculate later when using LES
-	}
-
-	// MainnetCheckpointOracle contains a set of configs for the main network oracle.
-	MainnetCheckpointOracle = &CheckpointOracleConfig{
-		Address: common.HexToAddress("0x9a9070028361F7AAbeB3f2F2Dc07F82C4a98A02a"),
-		Signers: []common.Address{
-			common.HexToAddress("0x1b2C260efc720BE89101890E4Db589b44E950527"), // Peter
-			common.HexToAddress("0x78d1aD571A1A09D60D9BBf25894b44e4C8859595"), // Martin
-			common.HexToAddress("0x286834935f4A8Cfb4FF4C77D5770C2775aE2b0E7"), // Zsolt
-			common.HexToAddress("0xb86e2B0Ab5A4B1373e40c51A7C712c70Ba2f9f8E"), // Gary
-			common.HexToAddress("0x0DF8fa387C602AE62559cC4aFa4972A7045d6707"), // Guillaume
-		},
-		Threshold: 2,
-	}
-
 	// TestnetChainConfig contains the chain parameters to run a node on the Dccs test network.
 	TestnetChainConfig = &ChainConfig{
 		ChainID:             big.NewInt(111111),
@@ -164,27 +140,6 @@ var (
 		},
 	}
 
-	// TestnetTrustedCheckpoint contains the light client trusted checkpoint for the Ropsten test network.
-	TestnetTrustedCheckpoint = &TrustedCheckpoint{
-		SectionIndex: 19,
-		SectionHead:  common.HexToHash("0x57a235095aba5f4c6cf7a1787c283003e93adadf68f46ecacbcc777d505d83b2"),
-		CHTRoot:      common.Hash{}, // TODO: will calculate later when using LES
-		BloomRoot:    common.Hash{}, // TODO: will calculate later when using LES
-	}
-
-	// TestnetCheckpointOracle contains a set of configs for the Ropsten test network oracle.
-	TestnetCheckpointOracle = &CheckpointOracleConfig{
-		Address: common.HexToAddress("0xEF79475013f154E6A65b54cB2742867791bf0B84"),
-		Signers: []common.Address{
-			common.HexToAddress("0x32162F3581E88a5f62e8A61892B42C46E2c18f7b"), // Peter
-			common.HexToAddress("0x78d1aD571A1A09D60D9BBf25894b44e4C8859595"), // Martin
-			common.HexToAddress("0x286834935f4A8Cfb4FF4C77D5770C2775aE2b0E7"), // Zsolt
-			common.HexToAddress("0xb86e2B0Ab5A4B1373e40c51A7C712c70Ba2f9f8E"), // Gary
-			common.HexToAddress("0x0DF8fa387C602AE62559cC4aFa4972A7045d6707"), // Guillaume
-		},
-		Threshold: 2,
-	}
-
 	// RinkebyChainConfig contains the chain parameters to run a node on the Rinkeby test network.
 	RinkebyChainConfig = &ChainConfig{
 		ChainID:             big.NewInt(4),
@@ -424,6 +379,8 @@ type DccsConfig struct {
 	AbsorptionExpiration    uint64   `json:"absorptionExpiration"`  // number of blocks that the absorption will be expired (a week)
 	SlashingRate            uint64   `json:"slashingRate"`          // slashing rate
 	LockdownExpiration      uint64   `json:"lockdownExpiration"`    // number of blocks that the lockdown will be expired (2 weeks)
+	// Developer chain
+	Developer bool `json:"developer,omitempty"` // Single sealer developer chain, not to be set on any public network
 }
 
 // IsPriceBlock returns whether a block could include a price
@@ -24,6 +24,7 @@ import (
 	"math/big"
 	"os"
 	"reflect"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
@@ -41,7 +42,7 @@ const (
 	// numberOfAccountsToDerive For hardware wallets, the number of accounts to derive
 	numberOfAccountsToDerive = 10
 	// ExternalAPIVersion -- see extapi_changelog.md
-	ExternalAPIVersion = "6.0.0"
+	ExternalAPIVersion = "6.1.0"
 	// InternalAPIVersion -- see intapi_changelog.md
 	InternalAPIVersion = "7.0.0"
 )
@@ -114,6 +115,9 @@ type SignerAPI struct {
 	validator   Validator
 	rejectMode  bool
 	credentials storage.Storage
+
+	sealings storage.Storage // Last DCCS header sealed by each account
+	sealLock sync.Mutex      // Serializes the DCCS sealings from check to record
 }
 
 // Metadata about a request
@@ -280,12 +284,28 @@ func NewSignerAPI(am *accounts.Manager, chainID int64, noUSB bool, ui UIClientAP
 	if advancedMode {
 		log.Info("Clef is in advanced mode: will warn instead of reject")
 	}
-	signer := &SignerAPI{big.NewInt(chainID), am, ui, validator, !advancedMode, credentials}
+	signer := &SignerAPI{
+		chainID:     big.NewInt(chainID),
+		am:          am,
+		UI:          ui,
+		validator:   validator,
+		rejectMode:  !advancedMode,
+		credentials: credentials,
+		sealings:    storage.NewEphemeralStorage(),
+	}
 	if !noUSB {
 		signer.startUSBListener()
 	}
 	return signer
 }
+
+// SetSealingStorage sets the storage of the last DCCS header sealed by each
+// account, guarding against signing conflicting headers. Without it, the records
+// are only kept in memory.
+func (api *SignerAPI) SetSealingStorage(sealings storage.Storage) {
+	api.sealings = sealings
+}
+
 func (api *SignerAPI) openTrezor(url accounts.URL) {
 	resp, err := api.UI.OnInputRequired(UserInputRequest{
 		Prompt: "Pin required to open Trezor wallet\n" +
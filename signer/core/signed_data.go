@@ -19,6 +19,7 @@ package core
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
@@ -38,6 +39,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
@@ -59,6 +61,10 @@ var (
 		accounts.MimetypeClique,
 		0x02,
 	}
+	ApplicationDccs = SigFormat{
+		accounts.MimetypeDccs,
+		0x02,
+	}
 	TextPlain = SigFormat{
 		accounts.MimetypeTextPlain,
 		0x45,
@@ -127,6 +133,18 @@ var typedDataReferenceTypeRegexp = regexp.MustCompile(`^[A-Z](\w*)(\[\])?$`)
 // Note, the produced signature conforms to the secp256k1 curve R, S and V values,
 // where the V value will be 27 or 28 for legacy reasons, if legacyV==true.
 func (api *SignerAPI) sign(addr common.MixedcaseAddress, req *SignDataRequest, legacyV bool) (hexutil.Bytes, error) {
+	// Whatever the UI approves, never seal two DCCS headers at the same height
+	var sealed *dccsSealed
+	if req.ContentType == accounts.MimetypeDccs {
+		api.sealLock.Lock()
+		defer api.sealLock.Unlock()
+
+		var err error
+		if sealed, err = api.checkDccsHeader(addr.Address(), req); err != nil {
+			log.Warn("Refusing to sign DCCS header", "err", err)
+			return nil, err
+		}
+	}
 	// We make the request prior to looking up if we actually have the account, to prevent
 	// account-enumeration via the API
 	res, err := api.UI.ApproveSignData(req)
@@ -156,6 +174,9 @@ func (api *SignerAPI) sign(addr common.MixedcaseAddress, req *SignDataRequest, l
 	if legacyV {
 		signature[64] += 27 // Transform V from 0/1 to 27/28 according to the yellow paper
 	}
+	if sealed != nil {
+		api.recordDccsHeader(addr.Address(), sealed)
+	}
 	return signature, nil
 }
 
@@ -259,6 +280,38 @@ func (api *SignerAPI) determineSignatureFormat(ctx context.Context, contentType
 		// Clique uses V on the form 0 or 1
 		useEthereumV = false
 		req = &SignDataRequest{ContentType: mediaType, Rawdata: cliqueRlp, Messages: messages, Hash: sighash}
+	case ApplicationDccs.Mime:
+		// DCCS headers are signed like the clique ones, but carry extended data
+		stringData, ok := data.(string)
+		if !ok {
+			return nil, useEthereumV, fmt.Errorf("input for %v must be an hex-encoded string", ApplicationDccs.Mime)
+		}
+		dccsData, err := hexutil.Decode(stringData)
+		if err != nil {
+			return nil, useEthereumV, err
+		}
+		header := &types.Header{}
+		if err := rlp.DecodeBytes(dccsData, header); err != nil {
+			return nil, useEthereumV, err
+		}
+		// The incoming header is sent without the seal, add it back for hashing
+		header.Extra = append(header.Extra, make([]byte, 65)...)
+		sighash, dccsRlp, err := dccsHeaderHashAndRlp(header)
+		if err != nil {
+			return nil, useEthereumV, err
+		}
+		messages := []*NameValueType{
+			{
+				Name:  "DCCS header",
+				Typ:   "dccs",
+				Value: fmt.Sprintf("dccs header %d [0x%x]", header.Number, header.Hash()),
+			},
+		}
+		messages = append(messages, dccsExtendedMessages(header.Extra[32:len(header.Extra)-65])...)
+
+		// DCCS uses V on the form 0 or 1
+		useEthereumV = false
+		req = &SignDataRequest{ContentType: mediaType, Rawdata: dccsRlp, Messages: messages, Hash: sighash}
 	default: // also case TextPlain.Mime:
 		// Calculates an Ethereum ECDSA signature for:
 		// hash = keccak256("\x19${byteVersion}Ethereum Signed Message:\n${message length}${message}")
@@ -311,6 +364,130 @@ func cliqueHeaderHashAndRlp(header *types.Header) (hash, rlp []byte, err error)
 	return hash, rlp, err
 }
 
+// Type bytes tagging the parts of the DCCS extended data, mirroring the ones of
+// consensus/dccs, which can't be imported here without an import cycle.
+const (
+	dccsExtendedSeed        = 0x01
+	dccsExtendedPrice       = 0x02
+	dccsExtendedSealerJoin  = 0xF0
+	dccsExtendedSealerLeave = 0xF1
+	dccsExtendedAnchor      = 0xFF
+)
+
+// dccsHeaderHashAndRlp returns the hash which is used as input for the DCCS
+// sealing, along with the signed rlp. DCCS seals the same fields as clique, so
+// the same encoding is used, failing instead of panicking on short extra data.
+func dccsHeaderHashAndRlp(header *types.Header) (hash, rlp []byte, err error) {
+	if len(header.Extra) < 32+65 {
+		err = fmt.Errorf("dccs header extradata too short, %d < %d", len(header.Extra), 32+65)
+		return
+	}
+	rlp = clique.CliqueRLP(header)
+	hash = clique.SealHash(header).Bytes()
+	return hash, rlp, err
+}
+
+// dccsSealed is the last DCCS header sealed by an account, kept in the sealing
+// storage under the account address.
+type dccsSealed struct {
+	Number uint64        `json:"number"`
+	Hash   hexutil.Bytes `json:"hash"` // Seal hash of the header
+}
+
+// checkDccsHeader ensures a DCCS header doesn't conflict with the ones already
+// sealed by the account: only the very same header may be signed again at the
+// last sealed height, and nothing below it.
+func (api *SignerAPI) checkDccsHeader(address common.Address, req *SignDataRequest) (*dccsSealed, error) {
+	header := new(types.Header)
+	if err := rlp.DecodeBytes(req.Rawdata, header); err != nil {
+		return nil, fmt.Errorf("invalid header: %v", err)
+	}
+	if header.Number == nil || !header.Number.IsUint64() {
+		return nil, errors.New("invalid header number")
+	}
+	sealed := &dccsSealed{Number: header.Number.Uint64(), Hash: req.Hash}
+
+	blob, err := api.sealings.Get(address.Hex())
+	if err != nil || blob == "" {
+		return sealed, nil // nothing sealed yet
+	}
+	last := new(dccsSealed)
+	if err := json.Unmarshal([]byte(blob), last); err != nil {
+		return nil, fmt.Errorf("corrupt sealing record: %v", err)
+	}
+	switch {
+	case sealed.Number < last.Number:
+		return nil, fmt.Errorf("header %d below last sealed %d", sealed.Number, last.Number)
+	case sealed.Number == last.Number && !bytes.Equal(sealed.Hash, last.Hash):
+		return nil, fmt.Errorf("header %d conflicts with sealed %x", sealed.Number, []byte(last.Hash))
+	}
+	return sealed, nil
+}
+
+// recordDccsHeader stores the DCCS header sealed by an account.
+func (api *SignerAPI) recordDccsHeader(address common.Address, sealed *dccsSealed) {
+	blob, err := json.Marshal(sealed)
+	if err != nil {
+		log.Warn("Failed to encode sealing record", "err", err)
+		return
+	}
+	api.sealings.Put(address.Hex(), string(blob))
+}
+
+// dccsExtendedMessages describes the extended data carried by a DCCS header
+// between the vanity and the seal: an optional anchor with the confirmed sealer
+// applications, an optional VDF seed and an optional price. Headers from before
+// CoLoa carry sealer lists instead, which are shown as they are.
+func dccsExtendedMessages(extra []byte) []*NameValueType {
+	if len(extra) == 0 {
+		return nil
+	}
+	raw := []*NameValueType{{Name: "Extra data", Typ: "hexdata", Value: hexutil.Encode(extra)}}
+
+	var messages []*NameValueType
+	rest := extra
+	if rest[0] == dccsExtendedAnchor {
+		if len(rest) < 1+2*common.HashLength {
+			return raw
+		}
+		messages = append(messages,
+			&NameValueType{Name: "Anchor destination", Typ: "hash", Value: common.BytesToHash(rest[1 : 1+common.HashLength]).Hex()},
+			&NameValueType{Name: "Sealers digest", Typ: "hash", Value: common.BytesToHash(rest[1+common.HashLength : 1+2*common.HashLength]).Hex()},
+		)
+		rest = rest[1+2*common.HashLength:]
+		for len(rest) > 0 && (rest[0] == dccsExtendedSealerJoin || rest[0] == dccsExtendedSealerLeave) {
+			if len(rest) < 1+common.AddressLength {
+				return raw
+			}
+			name := "Sealer joining"
+			if rest[0] == dccsExtendedSealerLeave {
+				name = "Sealer leaving"
+			}
+			messages = append(messages, &NameValueType{Name: name, Typ: "address", Value: common.BytesToAddress(rest[1 : 1+common.AddressLength]).Hex()})
+			rest = rest[1+common.AddressLength:]
+		}
+	}
+	if len(rest) >= 1+common.HashLength && rest[0] == dccsExtendedSeed {
+		messages = append(messages, &NameValueType{Name: "Random seed", Typ: "hexdata", Value: hexutil.Encode(rest[1 : 1+common.HashLength])})
+		rest = rest[1+common.HashLength:]
+	}
+	if len(rest) > 0 && rest[0] == dccsExtendedPrice {
+		var (
+			stream     = rlp.NewStream(bytes.NewReader(rest[1:]), uint64(len(rest)-1))
+			num, denom big.Int
+		)
+		if stream.Decode(&num) != nil || stream.Decode(&denom) != nil || denom.Sign() == 0 {
+			return raw
+		}
+		messages = append(messages, &NameValueType{Name: "Price", Typ: "price", Value: new(big.Rat).SetFrac(&num, &denom).FloatString(6)})
+		rest = nil
+	}
+	if len(rest) > 0 {
+		return raw
+	}
+	return messages
+}
+
 // SignTypedData signs EIP-712 conformant typed data
 // hash = keccak256("\x19${byteVersion}${domainSeparator}${hashStruct(message)}")
 func (api *SignerAPI) SignTypedData(ctx context.Context, addr common.MixedcaseAddress, typedData TypedData) (hexutil.Bytes, error) {
@@ -17,8 +17,12 @@
 package core
 
 import (
+	"bytes"
 	"math/big"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 func TestParseInteger(t *testing.T) {
@@ -49,3 +53,43 @@ func TestParseInteger(t *testing.T) {
 		}
 	}
 }
+
+func TestDccsExtendedMessages(t *testing.T) {
+	var (
+		dest   = common.HexToHash("0x01")
+		digest = common.HexToHash("0x02")
+		joiner = common.HexToAddress("0x03")
+		seed   = bytes.Repeat([]byte{0x04}, common.HashLength)
+	)
+	price, _ := rlp.EncodeToBytes(big.NewInt(3))
+	denom, _ := rlp.EncodeToBytes(big.NewInt(2))
+
+	extra := []byte{dccsExtendedAnchor}
+	extra = append(extra, dest[:]...)
+	extra = append(extra, digest[:]...)
+	extra = append(append(extra, dccsExtendedSealerJoin), joiner[:]...)
+	extra = append(append(extra, dccsExtendedSeed), seed...)
+	extra = append(append(append(extra, dccsExtendedPrice), price...), denom...)
+
+	messages := dccsExtendedMessages(extra)
+	want := []string{"Anchor destination", "Sealers digest", "Sealer joining", "Random seed", "Price"}
+	if len(messages) != len(want) {
+		t.Fatalf("message count mismatch: have %d, want %d", len(messages), len(want))
+	}
+	for i, name := range want {
+		if messages[i].Name != name {
+			t.Errorf("message %d name mismatch: have %s, want %s", i, messages[i].Name, name)
+		}
+	}
+	if messages[2].Value != joiner.Hex() || messages[4].Value != "1.500000" {
+		t.Errorf("message values mismatch: have %v, %v", messages[2].Value, messages[4].Value)
+	}
+	// Sealer lists of older headers, even if looking like an anchor, are shown raw
+	signers := append([]byte{dccsExtendedAnchor}, bytes.Repeat([]byte{0x05}, common.AddressLength-1)...)
+	if messages := dccsExtendedMessages(signers); len(messages) != 1 || messages[0].Typ != "hexdata" {
+		t.Errorf("sealer list not shown raw: %v", messages)
+	}
+	if messages := dccsExtendedMessages(nil); messages != nil {
+		t.Errorf("empty extended data described: %v", messages)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"path"
 	"strings"
 	"testing"
@@ -29,6 +30,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus/dccs"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/signer/core"
 )
@@ -228,6 +231,64 @@ func TestSignData(t *testing.T) {
 	if signature == nil || len(signature) != 65 {
 		t.Errorf("Expected 65 byte signature (got %d bytes)", len(signature))
 	}
+	// application/x-dccs-header
+	header := &types.Header{Number: big.NewInt(10), Difficulty: big.NewInt(2), Extra: make([]byte, 32+65)}
+	control.approveCh <- "Y"
+	control.inputCh <- "a_long_password"
+	signature, err = api.SignData(context.Background(), core.ApplicationDccs.Mime, a, hexutil.Encode(dccs.DccsRLP(header)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubkey, err := crypto.Ecrecover(dccs.SealHash(header).Bytes(), signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var signer common.Address
+	copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
+	if signer != list[0] {
+		t.Errorf("DCCS seal signer mismatch: have %x, want %x", signer, list[0])
+	}
+}
+
+// Tests that the signer refuses to seal conflicting DCCS headers, whatever the UI approves.
+func TestSignDccsConflicts(t *testing.T) {
+	api, control := setup(t)
+	createAccount(control, api, t)
+	control.approveCh <- "A"
+	list, err := api.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := common.NewMixedcaseAddress(list[0])
+
+	seal := func(number int64, time uint64) error {
+		header := &types.Header{Number: big.NewInt(number), Time: time, Difficulty: big.NewInt(2), Extra: make([]byte, 32+65)}
+		_, err := api.SignData(context.Background(), core.ApplicationDccs.Mime, a, hexutil.Encode(dccs.DccsRLP(header)))
+		return err
+	}
+	approve := func() {
+		control.approveCh <- "Y"
+		control.inputCh <- "a_long_password"
+	}
+	approve()
+	if err := seal(10, 1); err != nil {
+		t.Fatalf("failed to seal header: %v", err)
+	}
+	approve()
+	if err := seal(10, 1); err != nil {
+		t.Fatalf("failed to reseal the same header: %v", err)
+	}
+	// Conflicting headers are rejected before reaching the UI
+	if err := seal(10, 2); err == nil {
+		t.Errorf("sealed a conflicting header at the same height")
+	}
+	if err := seal(9, 1); err == nil {
+		t.Errorf("sealed a header below the last sealed height")
+	}
+	approve()
+	if err := seal(11, 2); err != nil {
+		t.Fatalf("failed to seal the next header: %v", err)
+	}
 }
 
 func TestDomainChainId(t *testing.T) {
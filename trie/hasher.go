@@ -25,10 +25,16 @@ import (
 	"golang.org/x/crypto/sha3"
 )
 
+// parallelHashThreshold is the number of updates since the last hashing from
+// which the subtries of the root are hashed concurrently. Below it, spawning the
+// goroutines costs more than hashing the few dirty nodes.
+const parallelHashThreshold = 100
+
 type hasher struct {
-	tmp    sliceBuffer
-	sha    keccakState
-	onleaf LeafCallback
+	tmp      sliceBuffer
+	sha      keccakState
+	onleaf   LeafCallback
+	parallel bool // Whether to hash the children of the next full node concurrently
 }
 
 // keccakState wraps sha3.state. In addition to the usual hash methods, it also supports
@@ -60,9 +66,10 @@ var hasherPool = sync.Pool{
 	},
 }
 
-func newHasher(onleaf LeafCallback) *hasher {
+func newHasher(onleaf LeafCallback, parallel bool) *hasher {
 	h := hasherPool.Get().(*hasher)
 	h.onleaf = onleaf
+	h.parallel = parallel
 	return h
 }
 
@@ -140,6 +147,14 @@ func (h *hasher) hashChildren(original node, db *Database) (node, node, error) {
 		// Hash the full node's children, caching the newly hashed subtrees
 		collapsed, cached := n.copy(), n.copy()
 
+		if h.parallel {
+			h.parallel = false // Only the topmost full node is split up
+			if err := h.hashChildrenParallel(n, collapsed, cached, db); err != nil {
+				return original, original, err
+			}
+			cached.Children[16] = n.Children[16]
+			return collapsed, cached, nil
+		}
 		for i := 0; i < 16; i++ {
 			if n.Children[i] != nil {
 				collapsed.Children[i], cached.Children[i], err = h.hash(n.Children[i], db, false)
@@ -157,6 +172,48 @@ func (h *hasher) hashChildren(original node, db *Database) (node, node, error) {
 	}
 }
 
+// hashChildrenParallel hashes the children of a full node concurrently, each of
+// them on a hasher of its own, filling in the collapsed and cached copies of the
+// node. The subtries being disjoint, the outcome is the same as hashing them one
+// after the other, only the order of the database insertions differs. Leaf
+// callbacks are serialized, so they don't have to be safe for concurrent use.
+func (h *hasher) hashChildrenParallel(n, collapsed, cached *fullNode, db *Database) error {
+	var (
+		onleaf = h.onleaf
+		lock   sync.Mutex
+		wg     sync.WaitGroup
+		errs   [16]error
+	)
+	if onleaf != nil {
+		onleaf = func(leaf []byte, parent common.Hash) error {
+			lock.Lock()
+			defer lock.Unlock()
+			return h.onleaf(leaf, parent)
+		}
+	}
+	for i := 0; i < 16; i++ {
+		if n.Children[i] == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			hasher := newHasher(onleaf, false)
+			collapsed.Children[i], cached.Children[i], errs[i] = hasher.hash(n.Children[i], db, false)
+			returnHasherToPool(hasher)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // store hashes the node n and if we have a storage layer specified, it writes
 // the key/value pair to it and tracks any node->child references as well as any
 // node->external trie references.
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	crand "crypto/rand"
 	mrand "math/rand"
+	"sort"
 	"testing"
 	"time"
 
@@ -148,6 +149,173 @@ func TestMissingKeyProof(t *testing.T) {
 	}
 }
 
+type entrySlice []*kv
+
+func (p entrySlice) Len() int           { return len(p) }
+func (p entrySlice) Less(i, j int) bool { return bytes.Compare(p[i].k, p[j].k) < 0 }
+func (p entrySlice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
+// sortedEntries returns the entries of a trie created by randomTrie, ordered by key.
+func sortedEntries(vals map[string]*kv) entrySlice {
+	var entries entrySlice
+	for _, kv := range vals {
+		entries = append(entries, kv)
+	}
+	sort.Sort(entries)
+	return entries
+}
+
+// proveRange creates the proof of the edges of a range.
+func proveRange(t *testing.T, trie *Trie, first, last []byte) *memorydb.Database {
+	proof := memorydb.New()
+	if err := trie.Prove(first, 0, proof); err != nil {
+		t.Fatalf("failed to prove the first node: %v", err)
+	}
+	if err := trie.Prove(last, 0, proof); err != nil {
+		t.Fatalf("failed to prove the last node: %v", err)
+	}
+	return proof
+}
+
+// rangeData splits a range of entries into keys and values.
+func rangeData(entries entrySlice) ([][]byte, [][]byte) {
+	var keys, vals [][]byte
+	for _, entry := range entries {
+		keys = append(keys, entry.k)
+		vals = append(vals, entry.v)
+	}
+	return keys, vals
+}
+
+// Tests that random ranges with existent edges are proven, and that the leaves
+// after them are detected.
+func TestRangeProof(t *testing.T) {
+	trie, vals := randomTrie(4096)
+	entries := sortedEntries(vals)
+	for i := 0; i < 200; i++ {
+		start := mrand.Intn(len(entries))
+		end := mrand.Intn(len(entries)-start) + start + 1
+
+		proof := proveRange(t, trie, entries[start].k, entries[end-1].k)
+		keys, vals := rangeData(entries[start:end])
+		more, err := VerifyRangeProof(trie.Hash(), keys[0], keys[len(keys)-1], keys, vals, proof)
+		if err != nil {
+			t.Fatalf("range [%d, %d): failed to verify proof: %v", start, end, err)
+		}
+		if more != (end < len(entries)) {
+			t.Fatalf("range [%d, %d): more entries mismatch: have %v, want %v", start, end, more, end < len(entries))
+		}
+	}
+}
+
+// Tests that ranges whose left edge proves the absence of a key are proven.
+func TestRangeProofWithNonExistentEdge(t *testing.T) {
+	trie, vals := randomTrie(4096)
+	entries := sortedEntries(vals)
+	for i := 0; i < 200; i++ {
+		start := mrand.Intn(len(entries))
+		end := mrand.Intn(len(entries)-start) + start + 1
+
+		// Use a key right before the first entry as the left edge
+		if start == 0 {
+			continue
+		}
+		first := common.CopyBytes(entries[start].k)
+		for j := len(first) - 1; j >= 0; j-- {
+			if first[j] > 0 {
+				first[j]--
+				break
+			}
+			first[j] = 0xff
+		}
+		if bytes.Equal(first, entries[start-1].k) {
+			continue
+		}
+		proof := proveRange(t, trie, first, entries[end-1].k)
+		keys, vals := rangeData(entries[start:end])
+		if _, err := VerifyRangeProof(trie.Hash(), first, keys[len(keys)-1], keys, vals, proof); err != nil {
+			t.Fatalf("range [%d, %d): failed to verify proof: %v", start, end, err)
+		}
+	}
+}
+
+// Tests the special cases of range proofs: no proof, no leaves and one leaf.
+func TestRangeProofSpecialCases(t *testing.T) {
+	trie, vals := randomTrie(4096)
+	entries := sortedEntries(vals)
+	root := trie.Hash()
+
+	// The entire trie needs no proof
+	keys, values := rangeData(entries)
+	if more, err := VerifyRangeProof(root, nil, nil, keys, values, nil); err != nil || more {
+		t.Fatalf("entire trie: have more %v, err %v", more, err)
+	}
+	if _, err := VerifyRangeProof(root, nil, nil, keys[1:], values[1:], nil); err == nil {
+		t.Fatalf("partial trie proven without proof")
+	}
+	// Nothing after the last entry
+	last := common.CopyBytes(entries[len(entries)-1].k)
+	last[len(last)-1]++
+	proof := memorydb.New()
+	trie.Prove(last, 0, proof)
+	if more, err := VerifyRangeProof(root, last, nil, nil, nil, proof); err != nil || more {
+		t.Fatalf("empty range: have more %v, err %v", more, err)
+	}
+	// Something after the first key, the absence of leaves must be rejected
+	first := common.CopyBytes(entries[0].k)
+	proof = memorydb.New()
+	trie.Prove(first, 0, proof)
+	if _, err := VerifyRangeProof(root, first, nil, nil, nil, proof); err == nil {
+		t.Fatalf("missing leaves accepted")
+	}
+	// A single leaf in the middle
+	entry := entries[len(entries)/2]
+	proof = memorydb.New()
+	trie.Prove(entry.k, 0, proof)
+	more, err := VerifyRangeProof(root, entry.k, entry.k, [][]byte{entry.k}, [][]byte{entry.v}, proof)
+	if err != nil || !more {
+		t.Fatalf("single leaf: have more %v, err %v", more, err)
+	}
+}
+
+// Tests that tampered ranges are rejected.
+func TestBadRangeProof(t *testing.T) {
+	trie, vals := randomTrie(4096)
+	entries := sortedEntries(vals)
+	root := trie.Hash()
+
+	for i := 0; i < 200; i++ {
+		start := mrand.Intn(len(entries))
+		end := mrand.Intn(len(entries)-start) + start + 1
+		if end-start < 3 {
+			continue
+		}
+		proof := proveRange(t, trie, entries[start].k, entries[end-1].k)
+		keys, vals := rangeData(entries[start:end])
+		first, last := keys[0], keys[len(keys)-1]
+
+		index := 1 + mrand.Intn(len(keys)-2)
+		switch mrand.Intn(3) {
+		case 0: // Modify a value
+			vals[index] = randBytes(20)
+		case 1: // Drop a leaf
+			keys = append(keys[:index:index], keys[index+1:]...)
+			vals = append(vals[:index:index], vals[index+1:]...)
+		case 2: // Add a leaf
+			key := common.CopyBytes(keys[index])
+			key[len(key)-1]++
+			if bytes.Equal(key, keys[index+1]) {
+				continue
+			}
+			keys = append(keys[:index+1:index+1], append([][]byte{key}, keys[index+1:]...)...)
+			vals = append(vals[:index+1:index+1], append([][]byte{randBytes(20)}, vals[index+1:]...)...)
+		}
+		if _, err := VerifyRangeProof(root, first, last, keys, vals, proof); err == nil {
+			t.Fatalf("range [%d, %d): tampered range accepted", start, end)
+		}
+	}
+}
+
 // mutateByte changes one byte in b.
 func mutateByte(b []byte) {
 	for r := mrand.Intn(len(b)); ; {
@@ -19,9 +19,11 @@ package trie
 import (
 	"errors"
 	"fmt"
+	"math/bits"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/prque"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 )
 
@@ -33,11 +35,19 @@ var ErrNotRequested = errors.New("not requested")
 // node it already processed previously.
 var ErrAlreadyProcessed = errors.New("already processed")
 
+// progressDepth is the depth, in nibbles, of the subtries whose completion is
+// tracked to estimate the progress of a sync.
+const progressDepth = 3
+
+// progressSubtries is the number of subtries tracked at progressDepth.
+const progressSubtries = 1 << (4 * progressDepth)
+
 // request represents a scheduled or already in-flight state retrieval request.
 type request struct {
 	hash common.Hash // Hash of the node data content to retrieve
 	data []byte      // Data content of the node, cached until all subtrees complete
 	raw  bool        // Whether this is a raw entry (code) or a trie node
+	path []byte      // Nibble path of the node in the synced trie, nil past progressDepth
 
 	parents []*request // Parent state nodes referencing this entry (notify all upon completion)
 	depth   int        // Depth level within the trie the node is located to prioritise DFS
@@ -66,25 +76,37 @@ func newSyncMemBatch() *syncMemBatch {
 	}
 }
 
+// SyncProgress is the progress of an unfinished trie sync, from which a new sync
+// of the same trie can pick up where it left off.
+type SyncProgress struct {
+	Root     common.Hash // Root of the trie being synced
+	Complete []byte      // Bitmap of the subtries progressDepth nibbles deep completed
+	Nodes    [][]byte    // Nodes downloaded but waiting for their subtries to complete
+}
+
 // Sync is the main state trie synchronisation scheduler, which provides yet
 // unknown trie hashes to retrieve, accepts node data associated with said hashes
 // and reconstructs the trie step by step until all is done.
 type Sync struct {
+	root     common.Hash              // Root of the trie being synced
 	database ethdb.KeyValueReader     // Persistent database to check for existing entries
 	membatch *syncMemBatch            // Memory buffer to avoid frequent database writes
 	requests map[common.Hash]*request // Pending requests pertaining to a key hash
 	queue    *prque.Prque             // Priority queue with the pending requests
 	bloom    *SyncBloom               // Bloom filter for fast node existence checks
+	complete []byte                   // Bitmap of the subtries completed, to estimate progress
 }
 
 // NewSync creates a new trie data download scheduler.
 func NewSync(root common.Hash, database ethdb.KeyValueReader, callback LeafCallback, bloom *SyncBloom) *Sync {
 	ts := &Sync{
+		root:     root,
 		database: database,
 		membatch: newSyncMemBatch(),
 		requests: make(map[common.Hash]*request),
 		queue:    prque.New(nil),
 		bloom:    bloom,
+		complete: make([]byte, progressSubtries/8),
 	}
 	ts.AddSubTrie(root, 0, common.Hash{}, callback)
 	return ts
@@ -92,17 +114,25 @@ func NewSync(root common.Hash, database ethdb.KeyValueReader, callback LeafCallb
 
 // AddSubTrie registers a new trie to the sync code, rooted at the designated parent.
 func (s *Sync) AddSubTrie(root common.Hash, depth int, parent common.Hash, callback LeafCallback) {
+	// Only the nodes of the synced trie itself are tracked for progress
+	var path []byte
+	if parent == (common.Hash{}) {
+		path = []byte{}
+	}
 	// Short circuit if the trie is empty or already known
 	if root == emptyRoot {
+		s.markComplete(path)
 		return
 	}
 	if _, ok := s.membatch.batch[root]; ok {
+		s.markComplete(path)
 		return
 	}
 	if s.bloom.Contains(root[:]) {
 		// Bloom filter says this might be a duplicate, double check
 		blob, _ := s.database.Get(root[:])
 		if local, err := decodeNode(root[:], blob); local != nil && err == nil {
+			s.markComplete(path)
 			return
 		}
 		// False positive, bump fault meter
@@ -111,6 +141,7 @@ func (s *Sync) AddSubTrie(root common.Hash, depth int, parent common.Hash, callb
 	// Assemble the new sub-trie sync request
 	req := &request{
 		hash:     root,
+		path:     path,
 		depth:    depth,
 		callback: callback,
 	}
@@ -240,6 +271,99 @@ func (s *Sync) Pending() int {
 	return len(s.requests)
 }
 
+// Completed returns an estimate of the fraction of the trie synced, from the
+// number of subtries progressDepth nibbles deep completed.
+func (s *Sync) Completed() float64 {
+	done := 0
+	for _, b := range s.complete {
+		done += bits.OnesCount8(b)
+	}
+	return float64(done) / progressSubtries
+}
+
+// Progress returns the progress of the sync: the completed subtries, and the
+// nodes downloaded but still waiting for their children. Those nodes are all
+// that is lost if the sync is interrupted, as the others are committed to the
+// database.
+func (s *Sync) Progress() *SyncProgress {
+	progress := &SyncProgress{
+		Root:     s.root,
+		Complete: common.CopyBytes(s.complete),
+	}
+	for _, req := range s.requests {
+		if req.data != nil {
+			progress.Nodes = append(progress.Nodes, req.data)
+		}
+	}
+	return progress
+}
+
+// Restore resumes an interrupted sync of the same trie from its progress. The
+// nodes it had downloaded are processed anew, which schedules the retrieval of
+// the children still missing from the database. Any error leaves the sync in an
+// undefined state.
+func (s *Sync) Restore(progress *SyncProgress) error {
+	if progress.Root != s.root {
+		return fmt.Errorf("sync root mismatch: have %x, want %x", progress.Root, s.root)
+	}
+	if len(progress.Complete) == len(s.complete) {
+		for i, b := range progress.Complete {
+			s.complete[i] |= b
+		}
+	}
+	nodes := make(map[common.Hash][]byte, len(progress.Nodes))
+	for _, blob := range progress.Nodes {
+		nodes[crypto.Keccak256Hash(blob)] = blob
+	}
+	// Feed the known nodes to the sync as it requests them, until the remaining
+	// requests all need a download
+	for len(nodes) > 0 {
+		var results []SyncResult
+		for _, hash := range s.Missing(0) {
+			if blob, ok := nodes[hash]; ok {
+				results = append(results, SyncResult{Hash: hash, Data: blob})
+				delete(nodes, hash)
+				continue
+			}
+			s.queue.Push(hash, int64(s.requests[hash].depth))
+		}
+		if len(results) == 0 {
+			break
+		}
+		if _, index, err := s.Process(results); err != nil {
+			return fmt.Errorf("failed to restore node %x: %v", results[index].Hash, err)
+		}
+	}
+	return nil
+}
+
+// markComplete flags the subtries under the given path complete.
+func (s *Sync) markComplete(path []byte) {
+	if path == nil {
+		return
+	}
+	from, to := progressSpan(path)
+	s.markSpan(from, to)
+}
+
+// markSpan flags the subtries from..to-1 complete.
+func (s *Sync) markSpan(from, to int) {
+	for i := from; i < to; i++ {
+		s.complete[i/8] |= 1 << uint(i%8)
+	}
+}
+
+// progressSpan returns the range of subtries progressDepth nibbles deep under
+// the given path, which must be no deeper.
+func progressSpan(path []byte) (int, int) {
+	index := 0
+	for _, nibble := range path {
+		index = index*16 + int(nibble)
+	}
+	width := 1 << uint(4*(progressDepth-len(path)))
+	return index * width, (index + 1) * width
+}
+
 // schedule inserts a new state retrieval request into the fetch queue. If there
 // is already a pending request for this node, the new request will be discarded
 // and only a parent reference added to the old one.
@@ -260,23 +384,49 @@ func (s *Sync) children(req *request, object node) ([]*request, error) {
 	// Gather all the children of the node, irrelevant whether known or not
 	type child struct {
 		node  node
+		path  []byte
 		depth int
 	}
 	var children []child
 
+	// Only track the paths near the root, which estimate the progress
+	tracked := req.path != nil && len(req.path) < progressDepth
+
 	switch node := (object).(type) {
 	case *shortNode:
-		children = []child{{
+		c := child{
 			node:  node.Val,
 			depth: req.depth + len(node.Key),
-		}}
+		}
+		if key := node.Key; tracked && !hasTerm(key) {
+			// The subtries beside the extension are empty, hence complete
+			path := append(append([]byte{}, req.path...), key...)
+			from, to := progressSpan(req.path)
+			if len(path) >= progressDepth {
+				path = path[:progressDepth]
+			} else {
+				c.path = path
+			}
+			cfrom, cto := progressSpan(path)
+			s.markSpan(from, cfrom)
+			s.markSpan(cto, to)
+		}
+		children = []child{c}
 	case *fullNode:
 		for i := 0; i < 17; i++ {
+			var path []byte
+			if tracked && i < 16 {
+				path = append(append([]byte{}, req.path...), byte(i))
+			}
 			if node.Children[i] != nil {
 				children = append(children, child{
 					node:  node.Children[i],
+					path:  path,
 					depth: req.depth + 1,
 				})
+			} else {
+				// Missing children are empty subtries, hence complete
+				s.markComplete(path)
 			}
 		}
 	default:
@@ -298,11 +448,13 @@ func (s *Sync) children(req *request, object node) ([]*request, error) {
 			// Try to resolve the node from the local database
 			hash := common.BytesToHash(node)
 			if _, ok := s.membatch.batch[hash]; ok {
+				s.markComplete(child.path)
 				continue
 			}
 			if s.bloom.Contains(node) {
 				// Bloom filter says this might be a duplicate, double check
 				if ok, _ := s.database.Has(node); ok {
+					s.markComplete(child.path)
 					continue
 				}
 				// False positive, bump fault meter
@@ -311,6 +463,7 @@ func (s *Sync) children(req *request, object node) ([]*request, error) {
 			// Locally unknown node, schedule for retrieval
 			requests = append(requests, &request{
 				hash:     hash,
+				path:     child.path,
 				parents:  []*request{req},
 				depth:    child.depth,
 				callback: req.callback,
@@ -326,6 +479,7 @@ func (s *Sync) children(req *request, object node) ([]*request, error) {
 func (s *Sync) commit(req *request) (err error) {
 	// Write the node content to the membatch
 	s.membatch.batch[req.hash] = req.data
+	s.markComplete(req.path)
 
 	delete(s.requests, req.hash)
 
@@ -57,15 +57,22 @@ func (f syncBloomHasher) Sum64() uint64                     { return binary.BigE
 // database on creation in a background thread and will only start returning live
 // results once that's finished.
 type SyncBloom struct {
-	bloom  *bloomfilter.Filter
-	inited uint32
-	closer sync.Once
-	closed uint32
-	pend   sync.WaitGroup
+	bloom   *bloomfilter.Filter
+	loading uint32
+	inited  uint32
+	closer  sync.Once
+	closed  uint32
+	pend    sync.WaitGroup
 }
 
 // NewSyncBloom creates a new bloom filter of the given size (in megabytes) and
 // initializes it from the database. The bloom is hard coded to use 3 filters.
+//
+// Without a database the bloom is never initialized, reporting any hash as
+// possibly present, which leaves all existence checks to the database. This is
+// cheaper than iterating the entire database when only a few checks are needed,
+// like when resuming an interrupted sync. Such a bloom may still be initialized
+// later on through Load.
 func NewSyncBloom(memory uint64, database ethdb.Iteratee) *SyncBloom {
 	// Create the bloom filter to track known trie nodes
 	bloom, err := bloomfilter.New(memory*1024*1024*8, 3)
@@ -78,16 +85,28 @@ func NewSyncBloom(memory uint64, database ethdb.Iteratee) *SyncBloom {
 	b := &SyncBloom{
 		bloom: bloom,
 	}
-	b.pend.Add(2)
+	if database != nil {
+		b.Load(database)
+	}
+	b.pend.Add(1)
 	go func() {
 		defer b.pend.Done()
-		b.init(database)
+		b.meter()
 	}()
+	return b
+}
+
+// Load starts initializing the bloom from the database in a background thread,
+// unless it is already being initialized.
+func (b *SyncBloom) Load(database ethdb.Iteratee) {
+	if !atomic.CompareAndSwapUint32(&b.loading, 0, 1) {
+		return
+	}
+	b.pend.Add(1)
 	go func() {
 		defer b.pend.Done()
-		b.meter()
+		b.init(database)
 	}()
-	return b
 }
 
 // init iterates over the database, pushing every trie hash into the bloom filter.
@@ -18,9 +18,12 @@ package trie
 
 import (
 	"bytes"
+	"encoding/binary"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb/memorydb"
 )
 
@@ -368,3 +371,146 @@ func TestIncompleteSync(t *testing.T) {
 		diskdb.Put(key, value)
 	}
 }
+
+// makeHashedTestTrie creates a sample test trie with hashed keys, which spread
+// over the key space like those of the state tries.
+func makeHashedTestTrie(n int) (*Database, *Trie, map[string][]byte) {
+	triedb := NewDatabase(memorydb.New())
+	trie, _ := New(common.Hash{}, triedb)
+
+	content := make(map[string][]byte)
+	for i := 0; i < n; i++ {
+		index := make([]byte, 8)
+		binary.BigEndian.PutUint64(index, uint64(i))
+
+		key, val := crypto.Keccak256(index), common.LeftPadBytes(index, 32)
+		content[string(key)] = val
+		trie.Update(key, val)
+	}
+	trie.Commit(nil)
+
+	return triedb, trie, content
+}
+
+// syncSteps runs the given number of sync iterations, or until the sync is
+// done if steps is negative, returning the number of nodes downloaded.
+func syncSteps(t *testing.T, srcDb *Database, diskdb *memorydb.Database, sched *Sync, steps int) int {
+	downloaded := 0
+	for ; steps != 0; steps-- {
+		queue := sched.Missing(10)
+		if len(queue) == 0 {
+			break
+		}
+		results := make([]SyncResult, len(queue))
+		for i, hash := range queue {
+			data, err := srcDb.Node(hash)
+			if err != nil {
+				t.Fatalf("failed to retrieve node data for %x: %v", hash, err)
+			}
+			results[i] = SyncResult{hash, data}
+		}
+		if _, index, err := sched.Process(results); err != nil {
+			t.Fatalf("failed to process result #%d: %v", index, err)
+		}
+		batch := diskdb.NewBatch()
+		if err := sched.Commit(batch); err != nil {
+			t.Fatalf("failed to commit data: %v", err)
+		}
+		batch.Write()
+
+		downloaded += len(queue)
+	}
+	return downloaded
+}
+
+// Tests that the completion estimate of a sync grows up to the whole trie.
+func TestSyncCompletion(t *testing.T) {
+	srcDb, srcTrie, _ := makeHashedTestTrie(10000)
+
+	diskdb := memorydb.New()
+	sched := NewSync(srcTrie.Hash(), diskdb, nil, NewSyncBloom(1, diskdb))
+
+	last := sched.Completed()
+	if last != 0 {
+		t.Fatalf("fresh sync completion mismatch: have %v, want 0", last)
+	}
+	for sched.Pending() > 0 {
+		syncSteps(t, srcDb, diskdb, sched, 1)
+		if completed := sched.Completed(); completed < last {
+			t.Fatalf("completion decreased: from %v to %v", last, completed)
+		} else {
+			last = completed
+		}
+	}
+	if last != 1 {
+		t.Fatalf("finished sync completion mismatch: have %v, want 1", last)
+	}
+	// A sync of a trie already present is complete from the start
+	if completed := NewSync(srcTrie.Hash(), diskdb, nil, NewSyncBloom(1, diskdb)).Completed(); completed != 1 {
+		t.Fatalf("existing trie completion mismatch: have %v, want 1", completed)
+	}
+}
+
+// Tests that an interrupted sync resumes from its progress without downloading
+// again the nodes it had already retrieved.
+func TestSyncResume(t *testing.T) {
+	srcDb, srcTrie, srcData := makeHashedTestTrie(10000)
+
+	// Sync the full trie once to count the nodes to download
+	total := syncSteps(t, srcDb, memorydb.New(), NewSync(srcTrie.Hash(), memorydb.New(), nil, NewSyncBloom(1, memorydb.New())), -1)
+
+	// Interrupt a sync half way, and resume it from its progress
+	diskdb := memorydb.New()
+	sched := NewSync(srcTrie.Hash(), diskdb, nil, NewSyncBloom(1, diskdb))
+
+	before := syncSteps(t, srcDb, diskdb, sched, 100)
+	progress, completed := sched.Progress(), sched.Completed()
+	if len(progress.Nodes) == 0 {
+		t.Fatalf("no pending nodes in progress")
+	}
+	sched = NewSync(srcTrie.Hash(), diskdb, nil, NewSyncBloom(1, nil))
+	if err := sched.Restore(progress); err != nil {
+		t.Fatalf("failed to restore sync: %v", err)
+	}
+	if have := sched.Completed(); have < completed {
+		t.Fatalf("restored completion mismatch: have %v, want at least %v", have, completed)
+	}
+	after := syncSteps(t, srcDb, diskdb, sched, -1)
+	if before+after != total {
+		t.Fatalf("downloaded node count mismatch: have %d+%d, want %d", before, after, total)
+	}
+	checkTrieContents(t, NewDatabase(diskdb), srcTrie.Hash().Bytes(), srcData)
+
+	// Progress of another trie must be rejected
+	progress.Root = common.Hash{1}
+	if err := NewSync(srcTrie.Hash(), memorydb.New(), nil, NewSyncBloom(1, nil)).Restore(progress); err == nil {
+		t.Fatalf("progress of another trie restored")
+	}
+}
+
+// Tests that a bloom created without a database reports every hash as possibly
+// present until it is loaded from a database later on.
+func TestSyncBloomLoad(t *testing.T) {
+	diskdb := memorydb.New()
+	blob := []byte("trie node")
+	hash := crypto.Keccak256(blob)
+	diskdb.Put(hash, blob)
+
+	bloom := NewSyncBloom(1, nil)
+	defer bloom.Close()
+
+	missing := crypto.Keccak256([]byte("missing node"))
+	if !bloom.Contains(missing) {
+		t.Fatalf("unloaded bloom reported a missing node")
+	}
+	bloom.Load(diskdb)
+	for i := 0; bloom.Contains(missing); i++ {
+		if i == 100 {
+			t.Fatalf("bloom not loaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !bloom.Contains(hash) {
+		t.Fatalf("loaded bloom misses a database node")
+	}
+}
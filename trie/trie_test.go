@@ -317,6 +317,63 @@ func TestLargeValue(t *testing.T) {
 	trie.Hash()
 }
 
+// Tests that hashing and committing the subtries concurrently yields the same
+// root, nodes and leaf callbacks as doing it sequentially.
+func TestParallelHashing(t *testing.T) {
+	random := rand.New(rand.NewSource(0))
+
+	keys, vals := make([][]byte, 2*parallelHashThreshold), make([][]byte, 2*parallelHashThreshold)
+	for i := range keys {
+		keys[i], vals[i] = make([]byte, 32), make([]byte, 40)
+		random.Read(keys[i])
+		random.Read(vals[i])
+	}
+	commit := func(parallel bool) (common.Hash, *memorydb.Database, int) {
+		diskdb := memorydb.New()
+		trie, _ := New(common.Hash{}, NewDatabase(diskdb))
+		for i := range keys {
+			trie.Update(keys[i], vals[i])
+			if !parallel && i%(parallelHashThreshold/2) == 0 {
+				trie.Hash()
+			}
+		}
+		if have := trie.unhashed >= parallelHashThreshold; have != parallel {
+			t.Fatalf("parallel hashing mismatch: have %v, want %v", have, parallel)
+		}
+		leaves := 0
+		root, err := trie.Commit(func(leaf []byte, parent common.Hash) error {
+			leaves++
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("failed to commit trie: %v", err)
+		}
+		if err := trie.db.Commit(root, false); err != nil {
+			t.Fatalf("failed to flush trie: %v", err)
+		}
+		return root, diskdb, leaves
+	}
+	seqRoot, seqDB, seqLeaves := commit(false)
+	parRoot, parDB, parLeaves := commit(true)
+
+	if parRoot != seqRoot {
+		t.Fatalf("root mismatch: have %x, want %x", parRoot, seqRoot)
+	}
+	if parLeaves != seqLeaves {
+		t.Errorf("leaf callback mismatch: have %d, want %d", parLeaves, seqLeaves)
+	}
+	if parDB.Len() != seqDB.Len() {
+		t.Errorf("node count mismatch: have %d, want %d", parDB.Len(), seqDB.Len())
+	}
+	it := seqDB.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		if blob, _ := parDB.Get(it.Key()); !bytes.Equal(blob, it.Value()) {
+			t.Errorf("node %x mismatch", it.Key())
+		}
+	}
+}
+
 type countingDB struct {
 	ethdb.KeyValueStore
 	gets map[string]int